@@ -0,0 +1,669 @@
+package main
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/geekAshish/DriveDesk/handler/codec"
+	"github.com/geekAshish/DriveDesk/handler/response"
+	"github.com/geekAshish/DriveDesk/middleware"
+	"github.com/geekAshish/DriveDesk/models"
+	"github.com/geekAshish/DriveDesk/openapi"
+
+	loginHandler "github.com/geekAshish/DriveDesk/handler/login"
+	ruleHandler "github.com/geekAshish/DriveDesk/handler/rule"
+	streamHandler "github.com/geekAshish/DriveDesk/handler/stream"
+	handlerV2 "github.com/geekAshish/DriveDesk/handler/v2"
+)
+
+// apiSpecV1 describes the v1 routes newRouter registers, which are also
+// served without a version prefix.
+func apiSpecV1() *openapi.Document {
+	doc := newAPISpec("1.0.0", "Deprecated in favour of v2, which fixes the car and engine field names and types. "+
+		"Responses carry `Deprecation` and `Sunset` headers and a `Link` to the v2 resource.")
+	doc.Servers = []openapi.Server{
+		{URL: "/v1"},
+		{URL: "/", Description: "Unversioned aliases of v1, deprecated in favour of /v1."},
+	}
+
+	addAuthOperations(doc)
+	addCarOperations(doc, models.Car{}, []models.Car{}, models.CarRequest{})
+	addEngineOperations(doc, models.Engine{}, models.EngineRequest{})
+	addReferenceOperations(doc)
+	addValidationRuleOperations(doc, models.CarRequest{}, models.EngineRequest{})
+	addWebhookOperations(doc)
+	addStreamOperations(doc)
+	addSyncOperations(doc)
+
+	doc.Deprecate()
+
+	return doc
+}
+
+// apiSpecV2 describes the v2 routes newRouter registers.
+func apiSpecV2() *openapi.Document {
+	doc := newAPISpec("2.0.0", "Years are numbers, cylinders integers, and the engine displacement is spelled `displacement`. "+
+		"Validation rules address fields by their v1 names, e.g. `engine.dispacement`.")
+	doc.Servers = []openapi.Server{{URL: "/v2"}}
+
+	addAuthOperations(doc)
+	addCarOperations(doc, handlerV2.Car{}, []handlerV2.Car{}, handlerV2.CarRequest{})
+	addEngineOperations(doc, handlerV2.Engine{}, handlerV2.EngineRequest{})
+	addReferenceOperations(doc)
+	addValidationRuleOperations(doc, handlerV2.CarRequest{}, handlerV2.EngineRequest{})
+	addWebhookOperations(doc)
+	addStreamOperations(doc)
+	addSyncOperations(doc)
+
+	return doc
+}
+
+// newAPISpec is a document with the parts every version shares.
+func newAPISpec(version, notes string) *openapi.Document {
+	doc := openapi.New(openapi.Info{
+		Title:   "DriveDesk API",
+		Version: version,
+		Description: "Manage a car inventory and the engines fitted to the cars.\n\n" +
+			"Get a token from `POST /login` and send it as `Authorization: Bearer <token>`. " +
+			"Every response carries an `X-Request-ID` header, and errors are RFC 7807 problem documents. " +
+			"Requests are rate limited per user, or per address before logging in; responses carry `RateLimit-*` headers " +
+			"and a client over its limit gets 429.\n\n" + notes,
+	})
+
+	doc.Tags = []openapi.Tag{
+		{Name: "auth"},
+		{Name: "cars"},
+		{Name: "engines"},
+		{Name: "reference data", Description: "Admin-managed values of enums such as fuel type."},
+		{Name: "validation rules", Description: "Deployment specific checks on car and engine requests."},
+		{Name: "webhooks", Description: "Signed HTTP callbacks for changes to cars and engines."},
+		{Name: "change stream", Description: "Changes to cars and engines pushed to connected clients."},
+		{Name: "sync", Description: "Deltas of cars and engines for clients that work offline."},
+	}
+
+	doc.Components.SecuritySchemes["bearerAuth"] = &openapi.SecurityScheme{
+		Type:         "http",
+		Scheme:       "bearer",
+		BearerFormat: "JWT",
+		Description:  "Token returned by POST /login.",
+	}
+	doc.Security = []openapi.SecurityRequirement{{"bearerAuth": {}}}
+
+	problems := map[string]string{
+		"BadRequest":       "The request could not be understood, e.g. the body is not valid JSON.",
+		"Unauthorized":     "The bearer token is missing or invalid.",
+		"Forbidden":        "The token is valid but its role does not allow the request.",
+		"NotFound":         "The resource does not exist.",
+		"Conflict":         "The request clashes with the current state, e.g. a duplicate key.",
+		"ValidationFailed": "The request is well-formed but some fields are invalid; `errors` lists all of them.",
+		"InternalError":    "Something went wrong on the server; quote the request_id when reporting it.",
+
+		"NotAcceptable":        "None of the media types in `Accept` can be produced.",
+		"UnsupportedMediaType": "The request body is in a media type that cannot be read.",
+		"TooManyRequests":      "The client made more requests than its rate limit allows; retry after `Retry-After` seconds.",
+	}
+	for name, description := range problems {
+		doc.Components.Responses[name] = &openapi.Response{
+			Description: description,
+			Content:     doc.JSON(response.ProblemContentType, response.Problem{}),
+		}
+	}
+	doc.Components.Responses["TooManyRequests"].Headers = map[string]openapi.Header{
+		"Retry-After":         {Description: "Seconds until the next request is allowed.", Schema: &openapi.Schema{Type: "integer"}},
+		"RateLimit-Limit":     {Description: "Requests the client can make at once.", Schema: &openapi.Schema{Type: "integer"}},
+		"RateLimit-Remaining": {Description: "Requests the client can make right now.", Schema: &openapi.Schema{Type: "integer"}},
+		"RateLimit-Reset":     {Description: "Seconds until the limit is whole again.", Schema: &openapi.Schema{Type: "integer"}},
+		"RateLimit-Policy":    {Description: "The limits applied, as `<requests>;w=<seconds>`.", Schema: &openapi.Schema{Type: "string"}},
+	}
+
+	return doc
+}
+
+// responses adds the failures every authenticated operation can answer with
+// to the given ones.
+func responses(r map[int]*openapi.Response) map[string]*openapi.Response {
+	all := map[string]*openapi.Response{
+		openapi.Status(http.StatusUnauthorized):        openapi.Ref("Unauthorized"),
+		openapi.Status(http.StatusTooManyRequests):     openapi.Ref("TooManyRequests"),
+		openapi.Status(http.StatusInternalServerError): openapi.Ref("InternalError"),
+	}
+	for status, response := range r {
+		all[openapi.Status(status)] = response
+	}
+	return all
+}
+
+// idempotencyKey makes retries of the POSTs that create resources safe.
+var idempotencyKey = openapi.HeaderParam(middleware.IdempotencyKeyHeader,
+	"Any unique string, e.g. a UUID. A retry with the same key and body gets the original response back, "+
+		"marked `Idempotent-Replayed: true`, for 24 hours; the same key with another body is a 409.")
+
+// negotiated offers the bodies of op in every media type the codec package
+// negotiates besides JSON, CSV included for list operations.
+func negotiated(op *openapi.Operation, list bool) *openapi.Operation {
+	alternatives := []string{codec.XML.MediaType, codec.MessagePack.MediaType}
+
+	if op.RequestBody != nil {
+		offer(op.RequestBody.Content, alternatives)
+		op.Responses[openapi.Status(http.StatusUnsupportedMediaType)] = openapi.Ref("UnsupportedMediaType")
+	}
+
+	for status, r := range op.Responses {
+		if r.Content == nil || !strings.HasPrefix(status, "2") {
+			continue
+		}
+
+		offer(r.Content, alternatives)
+		if list {
+			r.Content[codec.CSV.MediaType] = openapi.MediaType{Schema: &openapi.Schema{
+				Type:        "string",
+				Description: "A header row of JSON field paths such as engine.engine_id, then one row per item.",
+			}}
+		}
+	}
+
+	op.Responses[openapi.Status(http.StatusNotAcceptable)] = openapi.Ref("NotAcceptable")
+	return op
+}
+
+// conditional describes the HTTP caching of a GET operation: the validators
+// of its 200 response, the conditional request headers and 304. Only
+// operations with lastModified send Last-Modified.
+func conditional(op *openapi.Operation, lastModified bool) *openapi.Operation {
+	op.Parameters = append(op.Parameters,
+		openapi.HeaderParam("If-None-Match", "ETags of copies the client has. Answered with 304 when one is current."),
+		openapi.HeaderParam("If-Modified-Since", "Answered with 304 when nothing changed since. Ignored with If-None-Match."),
+	)
+
+	ok := op.Responses[openapi.Status(http.StatusOK)]
+	ok.Headers = map[string]openapi.Header{
+		"ETag":          {Description: "Strong ETag of the body.", Schema: &openapi.Schema{Type: "string"}},
+		"Cache-Control": {Description: "Configured per route; `" + middleware.DefaultCacheControl + "` by default.", Schema: &openapi.Schema{Type: "string"}},
+	}
+	if lastModified {
+		ok.Headers["Last-Modified"] = openapi.Header{Description: "When the resource last changed.", Schema: &openapi.Schema{Type: "string"}}
+	}
+
+	op.Responses[openapi.Status(http.StatusNotModified)] = &openapi.Response{Description: "The client's copy is current."}
+	return op
+}
+
+// offer adds the JSON schema of content under each of mediaTypes.
+func offer(content map[string]openapi.MediaType, mediaTypes []string) {
+	json := content["application/json"]
+	for _, mediaType := range mediaTypes {
+		content[mediaType] = json
+	}
+}
+
+func addAuthOperations(doc *openapi.Document) {
+	doc.Add(http.MethodPost, "/login", &openapi.Operation{
+		Tags:        []string{"auth"},
+		Summary:     "Log in",
+		OperationID: "login",
+		RequestBody: doc.Body(models.Credentials{}),
+		Responses: map[string]*openapi.Response{
+			openapi.Status(http.StatusOK):                  doc.Returns("A token valid for 24 hours.", loginHandler.TokenResponse{}),
+			openapi.Status(http.StatusBadRequest):          openapi.Ref("BadRequest"),
+			openapi.Status(http.StatusUnauthorized):        openapi.Ref("Unauthorized"),
+			openapi.Status(http.StatusTooManyRequests):     openapi.Ref("TooManyRequests"),
+			openapi.Status(http.StatusInternalServerError): openapi.Ref("InternalError"),
+		},
+		Security: []openapi.SecurityRequirement{},
+	})
+}
+
+// addCarOperations documents the car routes of a version whose cars, car
+// lists and car requests look like car, cars and request.
+func addCarOperations(doc *openapi.Document, car, cars, request any) {
+	id := openapi.PathParam("id", "Car id, a UUID.")
+
+	doc.Add(http.MethodGet, "/cars", conditional(negotiated(&openapi.Operation{
+		Tags:        []string{"cars"},
+		Summary:     "List the cars of a brand",
+		OperationID: "listCarsByBrand",
+		Parameters: []openapi.Parameter{
+			openapi.QueryParam("brand", "string", "Brand to list, e.g. Honda."),
+			openapi.QueryParam("isEngine", "boolean", "Include full engine details instead of only the engine id."),
+		},
+		Responses: responses(map[int]*openapi.Response{
+			http.StatusOK: doc.Returns("Cars of the brand, oldest first.", cars),
+		}),
+	}, true), false))
+
+	doc.Add(http.MethodPost, "/cars", negotiated(&openapi.Operation{
+		Tags:        []string{"cars"},
+		Summary:     "Add a car",
+		Description: "The engine must already exist. Fuel type aliases such as gasoline are stored as their canonical code.",
+		OperationID: "createCar",
+		Parameters:  []openapi.Parameter{idempotencyKey},
+		RequestBody: doc.Body(request),
+		Responses: responses(map[int]*openapi.Response{
+			http.StatusCreated:             doc.Returns("The car as stored.", car),
+			http.StatusBadRequest:          openapi.Ref("BadRequest"),
+			http.StatusConflict:            openapi.Ref("Conflict"),
+			http.StatusUnprocessableEntity: openapi.Ref("ValidationFailed"),
+		}),
+	}, false))
+
+	doc.Add(http.MethodGet, "/cars/{id}", conditional(negotiated(&openapi.Operation{
+		Tags:        []string{"cars"},
+		Summary:     "Get a car",
+		OperationID: "getCar",
+		Parameters:  []openapi.Parameter{id},
+		Responses: responses(map[int]*openapi.Response{
+			http.StatusOK:       doc.Returns("The car with its engine.", car),
+			http.StatusNotFound: openapi.Ref("NotFound"),
+		}),
+	}, false), true))
+
+	doc.Add(http.MethodPut, "/cars/{id}", negotiated(&openapi.Operation{
+		Tags:        []string{"cars"},
+		Summary:     "Replace a car",
+		OperationID: "updateCar",
+		Parameters:  []openapi.Parameter{id},
+		RequestBody: doc.Body(request),
+		Responses: responses(map[int]*openapi.Response{
+			http.StatusOK:                  doc.Returns("The car as stored.", car),
+			http.StatusBadRequest:          openapi.Ref("BadRequest"),
+			http.StatusNotFound:            openapi.Ref("NotFound"),
+			http.StatusConflict:            openapi.Ref("Conflict"),
+			http.StatusUnprocessableEntity: openapi.Ref("ValidationFailed"),
+		}),
+	}, false))
+
+	doc.Add(http.MethodDelete, "/cars/{id}", negotiated(&openapi.Operation{
+		Tags:        []string{"cars"},
+		Summary:     "Delete a car",
+		OperationID: "deleteCar",
+		Parameters:  []openapi.Parameter{id},
+		Responses: responses(map[int]*openapi.Response{
+			http.StatusOK:       doc.Returns("The deleted car.", car),
+			http.StatusNotFound: openapi.Ref("NotFound"),
+		}),
+	}, false))
+}
+
+// addEngineOperations documents the engine routes of a version whose engines
+// and engine requests look like engine and request.
+func addEngineOperations(doc *openapi.Document, engine, request any) {
+	id := openapi.PathParam("id", "Engine id, a UUID.")
+
+	doc.Add(http.MethodPost, "/engine", negotiated(&openapi.Operation{
+		Tags:        []string{"engines"},
+		Summary:     "Add an engine",
+		OperationID: "createEngine",
+		Parameters:  []openapi.Parameter{idempotencyKey},
+		RequestBody: doc.Body(request),
+		Responses: responses(map[int]*openapi.Response{
+			http.StatusCreated:             doc.Returns("The engine as stored.", engine),
+			http.StatusBadRequest:          openapi.Ref("BadRequest"),
+			http.StatusConflict:            openapi.Ref("Conflict"),
+			http.StatusUnprocessableEntity: openapi.Ref("ValidationFailed"),
+		}),
+	}, false))
+
+	doc.Add(http.MethodGet, "/engine/{id}", conditional(negotiated(&openapi.Operation{
+		Tags:        []string{"engines"},
+		Summary:     "Get an engine",
+		OperationID: "getEngine",
+		Parameters:  []openapi.Parameter{id},
+		Responses: responses(map[int]*openapi.Response{
+			http.StatusOK:       doc.Returns("The engine.", engine),
+			http.StatusNotFound: openapi.Ref("NotFound"),
+		}),
+	}, false), true))
+
+	doc.Add(http.MethodPut, "/engine/{id}", negotiated(&openapi.Operation{
+		Tags:        []string{"engines"},
+		Summary:     "Replace an engine",
+		OperationID: "updateEngine",
+		Parameters:  []openapi.Parameter{id},
+		RequestBody: doc.Body(request),
+		Responses: responses(map[int]*openapi.Response{
+			http.StatusOK:                  doc.Returns("The engine as stored.", engine),
+			http.StatusBadRequest:          openapi.Ref("BadRequest"),
+			http.StatusNotFound:            openapi.Ref("NotFound"),
+			http.StatusUnprocessableEntity: openapi.Ref("ValidationFailed"),
+		}),
+	}, false))
+
+	doc.Add(http.MethodDelete, "/engine/{id}", negotiated(&openapi.Operation{
+		Tags:        []string{"engines"},
+		Summary:     "Delete an engine",
+		Description: "Cars fitted with the engine are deleted with it.",
+		OperationID: "deleteEngine",
+		Parameters:  []openapi.Parameter{id},
+		Responses: responses(map[int]*openapi.Response{
+			http.StatusOK:       doc.Returns("The deleted engine.", engine),
+			http.StatusNotFound: openapi.Ref("NotFound"),
+		}),
+	}, false))
+}
+
+func addReferenceOperations(doc *openapi.Document) {
+	kind := openapi.PathParam("kind", "Kind of value, e.g. fuel_type, body_type or transmission.")
+	code := openapi.PathParam("code", "Canonical code of the value, e.g. petrol.")
+
+	doc.Add(http.MethodGet, "/reference/{kind}", &openapi.Operation{
+		Tags:        []string{"reference data"},
+		Summary:     "List the values of a kind",
+		OperationID: "listReferenceValues",
+		Parameters:  []openapi.Parameter{kind},
+		Responses: responses(map[int]*openapi.Response{
+			http.StatusOK:                  doc.Returns("Values ordered by code, inactive ones included.", []models.ReferenceValue{}),
+			http.StatusUnprocessableEntity: openapi.Ref("ValidationFailed"),
+		}),
+	})
+
+	doc.Add(http.MethodPost, "/reference/{kind}", &openapi.Operation{
+		Tags:        []string{"reference data"},
+		Summary:     "Add a value (admin)",
+		OperationID: "createReferenceValue",
+		Parameters:  []openapi.Parameter{kind},
+		RequestBody: doc.Body(models.ReferenceValueRequest{}),
+		Responses: responses(map[int]*openapi.Response{
+			http.StatusCreated:             doc.Returns("The value as stored.", models.ReferenceValue{}),
+			http.StatusBadRequest:          openapi.Ref("BadRequest"),
+			http.StatusForbidden:           openapi.Ref("Forbidden"),
+			http.StatusConflict:            openapi.Ref("Conflict"),
+			http.StatusUnprocessableEntity: openapi.Ref("ValidationFailed"),
+		}),
+	})
+
+	doc.Add(http.MethodGet, "/reference/{kind}/{code}", &openapi.Operation{
+		Tags:        []string{"reference data"},
+		Summary:     "Get a value",
+		OperationID: "getReferenceValue",
+		Parameters:  []openapi.Parameter{kind, code},
+		Responses: responses(map[int]*openapi.Response{
+			http.StatusOK:       doc.Returns("The value with its aliases.", models.ReferenceValue{}),
+			http.StatusNotFound: openapi.Ref("NotFound"),
+		}),
+	})
+
+	doc.Add(http.MethodPut, "/reference/{kind}/{code}", &openapi.Operation{
+		Tags:        []string{"reference data"},
+		Summary:     "Replace a value (admin)",
+		Description: "Replaces the label and aliases. Codes cannot be renamed; leave active out to keep it unchanged.",
+		OperationID: "updateReferenceValue",
+		Parameters:  []openapi.Parameter{kind, code},
+		RequestBody: doc.Body(models.ReferenceValueRequest{}),
+		Responses: responses(map[int]*openapi.Response{
+			http.StatusOK:                  doc.Returns("The value as stored.", models.ReferenceValue{}),
+			http.StatusBadRequest:          openapi.Ref("BadRequest"),
+			http.StatusForbidden:           openapi.Ref("Forbidden"),
+			http.StatusNotFound:            openapi.Ref("NotFound"),
+			http.StatusConflict:            openapi.Ref("Conflict"),
+			http.StatusUnprocessableEntity: openapi.Ref("ValidationFailed"),
+		}),
+	})
+
+	doc.Add(http.MethodDelete, "/reference/{kind}/{code}", &openapi.Operation{
+		Tags:        []string{"reference data"},
+		Summary:     "Delete a value (admin)",
+		OperationID: "deleteReferenceValue",
+		Parameters:  []openapi.Parameter{kind, code},
+		Responses: responses(map[int]*openapi.Response{
+			http.StatusOK:        doc.Returns("The deleted value.", models.ReferenceValue{}),
+			http.StatusForbidden: openapi.Ref("Forbidden"),
+			http.StatusNotFound:  openapi.Ref("NotFound"),
+		}),
+	})
+}
+
+func addValidationRuleOperations(doc *openapi.Document, carRequest, engineRequest any) {
+	doc.Add(http.MethodGet, "/validation/rules", &openapi.Operation{
+		Tags:        []string{"validation rules"},
+		Summary:     "List the active rules",
+		OperationID: "listValidationRules",
+		Responses: responses(map[int]*openapi.Response{
+			http.StatusOK: doc.Returns("Rules in the order they were added.", []models.ValidationRule{}),
+		}),
+	})
+
+	doc.Add(http.MethodPost, "/validation/rules", &openapi.Operation{
+		Tags:        []string{"validation rules"},
+		Summary:     "Add a rule (admin)",
+		Description: "Fails with 409 when the deployment reads its rules from a file.",
+		OperationID: "createValidationRule",
+		RequestBody: doc.Body(models.ValidationRuleRequest{}),
+		Responses: responses(map[int]*openapi.Response{
+			http.StatusCreated:             doc.Returns("The rule as stored.", models.ValidationRule{}),
+			http.StatusBadRequest:          openapi.Ref("BadRequest"),
+			http.StatusForbidden:           openapi.Ref("Forbidden"),
+			http.StatusConflict:            openapi.Ref("Conflict"),
+			http.StatusUnprocessableEntity: openapi.Ref("ValidationFailed"),
+		}),
+	})
+
+	doc.Add(http.MethodDelete, "/validation/rules/{id}", &openapi.Operation{
+		Tags:        []string{"validation rules"},
+		Summary:     "Delete a rule (admin)",
+		OperationID: "deleteValidationRule",
+		Parameters:  []openapi.Parameter{openapi.PathParam("id", "Rule id, a UUID.")},
+		Responses: responses(map[int]*openapi.Response{
+			http.StatusOK:        doc.Returns("The deleted rule.", models.ValidationRule{}),
+			http.StatusForbidden: openapi.Ref("Forbidden"),
+			http.StatusNotFound:  openapi.Ref("NotFound"),
+			http.StatusConflict:  openapi.Ref("Conflict"),
+		}),
+	})
+
+	target := openapi.PathParam("target", "What the payload is: car or engine.")
+	target.Schema.Enum = []any{models.RuleTargetCar, models.RuleTargetEngine}
+
+	doc.Add(http.MethodPost, "/validation/{target}/test", &openapi.Operation{
+		Tags:        []string{"validation rules"},
+		Summary:     "Check a payload against the active rules",
+		Description: "Runs the built-in checks and the deployment's rules without storing anything. The body is a car or engine request.",
+		OperationID: "testValidationRules",
+		Parameters:  []openapi.Parameter{target},
+		RequestBody: &openapi.RequestBody{
+			Required: true,
+			Content: map[string]openapi.MediaType{
+				"application/json": {Schema: &openapi.Schema{OneOf: []*openapi.Schema{
+					doc.Schema(carRequest),
+					doc.Schema(engineRequest),
+				}}},
+			},
+		},
+		Responses: responses(map[int]*openapi.Response{
+			http.StatusOK:         doc.Returns("Whether the payload passes, and every violation when it does not.", ruleHandler.TestResult{}),
+			http.StatusBadRequest: openapi.Ref("BadRequest"),
+			http.StatusNotFound:   openapi.Ref("NotFound"),
+		}),
+	})
+}
+
+func addWebhookOperations(doc *openapi.Document) {
+	id := openapi.PathParam("id", "Webhook id, a UUID.")
+	deliveryID := openapi.PathParam("id", "Delivery id, a UUID.")
+
+	doc.Add(http.MethodGet, "/webhooks", &openapi.Operation{
+		Tags:        []string{"webhooks"},
+		Summary:     "List the webhooks (admin)",
+		OperationID: "listWebhooks",
+		Responses: responses(map[int]*openapi.Response{
+			http.StatusOK:        doc.Returns("Webhooks in the order they were added. Secrets are never returned.", []models.Webhook{}),
+			http.StatusForbidden: openapi.Ref("Forbidden"),
+		}),
+	})
+
+	doc.Add(http.MethodPost, "/webhooks", &openapi.Operation{
+		Tags:    []string{"webhooks"},
+		Summary: "Subscribe a URL to events (admin)",
+		Description: "Events are " + strings.Join(models.EventTypes, ", ") + ". The secret, at least 16 characters, " +
+			"keys the `X-DriveDesk-Signature` of every delivery. A webhook is active unless `active` is false.",
+		OperationID: "createWebhook",
+		RequestBody: doc.Body(models.WebhookRequest{}),
+		Responses: responses(map[int]*openapi.Response{
+			http.StatusCreated:             doc.Returns("The webhook as stored.", models.Webhook{}),
+			http.StatusBadRequest:          openapi.Ref("BadRequest"),
+			http.StatusForbidden:           openapi.Ref("Forbidden"),
+			http.StatusUnprocessableEntity: openapi.Ref("ValidationFailed"),
+		}),
+	})
+
+	doc.Add(http.MethodGet, "/webhooks/{id}", &openapi.Operation{
+		Tags:        []string{"webhooks"},
+		Summary:     "Get a webhook (admin)",
+		OperationID: "getWebhook",
+		Parameters:  []openapi.Parameter{id},
+		Responses: responses(map[int]*openapi.Response{
+			http.StatusOK:        doc.Returns("The webhook.", models.Webhook{}),
+			http.StatusForbidden: openapi.Ref("Forbidden"),
+			http.StatusNotFound:  openapi.Ref("NotFound"),
+		}),
+	})
+
+	doc.Add(http.MethodPut, "/webhooks/{id}", &openapi.Operation{
+		Tags:        []string{"webhooks"},
+		Summary:     "Replace a webhook (admin)",
+		Description: "Leave the secret out to keep the current one, and active out to keep it unchanged.",
+		OperationID: "updateWebhook",
+		Parameters:  []openapi.Parameter{id},
+		RequestBody: doc.Body(models.WebhookRequest{}),
+		Responses: responses(map[int]*openapi.Response{
+			http.StatusOK:                  doc.Returns("The webhook as stored.", models.Webhook{}),
+			http.StatusBadRequest:          openapi.Ref("BadRequest"),
+			http.StatusForbidden:           openapi.Ref("Forbidden"),
+			http.StatusNotFound:            openapi.Ref("NotFound"),
+			http.StatusUnprocessableEntity: openapi.Ref("ValidationFailed"),
+		}),
+	})
+
+	doc.Add(http.MethodDelete, "/webhooks/{id}", &openapi.Operation{
+		Tags:        []string{"webhooks"},
+		Summary:     "Delete a webhook and its deliveries (admin)",
+		OperationID: "deleteWebhook",
+		Parameters:  []openapi.Parameter{id},
+		Responses: responses(map[int]*openapi.Response{
+			http.StatusOK:        doc.Returns("The deleted webhook.", models.Webhook{}),
+			http.StatusForbidden: openapi.Ref("Forbidden"),
+			http.StatusNotFound:  openapi.Ref("NotFound"),
+		}),
+	})
+
+	status := openapi.QueryParam("status", "string", "Only deliveries in this state; dead lists the dead letters.")
+	status.Schema.Enum = []any{models.DeliveryPending, models.DeliverySucceeded, models.DeliveryDead}
+
+	doc.Add(http.MethodGet, "/webhook-deliveries", &openapi.Operation{
+		Tags:        []string{"webhooks"},
+		Summary:     "List the delivery log (admin)",
+		OperationID: "listWebhookDeliveries",
+		Parameters: []openapi.Parameter{
+			openapi.QueryParam("webhook_id", "string", "Only deliveries to this webhook."),
+			status,
+			openapi.QueryParam("limit", "integer", "At most this many deliveries, 100 by default and 1000 at most."),
+		},
+		Responses: responses(map[int]*openapi.Response{
+			http.StatusOK:                  doc.Returns("Deliveries, newest first, with the outcome of their latest attempt.", []models.WebhookDelivery{}),
+			http.StatusForbidden:           openapi.Ref("Forbidden"),
+			http.StatusUnprocessableEntity: openapi.Ref("ValidationFailed"),
+		}),
+	})
+
+	doc.Add(http.MethodGet, "/webhook-deliveries/{id}", &openapi.Operation{
+		Tags:        []string{"webhooks"},
+		Summary:     "Get a delivery (admin)",
+		OperationID: "getWebhookDelivery",
+		Parameters:  []openapi.Parameter{deliveryID},
+		Responses: responses(map[int]*openapi.Response{
+			http.StatusOK:        doc.Returns("The delivery with its payload.", models.WebhookDelivery{}),
+			http.StatusForbidden: openapi.Ref("Forbidden"),
+			http.StatusNotFound:  openapi.Ref("NotFound"),
+		}),
+	})
+
+	doc.Add(http.MethodPost, "/webhook-deliveries/{id}/redeliver", &openapi.Operation{
+		Tags:        []string{"webhooks"},
+		Summary:     "Send a delivery again (admin)",
+		Description: "Queues the delivery with a fresh set of attempts, whatever its state. It is sent in the background.",
+		OperationID: "redeliverWebhookDelivery",
+		Parameters:  []openapi.Parameter{deliveryID},
+		Responses: responses(map[int]*openapi.Response{
+			http.StatusAccepted:  doc.Returns("The delivery, pending again.", models.WebhookDelivery{}),
+			http.StatusForbidden: openapi.Ref("Forbidden"),
+			http.StatusNotFound:  openapi.Ref("NotFound"),
+		}),
+	})
+}
+
+func addStreamOperations(doc *openapi.Document) {
+	entity := openapi.QueryParam("entity", "string", "Only changes to this entity.")
+	entity.Schema.Enum = []any{models.EntityCar, models.EntityEngine}
+
+	parameters := []openapi.Parameter{
+		entity,
+		openapi.QueryParam("brand", "string", "Only changes to cars of this brand, ignoring case. Engine changes have no brand."),
+		openapi.HeaderParam(streamHandler.LastEventIDHeader, "Resume after this event, sending the retained changes since first."),
+		openapi.QueryParam("last_event_id", "integer", "Same as the Last-Event-ID header, for clients that cannot set headers."),
+	}
+
+	doc.Add(http.MethodGet, "/events", &openapi.Operation{
+		Tags:    []string{"change stream"},
+		Summary: "Stream changes as Server-Sent Events",
+		Description: "Each event is named after its type, e.g. `car.created`, has the change's sequence number as its id " +
+			"and a ChangeEvent as its data. Comments are sent every 15 seconds to keep the connection open.",
+		OperationID: "streamEvents",
+		Parameters:  parameters,
+		Responses: responses(map[int]*openapi.Response{
+			http.StatusOK: {
+				Description: "A stream that lasts until the client disconnects.",
+				Content:     map[string]openapi.MediaType{"text/event-stream": {Schema: doc.Schema(models.ChangeEvent{})}},
+			},
+			http.StatusBadRequest:          openapi.Ref("BadRequest"),
+			http.StatusUnprocessableEntity: openapi.Ref("ValidationFailed"),
+		}),
+	})
+
+	doc.Add(http.MethodGet, "/events/ws", &openapi.Operation{
+		Tags:        []string{"change stream"},
+		Summary:     "Stream changes over a WebSocket",
+		Description: "Sends one ChangeEvent as a JSON text message per change. Takes the same filters as `GET /events`.",
+		OperationID: "streamEventsWebSocket",
+		Parameters:  parameters,
+		Responses: responses(map[int]*openapi.Response{
+			http.StatusSwitchingProtocols:  {Description: "The connection is now a WebSocket."},
+			http.StatusBadRequest:          openapi.Ref("BadRequest"),
+			http.StatusUnprocessableEntity: openapi.Ref("ValidationFailed"),
+		}),
+	})
+}
+
+func addSyncOperations(doc *openapi.Document) {
+	doc.Add(http.MethodGet, "/sync", &openapi.Operation{
+		Tags:    []string{"sync"},
+		Summary: "Pull the changes since a sync token",
+		Description: "Returns every car and engine changed since `since` as it is now, in the order of their versions, " +
+			"and a tombstone for each one deleted. Pass the returned token as `since` on the next pull, right away while " +
+			"`has_more` is set. Cars and engines have their v1 shape in every version of the API.",
+		OperationID: "pullSync",
+		Parameters: []openapi.Parameter{
+			openapi.QueryParam("since", "string", "Token of the last pull. Leave it out to pull everything."),
+			openapi.QueryParam("limit", "integer", "Most changes to return, between 1 and "+strconv.Itoa(models.MaxSyncLimit)+
+				"; "+strconv.Itoa(models.DefaultSyncLimit)+" by default."),
+		},
+		Responses: responses(map[int]*openapi.Response{
+			http.StatusOK:                  doc.Returns("The changes and the token to pull the next ones with.", models.SyncPage{}),
+			http.StatusUnprocessableEntity: openapi.Ref("ValidationFailed"),
+		}),
+	})
+
+	doc.Add(http.MethodPost, "/sync", &openapi.Operation{
+		Tags:    []string{"sync"},
+		Summary: "Push changes made offline",
+		Description: "Applies each change on its own, in order. A change to an existing car or engine names the version " +
+			"it was based on, and is a `conflict` carrying the current state when that is no longer the latest. Upserts " +
+			"without an id create a new car or engine. Changes with invalid payloads are `rejected` with their field errors.",
+		OperationID: "pushSync",
+		RequestBody: doc.Body(models.SyncPushRequest{}),
+		Responses: responses(map[int]*openapi.Response{
+			http.StatusOK:                  doc.Returns("The outcome of every change, in the order they were pushed.", models.SyncPushResponse{}),
+			http.StatusBadRequest:          openapi.Ref("BadRequest"),
+			http.StatusUnprocessableEntity: openapi.Ref("ValidationFailed"),
+		}),
+	})
+}
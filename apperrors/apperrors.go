@@ -0,0 +1,152 @@
+// Package apperrors defines the kinds of failure the store and service layers
+// report, so handlers can answer with the right HTTP status without knowing
+// which backend or validator produced the error.
+package apperrors
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Sentinels for errors.Is. Every *Error matches exactly one of them.
+var (
+	ErrBadRequest   = errors.New("bad request")
+	ErrNotFound     = errors.New("not found")
+	ErrValidation   = errors.New("validation failed")
+	ErrConflict     = errors.New("conflict")
+	ErrUnauthorized = errors.New("unauthorized")
+	ErrForbidden    = errors.New("forbidden")
+
+	ErrNotAcceptable        = errors.New("not acceptable")
+	ErrUnsupportedMediaType = errors.New("unsupported media type")
+
+	ErrTooManyRequests = errors.New("too many requests")
+)
+
+// Error is a failure of a known kind. Message is safe to show to API clients;
+// Err, when set, is the underlying cause and is only meant for logs. Fields
+// lists the offending fields of a validation failure.
+type Error struct {
+	Kind    error
+	Message string
+	Err     error
+	Fields  []FieldError
+}
+
+// FieldError is one invalid field of a request. Field is the JSON path of the
+// field, e.g. "engine.car_range", and Code a stable, machine-readable reason.
+type FieldError struct {
+	Field   string `json:"field"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *Error) Error() string {
+	if e.Err != nil {
+		return fmt.Sprintf("%s: %v", e.Message, e.Err)
+	}
+	return e.Message
+}
+
+func (e *Error) Unwrap() []error {
+	if e.Err != nil {
+		return []error{e.Kind, e.Err}
+	}
+	return []error{e.Kind}
+}
+
+// BadRequest reports a request that could not be understood, such as a body
+// that is not valid JSON.
+func BadRequest(format string, args ...any) error {
+	return newError(ErrBadRequest, format, args...)
+}
+
+// NotFound reports that the requested resource does not exist.
+func NotFound(format string, args ...any) error {
+	return newError(ErrNotFound, format, args...)
+}
+
+// Validation reports a well-formed request whose content is not acceptable.
+func Validation(format string, args ...any) error {
+	return newError(ErrValidation, format, args...)
+}
+
+// InvalidField reports a validation failure of a single field.
+func InvalidField(field, code, message string) error {
+	return &Error{
+		Kind:    ErrValidation,
+		Message: message,
+		Fields:  []FieldError{{Field: field, Code: code, Message: message}},
+	}
+}
+
+// InvalidFields reports a validation failure of one or more fields.
+func InvalidFields(fields []FieldError) error {
+	message := fields[0].Message
+	if len(fields) > 1 {
+		message = fmt.Sprintf("request has %d invalid fields", len(fields))
+	}
+
+	return &Error{Kind: ErrValidation, Message: message, Fields: fields}
+}
+
+// Conflict reports a request that clashes with the current state, such as a
+// duplicate key.
+func Conflict(format string, args ...any) error {
+	return newError(ErrConflict, format, args...)
+}
+
+// Unauthorized reports missing or invalid credentials.
+func Unauthorized(format string, args ...any) error {
+	return newError(ErrUnauthorized, format, args...)
+}
+
+// Forbidden reports valid credentials that do not allow the request.
+func Forbidden(format string, args ...any) error {
+	return newError(ErrForbidden, format, args...)
+}
+
+// NotAcceptable reports that none of the media types the client accepts can
+// be produced.
+func NotAcceptable(format string, args ...any) error {
+	return newError(ErrNotAcceptable, format, args...)
+}
+
+// UnsupportedMediaType reports a request body in a media type that cannot be
+// decoded.
+func UnsupportedMediaType(format string, args ...any) error {
+	return newError(ErrUnsupportedMediaType, format, args...)
+}
+
+// TooManyRequests reports a client that went over its rate limit.
+func TooManyRequests(format string, args ...any) error {
+	return newError(ErrTooManyRequests, format, args...)
+}
+
+// Wrap attaches a cause to an error of the given kind.
+func Wrap(kind error, err error, format string, args ...any) error {
+	return &Error{Kind: kind, Message: fmt.Sprintf(format, args...), Err: err}
+}
+
+// Message returns the client-facing message of err, or "" when err is not an
+// *Error.
+func Message(err error) string {
+	var appErr *Error
+	if errors.As(err, &appErr) {
+		return appErr.Message
+	}
+	return ""
+}
+
+// Fields returns the field errors carried by err, if any.
+func Fields(err error) []FieldError {
+	var appErr *Error
+	if errors.As(err, &appErr) {
+		return appErr.Fields
+	}
+	return nil
+}
+
+func newError(kind error, format string, args ...any) error {
+	return &Error{Kind: kind, Message: fmt.Sprintf(format, args...)}
+}
@@ -0,0 +1,26 @@
+// Package broker publishes car and engine events to message brokers. The
+// outbox relay sends each event to a Publisher through outbox.BrokerSink, at
+// least once and in order for each car or engine.
+package broker
+
+import "context"
+
+// Message is an event as published to a broker.
+type Message struct {
+	// ID is the event id, which brokers that drop duplicates key on.
+	ID string
+	// Type is the event type, e.g. car.created.
+	Type string
+	// Key is the id of the car or engine. Events with the same key keep
+	// their order.
+	Key string
+	// Body is the event as a structured CloudEvent.
+	Body []byte
+}
+
+// Publisher sends messages to a broker. Publish returns once the broker has
+// the message, so the relay only marks it published then.
+type Publisher interface {
+	Publish(ctx context.Context, message Message) error
+	Close() error
+}
@@ -0,0 +1,139 @@
+package broker
+
+import (
+	"context"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/geekAshish/DriveDesk/models"
+	"github.com/nats-io/nats-server/v2/server"
+	"github.com/nats-io/nats.go"
+	"github.com/segmentio/kafka-go"
+)
+
+// startNATS runs a NATS server in the test process, on a free port.
+func startNATS(t *testing.T) *server.Server {
+	t.Helper()
+
+	srv, err := server.NewServer(&server.Options{Host: "127.0.0.1", Port: server.RANDOM_PORT, NoLog: true, NoSigs: true})
+	if err != nil {
+		t.Fatalf("starting nats: %v", err)
+	}
+
+	go srv.Start()
+	t.Cleanup(srv.Shutdown)
+
+	if !srv.ReadyForConnections(5 * time.Second) {
+		t.Fatal("nats is not ready")
+	}
+	return srv
+}
+
+func TestNATSPublish(t *testing.T) {
+	srv := startNATS(t)
+
+	consumer, err := nats.Connect(srv.ClientURL())
+	if err != nil {
+		t.Fatalf("connecting consumer: %v", err)
+	}
+	defer consumer.Close()
+
+	received := make(chan *nats.Msg, 10)
+	if _, err := consumer.ChanSubscribe("drivedesk.car.>", received); err != nil {
+		t.Fatalf("subscribing: %v", err)
+	}
+	if err := consumer.Flush(); err != nil {
+		t.Fatalf("flushing subscription: %v", err)
+	}
+
+	publisher, err := NewNATS(srv.ClientURL(), "drivedesk.")
+	if err != nil {
+		t.Fatalf("NewNATS: %v", err)
+	}
+	defer publisher.Close()
+
+	for _, eventType := range []string{models.EventCarCreated, models.EventEngineCreated, models.EventCarUpdated} {
+		err := publisher.Publish(context.Background(), Message{ID: eventType + "-id", Type: eventType, Key: "42", Body: []byte(`{"type":"` + eventType + `"}`)})
+		if err != nil {
+			t.Fatalf("Publish: %v", err)
+		}
+	}
+
+	var got []*nats.Msg
+	for len(got) < 2 {
+		select {
+		case msg := <-received:
+			got = append(got, msg)
+		case <-time.After(5 * time.Second):
+			t.Fatalf("got %d car messages, want 2", len(got))
+		}
+	}
+
+	if got[0].Subject != "drivedesk.car.created" || got[1].Subject != "drivedesk.car.updated" {
+		t.Errorf("subjects = %s, %s, want the car events in order", got[0].Subject, got[1].Subject)
+	}
+	if string(got[0].Data) != `{"type":"car.created"}` {
+		t.Errorf("body = %s", got[0].Data)
+	}
+	if got[0].Header.Get("Content-Type") != models.CloudEventContentType || got[0].Header.Get(nats.MsgIdHdr) != "car.created-id" {
+		t.Errorf("headers = %v", got[0].Header)
+	}
+}
+
+func TestNATSUnreachable(t *testing.T) {
+	srv := startNATS(t)
+
+	publisher, err := NewNATS(srv.ClientURL(), "drivedesk.")
+	if err != nil {
+		t.Fatalf("NewNATS: %v", err)
+	}
+	defer publisher.Close()
+
+	srv.Shutdown()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+
+	if err := publisher.Publish(ctx, Message{ID: "1", Type: models.EventCarCreated, Key: "42", Body: []byte(`{}`)}); err == nil {
+		t.Error("Publish succeeded with the server down, want an error so the relay retries")
+	}
+}
+
+// TestKafkaPublish needs a Kafka cluster with auto-created topics, e.g.
+// TEST_KAFKA_BROKERS="localhost:9092".
+func TestKafkaPublish(t *testing.T) {
+	brokers := os.Getenv("TEST_KAFKA_BROKERS")
+	if brokers == "" {
+		t.Skip("TEST_KAFKA_BROKERS not set")
+	}
+
+	topic := "drivedesk-test-" + time.Now().Format("20060102150405")
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	conn, err := kafka.DialLeader(ctx, "tcp", strings.Split(brokers, ",")[0], topic, 0)
+	if err != nil {
+		t.Fatalf("creating topic: %v", err)
+	}
+	conn.Close()
+
+	publisher := NewKafka(strings.Split(brokers, ","), topic)
+	defer publisher.Close()
+
+	if err := publisher.Publish(ctx, Message{ID: "1", Type: models.EventCarCreated, Key: "42", Body: []byte(`{"n":1}`)}); err != nil {
+		t.Fatalf("Publish: %v", err)
+	}
+
+	reader := kafka.NewReader(kafka.ReaderConfig{Brokers: strings.Split(brokers, ","), Topic: topic})
+	defer reader.Close()
+
+	msg, err := reader.ReadMessage(ctx)
+	if err != nil {
+		t.Fatalf("ReadMessage: %v", err)
+	}
+	if string(msg.Key) != "42" || string(msg.Value) != `{"n":1}` {
+		t.Errorf("message = %s: %s, want the published one", msg.Key, msg.Value)
+	}
+}
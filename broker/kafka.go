@@ -0,0 +1,47 @@
+package broker
+
+import (
+	"context"
+
+	"github.com/geekAshish/DriveDesk/models"
+	"github.com/segmentio/kafka-go"
+	"go.opentelemetry.io/otel"
+)
+
+// Kafka publishes every message to one topic, keyed by the id of the car or
+// engine. Messages with the same key go to the same partition, so each car's
+// and engine's events stay in order.
+type Kafka struct {
+	writer *kafka.Writer
+}
+
+// NewKafka writes to topic on the cluster of brokers, host:port addresses.
+// It connects on the first Publish.
+func NewKafka(brokers []string, topic string) *Kafka {
+	return &Kafka{writer: &kafka.Writer{
+		Addr:         kafka.TCP(brokers...),
+		Topic:        topic,
+		Balancer:     &kafka.Hash{},
+		RequiredAcks: kafka.RequireAll,
+	}}
+}
+
+// Publish waits for every in-sync replica to have the message.
+func (k *Kafka) Publish(ctx context.Context, message Message) error {
+	tracer := otel.Tracer("KafkaPublisher")
+	ctx, span := tracer.Start(ctx, "Publish-Kafka")
+	defer span.End()
+
+	return k.writer.WriteMessages(ctx, kafka.Message{
+		Key:   []byte(message.Key),
+		Value: message.Body,
+		Headers: []kafka.Header{
+			{Key: "content-type", Value: []byte(models.CloudEventContentType)},
+			{Key: "ce_id", Value: []byte(message.ID)},
+		},
+	})
+}
+
+func (k *Kafka) Close() error {
+	return k.writer.Close()
+}
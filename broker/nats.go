@@ -0,0 +1,60 @@
+package broker
+
+import (
+	"context"
+	"time"
+
+	"github.com/geekAshish/DriveDesk/models"
+	"github.com/nats-io/nats.go"
+	"go.opentelemetry.io/otel"
+)
+
+// flushTimeout bounds the wait for the server to confirm a message.
+const flushTimeout = 10 * time.Second
+
+// NATS publishes every message on the subject of its type under a prefix,
+// e.g. "drivedesk.car.created", so consumers can subscribe to
+// "drivedesk.car.>" for every car change.
+type NATS struct {
+	conn   *nats.Conn
+	prefix string
+}
+
+// NewNATS connects to the servers at url, a comma separated list, and keeps
+// reconnecting for as long as it is open.
+func NewNATS(url, prefix string) (*NATS, error) {
+	conn, err := nats.Connect(url, nats.Name("drivedesk"), nats.MaxReconnects(-1))
+	if err != nil {
+		return nil, err
+	}
+
+	return &NATS{conn: conn, prefix: prefix}, nil
+}
+
+// Publish waits for the server to have the message. Nats-Msg-Id lets a
+// JetStream stream on the subjects drop the repeats of a message.
+func (n *NATS) Publish(ctx context.Context, message Message) error {
+	tracer := otel.Tracer("NATSPublisher")
+	ctx, span := tracer.Start(ctx, "Publish-NATS")
+	defer span.End()
+
+	msg := nats.NewMsg(n.prefix + message.Type)
+	msg.Data = message.Body
+	msg.Header.Set("Content-Type", models.CloudEventContentType)
+	msg.Header.Set(nats.MsgIdHdr, message.ID)
+
+	if err := n.conn.PublishMsg(msg); err != nil {
+		return err
+	}
+
+	// a round trip to the server, so the message is not just buffered
+	ctx, cancel := context.WithTimeout(ctx, flushTimeout)
+	defer cancel()
+
+	return n.conn.FlushWithContext(ctx)
+}
+
+// Close sends the buffered messages and closes the connection.
+func (n *NATS) Close() error {
+	return n.conn.Drain()
+}
@@ -0,0 +1,134 @@
+package driver
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// ChangeChannel is the channel the car and engine triggers notify on.
+const ChangeChannel = "drivedesk_changes"
+
+const (
+	minReconnectInterval = time.Second
+	maxReconnectInterval = time.Minute
+
+	// pingInterval checks an idle connection, so a dead one is noticed and
+	// replaced rather than waited on forever.
+	pingInterval = 90 * time.Second
+)
+
+// Change is a committed change to a car or an engine, as sent by the
+// triggers. Table is car or engine, Op insert, update or delete, and Brand
+// the car's brand, empty for engines.
+//
+// A Change with Resync set carries no row: the listener lost its connection
+// and may have missed changes, so subscribers should drop or reload
+// whatever they derived from the database.
+type Change struct {
+	Table  string `json:"table"`
+	Op     string `json:"op"`
+	ID     string `json:"id"`
+	Brand  string `json:"brand"`
+	Resync bool   `json:"-"`
+}
+
+// Listener listens for the changes of every instance on ChangeChannel and
+// fans them out to in-process subscribers. It reconnects by itself when the
+// connection drops, and tells subscribers to resync once it is back.
+type Listener struct {
+	connStr string
+
+	mu          sync.RWMutex
+	subscribers map[int]func(Change)
+	nextID      int
+}
+
+// NewListener listens on the database of connStr once Run is called.
+func NewListener(connStr string) *Listener {
+	return &Listener{connStr: connStr, subscribers: map[int]func(Change){}}
+}
+
+// Subscribe calls fn with every change until unsubscribe is called. fn runs
+// on the listener's goroutine, so it must not block; slow work belongs on a
+// goroutine of its own.
+func (l *Listener) Subscribe(fn func(Change)) (unsubscribe func()) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	id := l.nextID
+	l.nextID++
+	l.subscribers[id] = fn
+
+	return func() {
+		l.mu.Lock()
+		defer l.mu.Unlock()
+
+		delete(l.subscribers, id)
+	}
+}
+
+// Run listens until ctx is done.
+func (l *Listener) Run(ctx context.Context) error {
+	listener := pq.NewListener(l.connStr, minReconnectInterval, maxReconnectInterval, func(event pq.ListenerEventType, err error) {
+		switch event {
+		case pq.ListenerEventDisconnected:
+			log.Printf("change listener disconnected : %v", err)
+		case pq.ListenerEventReconnected:
+			log.Println("change listener reconnected")
+		case pq.ListenerEventConnectionAttemptFailed:
+			log.Printf("change listener could not reconnect : %v", err)
+		}
+	})
+	defer listener.Close()
+
+	if err := listener.Listen(ChangeChannel); err != nil {
+		return err
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case notification := <-listener.NotificationChannel():
+			// pq sends nil after reconnecting, as notifications sent
+			// meanwhile are lost
+			if notification == nil {
+				l.publish(Change{Resync: true})
+				continue
+			}
+
+			l.dispatch(notification.Extra)
+		case <-time.After(pingInterval):
+			go func() {
+				if err := listener.Ping(); err != nil {
+					log.Printf("change listener ping failed : %v", err)
+				}
+			}()
+		}
+	}
+}
+
+// dispatch publishes the change in a trigger's payload.
+func (l *Listener) dispatch(payload string) {
+	var change Change
+	if err := json.Unmarshal([]byte(payload), &change); err != nil {
+		log.Printf("ignoring change notification %q : %v", payload, err)
+		return
+	}
+
+	l.publish(change)
+}
+
+func (l *Listener) publish(change Change) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	for _, fn := range l.subscribers {
+		fn(change)
+	}
+}
@@ -12,8 +12,10 @@ import (
 
 var db *sql.DB
 
-func InitDB() {
-	connStr := fmt.Sprintf(
+// ConnString is the Postgres connection string built from the DB_*
+// environment variables.
+func ConnString() string {
+	return fmt.Sprintf(
 		"host=%s port=%s user=%s password=%s dbname=%s sslmode=disable",
 		os.Getenv("DB_HOST"),
 		os.Getenv("DB_PORT"),
@@ -21,6 +23,10 @@ func InitDB() {
 		os.Getenv("DB_PASSWORD"),
 		os.Getenv("DB_NAME"),
 	)
+}
+
+func InitDB() {
+	connStr := ConnString()
 
 	fmt.Println("waiting for database connection")
 	time.Sleep(5 * time.Second)
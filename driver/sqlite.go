@@ -0,0 +1,55 @@
+package driver
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"net/url"
+	"os"
+
+	_ "modernc.org/sqlite"
+)
+
+// InitSQLite opens the SQLite database file named by DB_PATH (drivedesk.db by
+// default). It shares GetDB and CloseDB with the Postgres connection.
+func InitSQLite() {
+	path := os.Getenv("DB_PATH")
+	if path == "" {
+		path = "drivedesk.db"
+	}
+
+	var err error
+	db, err = OpenSQLite(path)
+	if err != nil {
+		log.Fatalf("ERROR OPENING DATABASE : %v", err)
+	}
+
+	err = db.Ping()
+	if err != nil {
+		log.Fatalf("ERROR CONNECTING TO THE DATABASE : %v", err)
+	}
+
+	fmt.Println("Successfully opened sqlite database", path)
+}
+
+// OpenSQLite opens the database file at path with the settings the stores
+// rely on.
+func OpenSQLite(path string) (*sql.DB, error) {
+	// foreign keys are off by default in SQLite, and the busy timeout stops
+	// concurrent writers from failing straight away with SQLITE_BUSY
+	params := url.Values{}
+	params.Add("_pragma", "foreign_keys(1)")
+	params.Add("_pragma", "busy_timeout(5000)")
+	params.Add("_pragma", "journal_mode(WAL)")
+
+	sqliteDB, err := sql.Open("sqlite", fmt.Sprintf("file:%s?%s", path, params.Encode()))
+	if err != nil {
+		return nil, err
+	}
+
+	// SQLite allows a single writer, so one connection avoids lock contention
+	// between our own goroutines
+	sqliteDB.SetMaxOpenConns(1)
+
+	return sqliteDB, nil
+}
@@ -0,0 +1,75 @@
+package graphqlserver
+
+import (
+	"log"
+	"strings"
+
+	"github.com/geekAshish/DriveDesk/apperrors"
+	"github.com/geekAshish/DriveDesk/handler/response"
+
+	handlerV2 "github.com/geekAshish/DriveDesk/handler/v2"
+)
+
+// resolverError is an error of a resolver as GraphQL clients see it. Its
+// extensions carry the HTTP status the REST API would answer with and the
+// invalid fields, named like the GraphQL input fields.
+type resolverError struct {
+	err     error
+	message string
+	status  int
+}
+
+func newError(err error) error {
+	if resolved, ok := err.(*resolverError); ok {
+		return resolved
+	}
+
+	status := response.StatusCode(err)
+	message := apperrors.Message(err)
+
+	if status >= 500 {
+		log.Println("ERROR: ", err)
+		message = "internal error"
+	}
+
+	return &resolverError{err: err, message: message, status: status}
+}
+
+func (e *resolverError) Error() string {
+	return e.message
+}
+
+func (e *resolverError) Unwrap() error {
+	return e.err
+}
+
+func (e *resolverError) Extensions() map[string]any {
+	extensions := map[string]any{"status": e.status}
+
+	if fields := apperrors.Fields(e.err); len(fields) > 0 {
+		renamed := make([]apperrors.FieldError, len(fields))
+		for i, field := range fields {
+			field.Field = fieldName(field.Field)
+			renamed[i] = field
+		}
+		extensions["fields"] = renamed
+	}
+
+	return extensions
+}
+
+// fieldName turns the JSON path of a request field into the name of the
+// GraphQL input field, e.g. "fuel_type" into "fuelType".
+func fieldName(path string) string {
+	segments := strings.Split(handlerV2.FieldName(path), ".")
+	for i, segment := range segments {
+		words := strings.Split(segment, "_")
+		for j := 1; j < len(words); j++ {
+			if words[j] != "" {
+				words[j] = strings.ToUpper(words[j][:1]) + words[j][1:]
+			}
+		}
+		segments[i] = strings.Join(words, "")
+	}
+	return strings.Join(segments, ".")
+}
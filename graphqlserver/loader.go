@@ -0,0 +1,71 @@
+package graphqlserver
+
+import (
+	"context"
+	"time"
+
+	"github.com/geekAshish/DriveDesk/apperrors"
+	"github.com/geekAshish/DriveDesk/models"
+	"github.com/geekAshish/DriveDesk/service"
+	"github.com/graph-gophers/dataloader/v7"
+)
+
+// engineBatchWait is how long the engine loader collects ids before it looks
+// them up. The executor resolves the cars of a list concurrently, so their
+// engine ids arrive well within it.
+const engineBatchWait = 2 * time.Millisecond
+
+type loadersKey struct{}
+
+// loaders are the dataloaders of one request. They cache what they load, so
+// they must not outlive it.
+type loaders struct {
+	engine *dataloader.Loader[string, models.Engine]
+}
+
+func newLoaders(engines service.EngineServiceInterface) *loaders {
+	return &loaders{
+		engine: dataloader.NewBatchedLoader(engineBatch(engines), dataloader.WithWait[string, models.Engine](engineBatchWait)),
+	}
+}
+
+func withLoaders(ctx context.Context, l *loaders) context.Context {
+	return context.WithValue(ctx, loadersKey{}, l)
+}
+
+func loadersFrom(ctx context.Context) *loaders {
+	l, _ := ctx.Value(loadersKey{}).(*loaders)
+	return l
+}
+
+// engineBatch looks up every engine id requested during one wait with a
+// single GetEnginesByIds call.
+func engineBatch(engines service.EngineServiceInterface) dataloader.BatchFunc[string, models.Engine] {
+	return func(ctx context.Context, ids []string) []*dataloader.Result[models.Engine] {
+		results := make([]*dataloader.Result[models.Engine], len(ids))
+
+		found, err := engines.GetEnginesByIds(ctx, ids)
+		if err != nil {
+			for i := range results {
+				results[i] = &dataloader.Result[models.Engine]{Error: err}
+			}
+			return results
+		}
+
+		byID := make(map[string]models.Engine, len(found))
+		for _, engine := range found {
+			byID[engine.EngineID.String()] = engine
+		}
+
+		for i, id := range ids {
+			engine, ok := byID[id]
+			if !ok {
+				results[i] = &dataloader.Result[models.Engine]{Error: apperrors.NotFound("engine not found")}
+				continue
+			}
+			results[i] = &dataloader.Result[models.Engine]{Data: engine}
+		}
+
+		return results
+	}
+}
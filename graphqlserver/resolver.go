@@ -0,0 +1,275 @@
+package graphqlserver
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	"github.com/geekAshish/DriveDesk/apperrors"
+	"github.com/geekAshish/DriveDesk/models"
+	"github.com/geekAshish/DriveDesk/service"
+	"github.com/google/uuid"
+	"github.com/graph-gophers/graphql-go"
+	"go.opentelemetry.io/otel"
+)
+
+// Resolver resolves the Query and Mutation root types.
+type Resolver struct {
+	cars    service.CarServiceInterface
+	engines service.EngineServiceInterface
+	enums   service.EnumProvider
+}
+
+type carFilter struct {
+	Brand    string
+	FuelType *string
+	YearFrom *int32
+	YearTo   *int32
+	Name     *string
+}
+
+type carInput struct {
+	Name     string
+	Year     int32
+	Brand    string
+	FuelType string
+	Price    float64
+	Vin      *string
+	EngineId graphql.ID
+}
+
+type engineInput struct {
+	Displacement  float64
+	NoOfCylinders int32
+	CarRange      float64
+}
+
+func (r *Resolver) Car(ctx context.Context, args struct{ ID graphql.ID }) (*carResolver, error) {
+	tracer := otel.Tracer("GraphQLResolver")
+	ctx, span := tracer.Start(ctx, "Car-Resolver")
+	defer span.End()
+
+	car, err := r.cars.GetCarById(ctx, string(args.ID))
+	if err != nil {
+		return nil, newError(err)
+	}
+
+	return &carResolver{car: *car}, nil
+}
+
+// Cars lists the cars of the filter's brand and narrows them down in
+// memory; the engines are left to the engine loader.
+func (r *Resolver) Cars(ctx context.Context, args struct{ Filter carFilter }) ([]*carResolver, error) {
+	tracer := otel.Tracer("GraphQLResolver")
+	ctx, span := tracer.Start(ctx, "Cars-Resolver")
+	defer span.End()
+
+	cars, err := r.cars.GetCarByBrand(ctx, args.Filter.Brand, false)
+	if err != nil {
+		return nil, newError(err)
+	}
+
+	fuelType := ""
+	if args.Filter.FuelType != nil {
+		enums, err := r.enums.Enums(ctx)
+		if err != nil {
+			return nil, newError(err)
+		}
+		fuelType, _ = enums.Canonical(models.ReferenceKindFuelType, *args.Filter.FuelType)
+		if fuelType == "" {
+			fuelType = *args.Filter.FuelType
+		}
+	}
+
+	resolvers := []*carResolver{}
+	for _, car := range cars {
+		if matches(car, args.Filter, fuelType) {
+			resolvers = append(resolvers, &carResolver{car: car})
+		}
+	}
+
+	return resolvers, nil
+}
+
+func matches(car models.Car, filter carFilter, fuelType string) bool {
+	year, _ := strconv.Atoi(car.Year)
+
+	switch {
+	case fuelType != "" && car.FuelType != fuelType:
+		return false
+	case filter.YearFrom != nil && year < int(*filter.YearFrom):
+		return false
+	case filter.YearTo != nil && year > int(*filter.YearTo):
+		return false
+	case filter.Name != nil && !strings.Contains(strings.ToLower(car.Name), strings.ToLower(*filter.Name)):
+		return false
+	default:
+		return true
+	}
+}
+
+func (r *Resolver) Engine(ctx context.Context, args struct{ ID graphql.ID }) (*engineResolver, error) {
+	tracer := otel.Tracer("GraphQLResolver")
+	ctx, span := tracer.Start(ctx, "Engine-Resolver")
+	defer span.End()
+
+	engineID, err := uuid.Parse(string(args.ID))
+	if err != nil {
+		return nil, newError(apperrors.NotFound("engine not found"))
+	}
+
+	engine, err := loadersFrom(ctx).engine.Load(ctx, engineID.String())()
+	if err != nil {
+		return nil, newError(err)
+	}
+
+	return &engineResolver{id: engine.EngineID, engine: &engine}, nil
+}
+
+func (r *Resolver) CreateCar(ctx context.Context, args struct{ Input carInput }) (*carResolver, error) {
+	tracer := otel.Tracer("GraphQLResolver")
+	ctx, span := tracer.Start(ctx, "CreateCar-Resolver")
+	defer span.End()
+
+	carReq, err := r.carRequest(ctx, args.Input)
+	if err != nil {
+		return nil, newError(err)
+	}
+
+	car, err := r.cars.CreateCar(ctx, carReq)
+	if err != nil {
+		return nil, newError(err)
+	}
+
+	return &carResolver{car: *car}, nil
+}
+
+func (r *Resolver) UpdateCar(ctx context.Context, args struct {
+	ID    graphql.ID
+	Input carInput
+}) (*carResolver, error) {
+	tracer := otel.Tracer("GraphQLResolver")
+	ctx, span := tracer.Start(ctx, "UpdateCar-Resolver")
+	defer span.End()
+
+	carReq, err := r.carRequest(ctx, args.Input)
+	if err != nil {
+		return nil, newError(err)
+	}
+
+	car, err := r.cars.UpdateCar(ctx, string(args.ID), carReq)
+	if err != nil {
+		return nil, newError(err)
+	}
+
+	return &carResolver{car: *car}, nil
+}
+
+func (r *Resolver) DeleteCar(ctx context.Context, args struct{ ID graphql.ID }) (*carResolver, error) {
+	tracer := otel.Tracer("GraphQLResolver")
+	ctx, span := tracer.Start(ctx, "DeleteCar-Resolver")
+	defer span.End()
+
+	car, err := r.cars.DeleteCar(ctx, string(args.ID))
+	if err != nil {
+		return nil, newError(err)
+	}
+
+	return &carResolver{car: *car}, nil
+}
+
+func (r *Resolver) CreateEngine(ctx context.Context, args struct{ Input engineInput }) (*engineResolver, error) {
+	tracer := otel.Tracer("GraphQLResolver")
+	ctx, span := tracer.Start(ctx, "CreateEngine-Resolver")
+	defer span.End()
+
+	engine, err := r.engines.CreateEngine(ctx, engineRequest(args.Input))
+	if err != nil {
+		return nil, newError(err)
+	}
+
+	return r.primed(ctx, *engine), nil
+}
+
+func (r *Resolver) UpdateEngine(ctx context.Context, args struct {
+	ID    graphql.ID
+	Input engineInput
+}) (*engineResolver, error) {
+	tracer := otel.Tracer("GraphQLResolver")
+	ctx, span := tracer.Start(ctx, "UpdateEngine-Resolver")
+	defer span.End()
+
+	engine, err := r.engines.UpdateEngine(ctx, string(args.ID), engineRequest(args.Input))
+	if err != nil {
+		return nil, newError(err)
+	}
+
+	return r.primed(ctx, *engine), nil
+}
+
+func (r *Resolver) DeleteEngine(ctx context.Context, args struct{ ID graphql.ID }) (*engineResolver, error) {
+	tracer := otel.Tracer("GraphQLResolver")
+	ctx, span := tracer.Start(ctx, "DeleteEngine-Resolver")
+	defer span.End()
+
+	engine, err := r.engines.DeleteEngine(ctx, string(args.ID))
+	if err != nil {
+		return nil, newError(err)
+	}
+
+	loadersFrom(ctx).engine.Clear(ctx, engine.EngineID.String())
+
+	return &engineResolver{id: engine.EngineID, engine: engine}, nil
+}
+
+// primed resolves a stored engine and replaces whatever the loader cached
+// for it earlier in the request.
+func (r *Resolver) primed(ctx context.Context, engine models.Engine) *engineResolver {
+	id := engine.EngineID.String()
+	loadersFrom(ctx).engine.Clear(ctx, id).Prime(ctx, id, engine)
+
+	return &engineResolver{id: engine.EngineID, engine: &engine}
+}
+
+// carRequest builds the request the car service expects. It carries the
+// engine's details, which GraphQL clients do not send, so they are loaded
+// from the stored engine.
+func (r *Resolver) carRequest(ctx context.Context, input carInput) (*models.CarRequest, error) {
+	engineID, err := uuid.Parse(string(input.EngineId))
+	if err != nil {
+		return nil, apperrors.InvalidField("engine_id", "invalid_uuid", "engine ID is not a UUID")
+	}
+
+	engine, err := loadersFrom(ctx).engine.Load(ctx, engineID.String())()
+	if err != nil {
+		return nil, apperrors.InvalidField("engine_id", "not_found", "engine "+engineID.String()+" does not exist")
+	}
+
+	year := ""
+	if input.Year != 0 {
+		year = strconv.Itoa(int(input.Year))
+	}
+
+	vin := ""
+	if input.Vin != nil {
+		vin = *input.Vin
+	}
+
+	return &models.CarRequest{
+		Name:     input.Name,
+		Year:     year,
+		Brand:    input.Brand,
+		FuelType: input.FuelType,
+		Price:    input.Price,
+		VIN:      vin,
+		Engine:   engine,
+	}, nil
+}
+
+func engineRequest(input engineInput) *models.EngineRequest {
+	return &models.EngineRequest{
+		Dispacement:   input.Displacement,
+		NoOfCylinders: float64(input.NoOfCylinders),
+		CarRange:      input.CarRange,
+	}
+}
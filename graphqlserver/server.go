@@ -0,0 +1,62 @@
+// Package graphqlserver serves a GraphQL view of the car inventory, resolved
+// through the same services as the REST handlers. The engines of the cars
+// in a response are fetched in batches, so listing n cars with their engine
+// details costs two lookups instead of n+1.
+package graphqlserver
+
+import (
+	_ "embed"
+	"encoding/json"
+	"net/http"
+
+	"github.com/geekAshish/DriveDesk/apperrors"
+	"github.com/geekAshish/DriveDesk/handler/response"
+	"github.com/geekAshish/DriveDesk/service"
+	"github.com/graph-gophers/graphql-go"
+	"github.com/graph-gophers/graphql-go/trace/otel"
+)
+
+//go:embed schema.graphql
+var schema string
+
+// maxDepth stops clients from sending arbitrarily nested queries.
+const maxDepth = 10
+
+type Handler struct {
+	schema  *graphql.Schema
+	engines service.EngineServiceInterface
+}
+
+// request is a GraphQL request sent as JSON in a POST body.
+type request struct {
+	Query         string         `json:"query"`
+	OperationName string         `json:"operationName"`
+	Variables     map[string]any `json:"variables"`
+}
+
+func NewHandler(cars service.CarServiceInterface, engines service.EngineServiceInterface, enums service.EnumProvider) *Handler {
+	resolver := &Resolver{cars: cars, engines: engines, enums: enums}
+
+	return &Handler{
+		schema: graphql.MustParseSchema(schema, resolver,
+			graphql.UseStringDescriptions(),
+			graphql.MaxDepth(maxDepth),
+			graphql.Tracer(otel.DefaultTracer()),
+		),
+		engines: engines,
+	}
+}
+
+// ServeHTTP executes a query. Like any GraphQL server it answers 200 with
+// the errors in the body once the request could be parsed.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var req request
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, r, apperrors.Wrap(apperrors.ErrBadRequest, err, "request body is not a valid GraphQL request: %v", err))
+		return
+	}
+
+	ctx := withLoaders(r.Context(), newLoaders(h.engines))
+
+	response.JSON(w, http.StatusOK, h.schema.Exec(ctx, req.Query, req.OperationName, req.Variables))
+}
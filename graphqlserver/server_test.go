@@ -0,0 +1,197 @@
+package graphqlserver
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/geekAshish/DriveDesk/models"
+	"github.com/geekAshish/DriveDesk/service"
+	"github.com/geekAshish/DriveDesk/store/memory"
+
+	carService "github.com/geekAshish/DriveDesk/service/car"
+	engineService "github.com/geekAshish/DriveDesk/service/engine"
+	referenceService "github.com/geekAshish/DriveDesk/service/reference"
+	ruleService "github.com/geekAshish/DriveDesk/service/rule"
+)
+
+// countingEngines counts the engine lookups that reach the service.
+type countingEngines struct {
+	service.EngineServiceInterface
+	single  atomic.Int32
+	batches atomic.Int32
+}
+
+func (c *countingEngines) GetEngineById(ctx context.Context, id string) (*models.Engine, error) {
+	c.single.Add(1)
+	return c.EngineServiceInterface.GetEngineById(ctx, id)
+}
+
+func (c *countingEngines) GetEnginesByIds(ctx context.Context, ids []string) ([]models.Engine, error) {
+	c.batches.Add(1)
+	return c.EngineServiceInterface.GetEnginesByIds(ctx, ids)
+}
+
+type fixture struct {
+	handler *Handler
+	cars    service.CarServiceInterface
+	engines *countingEngines
+}
+
+func newFixture(t *testing.T) *fixture {
+	t.Helper()
+
+	db := memory.NewDB()
+	tx := memory.NewTxManager(db)
+	rules := ruleService.NewValidationRuleService(memory.NewValidationRuleStore(db), time.Minute)
+	references := referenceService.NewReferenceService(memory.NewReferenceStore(db), tx, time.Minute)
+
+	engines := &countingEngines{EngineServiceInterface: engineService.NewEngineService(memory.NewEngineStore(db), memory.NewCarStore(db), tx, rules, service.NoEvents{})}
+	cars := carService.NewCarService(memory.NewCarStore(db), tx, references, rules, service.NoEvents{})
+
+	return &fixture{handler: NewHandler(cars, engines, references), cars: cars, engines: engines}
+}
+
+type result struct {
+	Data   json.RawMessage `json:"data"`
+	Errors []struct {
+		Message    string         `json:"message"`
+		Extensions map[string]any `json:"extensions"`
+	} `json:"errors"`
+}
+
+func (f *fixture) exec(t *testing.T, query string, variables map[string]any) result {
+	t.Helper()
+
+	body, _ := json.Marshal(map[string]any{"query": query, "variables": variables})
+	recorder := httptest.NewRecorder()
+	f.handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/graphql", strings.NewReader(string(body))))
+
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("status = %d, body %s", recorder.Code, recorder.Body)
+	}
+
+	var res result
+	if err := json.Unmarshal(recorder.Body.Bytes(), &res); err != nil {
+		t.Fatalf("decoding response: %v", err)
+	}
+	return res
+}
+
+func (f *fixture) createCar(t *testing.T, name string, year string, engine models.Engine) {
+	t.Helper()
+
+	_, err := f.cars.CreateCar(context.Background(), &models.CarRequest{
+		Name: name, Year: year, Brand: "Honda", FuelType: "petrol", Price: 10000, Engine: engine,
+	})
+	if err != nil {
+		t.Fatalf("CreateCar: %v", err)
+	}
+}
+
+func (f *fixture) createEngine(t *testing.T, displacement float64) models.Engine {
+	t.Helper()
+
+	engine, err := f.engines.CreateEngine(context.Background(), &models.EngineRequest{Dispacement: displacement, NoOfCylinders: 4, CarRange: 500})
+	if err != nil {
+		t.Fatalf("CreateEngine: %v", err)
+	}
+	return *engine
+}
+
+func TestCarsBatchEngineLookups(t *testing.T) {
+	f := newFixture(t)
+
+	small, large := f.createEngine(t, 1200), f.createEngine(t, 2000)
+	f.createCar(t, "Jazz", "2018", small)
+	f.createCar(t, "City", "2020", small)
+	f.createCar(t, "Accord", "2022", large)
+	f.createCar(t, "Civic", "2023", large)
+
+	res := f.exec(t, `query($filter: CarFilter!) { cars(filter: $filter) { name year engine { displacement } } }`,
+		map[string]any{"filter": map[string]any{"brand": "Honda", "yearFrom": 2019}})
+	if len(res.Errors) > 0 {
+		t.Fatalf("errors: %+v", res.Errors)
+	}
+
+	var data struct {
+		Cars []struct {
+			Name   string
+			Year   int
+			Engine struct{ Displacement float64 }
+		}
+	}
+	if err := json.Unmarshal(res.Data, &data); err != nil {
+		t.Fatalf("decoding data: %v", err)
+	}
+
+	if len(data.Cars) != 3 {
+		t.Fatalf("got %d cars from 2019 on, want 3: %+v", len(data.Cars), data.Cars)
+	}
+	for _, car := range data.Cars {
+		if car.Engine.Displacement == 0 {
+			t.Errorf("%s has no engine details", car.Name)
+		}
+	}
+
+	if single, batches := f.engines.single.Load(), f.engines.batches.Load(); single != 0 || batches != 1 {
+		t.Errorf("engine lookups: %d single and %d batches, want 0 and 1", single, batches)
+	}
+}
+
+func TestEngineIdNeedsNoLookup(t *testing.T) {
+	f := newFixture(t)
+	f.createCar(t, "City", "2020", f.createEngine(t, 1500))
+
+	res := f.exec(t, `{ cars(filter: {brand: "Honda"}) { engine { id } } }`, nil)
+	if len(res.Errors) > 0 {
+		t.Fatalf("errors: %+v", res.Errors)
+	}
+
+	if batches := f.engines.batches.Load(); batches != 0 {
+		t.Errorf("querying only engine ids made %d engine lookups, want none", batches)
+	}
+}
+
+func TestCreateCarReportsFields(t *testing.T) {
+	f := newFixture(t)
+	engine := f.createEngine(t, 1500)
+
+	res := f.exec(t, `mutation($input: CarInput!) { createCar(input: $input) { id fuelType engine { displacement } } }`,
+		map[string]any{"input": map[string]any{
+			"name": "City", "year": 2020, "brand": "Honda", "fuelType": "steam", "price": 0, "engineId": engine.EngineID.String(),
+		}})
+
+	if len(res.Errors) != 1 {
+		t.Fatalf("got errors %+v, want one validation error", res.Errors)
+	}
+
+	extensions := res.Errors[0].Extensions
+	if extensions["status"] != float64(http.StatusUnprocessableEntity) {
+		t.Errorf("status extension = %v, want 422", extensions["status"])
+	}
+
+	fields := map[string]bool{}
+	for _, field := range extensions["fields"].([]any) {
+		fields[field.(map[string]any)["field"].(string)] = true
+	}
+	if !fields["fuelType"] || !fields["price"] || len(fields) != 2 {
+		t.Errorf("invalid fields = %v, want fuelType and price", fields)
+	}
+
+	res = f.exec(t, `mutation($input: CarInput!) { createCar(input: $input) { fuelType engine { displacement } } }`,
+		map[string]any{"input": map[string]any{
+			"name": "City", "year": 2020, "brand": "Honda", "fuelType": "gasoline", "price": 9000, "engineId": engine.EngineID.String(),
+		}})
+	if len(res.Errors) > 0 {
+		t.Fatalf("errors: %+v", res.Errors)
+	}
+	if want := `{"createCar":{"fuelType":"petrol","engine":{"displacement":1500}}}`; string(res.Data) != want {
+		t.Errorf("data = %s, want %s", res.Data, want)
+	}
+}
@@ -0,0 +1,112 @@
+package graphqlserver
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/geekAshish/DriveDesk/models"
+	"github.com/google/uuid"
+	"github.com/graph-gophers/graphql-go"
+)
+
+type carResolver struct {
+	car models.Car
+}
+
+func (r *carResolver) ID() graphql.ID {
+	return graphql.ID(r.car.ID.String())
+}
+
+func (r *carResolver) Name() string {
+	return r.car.Name
+}
+
+// Year is a number like in REST v2; stored years that are not numbers
+// predate validation and read as 0.
+func (r *carResolver) Year() int32 {
+	year, _ := strconv.Atoi(r.car.Year)
+	return int32(year)
+}
+
+func (r *carResolver) Brand() string {
+	return r.car.Brand
+}
+
+func (r *carResolver) FuelType() string {
+	return r.car.FuelType
+}
+
+func (r *carResolver) Price() float64 {
+	return r.car.Price
+}
+
+func (r *carResolver) Vin() *string {
+	if r.car.VIN == "" {
+		return nil
+	}
+	return &r.car.VIN
+}
+
+// Engine resolves lazily: the details are only loaded, in a batch with the
+// engines of the other cars, when the query asks for more than the id.
+func (r *carResolver) Engine() *engineResolver {
+	engine := &engineResolver{id: r.car.Engine.EngineID}
+	if !r.car.Engine.CreateAt.IsZero() {
+		engine.engine = &r.car.Engine
+	}
+	return engine
+}
+
+func (r *carResolver) CreatedAt() graphql.Time {
+	return graphql.Time{Time: r.car.CreateAt}
+}
+
+func (r *carResolver) UpdatedAt() graphql.Time {
+	return graphql.Time{Time: r.car.UpdateAt}
+}
+
+type engineResolver struct {
+	id     uuid.UUID
+	engine *models.Engine
+}
+
+func (r *engineResolver) load(ctx context.Context) (models.Engine, error) {
+	if r.engine != nil {
+		return *r.engine, nil
+	}
+
+	engine, err := loadersFrom(ctx).engine.Load(ctx, r.id.String())()
+	if err != nil {
+		return models.Engine{}, newError(err)
+	}
+	return engine, nil
+}
+
+func (r *engineResolver) ID() graphql.ID {
+	return graphql.ID(r.id.String())
+}
+
+func (r *engineResolver) Displacement(ctx context.Context) (float64, error) {
+	engine, err := r.load(ctx)
+	return engine.Dispacement, err
+}
+
+func (r *engineResolver) NoOfCylinders(ctx context.Context) (int32, error) {
+	engine, err := r.load(ctx)
+	return int32(engine.NoOfCylinders), err
+}
+
+func (r *engineResolver) CarRange(ctx context.Context) (float64, error) {
+	engine, err := r.load(ctx)
+	return engine.CarRange, err
+}
+
+func (r *engineResolver) CreatedAt(ctx context.Context) (graphql.Time, error) {
+	engine, err := r.load(ctx)
+	return graphql.Time{Time: engine.CreateAt}, err
+}
+
+func (r *engineResolver) UpdatedAt(ctx context.Context) (graphql.Time, error) {
+	engine, err := r.load(ctx)
+	return graphql.Time{Time: engine.UpdateAt}, err
+}
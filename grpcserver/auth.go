@@ -0,0 +1,100 @@
+package grpcserver
+
+import (
+	"context"
+	"strings"
+
+	"github.com/geekAshish/DriveDesk/middleware"
+	"github.com/geekAshish/DriveDesk/models"
+	"go.opentelemetry.io/otel"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+
+	loginHandler "github.com/geekAshish/DriveDesk/handler/login"
+	pb "github.com/geekAshish/DriveDesk/proto/drivedesk/v1"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	reflectionpb "google.golang.org/grpc/reflection/grpc_reflection_v1"
+	reflectionv1alphapb "google.golang.org/grpc/reflection/grpc_reflection_v1alpha"
+)
+
+// publicServices can be called without a token, like POST /login and the
+// infrastructure routes of the REST API.
+var publicServices = map[string]bool{
+	pb.AuthService_ServiceDesc.ServiceName:                       true,
+	healthpb.Health_ServiceDesc.ServiceName:                      true,
+	reflectionpb.ServerReflection_ServiceDesc.ServiceName:        true,
+	reflectionv1alphapb.ServerReflection_ServiceDesc.ServiceName: true,
+}
+
+type AuthServer struct {
+	pb.UnimplementedAuthServiceServer
+}
+
+func NewAuthServer() *AuthServer {
+	return &AuthServer{}
+}
+
+func (s *AuthServer) Login(ctx context.Context, req *pb.LoginRequest) (*pb.LoginResponse, error) {
+	tracer := otel.Tracer("AuthServer")
+	_, span := tracer.Start(ctx, "Login-GRPC")
+	defer span.End()
+
+	token, err := loginHandler.Login(models.Credentials{UserName: req.GetUserName(), Password: req.GetPassword()})
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.LoginResponse{Token: token}, nil
+}
+
+// authenticate checks the token in the "authorization" metadata of calls to
+// protected services, the same way AuthMiddleware checks the header.
+func authenticate(ctx context.Context, fullMethod string) (context.Context, error) {
+	if publicServices[serviceName(fullMethod)] {
+		return ctx, nil
+	}
+
+	authHeader := ""
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get("authorization"); len(values) > 0 {
+			authHeader = values[0]
+		}
+	}
+
+	return middleware.Authenticate(ctx, authHeader)
+}
+
+func authUnaryInterceptor(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	ctx, err := authenticate(ctx, info.FullMethod)
+	if err != nil {
+		return nil, err
+	}
+
+	return handler(ctx, req)
+}
+
+func authStreamInterceptor(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	ctx, err := authenticate(ss.Context(), info.FullMethod)
+	if err != nil {
+		return err
+	}
+
+	return handler(srv, &contextStream{ServerStream: ss, ctx: ctx})
+}
+
+// contextStream is a server stream with a replaced context.
+type contextStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *contextStream) Context() context.Context {
+	return s.ctx
+}
+
+// serviceName returns the service part of a full method name such as
+// "/drivedesk.v1.CarService/GetCar".
+func serviceName(fullMethod string) string {
+	service, _, _ := strings.Cut(strings.TrimPrefix(fullMethod, "/"), "/")
+	return service
+}
@@ -0,0 +1,101 @@
+package grpcserver
+
+import (
+	"context"
+
+	"github.com/geekAshish/DriveDesk/service"
+	"go.opentelemetry.io/otel"
+
+	pb "github.com/geekAshish/DriveDesk/proto/drivedesk/v1"
+)
+
+type CarServer struct {
+	pb.UnimplementedCarServiceServer
+	service service.CarServiceInterface
+}
+
+func NewCarServer(service service.CarServiceInterface) *CarServer {
+	return &CarServer{
+		service: service,
+	}
+}
+
+func (s *CarServer) GetCar(ctx context.Context, req *pb.GetCarRequest) (*pb.Car, error) {
+	tracer := otel.Tracer("CarServer")
+	ctx, span := tracer.Start(ctx, "GetCar-GRPC")
+	defer span.End()
+
+	car, err := s.service.GetCarById(ctx, req.GetId())
+	if err != nil {
+		return nil, err
+	}
+
+	return toCar(car), nil
+}
+
+func (s *CarServer) ListCars(ctx context.Context, req *pb.ListCarsRequest) (*pb.ListCarsResponse, error) {
+	tracer := otel.Tracer("CarServer")
+	ctx, span := tracer.Start(ctx, "ListCars-GRPC")
+	defer span.End()
+
+	cars, err := s.service.GetCarByBrand(ctx, req.GetBrand(), req.GetIncludeEngine())
+	if err != nil {
+		return nil, err
+	}
+
+	res := &pb.ListCarsResponse{Cars: make([]*pb.Car, 0, len(cars))}
+	for i := range cars {
+		res.Cars = append(res.Cars, toCar(&cars[i]))
+	}
+
+	return res, nil
+}
+
+func (s *CarServer) CreateCar(ctx context.Context, req *pb.CreateCarRequest) (*pb.Car, error) {
+	tracer := otel.Tracer("CarServer")
+	ctx, span := tracer.Start(ctx, "CreateCar-GRPC")
+	defer span.End()
+
+	carReq, err := carRequest(req.GetCar())
+	if err != nil {
+		return nil, err
+	}
+
+	car, err := s.service.CreateCar(ctx, carReq)
+	if err != nil {
+		return nil, err
+	}
+
+	return toCar(car), nil
+}
+
+func (s *CarServer) UpdateCar(ctx context.Context, req *pb.UpdateCarRequest) (*pb.Car, error) {
+	tracer := otel.Tracer("CarServer")
+	ctx, span := tracer.Start(ctx, "UpdateCar-GRPC")
+	defer span.End()
+
+	carReq, err := carRequest(req.GetCar())
+	if err != nil {
+		return nil, err
+	}
+
+	car, err := s.service.UpdateCar(ctx, req.GetId(), carReq)
+	if err != nil {
+		return nil, err
+	}
+
+	return toCar(car), nil
+}
+
+func (s *CarServer) DeleteCar(ctx context.Context, req *pb.DeleteCarRequest) (*pb.Car, error) {
+	tracer := otel.Tracer("CarServer")
+	ctx, span := tracer.Start(ctx, "DeleteCar-GRPC")
+	defer span.End()
+
+	car, err := s.service.DeleteCar(ctx, req.GetId())
+	if err != nil {
+		return nil, err
+	}
+
+	return toCar(car), nil
+}
@@ -0,0 +1,88 @@
+package grpcserver
+
+import (
+	"strconv"
+
+	"github.com/geekAshish/DriveDesk/apperrors"
+	"github.com/geekAshish/DriveDesk/models"
+	"github.com/google/uuid"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	pb "github.com/geekAshish/DriveDesk/proto/drivedesk/v1"
+)
+
+// The messages follow REST v2: numeric years, integer cylinders and the
+// corrected displacement spelling.
+
+func toCar(car *models.Car) *pb.Car {
+	// years are stored as text; ones that are not numbers predate validation and read as 0
+	year, _ := strconv.Atoi(car.Year)
+
+	return &pb.Car{
+		Id:        car.ID.String(),
+		Name:      car.Name,
+		Year:      int32(year),
+		Brand:     car.Brand,
+		FuelType:  car.FuelType,
+		Price:     car.Price,
+		Vin:       car.VIN,
+		Engine:    toEngine(&car.Engine),
+		CreatedAt: timestamppb.New(car.CreateAt),
+		UpdatedAt: timestamppb.New(car.UpdateAt),
+	}
+}
+
+func toEngine(engine *models.Engine) *pb.Engine {
+	converted := &pb.Engine{
+		EngineId:      engine.EngineID.String(),
+		Displacement:  engine.Dispacement,
+		NoOfCylinders: int32(engine.NoOfCylinders),
+		CarRange:      engine.CarRange,
+	}
+
+	if !engine.CreateAt.IsZero() {
+		converted.CreatedAt = timestamppb.New(engine.CreateAt)
+		converted.UpdatedAt = timestamppb.New(engine.UpdateAt)
+	}
+
+	return converted
+}
+
+func carRequest(input *pb.CarInput) (*models.CarRequest, error) {
+	year := ""
+	if input.GetYear() != 0 {
+		year = strconv.Itoa(int(input.GetYear()))
+	}
+
+	// an empty id is left as the zero UUID for the required check to report
+	var engineID uuid.UUID
+	if id := input.GetEngine().GetEngineId(); id != "" {
+		var err error
+		if engineID, err = uuid.Parse(id); err != nil {
+			return nil, apperrors.InvalidField("engine.engine_id", "invalid_uuid", "engine ID is not a UUID")
+		}
+	}
+
+	return &models.CarRequest{
+		Name:     input.GetName(),
+		Year:     year,
+		Brand:    input.GetBrand(),
+		FuelType: input.GetFuelType(),
+		Price:    input.GetPrice(),
+		VIN:      input.GetVin(),
+		Engine: models.Engine{
+			EngineID:      engineID,
+			Dispacement:   input.GetEngine().GetDisplacement(),
+			NoOfCylinders: float64(input.GetEngine().GetNoOfCylinders()),
+			CarRange:      input.GetEngine().GetCarRange(),
+		},
+	}, nil
+}
+
+func engineRequest(input *pb.EngineInput) *models.EngineRequest {
+	return &models.EngineRequest{
+		Dispacement:   input.GetDisplacement(),
+		NoOfCylinders: float64(input.GetNoOfCylinders()),
+		CarRange:      input.GetCarRange(),
+	}
+}
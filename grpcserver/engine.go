@@ -0,0 +1,73 @@
+package grpcserver
+
+import (
+	"context"
+
+	"github.com/geekAshish/DriveDesk/service"
+	"go.opentelemetry.io/otel"
+
+	pb "github.com/geekAshish/DriveDesk/proto/drivedesk/v1"
+)
+
+type EngineServer struct {
+	pb.UnimplementedEngineServiceServer
+	service service.EngineServiceInterface
+}
+
+func NewEngineServer(service service.EngineServiceInterface) *EngineServer {
+	return &EngineServer{
+		service: service,
+	}
+}
+
+func (s *EngineServer) GetEngine(ctx context.Context, req *pb.GetEngineRequest) (*pb.Engine, error) {
+	tracer := otel.Tracer("EngineServer")
+	ctx, span := tracer.Start(ctx, "GetEngine-GRPC")
+	defer span.End()
+
+	engine, err := s.service.GetEngineById(ctx, req.GetId())
+	if err != nil {
+		return nil, err
+	}
+
+	return toEngine(engine), nil
+}
+
+func (s *EngineServer) CreateEngine(ctx context.Context, req *pb.CreateEngineRequest) (*pb.Engine, error) {
+	tracer := otel.Tracer("EngineServer")
+	ctx, span := tracer.Start(ctx, "CreateEngine-GRPC")
+	defer span.End()
+
+	engine, err := s.service.CreateEngine(ctx, engineRequest(req.GetEngine()))
+	if err != nil {
+		return nil, err
+	}
+
+	return toEngine(engine), nil
+}
+
+func (s *EngineServer) UpdateEngine(ctx context.Context, req *pb.UpdateEngineRequest) (*pb.Engine, error) {
+	tracer := otel.Tracer("EngineServer")
+	ctx, span := tracer.Start(ctx, "UpdateEngine-GRPC")
+	defer span.End()
+
+	engine, err := s.service.UpdateEngine(ctx, req.GetId(), engineRequest(req.GetEngine()))
+	if err != nil {
+		return nil, err
+	}
+
+	return toEngine(engine), nil
+}
+
+func (s *EngineServer) DeleteEngine(ctx context.Context, req *pb.DeleteEngineRequest) (*pb.Engine, error) {
+	tracer := otel.Tracer("EngineServer")
+	ctx, span := tracer.Start(ctx, "DeleteEngine-GRPC")
+	defer span.End()
+
+	engine, err := s.service.DeleteEngine(ctx, req.GetId())
+	if err != nil {
+		return nil, err
+	}
+
+	return toEngine(engine), nil
+}
@@ -0,0 +1,95 @@
+package grpcserver
+
+import (
+	"context"
+	"errors"
+	"log"
+
+	"github.com/geekAshish/DriveDesk/apperrors"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	handlerV2 "github.com/geekAshish/DriveDesk/handler/v2"
+)
+
+// Status returns the gRPC status for err, the counterpart of the problem
+// documents the REST API answers with. Field errors become a BadRequest
+// detail using the v2 field names, and errors of unknown kind are logged
+// and reported without any detail.
+func Status(err error) *status.Status {
+	if s, ok := status.FromError(err); ok {
+		return s
+	}
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return status.FromContextError(err)
+	}
+
+	code := Code(err)
+	if code == codes.Internal {
+		log.Println("ERROR: ", err)
+		return status.New(codes.Internal, "internal error")
+	}
+
+	s := status.New(code, apperrors.Message(err))
+
+	fields := apperrors.Fields(err)
+	if len(fields) == 0 {
+		return s
+	}
+
+	violations := make([]*errdetails.BadRequest_FieldViolation, 0, len(fields))
+	for _, field := range fields {
+		violations = append(violations, &errdetails.BadRequest_FieldViolation{
+			Field:       handlerV2.FieldName(field.Field),
+			Description: field.Message,
+			Reason:      field.Code,
+		})
+	}
+
+	detailed, detailErr := s.WithDetails(&errdetails.BadRequest{FieldViolations: violations})
+	if detailErr != nil {
+		log.Println("ERROR: ", detailErr)
+		return s
+	}
+
+	return detailed
+}
+
+// Code returns the gRPC code for err.
+func Code(err error) codes.Code {
+	switch {
+	case errors.Is(err, apperrors.ErrBadRequest), errors.Is(err, apperrors.ErrValidation):
+		return codes.InvalidArgument
+	case errors.Is(err, apperrors.ErrUnauthorized):
+		return codes.Unauthenticated
+	case errors.Is(err, apperrors.ErrForbidden):
+		return codes.PermissionDenied
+	case errors.Is(err, apperrors.ErrNotFound):
+		return codes.NotFound
+	case errors.Is(err, apperrors.ErrConflict):
+		return codes.AlreadyExists
+	case errors.Is(err, apperrors.ErrTooManyRequests):
+		return codes.ResourceExhausted
+	default:
+		return codes.Internal
+	}
+}
+
+func errorUnaryInterceptor(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	resp, err := handler(ctx, req)
+	if err != nil {
+		return nil, Status(err).Err()
+	}
+
+	return resp, nil
+}
+
+func errorStreamInterceptor(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	if err := handler(srv, ss); err != nil {
+		return Status(err).Err()
+	}
+
+	return nil
+}
@@ -0,0 +1,75 @@
+package grpcserver
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+)
+
+// the gRPC counterparts of the metrics MetricMiddleware records for HTTP
+var (
+	requestCounter = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "grpc_requests_total",
+			Help: "Total number of grpc request",
+		},
+		[]string{"service", "method"},
+	)
+
+	requestDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name: "grpc_requests_duration_seconds",
+			Help: "Duration of grpc request in seconds",
+		},
+		[]string{"service", "method"},
+	)
+
+	statusCounter = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "grpc_response_status_total",
+			Help: "Total number of grpc request by status code",
+		},
+		[]string{"service", "method", "status_code"},
+	)
+)
+
+func init() {
+	prometheus.MustRegister(requestCounter, requestDuration, statusCounter)
+}
+
+func metricUnaryInterceptor(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	start := time.Now()
+
+	resp, err := handler(ctx, req)
+
+	observe(info.FullMethod, start, err)
+
+	return resp, err
+}
+
+func metricStreamInterceptor(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	start := time.Now()
+
+	err := handler(srv, ss)
+
+	observe(info.FullMethod, start, err)
+
+	return err
+}
+
+func observe(fullMethod string, start time.Time, err error) {
+	duration := time.Since(start).Seconds()
+
+	service := serviceName(fullMethod)
+	method := fullMethod[strings.LastIndex(fullMethod, "/")+1:]
+
+	requestCounter.WithLabelValues(service, method).Inc()
+
+	requestDuration.WithLabelValues(service, method).Observe(duration)
+
+	statusCounter.WithLabelValues(service, method, status.Code(err).String()).Inc()
+}
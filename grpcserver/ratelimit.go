@@ -0,0 +1,116 @@
+package grpcserver
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/geekAshish/DriveDesk/middleware"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+
+	pb "github.com/geekAshish/DriveDesk/proto/drivedesk/v1"
+)
+
+// gRPC calls are limited like HTTP POSTs to a route named after the method,
+// e.g. "POST /drivedesk.v1.CarService/GetCar". Logins count as POST /login,
+// so they share their limit with the REST API and cannot be spread over both
+// to get twice the attempts.
+const rateLimitMethod = "POST"
+
+var rateLimitRoutes = map[string]string{
+	pb.AuthService_Login_FullMethodName: "/login",
+}
+
+// rateLimited is false for the infrastructure services, which the REST API
+// does not limit either.
+func rateLimited(fullMethod string) bool {
+	return !publicServices[serviceName(fullMethod)] || serviceName(fullMethod) == pb.AuthService_ServiceDesc.ServiceName
+}
+
+func rateLimitRoute(fullMethod string) string {
+	if route, ok := rateLimitRoutes[fullMethod]; ok {
+		return route
+	}
+	return fullMethod
+}
+
+// peerClient is the client key of the address ctx was called from.
+func peerClient(ctx context.Context) string {
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		return middleware.AddressClient(p.Addr.String())
+	}
+	return middleware.AddressClient("")
+}
+
+// limitPreAuth is RateLimiter.PreAuth for gRPC calls to protected services.
+func limitPreAuth(ctx context.Context, limiter *middleware.RateLimiter, fullMethod string) error {
+	if publicServices[serviceName(fullMethod)] {
+		return nil
+	}
+
+	decision := limiter.TakePreAuth(ctx, peerClient(ctx), rateLimitMethod, rateLimitRoute(fullMethod))
+	return refuse(ctx, decision)
+}
+
+// limit is RateLimiter.Middleware for gRPC calls, made after authenticate.
+func limit(ctx context.Context, limiter *middleware.RateLimiter, fullMethod string) error {
+	if !rateLimited(fullMethod) {
+		return nil
+	}
+
+	client := peerClient(ctx)
+	if userName := middleware.UserName(ctx); userName != "" {
+		client = middleware.UserClient(userName)
+	}
+
+	decision := limiter.Take(ctx, client, middleware.Role(ctx), rateLimitMethod, rateLimitRoute(fullMethod))
+	return refuse(ctx, decision)
+}
+
+// refuse returns the error of a refused call, after telling the client when
+// to retry in the "retry-after" header metadata.
+func refuse(ctx context.Context, decision middleware.RateLimitDecision) error {
+	if decision.Allowed {
+		return nil
+	}
+
+	_ = grpc.SetHeader(ctx, metadata.Pairs("retry-after", strconv.Itoa(decision.RetryAfter())))
+	return decision.Err()
+}
+
+func rateLimitUnaryInterceptors(limiter *middleware.RateLimiter) (preAuth, postAuth grpc.UnaryServerInterceptor) {
+	preAuth = func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if err := limitPreAuth(ctx, limiter, info.FullMethod); err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+
+	postAuth = func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if err := limit(ctx, limiter, info.FullMethod); err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+
+	return preAuth, postAuth
+}
+
+func rateLimitStreamInterceptors(limiter *middleware.RateLimiter) (preAuth, postAuth grpc.StreamServerInterceptor) {
+	preAuth = func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if err := limitPreAuth(ss.Context(), limiter, info.FullMethod); err != nil {
+			return err
+		}
+		return handler(srv, ss)
+	}
+
+	postAuth = func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if err := limit(ss.Context(), limiter, info.FullMethod); err != nil {
+			return err
+		}
+		return handler(srv, ss)
+	}
+
+	return preAuth, postAuth
+}
@@ -0,0 +1,47 @@
+// Package grpcserver serves the car, engine and auth services of
+// proto/drivedesk/v1 over gRPC. It is a thin layer over the same services the
+// REST handlers use, with interceptors for JWT auth, rate limits, error
+// mapping and Prometheus metrics, plus the standard health and reflection
+// services.
+package grpcserver
+
+import (
+	"github.com/geekAshish/DriveDesk/middleware"
+	"github.com/geekAshish/DriveDesk/service"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
+
+	pb "github.com/geekAshish/DriveDesk/proto/drivedesk/v1"
+)
+
+// New returns a gRPC server with every service registered and reporting
+// healthy. Calls are limited by rateLimit like the REST API, which may be nil
+// for no limits.
+func New(cars service.CarServiceInterface, engines service.EngineServiceInterface, rateLimit *middleware.RateLimiter) *grpc.Server {
+	preAuthUnary, rateLimitUnary := rateLimitUnaryInterceptors(rateLimit)
+	preAuthStream, rateLimitStream := rateLimitStreamInterceptors(rateLimit)
+
+	server := grpc.NewServer(
+		// otel handler for tracing, the counterpart of otelmux
+		grpc.StatsHandler(otelgrpc.NewServerHandler()),
+		grpc.ChainUnaryInterceptor(metricUnaryInterceptor, errorUnaryInterceptor, preAuthUnary, authUnaryInterceptor, rateLimitUnary),
+		grpc.ChainStreamInterceptor(metricStreamInterceptor, errorStreamInterceptor, preAuthStream, authStreamInterceptor, rateLimitStream),
+	)
+
+	pb.RegisterAuthServiceServer(server, NewAuthServer())
+	pb.RegisterCarServiceServer(server, NewCarServer(cars))
+	pb.RegisterEngineServiceServer(server, NewEngineServer(engines))
+
+	healthServer := health.NewServer()
+	for name := range server.GetServiceInfo() {
+		healthServer.SetServingStatus(name, healthpb.HealthCheckResponse_SERVING)
+	}
+	healthpb.RegisterHealthServer(server, healthServer)
+
+	reflection.Register(server)
+
+	return server
+}
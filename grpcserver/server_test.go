@@ -0,0 +1,205 @@
+package grpcserver
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/geekAshish/DriveDesk/middleware"
+	"github.com/geekAshish/DriveDesk/models"
+	"github.com/geekAshish/DriveDesk/service"
+	"github.com/geekAshish/DriveDesk/store/memory"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	pb "github.com/geekAshish/DriveDesk/proto/drivedesk/v1"
+	carService "github.com/geekAshish/DriveDesk/service/car"
+	engineService "github.com/geekAshish/DriveDesk/service/engine"
+	referenceService "github.com/geekAshish/DriveDesk/service/reference"
+	ruleService "github.com/geekAshish/DriveDesk/service/rule"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// dial serves a server backed by the memory stores and returns a client
+// connection to it.
+func dial(t *testing.T) *grpc.ClientConn {
+	t.Helper()
+
+	return dialLimited(t, nil)
+}
+
+// dialLimited is dial with the calls limited by rateLimit.
+func dialLimited(t *testing.T, rateLimit *middleware.RateLimiter) *grpc.ClientConn {
+	t.Helper()
+
+	db := memory.NewDB()
+	tx := memory.NewTxManager(db)
+	rules := ruleService.NewValidationRuleService(memory.NewValidationRuleStore(db), time.Minute)
+	references := referenceService.NewReferenceService(memory.NewReferenceStore(db), tx, time.Minute)
+
+	server := New(
+		carService.NewCarService(memory.NewCarStore(db), tx, references, rules, service.NoEvents{}),
+		engineService.NewEngineService(memory.NewEngineStore(db), memory.NewCarStore(db), tx, rules, service.NoEvents{}),
+		rateLimit,
+	)
+
+	listener := bufconn.Listen(1 << 20)
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatalf("dialing: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	return conn
+}
+
+func login(t *testing.T, conn *grpc.ClientConn) context.Context {
+	t.Helper()
+
+	res, err := pb.NewAuthServiceClient(conn).Login(context.Background(), &pb.LoginRequest{UserName: "admin", Password: "admin123"})
+	if err != nil {
+		t.Fatalf("Login: %v", err)
+	}
+
+	return metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer "+res.GetToken())
+}
+
+func TestProtectedServicesNeedToken(t *testing.T) {
+	conn := dial(t)
+
+	_, err := pb.NewCarServiceClient(conn).GetCar(context.Background(), &pb.GetCarRequest{Id: "f0f4b1d6-2f53-4b8e-9b58-7cba0a0d1c3e"})
+	if status.Code(err) != codes.Unauthenticated {
+		t.Errorf("GetCar without token: got %v, want Unauthenticated", err)
+	}
+
+	_, err = pb.NewAuthServiceClient(conn).Login(context.Background(), &pb.LoginRequest{UserName: "admin", Password: "wrong"})
+	if status.Code(err) != codes.Unauthenticated {
+		t.Errorf("Login with a wrong password: got %v, want Unauthenticated", err)
+	}
+
+	health, err := healthpb.NewHealthClient(conn).Check(context.Background(), &healthpb.HealthCheckRequest{Service: pb.CarService_ServiceDesc.ServiceName})
+	if err != nil || health.GetStatus() != healthpb.HealthCheckResponse_SERVING {
+		t.Errorf("health of CarService = %v, %v; want SERVING", health.GetStatus(), err)
+	}
+}
+
+func TestCarLifecycle(t *testing.T) {
+	conn := dial(t)
+	ctx := login(t, conn)
+
+	engines := pb.NewEngineServiceClient(conn)
+	cars := pb.NewCarServiceClient(conn)
+
+	engine, err := engines.CreateEngine(ctx, &pb.CreateEngineRequest{Engine: &pb.EngineInput{Displacement: 1500, NoOfCylinders: 4, CarRange: 600}})
+	if err != nil {
+		t.Fatalf("CreateEngine: %v", err)
+	}
+
+	created, err := cars.CreateCar(ctx, &pb.CreateCarRequest{Car: &pb.CarInput{
+		Name: "City", Year: 2024, Brand: "Honda", FuelType: "gasoline", Price: 12000,
+		Engine: engine,
+	}})
+	if err != nil {
+		t.Fatalf("CreateCar: %v", err)
+	}
+	if created.GetYear() != 2024 || created.GetFuelType() != "petrol" || created.GetEngine().GetNoOfCylinders() != 4 {
+		t.Errorf("CreateCar = %v, want year 2024, fuel type petrol and the engine details", created)
+	}
+
+	listed, err := cars.ListCars(ctx, &pb.ListCarsRequest{Brand: "Honda"})
+	if err != nil || len(listed.GetCars()) != 1 {
+		t.Fatalf("ListCars = %v, %v; want the created car", listed, err)
+	}
+
+	if _, err := cars.DeleteCar(ctx, &pb.DeleteCarRequest{Id: created.GetId()}); err != nil {
+		t.Fatalf("DeleteCar: %v", err)
+	}
+
+	_, err = cars.GetCar(ctx, &pb.GetCarRequest{Id: created.GetId()})
+	if status.Code(err) != codes.NotFound {
+		t.Errorf("GetCar after delete: got %v, want NotFound", err)
+	}
+}
+
+func TestValidationErrorsCarryFieldViolations(t *testing.T) {
+	conn := dial(t)
+	ctx := login(t, conn)
+
+	_, err := pb.NewCarServiceClient(conn).CreateCar(ctx, &pb.CreateCarRequest{Car: &pb.CarInput{
+		Name: "City", Year: 2024, Brand: "Honda", FuelType: "petrol", Price: 12000,
+		Engine: &pb.Engine{EngineId: "f0f4b1d6-2f53-4b8e-9b58-7cba0a0d1c3e", NoOfCylinders: 4, CarRange: 600},
+	}})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("CreateCar without displacement: got %v, want InvalidArgument", err)
+	}
+
+	var fields []string
+	for _, detail := range status.Convert(err).Details() {
+		if badRequest, ok := detail.(*errdetails.BadRequest); ok {
+			for _, violation := range badRequest.GetFieldViolations() {
+				fields = append(fields, violation.GetField())
+			}
+		}
+	}
+
+	if len(fields) != 1 || fields[0] != "engine.displacement" {
+		t.Errorf("field violations = %v, want [engine.displacement]", fields)
+	}
+}
+
+func TestLoginRateLimited(t *testing.T) {
+	limiter := middleware.NewRateLimiter(memory.NewRateLimitStore(memory.NewDB()), models.RateLimitConfig{
+		PreAuth: models.RateLimit{Rate: 1, Burst: 3},
+		Routes: map[string]models.RateLimitPolicy{
+			"POST /login": {RateLimit: models.RateLimit{Rate: 0.01, Burst: 2}},
+		},
+	})
+	conn := dialLimited(t, limiter)
+	auth := pb.NewAuthServiceClient(conn)
+
+	for range 2 {
+		if _, err := auth.Login(context.Background(), &pb.LoginRequest{UserName: "admin", Password: "wrong"}); status.Code(err) != codes.Unauthenticated {
+			t.Fatalf("Login = %v, want Unauthenticated", err)
+		}
+	}
+
+	var header metadata.MD
+	_, err := auth.Login(context.Background(), &pb.LoginRequest{UserName: "admin", Password: "admin123"}, grpc.Header(&header))
+	if status.Code(err) != codes.ResourceExhausted || len(header.Get("retry-after")) != 1 {
+		t.Fatalf("third Login = %v with %v, want ResourceExhausted and retry-after", err, header)
+	}
+
+	// calls with invalid tokens are limited before the token is checked
+	cars := pb.NewCarServiceClient(conn)
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer not-a-token")
+	for range 3 {
+		if _, err := cars.GetCar(ctx, &pb.GetCarRequest{Id: "f0f4b1d6-2f53-4b8e-9b58-7cba0a0d1c3e"}); status.Code(err) != codes.Unauthenticated {
+			t.Fatalf("GetCar with an invalid token = %v, want Unauthenticated", err)
+		}
+	}
+	if _, err := cars.GetCar(ctx, &pb.GetCarRequest{Id: "f0f4b1d6-2f53-4b8e-9b58-7cba0a0d1c3e"}); status.Code(err) != codes.ResourceExhausted {
+		t.Errorf("GetCar past the limit = %v, want ResourceExhausted", err)
+	}
+
+	// health checks are never limited
+	health := healthpb.NewHealthClient(conn)
+	for range 5 {
+		if _, err := health.Check(context.Background(), &healthpb.HealthCheckRequest{}); err != nil {
+			t.Fatalf("health Check: %v", err)
+		}
+	}
+}
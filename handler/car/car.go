@@ -1,11 +1,10 @@
 package car
 
 import (
-	"encoding/json"
-	"io"
-	"log"
 	"net/http"
 
+	"github.com/geekAshish/DriveDesk/handler/codec"
+	"github.com/geekAshish/DriveDesk/handler/response"
 	"github.com/geekAshish/DriveDesk/models"
 	"github.com/geekAshish/DriveDesk/service"
 	"github.com/gorilla/mux"
@@ -25,7 +24,7 @@ func NewCarHandler(service service.CarServiceInterface) *CarHandler {
 func (h *CarHandler) GetCarById(w http.ResponseWriter, r *http.Request) {
 	tracer := otel.Tracer("CarHandler")
 	ctx, span := tracer.Start(r.Context(), "GetCarById-Handler")
-	defer span.End();
+	defer span.End()
 
 	// ctx := r.Context() // now we will use tracer context
 	vars := mux.Vars(r)
@@ -33,32 +32,18 @@ func (h *CarHandler) GetCarById(w http.ResponseWriter, r *http.Request) {
 
 	res, err := h.service.GetCarById(ctx, id)
 	if err != nil {
-		w.WriteHeader(http.StatusInternalServerError)
-		log.Println("ERROR: ", err)
+		response.Error(w, r, err)
 		return
 	}
 
-	body, err := json.Marshal(res)
-	if err != nil {
-		w.WriteHeader(http.StatusInternalServerError)
-		log.Println("ERROR: ", err)
-		return
-	}
-
-	w.Header().Set("Content-Type", "applilcation/json")
-	w.WriteHeader(http.StatusOK)
-
-	// write the response body
-	_, err = w.Write(body)
-	if err != nil {
-		log.Println("ERROR: ", err)
-	}
+	response.LastModified(w, res.LastModified())
+	codec.Write(w, r, http.StatusOK, res)
 }
 
 func (h *CarHandler) GetCarByBrand(w http.ResponseWriter, r *http.Request) {
 	tracer := otel.Tracer("CarHandler")
 	ctx, span := tracer.Start(r.Context(), "GetCarByBrand-Handler")
-	defer span.End();
+	defer span.End()
 
 	// ctx := r.Context()
 	brand := r.URL.Query().Get("brand")
@@ -66,128 +51,63 @@ func (h *CarHandler) GetCarByBrand(w http.ResponseWriter, r *http.Request) {
 
 	res, err := h.service.GetCarByBrand(ctx, brand, isEngine)
 	if err != nil {
-		w.WriteHeader(http.StatusInternalServerError)
-		log.Println("ERROR: ", err)
-		return
-	}
-
-	body, err := json.Marshal(res)
-	if err != nil {
-		w.WriteHeader(http.StatusInternalServerError)
-		log.Println("ERROR: ", err)
+		response.Error(w, r, err)
 		return
 	}
 
-	w.Header().Set("Content-Type", "applilcation/json")
-	w.WriteHeader(http.StatusOK)
-
-	// write the response body
-	_, err = w.Write(body)
-	if err != nil {
-		log.Println("ERROR: ", err)
-	}
+	codec.Write(w, r, http.StatusOK, res)
 }
 
 func (h *CarHandler) CreateCar(w http.ResponseWriter, r *http.Request) {
 	tracer := otel.Tracer("CarHandler")
 	ctx, span := tracer.Start(r.Context(), "CreateCar-Handler")
-	defer span.End();
-
+	defer span.End()
 
 	// ctx := r.Context()
 
-	body, err := io.ReadAll(r.Body)
-	if err != nil {
-		log.Println("ERROR: ", err)
-		w.WriteHeader(http.StatusInternalServerError)
-		return
-	}
-
-	var carReq models.CarRequest
-	err = json.Unmarshal(body, &carReq)
-	if err != nil {
-		log.Println("ERROR: ", err)
-		w.WriteHeader(http.StatusInternalServerError)
-		return
-	}
-
-	createdCar, err := h.service.CreateCar(ctx, &carReq)
+	carReq, err := decodeCarRequest(r)
 	if err != nil {
-		log.Println("ERROR: ", err)
-		w.WriteHeader(http.StatusInternalServerError)
+		response.Error(w, r, err)
 		return
 	}
 
-	responseBody, err := json.Marshal(createdCar)
+	createdCar, err := h.service.CreateCar(ctx, carReq)
 	if err != nil {
-		log.Println("ERROR: ", err)
-		w.WriteHeader(http.StatusInternalServerError)
+		response.Error(w, r, err)
 		return
 	}
 
-	w.Header().Set("Content-Type", "applilcation/json")
-	w.WriteHeader(http.StatusCreated)
-
-	// write the response body
-	_, err = w.Write(responseBody)
-	if err != nil {
-		log.Println("ERROR: ", err)
-	}
+	codec.Write(w, r, http.StatusCreated, createdCar)
 }
 
 func (h *CarHandler) UpdateCar(w http.ResponseWriter, r *http.Request) {
 	tracer := otel.Tracer("CarHandler")
 	ctx, span := tracer.Start(r.Context(), "UpdateCar-Handler")
-	defer span.End();
-
+	defer span.End()
 
 	// ctx := r.Context()
 	params := mux.Vars(r)
 	id := params["id"]
 
-	body, err := io.ReadAll(r.Body)
-	if err != nil {
-		log.Println("ERROR READING REQUEST: ", err)
-		w.WriteHeader(http.StatusInternalServerError)
-		return
-	}
-
-	var carReq models.CarRequest
-	err = json.Unmarshal(body, &carReq)
+	carReq, err := decodeCarRequest(r)
 	if err != nil {
-		log.Println("ERROR: ", err)
-		w.WriteHeader(http.StatusInternalServerError)
+		response.Error(w, r, err)
 		return
 	}
 
-	updatedCar, err := h.service.UpdateCar(ctx, id, &carReq)
+	updatedCar, err := h.service.UpdateCar(ctx, id, carReq)
 	if err != nil {
-		log.Println("ERROR WHILE UPDATING THE CART: ", err)
-		w.WriteHeader(http.StatusInternalServerError)
+		response.Error(w, r, err)
 		return
 	}
 
-	responseBody, err := json.Marshal(updatedCar)
-	if err != nil {
-		log.Println("ERROR: ", err)
-		w.WriteHeader(http.StatusInternalServerError)
-		return
-	}
-
-	w.Header().Set("Content-Type", "applilcation/json")
-	w.WriteHeader(http.StatusOK)
-
-	// write the response body
-	_, err = w.Write(responseBody)
-	if err != nil {
-		log.Println("ERROR: ", err)
-	}
+	codec.Write(w, r, http.StatusOK, updatedCar)
 }
 
 func (h *CarHandler) DeleteCar(w http.ResponseWriter, r *http.Request) {
 	tracer := otel.Tracer("CarHandler")
 	ctx, span := tracer.Start(r.Context(), "DeleteCar-Handler")
-	defer span.End();
+	defer span.End()
 
 	// ctx := r.Context()
 
@@ -196,25 +116,18 @@ func (h *CarHandler) DeleteCar(w http.ResponseWriter, r *http.Request) {
 
 	deleteCar, err := h.service.DeleteCar(ctx, id)
 	if err != nil {
-		log.Println("ERROR WHILE DELETEING THE CART: ", err)
-		w.WriteHeader(http.StatusInternalServerError)
+		response.Error(w, r, err)
 		return
 	}
 
-	responseBody, err := json.Marshal(deleteCar)
-	if err != nil {
-		log.Println("ERROR: ", err)
-		w.WriteHeader(http.StatusInternalServerError)
-		return
-	}
-
-	w.Header().Set("Content-Type", "applilcation/json")
-	w.WriteHeader(http.StatusOK)
+	codec.Write(w, r, http.StatusOK, deleteCar)
+}
 
-	// write the response body
-	_, err = w.Write(responseBody)
-	if err != nil {
-		log.Println("ERROR: ", err)
+func decodeCarRequest(r *http.Request) (*models.CarRequest, error) {
+	var carReq models.CarRequest
+	if err := codec.Decode(r, &carReq, "car"); err != nil {
+		return nil, err
 	}
 
+	return &carReq, nil
 }
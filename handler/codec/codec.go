@@ -0,0 +1,297 @@
+// Package codec lets the car and engine handlers answer in the media type
+// the client asks for with Accept, and read request bodies in the one named
+// by Content-Type. JSON stays the default for both.
+package codec
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"log"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/geekAshish/DriveDesk/apperrors"
+	"github.com/geekAshish/DriveDesk/handler/response"
+)
+
+// Format is a media type the handlers can write and, unless it is
+// response-only, read.
+type Format struct {
+	// MediaType is sent in Content-Type unless the client asked for one of
+	// the aliases by name.
+	MediaType string
+	Aliases   []string
+
+	// charset is appended to text based media types.
+	charset bool
+
+	encode func(w io.Writer, v any) error
+	decode func(body []byte, dst any) error
+}
+
+var (
+	JSON = &Format{
+		MediaType: "application/json",
+		encode:    encodeJSON,
+		decode:    decodeJSON,
+	}
+	XML = &Format{
+		MediaType: "application/xml",
+		Aliases:   []string{"text/xml"},
+		charset:   true,
+		encode:    encodeXML,
+		decode:    decodeXML,
+	}
+	// CSV is only offered for lists, one row per item.
+	CSV = &Format{
+		MediaType: "text/csv",
+		charset:   true,
+		encode:    encodeCSV,
+	}
+	MessagePack = &Format{
+		MediaType: "application/msgpack",
+		Aliases:   []string{"application/x-msgpack", "application/vnd.msgpack"},
+		encode:    encodeMessagePack,
+		decode:    decodeMessagePack,
+	}
+)
+
+// formats offered for single resources and for lists, preferred first.
+var (
+	single = []*Format{JSON, XML, MessagePack}
+	list   = []*Format{JSON, XML, CSV, MessagePack}
+)
+
+// readable are the formats request bodies can come in.
+var readable = []*Format{JSON, XML, MessagePack}
+
+type choiceKey struct{}
+
+// choice is the format picked for a response, with the media type to send.
+type choice struct {
+	format    *Format
+	mediaType string
+}
+
+// Negotiate wraps a handler of a single resource. The request is rejected
+// with 406 before next runs when Accept allows none of JSON, XML and
+// MessagePack, and with 415 when its body is in a media type that cannot be
+// read; nothing is changed in either case.
+func Negotiate(next http.HandlerFunc) http.Handler {
+	return negotiate(single, next)
+}
+
+// NegotiateList is Negotiate for handlers of lists, which can also answer
+// in CSV.
+func NegotiateList(next http.HandlerFunc) http.Handler {
+	return negotiate(list, next)
+}
+
+func negotiate(offers []*Format, next http.HandlerFunc) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Vary", "Accept")
+
+		chosen, err := accepted(r.Header.Get("Accept"), offers)
+		if err != nil {
+			response.Error(w, r, err)
+			return
+		}
+
+		if r.Header.Get("Content-Type") != "" {
+			if _, err := contentFormat(r); err != nil {
+				response.Error(w, r, err)
+				return
+			}
+		}
+
+		next(w, r.WithContext(context.WithValue(r.Context(), choiceKey{}, chosen)))
+	})
+}
+
+// Write writes body with the given status code in the format negotiated for
+// r, or as JSON when r did not go through Negotiate.
+func Write(w http.ResponseWriter, r *http.Request, status int, body any) {
+	chosen, ok := r.Context().Value(choiceKey{}).(choice)
+	if !ok {
+		chosen = choice{format: JSON, mediaType: JSON.MediaType}
+	}
+
+	var buf bytes.Buffer
+	if err := chosen.format.encode(&buf, body); err != nil {
+		response.Error(w, r, err)
+		return
+	}
+
+	contentType := chosen.mediaType
+	if chosen.format.charset {
+		contentType += "; charset=utf-8"
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	w.WriteHeader(status)
+
+	// the status is sent, so a failed write can only be logged
+	_, err := w.Write(buf.Bytes())
+	if err != nil {
+		log.Println("ERROR: ", err)
+	}
+}
+
+// Decode reads the body of r into dst according to its Content-Type, which
+// defaults to JSON. what names the expected resource in error messages.
+func Decode(r *http.Request, dst any, what string) error {
+	format, err := contentFormat(r)
+	if err != nil {
+		return err
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return apperrors.Wrap(apperrors.ErrBadRequest, err, "could not read request body")
+	}
+
+	if err := format.decode(body, dst); err != nil {
+		return apperrors.Wrap(apperrors.ErrBadRequest, err, "request body is not a valid %s: %v", what, err)
+	}
+
+	return nil
+}
+
+func contentFormat(r *http.Request) (*Format, error) {
+	header := r.Header.Get("Content-Type")
+	if header == "" {
+		return JSON, nil
+	}
+
+	mediaType, _, err := mime.ParseMediaType(header)
+	if err == nil {
+		for _, format := range readable {
+			if format.is(mediaType) {
+				return format, nil
+			}
+		}
+	}
+
+	return nil, apperrors.UnsupportedMediaType("request bodies can not be %s, expected one of %s", header, mediaTypes(readable))
+}
+
+// accepted picks the offer the Accept header prefers, the first offer
+// winning ties. An empty header accepts anything.
+func accepted(header string, offers []*Format) (choice, error) {
+	if strings.TrimSpace(header) == "" {
+		return choice{format: offers[0], mediaType: offers[0].MediaType}, nil
+	}
+
+	ranges := parseAccept(header)
+
+	var best choice
+	bestQuality := 0.0
+	for _, offer := range offers {
+		mediaType, quality := offer.quality(ranges)
+		if quality > bestQuality {
+			best, bestQuality = choice{format: offer, mediaType: mediaType}, quality
+		}
+	}
+
+	if best.format == nil {
+		return choice{}, apperrors.NotAcceptable("can not answer with any of %s, expected one of %s", header, mediaTypes(offers))
+	}
+
+	return best, nil
+}
+
+// mediaRange is one entry of an Accept header.
+type mediaRange struct {
+	mediaType string
+	quality   float64
+}
+
+func parseAccept(header string) []mediaRange {
+	var ranges []mediaRange
+	for _, part := range strings.Split(header, ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+
+		quality := 1.0
+		if q, ok := params["q"]; ok {
+			quality, err = strconv.ParseFloat(q, 64)
+			if err != nil {
+				continue
+			}
+		}
+
+		ranges = append(ranges, mediaRange{mediaType: mediaType, quality: quality})
+	}
+
+	return ranges
+}
+
+// quality returns the media type f would be sent as and how much the
+// client wants it. Of the ranges matching a media type, the most specific
+// one decides, so "*/*;q=0.1, text/csv" prefers CSV.
+func (f *Format) quality(ranges []mediaRange) (string, float64) {
+	mediaType, best := f.MediaType, 0.0
+	for _, candidate := range append([]string{f.MediaType}, f.Aliases...) {
+		specificity, quality := -1, 0.0
+		for _, r := range ranges {
+			if s := matches(r.mediaType, candidate); s > specificity {
+				specificity, quality = s, r.quality
+			}
+		}
+
+		if quality > best {
+			best = quality
+			// wildcards get the canonical media type
+			if specificity == 2 {
+				mediaType = candidate
+			} else {
+				mediaType = f.MediaType
+			}
+		}
+	}
+
+	return mediaType, best
+}
+
+// matches returns how specifically the media range r matches mediaType: 2
+// for an exact match, 1 for type/*, 0 for */* and -1 when it does not.
+func matches(r, mediaType string) int {
+	switch {
+	case r == mediaType:
+		return 2
+	case r == "*/*":
+		return 0
+	case strings.HasSuffix(r, "/*") && strings.HasPrefix(mediaType, strings.TrimSuffix(r, "*")):
+		return 1
+	default:
+		return -1
+	}
+}
+
+func (f *Format) is(mediaType string) bool {
+	if mediaType == f.MediaType {
+		return true
+	}
+
+	for _, alias := range f.Aliases {
+		if mediaType == alias {
+			return true
+		}
+	}
+
+	return false
+}
+
+func mediaTypes(formats []*Format) string {
+	names := make([]string, len(formats))
+	for i, format := range formats {
+		names[i] = format.MediaType
+	}
+
+	return strings.Join(names, ", ")
+}
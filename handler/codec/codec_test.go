@@ -0,0 +1,241 @@
+package codec
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/geekAshish/DriveDesk/models"
+	"github.com/google/uuid"
+)
+
+var testCar = models.Car{
+	ID:       uuid.MustParse("7f3c1a2e-0d4b-4c59-9a3e-2b8f6d1e4c70"),
+	Name:     "Civic",
+	Year:     "2020",
+	Brand:    "Honda",
+	FuelType: "petrol",
+	Price:    21999.5,
+	Engine: models.Engine{
+		EngineID:      uuid.MustParse("0a1b2c3d-4e5f-4a6b-8c7d-9e0f1a2b3c4d"),
+		Dispacement:   1500,
+		NoOfCylinders: 4,
+		CarRange:      600,
+	},
+	CreateAt: time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC),
+	UpdateAt: time.Date(2024, 5, 2, 12, 0, 0, 0, time.UTC),
+}
+
+func TestAccepted(t *testing.T) {
+	tests := []struct {
+		accept string
+		offers []*Format
+		want   string
+	}{
+		{"", single, "application/json"},
+		{"*/*", single, "application/json"},
+		{"application/xml", single, "application/xml"},
+		{"text/xml", single, "text/xml"},
+		{"application/*", single, "application/json"},
+		{"application/json;q=0.5, application/msgpack", single, "application/msgpack"},
+		{"application/x-msgpack", single, "application/x-msgpack"},
+		{"text/*", list, "application/xml"},
+		{"text/csv, */*;q=0.1", list, "text/csv"},
+		{"text/csv, */*;q=0.1", single, "application/json"},
+		{"*/*, application/json;q=0", single, "application/xml"},
+	}
+
+	for _, tt := range tests {
+		got, err := accepted(tt.accept, tt.offers)
+		if err != nil {
+			t.Errorf("accepted(%q) error = %v", tt.accept, err)
+			continue
+		}
+		if got.mediaType != tt.want {
+			t.Errorf("accepted(%q) = %s, want %s", tt.accept, got.mediaType, tt.want)
+		}
+	}
+}
+
+func TestNegotiateRejects(t *testing.T) {
+	tests := []struct {
+		name        string
+		handler     func(http.HandlerFunc) http.Handler
+		accept      string
+		contentType string
+		want        int
+	}{
+		{"html", Negotiate, "text/html", "", http.StatusNotAcceptable},
+		{"csv of one car", Negotiate, "text/csv", "", http.StatusNotAcceptable},
+		{"everything refused", NegotiateList, "*/*;q=0", "", http.StatusNotAcceptable},
+		{"plain text body", Negotiate, "", "text/plain", http.StatusUnsupportedMediaType},
+		{"csv body", Negotiate, "", "text/csv", http.StatusUnsupportedMediaType},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			called := false
+			handler := tt.handler(func(w http.ResponseWriter, r *http.Request) { called = true })
+
+			r := httptest.NewRequest(http.MethodPost, "/cars", strings.NewReader("name=Civic"))
+			r.Header.Set("Accept", tt.accept)
+			if tt.contentType != "" {
+				r.Header.Set("Content-Type", tt.contentType)
+			}
+			w := httptest.NewRecorder()
+
+			handler.ServeHTTP(w, r)
+
+			if w.Code != tt.want {
+				t.Errorf("status = %d, want %d", w.Code, tt.want)
+			}
+			if called {
+				t.Error("handler ran for a rejected request")
+			}
+		})
+	}
+}
+
+// serve writes body through a negotiated handler with the given Accept header.
+func serve(t *testing.T, accept string, body any) *httptest.ResponseRecorder {
+	t.Helper()
+
+	handler := NegotiateList(func(w http.ResponseWriter, r *http.Request) {
+		Write(w, r, http.StatusOK, body)
+	})
+
+	r := httptest.NewRequest(http.MethodGet, "/cars", nil)
+	r.Header.Set("Accept", accept)
+	w := httptest.NewRecorder()
+
+	handler.ServeHTTP(w, r)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, body %s", w.Code, w.Body)
+	}
+	return w
+}
+
+func TestWriteCSV(t *testing.T) {
+	w := serve(t, "text/csv", []models.Car{testCar})
+
+	if got := w.Header().Get("Content-Type"); got != "text/csv; charset=utf-8" {
+		t.Errorf("Content-Type = %q", got)
+	}
+
+	want := "id,name,year,brand,fuel_type,price,vin,engine.engine_id,engine.dispacement,engine.no_of_cylinders,engine.car_range,engine.created_at,engine.updated_at,created_at,updated_at\n" +
+		"7f3c1a2e-0d4b-4c59-9a3e-2b8f6d1e4c70,Civic,2020,Honda,petrol,21999.5,,0a1b2c3d-4e5f-4a6b-8c7d-9e0f1a2b3c4d,1500,4,600,0001-01-01T00:00:00Z,0001-01-01T00:00:00Z,2024-05-01T12:00:00Z,2024-05-02T12:00:00Z\n"
+	if got := w.Body.String(); got != want {
+		t.Errorf("body =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestWriteXML(t *testing.T) {
+	w := serve(t, "application/xml", []models.Car{testCar})
+
+	if got := w.Header().Get("Content-Type"); got != "application/xml; charset=utf-8" {
+		t.Errorf("Content-Type = %q", got)
+	}
+
+	body := w.Body.String()
+	for _, want := range []string{"<cars><car><id>7f3c1a2e-0d4b-4c59-9a3e-2b8f6d1e4c70</id>", "<fuel_type>petrol</fuel_type>", "<engine><engine_id>", "</car></cars>"} {
+		if !strings.Contains(body, want) {
+			t.Errorf("body %s does not contain %s", body, want)
+		}
+	}
+}
+
+func TestWriteMessagePack(t *testing.T) {
+	w := serve(t, "application/msgpack", testCar)
+
+	if got := w.Header().Get("Content-Type"); got != "application/msgpack" {
+		t.Errorf("Content-Type = %q", got)
+	}
+
+	var got models.Car
+	if err := decodeMessagePack(w.Body.Bytes(), &got); err != nil {
+		t.Fatalf("decoding: %v", err)
+	}
+	if !reflect.DeepEqual(got, testCar) {
+		t.Errorf("round trip = %+v, want %+v", got, testCar)
+	}
+}
+
+func TestDecode(t *testing.T) {
+	want := models.CarRequest{
+		Name:     testCar.Name,
+		Year:     testCar.Year,
+		Brand:    testCar.Brand,
+		FuelType: testCar.FuelType,
+		Price:    testCar.Price,
+		Engine:   models.Engine{EngineID: testCar.Engine.EngineID},
+	}
+
+	jsonBody, _ := json.Marshal(want)
+
+	var msgpackBody bytes.Buffer
+	if err := encodeMessagePack(&msgpackBody, want); err != nil {
+		t.Fatal(err)
+	}
+
+	xmlBody := `<car><name>Civic</name><year>2020</year><brand>Honda</brand><fuel_type>petrol</fuel_type>` +
+		`<price>21999.5</price><engine><engine_id>0a1b2c3d-4e5f-4a6b-8c7d-9e0f1a2b3c4d</engine_id></engine></car>`
+
+	tests := []struct {
+		contentType string
+		body        []byte
+	}{
+		{"", jsonBody},
+		{"application/json; charset=utf-8", jsonBody},
+		{"application/xml", []byte(xmlBody)},
+		{"text/xml; charset=utf-8", []byte(xmlBody)},
+		{"application/msgpack", msgpackBody.Bytes()},
+	}
+
+	for _, tt := range tests {
+		r := httptest.NewRequest(http.MethodPost, "/cars", bytes.NewReader(tt.body))
+		if tt.contentType != "" {
+			r.Header.Set("Content-Type", tt.contentType)
+		}
+
+		var got models.CarRequest
+		if err := Decode(r, &got, "car"); err != nil {
+			t.Errorf("Decode(%q) error = %v", tt.contentType, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("Decode(%q) = %+v, want %+v", tt.contentType, got, want)
+		}
+	}
+}
+
+// brokenWriter counts the status lines written and fails every body write,
+// like a connection the client closed.
+type brokenWriter struct {
+	*httptest.ResponseRecorder
+	headers int
+}
+
+func (w *brokenWriter) WriteHeader(status int) {
+	w.headers++
+	w.ResponseRecorder.WriteHeader(status)
+}
+
+func (w *brokenWriter) Write(b []byte) (int, error) {
+	return 0, errors.New("connection closed")
+}
+
+func TestWriteFailureAfterHeader(t *testing.T) {
+	w := &brokenWriter{ResponseRecorder: httptest.NewRecorder()}
+	Write(w, httptest.NewRequest(http.MethodGet, "/cars", nil), http.StatusOK, testCar)
+
+	if w.headers != 1 || w.Code != http.StatusOK || w.Header().Get("Content-Type") != "application/json" {
+		t.Errorf("wrote %d status lines, %d with %q, want only the 200 already sent", w.headers, w.Code, w.Header().Get("Content-Type"))
+	}
+}
@@ -0,0 +1,252 @@
+package codec
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+func encodeJSON(w io.Writer, v any) error {
+	body, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	_, err = w.Write(body)
+	return err
+}
+
+func decodeJSON(body []byte, dst any) error {
+	return json.Unmarshal(body, dst)
+}
+
+// encodeXML writes v as a document whose root is named after its type, e.g.
+// <car>. Lists are wrapped in the plural, e.g. <cars><car>...</car></cars>.
+func encodeXML(w io.Writer, v any) error {
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+
+	encoder := xml.NewEncoder(w)
+	value := reflect.ValueOf(v)
+
+	if value.Kind() == reflect.Slice {
+		name := elementName(value.Type().Elem())
+		root := xml.StartElement{Name: xml.Name{Local: name + "s"}}
+
+		if err := encoder.EncodeToken(root); err != nil {
+			return err
+		}
+		for i := range value.Len() {
+			if err := encoder.EncodeElement(value.Index(i).Interface(), element(name)); err != nil {
+				return err
+			}
+		}
+		if err := encoder.EncodeToken(root.End()); err != nil {
+			return err
+		}
+	} else if err := encoder.EncodeElement(v, element(elementName(value.Type()))); err != nil {
+		return err
+	}
+
+	return encoder.Flush()
+}
+
+func decodeXML(body []byte, dst any) error {
+	return xml.Unmarshal(body, dst)
+}
+
+func element(name string) xml.StartElement {
+	return xml.StartElement{Name: xml.Name{Local: name}}
+}
+
+func elementName(t reflect.Type) string {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	return strings.ToLower(t.Name()[:1]) + t.Name()[1:]
+}
+
+// encodeMessagePack writes v with the same shape as its JSON, so ids and
+// times are strings and the field names are the JSON ones.
+func encodeMessagePack(w io.Writer, v any) error {
+	body, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+
+	var tree any
+	if err := decoder.Decode(&tree); err != nil {
+		return err
+	}
+
+	encoder := msgpack.NewEncoder(w)
+	encoder.SetSortMapKeys(true)
+	return encoder.Encode(numbers(tree))
+}
+
+func decodeMessagePack(body []byte, dst any) error {
+	var tree any
+	if err := msgpack.Unmarshal(body, &tree); err != nil {
+		return err
+	}
+
+	body, err := json.Marshal(tree)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(body, dst)
+}
+
+// numbers replaces the json.Numbers in tree with integers where they are
+// whole, so MessagePack gets its compact integer encoding.
+func numbers(tree any) any {
+	switch v := tree.(type) {
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return i
+		}
+		f, _ := v.Float64()
+		return f
+	case map[string]any:
+		for key, value := range v {
+			v[key] = numbers(value)
+		}
+	case []any:
+		for i, value := range v {
+			v[i] = numbers(value)
+		}
+	}
+
+	return tree
+}
+
+var textMarshaler = reflect.TypeFor[encoding.TextMarshaler]()
+
+// column is a CSV column: the JSON path of a field, with nested objects
+// joined by dots, e.g. engine.engine_id.
+type column struct {
+	name  string
+	index []int
+}
+
+// encodeCSV writes a list of structs as a header row followed by one row
+// per item.
+func encodeCSV(w io.Writer, v any) error {
+	value := reflect.ValueOf(v)
+	if value.Kind() != reflect.Slice {
+		return fmt.Errorf("codec: CSV needs a list, got %T", v)
+	}
+
+	item := value.Type().Elem()
+	if item.Kind() != reflect.Struct {
+		return fmt.Errorf("codec: CSV needs a list of objects, got %T", v)
+	}
+
+	columns := columnsOf(item, "", nil)
+
+	writer := csv.NewWriter(w)
+
+	header := make([]string, len(columns))
+	for i, c := range columns {
+		header[i] = c.name
+	}
+	if err := writer.Write(header); err != nil {
+		return err
+	}
+
+	row := make([]string, len(columns))
+	for i := range value.Len() {
+		for j, c := range columns {
+			cell, err := value.Index(i).FieldByIndexErr(c.index)
+			if err != nil {
+				// a nil pointer on the way leaves the cell empty
+				row[j] = ""
+				continue
+			}
+
+			row[j], err = text(cell)
+			if err != nil {
+				return err
+			}
+		}
+
+		if err := writer.Write(row); err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+func columnsOf(t reflect.Type, prefix string, index []int) []column {
+	var columns []column
+	for i := range t.NumField() {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		fieldIndex := append(append([]int(nil), index...), i)
+
+		fieldType := field.Type
+		if fieldType.Kind() == reflect.Pointer {
+			fieldType = fieldType.Elem()
+		}
+
+		if fieldType.Kind() == reflect.Struct && !reflect.PointerTo(fieldType).Implements(textMarshaler) {
+			columns = append(columns, columnsOf(fieldType, prefix+name+".", fieldIndex)...)
+			continue
+		}
+
+		columns = append(columns, column{name: prefix + name, index: fieldIndex})
+	}
+
+	return columns
+}
+
+func text(v reflect.Value) (string, error) {
+	if v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return "", nil
+		}
+		v = v.Elem()
+	}
+
+	if marshaler, ok := v.Interface().(encoding.TextMarshaler); ok {
+		b, err := marshaler.MarshalText()
+		return string(b), err
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		return v.String(), nil
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(v.Float(), 'f', -1, 64), nil
+	default:
+		return fmt.Sprint(v.Interface()), nil
+	}
+}
@@ -1,14 +1,12 @@
 package engine
 
 import (
-	"encoding/json"
-	"io"
-	"log"
 	"net/http"
 
+	"github.com/geekAshish/DriveDesk/handler/codec"
+	"github.com/geekAshish/DriveDesk/handler/response"
 	"github.com/geekAshish/DriveDesk/models"
 	"github.com/geekAshish/DriveDesk/service"
-	"github.com/google/uuid"
 	"github.com/gorilla/mux"
 	"go.opentelemetry.io/otel"
 )
@@ -34,26 +32,12 @@ func (h *EngineHandler) GetEngineById(w http.ResponseWriter, r *http.Request) {
 
 	res, err := h.service.GetEngineById(ctx, id)
 	if err != nil {
-		w.WriteHeader(http.StatusInternalServerError)
-		log.Println("ERROR: ", err)
+		response.Error(w, r, err)
 		return
 	}
 
-	body, err := json.Marshal(res)
-	if err != nil {
-		w.WriteHeader(http.StatusInternalServerError)
-		log.Println("ERROR: ", err)
-		return
-	}
-
-	w.Header().Set("Content-Type", "applilcation/json")
-	w.WriteHeader(http.StatusOK)
-
-	// write the response body
-	_, err = w.Write(body)
-	if err != nil {
-		log.Println("ERROR: ", err)
-	}
+	response.LastModified(w, res.UpdateAt)
+	codec.Write(w, r, http.StatusOK, res)
 }
 
 func (h *EngineHandler) CreateEngine(w http.ResponseWriter, r *http.Request) {
@@ -62,43 +46,19 @@ func (h *EngineHandler) CreateEngine(w http.ResponseWriter, r *http.Request) {
 	defer span.End()
 	// ctx := r.Context()
 
-	body, err := io.ReadAll(r.Body)
-	if err != nil {
-		log.Println("ERROR: ", err)
-		w.WriteHeader(http.StatusInternalServerError)
-		return
-	}
-
-	var engineReq models.EngineRequest
-	err = json.Unmarshal(body, &engineReq)
-	if err != nil {
-		log.Println("ERROR: ", err)
-		w.WriteHeader(http.StatusInternalServerError)
-		return
-	}
-
-	createdEngine, err := h.service.CreateEngine(ctx, &engineReq)
+	engineReq, err := decodeEngineRequest(r)
 	if err != nil {
-		log.Println("ERROR: ", err)
-		w.WriteHeader(http.StatusInternalServerError)
+		response.Error(w, r, err)
 		return
 	}
 
-	responseBody, err := json.Marshal(createdEngine)
+	createdEngine, err := h.service.CreateEngine(ctx, engineReq)
 	if err != nil {
-		log.Println("ERROR: ", err)
-		w.WriteHeader(http.StatusInternalServerError)
+		response.Error(w, r, err)
 		return
 	}
 
-	w.Header().Set("Content-Type", "applilcation/json")
-	w.WriteHeader(http.StatusCreated)
-
-	// write the response body
-	_, err = w.Write(responseBody)
-	if err != nil {
-		log.Println("ERROR: ", err)
-	}
+	codec.Write(w, r, http.StatusCreated, createdEngine)
 }
 
 func (h *EngineHandler) UpdateEngine(w http.ResponseWriter, r *http.Request) {
@@ -109,43 +69,19 @@ func (h *EngineHandler) UpdateEngine(w http.ResponseWriter, r *http.Request) {
 	params := mux.Vars(r)
 	id := params["id"]
 
-	body, err := io.ReadAll(r.Body)
-	if err != nil {
-		log.Println("ERROR READING REQUEST: ", err)
-		w.WriteHeader(http.StatusInternalServerError)
-		return
-	}
-
-	var engineReq models.EngineRequest
-	err = json.Unmarshal(body, &engineReq)
-	if err != nil {
-		log.Println("ERROR: ", err)
-		w.WriteHeader(http.StatusInternalServerError)
-		return
-	}
-
-	updatedEngine, err := h.service.UpdateEngine(ctx, id, &engineReq)
+	engineReq, err := decodeEngineRequest(r)
 	if err != nil {
-		log.Println("ERROR WHILE UPDATING THE Engine: ", err)
-		w.WriteHeader(http.StatusInternalServerError)
+		response.Error(w, r, err)
 		return
 	}
 
-	responseBody, err := json.Marshal(updatedEngine)
+	updatedEngine, err := h.service.UpdateEngine(ctx, id, engineReq)
 	if err != nil {
-		log.Println("ERROR: ", err)
-		w.WriteHeader(http.StatusInternalServerError)
+		response.Error(w, r, err)
 		return
 	}
 
-	w.Header().Set("Content-Type", "applilcation/json")
-	w.WriteHeader(http.StatusOK)
-
-	// write the response body
-	_, err = w.Write(responseBody)
-	if err != nil {
-		log.Println("ERROR: ", err)
-	}
+	codec.Write(w, r, http.StatusOK, updatedEngine)
 }
 
 func (h *EngineHandler) DeleteEngine(w http.ResponseWriter, r *http.Request) {
@@ -158,39 +94,18 @@ func (h *EngineHandler) DeleteEngine(w http.ResponseWriter, r *http.Request) {
 
 	deletedEngine, err := h.service.DeleteEngine(ctx, id)
 	if err != nil {
-		log.Println("error deleting engine : ", err)
-		w.WriteHeader(http.StatusInternalServerError)
-		response := map[string]string{"error": "Invalid ID or Engine not available"}
-		jsonResponse, _ := json.Marshal(response)
-		_, _ = w.Write(jsonResponse)
+		response.Error(w, r, err)
 		return
 	}
 
-	// check if engine was deleted successfully
-	if deletedEngine.EngineID == uuid.Nil {
-		w.WriteHeader(http.StatusNotFound)
-		response := map[string]string{"error": "Engine not found"}
-		jsonResponse, _ := json.Marshal(response)
-		_, _ = w.Write(jsonResponse)
-		return
-	}
+	codec.Write(w, r, http.StatusOK, deletedEngine)
+}
 
-	responseBody, err := json.Marshal(deletedEngine)
-	if err != nil {
-		log.Println("Error while marshalling deleted engine", err)
-		w.WriteHeader(http.StatusInternalServerError)
-		response := map[string]string{"error": "Internal server error"}
-		jsonResponse, _ := json.Marshal(response)
-		_, _ = w.Write(jsonResponse)
-		return
+func decodeEngineRequest(r *http.Request) (*models.EngineRequest, error) {
+	var engineReq models.EngineRequest
+	if err := codec.Decode(r, &engineReq, "engine"); err != nil {
+		return nil, err
 	}
 
-	w.Header().Set("Content-Type", "applilcation/json")
-	w.WriteHeader(http.StatusOK)
-
-	// write the response body
-	_, err = w.Write(responseBody)
-	if err != nil {
-		log.Println("ERROR: ", err)
-	}
+	return &engineReq, nil
 }
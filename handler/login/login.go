@@ -6,41 +6,57 @@ import (
 	"net/http"
 	"time"
 
-	"github.com/geekAshish/DriveDesk/models"
+	"github.com/geekAshish/DriveDesk/apperrors"
+	"github.com/geekAshish/DriveDesk/handler/response"
 	"github.com/geekAshish/DriveDesk/middleware"
+	"github.com/geekAshish/DriveDesk/models"
 	"github.com/golang-jwt/jwt/v5"
 )
 
+// TokenResponse is the body of a successful login.
+type TokenResponse struct {
+	Token string `json:"token"`
+}
+
 func LoginHandler(w http.ResponseWriter, r *http.Request) {
 	var credentials models.Credentials
 	if err := json.NewDecoder(r.Body).Decode(&credentials); err != nil {
-		http.Error(w, "Invalid body", http.StatusBadRequest)
+		response.Error(w, r, apperrors.Wrap(apperrors.ErrBadRequest, err, "Invalid body"))
 		return
 	}
 
+	tokenString, err := Login(credentials)
+	if err != nil {
+		response.Error(w, r, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, TokenResponse{Token: tokenString})
+}
+
+// Login checks the credentials and returns a token for them. The gRPC
+// AuthService logs in the same way.
+func Login(credentials models.Credentials) (string, error) {
 	valid := (credentials.UserName == "admin" && credentials.Password == "admin123")
 
 	if !valid {
-		http.Error(w, "Incorrect user name password", http.StatusUnauthorized)
-		return
+		return "", apperrors.Unauthorized("Incorrect user name password")
 	}
 
-	tokenString, err := GenerateToken(credentials.UserName)
+	tokenString, err := GenerateToken(credentials.UserName, middleware.RoleAdmin)
 	if err != nil {
-		http.Error(w, "Unable to generate token", http.StatusInternalServerError)
-		fmt.Println("Unable to generate token", err)
+		return "", fmt.Errorf("unable to generate token: %w", err)
 	}
 
-	response := map[string]string{"token": tokenString}
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(response)
+	return tokenString, nil
 }
 
-func GenerateToken(userName string) (string, error) {
+func GenerateToken(userName, role string) (string, error) {
 	expiration := time.Now().Add(24 * time.Hour)
 
 	claims := &middleware.Claims{
 		UserName: userName,
+		Role:     role,
 		RegisteredClaims: jwt.RegisteredClaims{
 			ExpiresAt: jwt.NewNumericDate(expiration),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
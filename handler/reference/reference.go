@@ -0,0 +1,126 @@
+package reference
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+
+	"github.com/geekAshish/DriveDesk/apperrors"
+	"github.com/geekAshish/DriveDesk/handler/response"
+	"github.com/geekAshish/DriveDesk/models"
+	"github.com/geekAshish/DriveDesk/service"
+	"github.com/gorilla/mux"
+	"go.opentelemetry.io/otel"
+)
+
+type ReferenceHandler struct {
+	service service.ReferenceServiceInterface
+}
+
+func NewReferenceHandler(service service.ReferenceServiceInterface) *ReferenceHandler {
+	return &ReferenceHandler{
+		service: service,
+	}
+}
+
+func (h *ReferenceHandler) ListReferenceValues(w http.ResponseWriter, r *http.Request) {
+	tracer := otel.Tracer("ReferenceHandler")
+	ctx, span := tracer.Start(r.Context(), "ListReferenceValues-Handler")
+	defer span.End()
+
+	values, err := h.service.ListReferenceValues(ctx, mux.Vars(r)["kind"])
+	if err != nil {
+		response.Error(w, r, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, values)
+}
+
+func (h *ReferenceHandler) GetReferenceValue(w http.ResponseWriter, r *http.Request) {
+	tracer := otel.Tracer("ReferenceHandler")
+	ctx, span := tracer.Start(r.Context(), "GetReferenceValue-Handler")
+	defer span.End()
+
+	vars := mux.Vars(r)
+
+	value, err := h.service.GetReferenceValue(ctx, vars["kind"], vars["code"])
+	if err != nil {
+		response.Error(w, r, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, value)
+}
+
+func (h *ReferenceHandler) CreateReferenceValue(w http.ResponseWriter, r *http.Request) {
+	tracer := otel.Tracer("ReferenceHandler")
+	ctx, span := tracer.Start(r.Context(), "CreateReferenceValue-Handler")
+	defer span.End()
+
+	req, err := decodeReferenceValueRequest(r)
+	if err != nil {
+		response.Error(w, r, err)
+		return
+	}
+
+	created, err := h.service.CreateReferenceValue(ctx, mux.Vars(r)["kind"], req)
+	if err != nil {
+		response.Error(w, r, err)
+		return
+	}
+
+	response.JSON(w, http.StatusCreated, created)
+}
+
+func (h *ReferenceHandler) UpdateReferenceValue(w http.ResponseWriter, r *http.Request) {
+	tracer := otel.Tracer("ReferenceHandler")
+	ctx, span := tracer.Start(r.Context(), "UpdateReferenceValue-Handler")
+	defer span.End()
+
+	vars := mux.Vars(r)
+
+	req, err := decodeReferenceValueRequest(r)
+	if err != nil {
+		response.Error(w, r, err)
+		return
+	}
+
+	updated, err := h.service.UpdateReferenceValue(ctx, vars["kind"], vars["code"], req)
+	if err != nil {
+		response.Error(w, r, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, updated)
+}
+
+func (h *ReferenceHandler) DeleteReferenceValue(w http.ResponseWriter, r *http.Request) {
+	tracer := otel.Tracer("ReferenceHandler")
+	ctx, span := tracer.Start(r.Context(), "DeleteReferenceValue-Handler")
+	defer span.End()
+
+	vars := mux.Vars(r)
+
+	deleted, err := h.service.DeleteReferenceValue(ctx, vars["kind"], vars["code"])
+	if err != nil {
+		response.Error(w, r, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, deleted)
+}
+
+func decodeReferenceValueRequest(r *http.Request) (*models.ReferenceValueRequest, error) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return nil, apperrors.Wrap(apperrors.ErrBadRequest, err, "could not read request body")
+	}
+
+	var req models.ReferenceValueRequest
+	if err := json.Unmarshal(body, &req); err != nil {
+		return nil, apperrors.Wrap(apperrors.ErrBadRequest, err, "request body is not a valid reference value: %v", err)
+	}
+
+	return &req, nil
+}
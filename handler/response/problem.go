@@ -0,0 +1,125 @@
+package response
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/geekAshish/DriveDesk/apperrors"
+)
+
+const ProblemContentType = "application/problem+json"
+
+// Problem is an RFC 7807 problem details object.
+type Problem struct {
+	Type      string                 `json:"type"`
+	Title     string                 `json:"title"`
+	Status    int                    `json:"status"`
+	Detail    string                 `json:"detail,omitempty"`
+	Instance  string                 `json:"instance,omitempty"`
+	RequestID string                 `json:"request_id,omitempty"`
+	Errors    []apperrors.FieldError `json:"errors,omitempty"`
+}
+
+// problem types, relative to the API root
+const (
+	TypeBadRequest       = "/problems/bad-request"
+	TypeUnauthorized     = "/problems/unauthorized"
+	TypeForbidden        = "/problems/forbidden"
+	TypeNotFound         = "/problems/not-found"
+	TypeMethodNotAllowed = "/problems/method-not-allowed"
+	TypeConflict         = "/problems/conflict"
+	TypeValidation       = "/problems/validation"
+	TypeNotAcceptable    = "/problems/not-acceptable"
+	TypeUnsupportedMedia = "/problems/unsupported-media-type"
+	TypeTooManyRequests  = "/problems/too-many-requests"
+	TypeInternal         = "/problems/internal"
+)
+
+// NewProblem builds the problem describing err for the request r. Errors of
+// unknown kind are logged and reported without any detail.
+func NewProblem(r *http.Request, err error) Problem {
+	status := StatusCode(err)
+
+	problem := Problem{
+		Type:     problemType(err),
+		Title:    http.StatusText(status),
+		Status:   status,
+		Detail:   apperrors.Message(err),
+		Instance: r.URL.Path,
+		Errors:   apperrors.Fields(err),
+	}
+
+	if status == http.StatusInternalServerError {
+		log.Println("ERROR: ", err)
+		problem.Detail = ""
+	}
+
+	return problem
+}
+
+// WriteProblem writes problem as application/problem+json. The request id is
+// taken from the X-Request-ID response header set by the request id
+// middleware.
+func WriteProblem(w http.ResponseWriter, problem Problem) {
+	if problem.RequestID == "" {
+		problem.RequestID = w.Header().Get("X-Request-ID")
+	}
+
+	body, err := json.Marshal(problem)
+	if err != nil {
+		log.Println("ERROR: ", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", ProblemContentType)
+	w.WriteHeader(problem.Status)
+
+	_, err = w.Write(body)
+	if err != nil {
+		log.Println("ERROR: ", err)
+	}
+}
+
+// NotFound answers requests that match no route.
+func NotFound(w http.ResponseWriter, r *http.Request) {
+	Error(w, r, apperrors.NotFound("no route for %s", r.URL.Path))
+}
+
+// MethodNotAllowed answers requests to a known route with an unsupported method.
+func MethodNotAllowed(w http.ResponseWriter, r *http.Request) {
+	WriteProblem(w, Problem{
+		Type:     TypeMethodNotAllowed,
+		Title:    http.StatusText(http.StatusMethodNotAllowed),
+		Status:   http.StatusMethodNotAllowed,
+		Detail:   r.Method + " is not supported on " + r.URL.Path,
+		Instance: r.URL.Path,
+	})
+}
+
+func problemType(err error) string {
+	switch {
+	case errors.Is(err, apperrors.ErrBadRequest):
+		return TypeBadRequest
+	case errors.Is(err, apperrors.ErrUnauthorized):
+		return TypeUnauthorized
+	case errors.Is(err, apperrors.ErrForbidden):
+		return TypeForbidden
+	case errors.Is(err, apperrors.ErrNotFound):
+		return TypeNotFound
+	case errors.Is(err, apperrors.ErrConflict):
+		return TypeConflict
+	case errors.Is(err, apperrors.ErrValidation):
+		return TypeValidation
+	case errors.Is(err, apperrors.ErrNotAcceptable):
+		return TypeNotAcceptable
+	case errors.Is(err, apperrors.ErrUnsupportedMediaType):
+		return TypeUnsupportedMedia
+	case errors.Is(err, apperrors.ErrTooManyRequests):
+		return TypeTooManyRequests
+	default:
+		return TypeInternal
+	}
+}
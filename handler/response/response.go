@@ -0,0 +1,72 @@
+// Package response writes handler results and maps apperrors kinds to HTTP
+// status codes and problem details in one place.
+package response
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/geekAshish/DriveDesk/apperrors"
+)
+
+// JSON writes body as JSON with the given status code.
+func JSON(w http.ResponseWriter, status int, body any) {
+	responseBody, err := json.Marshal(body)
+	if err != nil {
+		log.Println("ERROR: ", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+
+	// write the response body
+	_, err = w.Write(responseBody)
+	if err != nil {
+		log.Println("ERROR: ", err)
+	}
+}
+
+// LastModified sets the Last-Modified header to t, the updated_at of what is
+// returned, or leaves it out when t is zero.
+func LastModified(w http.ResponseWriter, t time.Time) {
+	if !t.IsZero() {
+		w.Header().Set("Last-Modified", t.UTC().Format(http.TimeFormat))
+	}
+}
+
+// Error answers with an RFC 7807 problem whose status matches the kind of
+// err.
+func Error(w http.ResponseWriter, r *http.Request, err error) {
+	WriteProblem(w, NewProblem(r, err))
+}
+
+// StatusCode returns the HTTP status for err.
+func StatusCode(err error) int {
+	switch {
+	case errors.Is(err, apperrors.ErrBadRequest):
+		return http.StatusBadRequest
+	case errors.Is(err, apperrors.ErrUnauthorized):
+		return http.StatusUnauthorized
+	case errors.Is(err, apperrors.ErrForbidden):
+		return http.StatusForbidden
+	case errors.Is(err, apperrors.ErrNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, apperrors.ErrConflict):
+		return http.StatusConflict
+	case errors.Is(err, apperrors.ErrValidation):
+		return http.StatusUnprocessableEntity
+	case errors.Is(err, apperrors.ErrNotAcceptable):
+		return http.StatusNotAcceptable
+	case errors.Is(err, apperrors.ErrUnsupportedMediaType):
+		return http.StatusUnsupportedMediaType
+	case errors.Is(err, apperrors.ErrTooManyRequests):
+		return http.StatusTooManyRequests
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,85 @@
+package response
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/geekAshish/DriveDesk/apperrors"
+)
+
+func TestStatusCode(t *testing.T) {
+	tests := []struct {
+		err  error
+		want int
+	}{
+		{apperrors.BadRequest("bad body"), http.StatusBadRequest},
+		{apperrors.Unauthorized("no token"), http.StatusUnauthorized},
+		{apperrors.Forbidden("admins only"), http.StatusForbidden},
+		{apperrors.NotFound("car not found"), http.StatusNotFound},
+		{apperrors.Conflict("duplicate"), http.StatusConflict},
+		{apperrors.Validation("name is required"), http.StatusUnprocessableEntity},
+		{apperrors.NotAcceptable("text/html"), http.StatusNotAcceptable},
+		{apperrors.UnsupportedMediaType("text/plain"), http.StatusUnsupportedMediaType},
+		{apperrors.TooManyRequests("slow down"), http.StatusTooManyRequests},
+		{fmt.Errorf("service: %w", apperrors.NotFound("car not found")), http.StatusNotFound},
+		{errors.New("connection refused"), http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		if got := StatusCode(tt.err); got != tt.want {
+			t.Errorf("StatusCode(%v) = %d, want %d", tt.err, got, tt.want)
+		}
+	}
+}
+
+func TestWriteProblem(t *testing.T) {
+	r := httptest.NewRequest(http.MethodPost, "/cars", nil)
+	w := httptest.NewRecorder()
+	w.Header().Set("X-Request-ID", "req-1")
+
+	Error(w, r, apperrors.InvalidField("engine.car_range", "must_be_positive", "car range must be greater than zero"))
+
+	if got := w.Header().Get("Content-Type"); got != ProblemContentType {
+		t.Fatalf("Content-Type = %q, want %q", got, ProblemContentType)
+	}
+
+	var problem Problem
+	if err := json.Unmarshal(w.Body.Bytes(), &problem); err != nil {
+		t.Fatalf("decoding problem: %v", err)
+	}
+
+	want := Problem{
+		Type:      TypeValidation,
+		Title:     "Unprocessable Entity",
+		Status:    http.StatusUnprocessableEntity,
+		Detail:    "car range must be greater than zero",
+		Instance:  "/cars",
+		RequestID: "req-1",
+		Errors: []apperrors.FieldError{
+			{Field: "engine.car_range", Code: "must_be_positive", Message: "car range must be greater than zero"},
+		},
+	}
+	if !reflect.DeepEqual(problem, want) {
+		t.Fatalf("problem = %+v, want %+v", problem, want)
+	}
+}
+
+func TestWriteProblemHidesInternalErrors(t *testing.T) {
+	r := httptest.NewRequest(http.MethodGet, "/cars/1", nil)
+	w := httptest.NewRecorder()
+
+	Error(w, r, errors.New("pq: password authentication failed"))
+
+	if w.Code != http.StatusInternalServerError {
+		t.Fatalf("status = %d, want 500", w.Code)
+	}
+	if strings.Contains(w.Body.String(), "password") {
+		t.Fatalf("internal error leaked to the client: %s", w.Body.String())
+	}
+}
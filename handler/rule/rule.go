@@ -0,0 +1,134 @@
+package rule
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/geekAshish/DriveDesk/apperrors"
+	"github.com/geekAshish/DriveDesk/handler/response"
+	"github.com/geekAshish/DriveDesk/models"
+	"github.com/geekAshish/DriveDesk/service"
+	"github.com/gorilla/mux"
+	"go.opentelemetry.io/otel"
+)
+
+type RuleHandler struct {
+	service service.ValidationRuleServiceInterface
+	cars    service.CarServiceInterface
+	engines service.EngineServiceInterface
+}
+
+func NewRuleHandler(service service.ValidationRuleServiceInterface, cars service.CarServiceInterface, engines service.EngineServiceInterface) *RuleHandler {
+	return &RuleHandler{
+		service: service,
+		cars:    cars,
+		engines: engines,
+	}
+}
+
+// TestResult is the outcome of checking a payload against the active rules.
+type TestResult struct {
+	Valid  bool                   `json:"valid"`
+	Errors []apperrors.FieldError `json:"errors"`
+}
+
+func (h *RuleHandler) ListValidationRules(w http.ResponseWriter, r *http.Request) {
+	tracer := otel.Tracer("RuleHandler")
+	ctx, span := tracer.Start(r.Context(), "ListValidationRules-Handler")
+	defer span.End()
+
+	rules, err := h.service.ListValidationRules(ctx)
+	if err != nil {
+		response.Error(w, r, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, rules)
+}
+
+func (h *RuleHandler) CreateValidationRule(w http.ResponseWriter, r *http.Request) {
+	tracer := otel.Tracer("RuleHandler")
+	ctx, span := tracer.Start(r.Context(), "CreateValidationRule-Handler")
+	defer span.End()
+
+	var req models.ValidationRuleRequest
+	if err := decode(r, &req, "validation rule"); err != nil {
+		response.Error(w, r, err)
+		return
+	}
+
+	created, err := h.service.CreateValidationRule(ctx, &req)
+	if err != nil {
+		response.Error(w, r, err)
+		return
+	}
+
+	response.JSON(w, http.StatusCreated, created)
+}
+
+func (h *RuleHandler) DeleteValidationRule(w http.ResponseWriter, r *http.Request) {
+	tracer := otel.Tracer("RuleHandler")
+	ctx, span := tracer.Start(r.Context(), "DeleteValidationRule-Handler")
+	defer span.End()
+
+	deleted, err := h.service.DeleteValidationRule(ctx, mux.Vars(r)["id"])
+	if err != nil {
+		response.Error(w, r, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, deleted)
+}
+
+// TestPayload checks a car or engine request against the built-in and the
+// active deployment rules without storing anything. A payload that breaks
+// rules is still a successful test, so it answers 200 with the violations.
+func (h *RuleHandler) TestPayload(w http.ResponseWriter, r *http.Request) {
+	tracer := otel.Tracer("RuleHandler")
+	ctx, span := tracer.Start(r.Context(), "TestPayload-Handler")
+	defer span.End()
+
+	var err error
+
+	switch target := mux.Vars(r)["target"]; target {
+	case models.RuleTargetCar:
+		var car models.CarRequest
+		if err = decode(r, &car, "car"); err == nil {
+			err = h.cars.ValidateCar(ctx, &car)
+		}
+	case models.RuleTargetEngine:
+		var engine models.EngineRequest
+		if err = decode(r, &engine, "engine"); err == nil {
+			err = h.engines.ValidateEngine(ctx, &engine)
+		}
+	default:
+		err = apperrors.NotFound("no validation target %s, expected car or engine", target)
+	}
+
+	if err != nil && !errors.Is(err, apperrors.ErrValidation) {
+		response.Error(w, r, err)
+		return
+	}
+
+	fields := apperrors.Fields(err)
+	if fields == nil {
+		fields = []apperrors.FieldError{}
+	}
+
+	response.JSON(w, http.StatusOK, TestResult{Valid: err == nil, Errors: fields})
+}
+
+func decode(r *http.Request, dst any, what string) error {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return apperrors.Wrap(apperrors.ErrBadRequest, err, "could not read request body")
+	}
+
+	if err := json.Unmarshal(body, dst); err != nil {
+		return apperrors.Wrap(apperrors.ErrBadRequest, err, "request body is not a valid %s: %v", what, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,200 @@
+package stream
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/geekAshish/DriveDesk/apperrors"
+	"github.com/geekAshish/DriveDesk/handler/response"
+	"github.com/geekAshish/DriveDesk/models"
+	"github.com/geekAshish/DriveDesk/service"
+	"github.com/gorilla/websocket"
+	"go.opentelemetry.io/otel"
+)
+
+const (
+	// LastEventIDHeader is sent by reconnecting EventSource clients.
+	LastEventIDHeader = "Last-Event-ID"
+
+	// lastEventIDParam does the same for clients that cannot set headers,
+	// such as browser WebSockets.
+	lastEventIDParam = "last_event_id"
+
+	// keepAlive is how often an idle stream sends something, so proxies do
+	// not close it.
+	keepAlive = 15 * time.Second
+
+	// reconnectDelay is how long EventSource clients wait before
+	// reconnecting.
+	reconnectDelay = 3 * time.Second
+
+	// writeTimeout bounds a single WebSocket write to a stuck client.
+	writeTimeout = 10 * time.Second
+)
+
+var upgrader = websocket.Upgrader{
+	// the API authenticates with bearer tokens rather than cookies, so a
+	// page from another origin gains nothing it could not do with fetch
+	CheckOrigin: func(r *http.Request) bool { return true },
+}
+
+type StreamHandler struct {
+	stream service.ChangeStream
+}
+
+func NewStreamHandler(stream service.ChangeStream) *StreamHandler {
+	return &StreamHandler{stream: stream}
+}
+
+// Events streams the changes as Server-Sent Events. Each event has the
+// sequence number as its id, the event type as its name and the
+// ChangeEvent as JSON data.
+func (h *StreamHandler) Events(w http.ResponseWriter, r *http.Request) {
+	tracer := otel.Tracer("StreamHandler")
+	ctx, span := tracer.Start(r.Context(), "Events-Handler")
+	defer span.End()
+
+	sub, err := h.subscribe(ctx, r)
+	if err != nil {
+		response.Error(w, r, err)
+		return
+	}
+	defer sub.Close()
+
+	controller := http.NewResponseController(w)
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	fmt.Fprintf(w, "retry: %d\n\n", reconnectDelay.Milliseconds())
+	if err := controller.Flush(); err != nil {
+		log.Println("ERROR: ", err)
+		return
+	}
+
+	ticker := time.NewTicker(keepAlive)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-ticker.C:
+			fmt.Fprint(w, ": keep-alive\n\n")
+		case event, ok := <-sub.Events():
+			if !ok {
+				// fell behind, the client reconnects from its last event
+				return
+			}
+
+			data, err := json.Marshal(event)
+			if err != nil {
+				log.Println("ERROR: ", err)
+				return
+			}
+			fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", event.Seq, event.Type, data)
+		}
+
+		if err := controller.Flush(); err != nil {
+			return
+		}
+	}
+}
+
+// WebSocket streams the changes over a WebSocket, one ChangeEvent as a JSON
+// text message each. Messages from the client are ignored.
+func (h *StreamHandler) WebSocket(w http.ResponseWriter, r *http.Request) {
+	tracer := otel.Tracer("StreamHandler")
+	ctx, span := tracer.Start(r.Context(), "WebSocket-Handler")
+	defer span.End()
+
+	sub, err := h.subscribe(ctx, r)
+	if err != nil {
+		response.Error(w, r, err)
+		return
+	}
+	defer sub.Close()
+
+	// Upgrade answers failed handshakes itself
+	conn, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+
+	// reading handles pings and notices the client going away
+	closed := make(chan struct{})
+	go func() {
+		defer close(closed)
+		for {
+			if _, _, err := conn.NextReader(); err != nil {
+				return
+			}
+		}
+	}()
+
+	ticker := time.NewTicker(keepAlive)
+	defer ticker.Stop()
+
+	for {
+		var err error
+
+		select {
+		case <-closed:
+			return
+		case <-ticker.C:
+			err = conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(writeTimeout))
+		case event, ok := <-sub.Events():
+			if !ok {
+				conn.WriteControl(websocket.CloseMessage,
+					websocket.FormatCloseMessage(websocket.CloseTryAgainLater, "fell behind, reconnect from the last event"),
+					time.Now().Add(writeTimeout))
+				return
+			}
+
+			conn.SetWriteDeadline(time.Now().Add(writeTimeout))
+			err = conn.WriteJSON(event)
+		}
+
+		if err != nil {
+			return
+		}
+	}
+}
+
+// subscribe subscribes to the events the request asks for: those of the
+// entity and brand query parameters, after the id in Last-Event-ID or the
+// last_event_id parameter when given.
+func (h *StreamHandler) subscribe(ctx context.Context, r *http.Request) (service.Subscription, error) {
+	query := r.URL.Query()
+
+	filter := models.EventFilter{
+		Entity: query.Get("entity"),
+		Brand:  query.Get("brand"),
+	}
+	if err := models.ValidateEventFilter(filter); err != nil {
+		return nil, err
+	}
+
+	lastSeq := int64(-1)
+
+	lastEventID := r.Header.Get(LastEventIDHeader)
+	if lastEventID == "" {
+		lastEventID = query.Get(lastEventIDParam)
+	}
+	if lastEventID != "" {
+		var err error
+		if lastSeq, err = strconv.ParseInt(lastEventID, 10, 64); err != nil || lastSeq < 0 {
+			return nil, apperrors.BadRequest("%s must be the id of an event", LastEventIDHeader)
+		}
+	}
+
+	return h.stream.Subscribe(ctx, filter, lastSeq)
+}
@@ -0,0 +1,160 @@
+package stream
+
+import (
+	"bufio"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/geekAshish/DriveDesk/models"
+	"github.com/geekAshish/DriveDesk/store/memory"
+	"github.com/google/uuid"
+	"github.com/gorilla/websocket"
+
+	streamService "github.com/geekAshish/DriveDesk/service/stream"
+)
+
+type fixture struct {
+	hub    *streamService.Hub
+	server *httptest.Server
+}
+
+func newFixture(t *testing.T) *fixture {
+	t.Helper()
+
+	hub := streamService.NewHub(memory.NewEventStore(memory.NewDB()))
+	if err := hub.Start(context.Background()); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go hub.Run(ctx, 10*time.Millisecond)
+
+	handler := NewStreamHandler(hub)
+	mux := http.NewServeMux()
+	mux.HandleFunc("/events", handler.Events)
+	mux.HandleFunc("/events/ws", handler.WebSocket)
+
+	server := httptest.NewServer(mux)
+	t.Cleanup(func() {
+		cancel()
+		server.Close()
+	})
+
+	return &fixture{hub: hub, server: server}
+}
+
+func (f *fixture) publishCar(t *testing.T, brand string) {
+	t.Helper()
+
+	car := models.Car{ID: uuid.New(), Brand: brand}
+	if err := f.hub.Publish(context.Background(), models.NewEvent(models.EventCarCreated, car.ID.String(), car)); err != nil {
+		t.Fatalf("Publish: %v", err)
+	}
+}
+
+// readEvent reads the next event of an SSE stream, skipping comments and
+// the retry field.
+func readEvent(t *testing.T, reader *bufio.Reader) map[string]string {
+	t.Helper()
+
+	event := map[string]string{}
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			t.Fatalf("reading the stream: %v", err)
+		}
+
+		line = strings.TrimSuffix(line, "\n")
+		if line == "" {
+			if event["id"] != "" {
+				return event
+			}
+			continue
+		}
+
+		field, value, _ := strings.Cut(line, ": ")
+		event[field] = value
+	}
+}
+
+func TestEventsResume(t *testing.T) {
+	f := newFixture(t)
+
+	f.publishCar(t, "Honda")
+	f.publishCar(t, "Toyota")
+	f.publishCar(t, "Honda")
+	time.Sleep(50 * time.Millisecond)
+
+	req, _ := http.NewRequest(http.MethodGet, f.server.URL+"/events?brand=Honda", nil)
+	req.Header.Set(LastEventIDHeader, "1")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	if got := resp.Header.Get("Content-Type"); got != "text/event-stream" {
+		t.Fatalf("Content-Type = %q", got)
+	}
+
+	reader := bufio.NewReader(resp.Body)
+
+	// the backlog after event 1 holds one Honda
+	event := readEvent(t, reader)
+	if event["id"] != "3" || event["event"] != models.EventCarCreated || !strings.Contains(event["data"], `"brand":"Honda"`) {
+		t.Errorf("first event = %v, want Honda event 3", event)
+	}
+
+	f.publishCar(t, "Toyota")
+	f.publishCar(t, "Honda")
+
+	if event := readEvent(t, reader); event["id"] != "5" {
+		t.Errorf("live event = %v, want Honda event 5", event)
+	}
+}
+
+func TestEventsRejectsBadQuery(t *testing.T) {
+	f := newFixture(t)
+
+	for _, url := range []string{"/events?entity=truck", "/events?last_event_id=abc"} {
+		resp, err := http.Get(f.server.URL + url)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+
+		if resp.StatusCode == http.StatusOK {
+			t.Errorf("GET %s = 200, want an error", url)
+		}
+	}
+}
+
+func TestWebSocket(t *testing.T) {
+	f := newFixture(t)
+
+	url := "ws" + strings.TrimPrefix(f.server.URL, "http") + "/events/ws?entity=car"
+	conn, _, err := websocket.DefaultDialer.Dial(url, nil)
+	if err != nil {
+		t.Fatalf("Dial: %v", err)
+	}
+	defer conn.Close()
+
+	// let the subscription start before publishing
+	time.Sleep(20 * time.Millisecond)
+	f.publishCar(t, "Honda")
+
+	conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+
+	var event models.ChangeEvent
+	if err := conn.ReadJSON(&event); err != nil {
+		t.Fatalf("ReadJSON: %v", err)
+	}
+	if event.Seq != 1 || event.Type != models.EventCarCreated || event.Brand != "Honda" {
+		t.Errorf("event = %+v, want the Honda car", event)
+	}
+}
@@ -0,0 +1,78 @@
+package sync
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/geekAshish/DriveDesk/apperrors"
+	"github.com/geekAshish/DriveDesk/handler/response"
+	"github.com/geekAshish/DriveDesk/models"
+	"github.com/geekAshish/DriveDesk/service"
+	"go.opentelemetry.io/otel"
+)
+
+type SyncHandler struct {
+	service service.SyncServiceInterface
+}
+
+func NewSyncHandler(service service.SyncServiceInterface) *SyncHandler {
+	return &SyncHandler{service: service}
+}
+
+func (h *SyncHandler) Pull(w http.ResponseWriter, r *http.Request) {
+	tracer := otel.Tracer("SyncHandler")
+	ctx, span := tracer.Start(r.Context(), "Pull-Handler")
+	defer span.End()
+
+	query := r.URL.Query()
+
+	since, err := models.ParseSyncToken(query.Get("since"))
+	if err != nil {
+		response.Error(w, r, err)
+		return
+	}
+
+	limit := models.DefaultSyncLimit
+	if value := query.Get("limit"); value != "" {
+		if limit, err = strconv.Atoi(value); err != nil {
+			response.Error(w, r, apperrors.InvalidField("limit", "invalid_format", "limit must be a whole number"))
+			return
+		}
+	}
+
+	page, err := h.service.Pull(ctx, since, limit)
+	if err != nil {
+		response.Error(w, r, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, page)
+}
+
+func (h *SyncHandler) Push(w http.ResponseWriter, r *http.Request) {
+	tracer := otel.Tracer("SyncHandler")
+	ctx, span := tracer.Start(r.Context(), "Push-Handler")
+	defer span.End()
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		response.Error(w, r, apperrors.Wrap(apperrors.ErrBadRequest, err, "could not read request body"))
+		return
+	}
+
+	var req models.SyncPushRequest
+	if err := json.Unmarshal(body, &req); err != nil {
+		response.Error(w, r, apperrors.Wrap(apperrors.ErrBadRequest, err, "request body is not a valid sync push: %v", err))
+		return
+	}
+
+	resp, err := h.service.Push(ctx, &req)
+	if err != nil {
+		response.Error(w, r, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, resp)
+}
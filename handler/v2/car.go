@@ -0,0 +1,109 @@
+package v2
+
+import (
+	"net/http"
+
+	"github.com/geekAshish/DriveDesk/handler/codec"
+	"github.com/geekAshish/DriveDesk/handler/response"
+	"github.com/geekAshish/DriveDesk/service"
+	"github.com/gorilla/mux"
+	"go.opentelemetry.io/otel"
+)
+
+type CarHandler struct {
+	service service.CarServiceInterface
+}
+
+func NewCarHandler(service service.CarServiceInterface) *CarHandler {
+	return &CarHandler{
+		service: service,
+	}
+}
+
+func (h *CarHandler) GetCarById(w http.ResponseWriter, r *http.Request) {
+	tracer := otel.Tracer("CarHandlerV2")
+	ctx, span := tracer.Start(r.Context(), "GetCarById-HandlerV2")
+	defer span.End()
+
+	res, err := h.service.GetCarById(ctx, mux.Vars(r)["id"])
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	response.LastModified(w, res.LastModified())
+	codec.Write(w, r, http.StatusOK, NewCar(*res))
+}
+
+func (h *CarHandler) GetCarByBrand(w http.ResponseWriter, r *http.Request) {
+	tracer := otel.Tracer("CarHandlerV2")
+	ctx, span := tracer.Start(r.Context(), "GetCarByBrand-HandlerV2")
+	defer span.End()
+
+	brand := r.URL.Query().Get("brand")
+	isEngine := r.URL.Query().Get("isEngine") == "true"
+
+	res, err := h.service.GetCarByBrand(ctx, brand, isEngine)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	codec.Write(w, r, http.StatusOK, NewCars(res))
+}
+
+func (h *CarHandler) CreateCar(w http.ResponseWriter, r *http.Request) {
+	tracer := otel.Tracer("CarHandlerV2")
+	ctx, span := tracer.Start(r.Context(), "CreateCar-HandlerV2")
+	defer span.End()
+
+	var carReq CarRequest
+	if err := codec.Decode(r, &carReq, "car"); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	req := carReq.Model()
+	createdCar, err := h.service.CreateCar(ctx, &req)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	codec.Write(w, r, http.StatusCreated, NewCar(*createdCar))
+}
+
+func (h *CarHandler) UpdateCar(w http.ResponseWriter, r *http.Request) {
+	tracer := otel.Tracer("CarHandlerV2")
+	ctx, span := tracer.Start(r.Context(), "UpdateCar-HandlerV2")
+	defer span.End()
+
+	var carReq CarRequest
+	if err := codec.Decode(r, &carReq, "car"); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	req := carReq.Model()
+	updatedCar, err := h.service.UpdateCar(ctx, mux.Vars(r)["id"], &req)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	codec.Write(w, r, http.StatusOK, NewCar(*updatedCar))
+}
+
+func (h *CarHandler) DeleteCar(w http.ResponseWriter, r *http.Request) {
+	tracer := otel.Tracer("CarHandlerV2")
+	ctx, span := tracer.Start(r.Context(), "DeleteCar-HandlerV2")
+	defer span.End()
+
+	deletedCar, err := h.service.DeleteCar(ctx, mux.Vars(r)["id"])
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	codec.Write(w, r, http.StatusOK, NewCar(*deletedCar))
+}
@@ -0,0 +1,6 @@
+// Package v2 serves the second version of the car and engine API. It keeps
+// the services of v1 but corrects the wire format: the year is a number, the
+// number of cylinders an integer and the engine displacement is spelled
+// "displacement". Requests and responses are converted to and from models at
+// the handler boundary, so the stores and services stay version-agnostic.
+package v2
@@ -0,0 +1,92 @@
+package v2
+
+import (
+	"net/http"
+
+	"github.com/geekAshish/DriveDesk/handler/codec"
+	"github.com/geekAshish/DriveDesk/handler/response"
+	"github.com/geekAshish/DriveDesk/service"
+	"github.com/gorilla/mux"
+	"go.opentelemetry.io/otel"
+)
+
+type EngineHandler struct {
+	service service.EngineServiceInterface
+}
+
+func NewEngineHandler(service service.EngineServiceInterface) *EngineHandler {
+	return &EngineHandler{
+		service: service,
+	}
+}
+
+func (h *EngineHandler) GetEngineById(w http.ResponseWriter, r *http.Request) {
+	tracer := otel.Tracer("EngineHandlerV2")
+	ctx, span := tracer.Start(r.Context(), "GetEngineById-HandlerV2")
+	defer span.End()
+
+	res, err := h.service.GetEngineById(ctx, mux.Vars(r)["id"])
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	response.LastModified(w, res.UpdateAt)
+	codec.Write(w, r, http.StatusOK, NewEngine(*res))
+}
+
+func (h *EngineHandler) CreateEngine(w http.ResponseWriter, r *http.Request) {
+	tracer := otel.Tracer("EngineHandlerV2")
+	ctx, span := tracer.Start(r.Context(), "CreateEngine-HandlerV2")
+	defer span.End()
+
+	var engineReq EngineRequest
+	if err := codec.Decode(r, &engineReq, "engine"); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	req := engineReq.Model()
+	createdEngine, err := h.service.CreateEngine(ctx, &req)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	codec.Write(w, r, http.StatusCreated, NewEngine(*createdEngine))
+}
+
+func (h *EngineHandler) UpdateEngine(w http.ResponseWriter, r *http.Request) {
+	tracer := otel.Tracer("EngineHandlerV2")
+	ctx, span := tracer.Start(r.Context(), "UpdateEngine-HandlerV2")
+	defer span.End()
+
+	var engineReq EngineRequest
+	if err := codec.Decode(r, &engineReq, "engine"); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	req := engineReq.Model()
+	updatedEngine, err := h.service.UpdateEngine(ctx, mux.Vars(r)["id"], &req)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	codec.Write(w, r, http.StatusOK, NewEngine(*updatedEngine))
+}
+
+func (h *EngineHandler) DeleteEngine(w http.ResponseWriter, r *http.Request) {
+	tracer := otel.Tracer("EngineHandlerV2")
+	ctx, span := tracer.Start(r.Context(), "DeleteEngine-HandlerV2")
+	defer span.End()
+
+	deletedEngine, err := h.service.DeleteEngine(ctx, mux.Vars(r)["id"])
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	codec.Write(w, r, http.StatusOK, NewEngine(*deletedEngine))
+}
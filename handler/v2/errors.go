@@ -0,0 +1,44 @@
+package v2
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/geekAshish/DriveDesk/apperrors"
+	"github.com/geekAshish/DriveDesk/handler/response"
+)
+
+// writeError answers like response.Error, with field errors renamed to the
+// v2 field names.
+func writeError(w http.ResponseWriter, r *http.Request, err error) {
+	response.Error(w, r, translate(err))
+}
+
+func translate(err error) error {
+	var appErr *apperrors.Error
+	if !errors.As(err, &appErr) || len(appErr.Fields) == 0 {
+		return err
+	}
+
+	translated := *appErr
+	translated.Fields = make([]apperrors.FieldError, len(appErr.Fields))
+
+	for i, field := range appErr.Fields {
+		renamed := FieldName(field.Field)
+		if renamed != field.Field {
+			field.Message = strings.ReplaceAll(field.Message, lastSegment(field.Field), lastSegment(renamed))
+		}
+		field.Field = renamed
+		translated.Fields[i] = field
+	}
+	if len(appErr.Fields) == 1 {
+		translated.Message = translated.Fields[0].Message
+	}
+
+	return &translated
+}
+
+func lastSegment(path string) string {
+	return path[strings.LastIndex(path, ".")+1:]
+}
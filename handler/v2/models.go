@@ -0,0 +1,153 @@
+package v2
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/geekAshish/DriveDesk/models"
+	"github.com/google/uuid"
+)
+
+// Car is a car as v2 sends it. Unlike v1 the year is a number and the engine
+// uses the corrected field names.
+type Car struct {
+	ID       uuid.UUID `json:"id" xml:"id"`
+	Name     string    `json:"name" xml:"name"`
+	Year     int       `json:"year" xml:"year"`
+	Brand    string    `json:"brand" xml:"brand"`
+	FuelType string    `json:"fuel_type" xml:"fuel_type"`
+	Price    float64   `json:"price" xml:"price"`
+	VIN      string    `json:"vin,omitempty" xml:"vin,omitempty"`
+	Engine   Engine    `json:"engine" xml:"engine"`
+	CreateAt time.Time `json:"created_at" xml:"created_at"`
+	UpdateAt time.Time `json:"updated_at" xml:"updated_at"`
+}
+
+// Engine is an engine as v2 sends it. Listings that leave engine details out
+// only carry the id.
+type Engine struct {
+	EngineID      uuid.UUID  `json:"engine_id" xml:"engine_id"`
+	Displacement  float64    `json:"displacement,omitempty" xml:"displacement,omitempty"`
+	NoOfCylinders int        `json:"no_of_cylinders,omitempty" xml:"no_of_cylinders,omitempty"`
+	CarRange      float64    `json:"car_range,omitempty" xml:"car_range,omitempty"`
+	CreateAt      *time.Time `json:"created_at,omitempty" xml:"created_at,omitempty"`
+	UpdateAt      *time.Time `json:"updated_at,omitempty" xml:"updated_at,omitempty"`
+}
+
+type CarRequest struct {
+	Name     string    `json:"name" xml:"name"`
+	Year     int       `json:"year" xml:"year"`
+	Brand    string    `json:"brand" xml:"brand"`
+	FuelType string    `json:"fuel_type" xml:"fuel_type"`
+	Price    float64   `json:"price" xml:"price"`
+	VIN      string    `json:"vin,omitempty" xml:"vin,omitempty"`
+	Engine   CarEngine `json:"engine" xml:"engine"`
+}
+
+// CarEngine is the engine a car request fits to the car.
+type CarEngine struct {
+	EngineID      uuid.UUID `json:"engine_id" xml:"engine_id"`
+	Displacement  float64   `json:"displacement" xml:"displacement"`
+	NoOfCylinders int       `json:"no_of_cylinders" xml:"no_of_cylinders"`
+	CarRange      float64   `json:"car_range" xml:"car_range"`
+}
+
+type EngineRequest struct {
+	Displacement  float64 `json:"displacement" xml:"displacement"`
+	NoOfCylinders int     `json:"no_of_cylinders" xml:"no_of_cylinders"`
+	CarRange      float64 `json:"car_range" xml:"car_range"`
+}
+
+// NewCar converts a stored car to its v2 form.
+func NewCar(car models.Car) Car {
+	// years are stored as text; ones that are not numbers predate validation and read as 0
+	year, _ := strconv.Atoi(car.Year)
+
+	return Car{
+		ID:       car.ID,
+		Name:     car.Name,
+		Year:     year,
+		Brand:    car.Brand,
+		FuelType: car.FuelType,
+		Price:    car.Price,
+		VIN:      car.VIN,
+		Engine:   NewEngine(car.Engine),
+		CreateAt: car.CreateAt,
+		UpdateAt: car.UpdateAt,
+	}
+}
+
+func NewCars(cars []models.Car) []Car {
+	converted := make([]Car, 0, len(cars))
+	for _, car := range cars {
+		converted = append(converted, NewCar(car))
+	}
+	return converted
+}
+
+// NewEngine converts a stored engine to its v2 form.
+func NewEngine(engine models.Engine) Engine {
+	converted := Engine{
+		EngineID:      engine.EngineID,
+		Displacement:  engine.Dispacement,
+		NoOfCylinders: int(engine.NoOfCylinders),
+		CarRange:      engine.CarRange,
+	}
+
+	if !engine.CreateAt.IsZero() {
+		converted.CreateAt = &engine.CreateAt
+		converted.UpdateAt = &engine.UpdateAt
+	}
+
+	return converted
+}
+
+// Model returns the request the services work with.
+func (r CarRequest) Model() models.CarRequest {
+	year := ""
+	if r.Year != 0 {
+		year = strconv.Itoa(r.Year)
+	}
+
+	return models.CarRequest{
+		Name:     r.Name,
+		Year:     year,
+		Brand:    r.Brand,
+		FuelType: r.FuelType,
+		Price:    r.Price,
+		VIN:      r.VIN,
+		Engine: models.Engine{
+			EngineID:      r.Engine.EngineID,
+			Dispacement:   r.Engine.Displacement,
+			NoOfCylinders: float64(r.Engine.NoOfCylinders),
+			CarRange:      r.Engine.CarRange,
+		},
+	}
+}
+
+// Model returns the request the services work with.
+func (r EngineRequest) Model() models.EngineRequest {
+	return models.EngineRequest{
+		Dispacement:   r.Displacement,
+		NoOfCylinders: float64(r.NoOfCylinders),
+		CarRange:      r.CarRange,
+	}
+}
+
+// renamedFields maps v1 JSON field names to their v2 spelling.
+var renamedFields = map[string]string{
+	"dispacement": "displacement",
+}
+
+// FieldName translates the path of a v1 field, as reported by the
+// validators, to v2.
+func FieldName(path string) string {
+	segments := strings.Split(path, ".")
+	for i, segment := range segments {
+		if renamed, ok := renamedFields[segment]; ok {
+			segments[i] = renamed
+		}
+	}
+	return strings.Join(segments, ".")
+}
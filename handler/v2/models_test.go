@@ -0,0 +1,72 @@
+package v2
+
+import (
+	"testing"
+	"time"
+
+	"github.com/geekAshish/DriveDesk/apperrors"
+	"github.com/geekAshish/DriveDesk/models"
+	"github.com/google/uuid"
+)
+
+func TestCarRequestModel(t *testing.T) {
+	engineID := uuid.New()
+
+	got := CarRequest{
+		Name:     "City",
+		Year:     2024,
+		Brand:    "Honda",
+		FuelType: "petrol",
+		Price:    12000,
+		Engine:   CarEngine{EngineID: engineID, Displacement: 1500, NoOfCylinders: 4, CarRange: 600},
+	}.Model()
+
+	if got.Year != "2024" {
+		t.Errorf("Year = %q, want 2024", got.Year)
+	}
+	if got.Engine.EngineID != engineID || got.Engine.Dispacement != 1500 || got.Engine.NoOfCylinders != 4 {
+		t.Errorf("Engine = %+v, want the v2 engine fields", got.Engine)
+	}
+
+	// a missing year must still fail the required check
+	if year := (CarRequest{}).Model().Year; year != "" {
+		t.Errorf("Year of an empty request = %q, want empty", year)
+	}
+}
+
+func TestNewCar(t *testing.T) {
+	got := NewCar(models.Car{
+		Year:   "2019",
+		Engine: models.Engine{EngineID: uuid.New(), Dispacement: 2000, NoOfCylinders: 6, CreateAt: time.Now()},
+	})
+
+	if got.Year != 2019 || got.Engine.Displacement != 2000 || got.Engine.NoOfCylinders != 6 {
+		t.Errorf("NewCar = %+v, want year 2019 and the engine details", got)
+	}
+	if got.Engine.CreateAt == nil {
+		t.Error("engine timestamps dropped although they are known")
+	}
+
+	// listings without engine details only carry the id
+	if engine := NewEngine(models.Engine{EngineID: uuid.New()}); engine.CreateAt != nil {
+		t.Errorf("engine without details has timestamp %v", engine.CreateAt)
+	}
+}
+
+func TestTranslateRenamesFields(t *testing.T) {
+	err := translate(apperrors.InvalidFields([]apperrors.FieldError{
+		{Field: "engine.dispacement", Code: "must_be_positive", Message: "dispacement must be positive"},
+		{Field: "year", Code: "required", Message: "year is required"},
+	}))
+
+	fields := apperrors.Fields(err)
+	if len(fields) != 2 {
+		t.Fatalf("got %d field errors, want 2", len(fields))
+	}
+	if fields[0].Field != "engine.displacement" || fields[0].Message != "displacement must be positive" {
+		t.Errorf("first field error = %+v, want it renamed to displacement", fields[0])
+	}
+	if fields[1].Field != "year" {
+		t.Errorf("second field error = %+v, want it unchanged", fields[1])
+	}
+}
@@ -0,0 +1,68 @@
+package v2
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/geekAshish/DriveDesk/apperrors"
+	"github.com/geekAshish/DriveDesk/handler/codec"
+	"github.com/geekAshish/DriveDesk/handler/response"
+	ruleHandler "github.com/geekAshish/DriveDesk/handler/rule"
+	"github.com/geekAshish/DriveDesk/models"
+	"github.com/geekAshish/DriveDesk/service"
+	"github.com/gorilla/mux"
+	"go.opentelemetry.io/otel"
+)
+
+// ValidationHandler tests v2 payloads against the validation rules. Rules
+// themselves are version-agnostic and managed through the v1 handlers.
+type ValidationHandler struct {
+	cars    service.CarServiceInterface
+	engines service.EngineServiceInterface
+}
+
+func NewValidationHandler(cars service.CarServiceInterface, engines service.EngineServiceInterface) *ValidationHandler {
+	return &ValidationHandler{
+		cars:    cars,
+		engines: engines,
+	}
+}
+
+// TestPayload is the v2 counterpart of the rule handler's TestPayload; field
+// errors use the v2 field names.
+func (h *ValidationHandler) TestPayload(w http.ResponseWriter, r *http.Request) {
+	tracer := otel.Tracer("ValidationHandlerV2")
+	ctx, span := tracer.Start(r.Context(), "TestPayload-HandlerV2")
+	defer span.End()
+
+	var err error
+
+	switch target := mux.Vars(r)["target"]; target {
+	case models.RuleTargetCar:
+		var car CarRequest
+		if err = codec.Decode(r, &car, "car"); err == nil {
+			req := car.Model()
+			err = h.cars.ValidateCar(ctx, &req)
+		}
+	case models.RuleTargetEngine:
+		var engine EngineRequest
+		if err = codec.Decode(r, &engine, "engine"); err == nil {
+			req := engine.Model()
+			err = h.engines.ValidateEngine(ctx, &req)
+		}
+	default:
+		err = apperrors.NotFound("no validation target %s, expected car or engine", target)
+	}
+
+	if err != nil && !errors.Is(err, apperrors.ErrValidation) {
+		writeError(w, r, err)
+		return
+	}
+
+	fields := apperrors.Fields(translate(err))
+	if fields == nil {
+		fields = []apperrors.FieldError{}
+	}
+
+	response.JSON(w, http.StatusOK, ruleHandler.TestResult{Valid: err == nil, Errors: fields})
+}
@@ -0,0 +1,184 @@
+package webhook
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/geekAshish/DriveDesk/apperrors"
+	"github.com/geekAshish/DriveDesk/handler/response"
+	"github.com/geekAshish/DriveDesk/models"
+	"github.com/geekAshish/DriveDesk/service"
+	"github.com/gorilla/mux"
+	"go.opentelemetry.io/otel"
+)
+
+// defaultDeliveryLimit is how many deliveries a listing returns when the
+// client does not ask for a limit.
+const defaultDeliveryLimit = 100
+
+type WebhookHandler struct {
+	service service.WebhookServiceInterface
+}
+
+func NewWebhookHandler(service service.WebhookServiceInterface) *WebhookHandler {
+	return &WebhookHandler{service: service}
+}
+
+func (h *WebhookHandler) ListWebhooks(w http.ResponseWriter, r *http.Request) {
+	tracer := otel.Tracer("WebhookHandler")
+	ctx, span := tracer.Start(r.Context(), "ListWebhooks-Handler")
+	defer span.End()
+
+	webhooks, err := h.service.ListWebhooks(ctx)
+	if err != nil {
+		response.Error(w, r, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, webhooks)
+}
+
+func (h *WebhookHandler) GetWebhook(w http.ResponseWriter, r *http.Request) {
+	tracer := otel.Tracer("WebhookHandler")
+	ctx, span := tracer.Start(r.Context(), "GetWebhook-Handler")
+	defer span.End()
+
+	webhook, err := h.service.GetWebhook(ctx, mux.Vars(r)["id"])
+	if err != nil {
+		response.Error(w, r, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, webhook)
+}
+
+func (h *WebhookHandler) CreateWebhook(w http.ResponseWriter, r *http.Request) {
+	tracer := otel.Tracer("WebhookHandler")
+	ctx, span := tracer.Start(r.Context(), "CreateWebhook-Handler")
+	defer span.End()
+
+	var req models.WebhookRequest
+	if err := decode(r, &req); err != nil {
+		response.Error(w, r, err)
+		return
+	}
+
+	created, err := h.service.CreateWebhook(ctx, &req)
+	if err != nil {
+		response.Error(w, r, err)
+		return
+	}
+
+	response.JSON(w, http.StatusCreated, created)
+}
+
+func (h *WebhookHandler) UpdateWebhook(w http.ResponseWriter, r *http.Request) {
+	tracer := otel.Tracer("WebhookHandler")
+	ctx, span := tracer.Start(r.Context(), "UpdateWebhook-Handler")
+	defer span.End()
+
+	var req models.WebhookRequest
+	if err := decode(r, &req); err != nil {
+		response.Error(w, r, err)
+		return
+	}
+
+	updated, err := h.service.UpdateWebhook(ctx, mux.Vars(r)["id"], &req)
+	if err != nil {
+		response.Error(w, r, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, updated)
+}
+
+func (h *WebhookHandler) DeleteWebhook(w http.ResponseWriter, r *http.Request) {
+	tracer := otel.Tracer("WebhookHandler")
+	ctx, span := tracer.Start(r.Context(), "DeleteWebhook-Handler")
+	defer span.End()
+
+	deleted, err := h.service.DeleteWebhook(ctx, mux.Vars(r)["id"])
+	if err != nil {
+		response.Error(w, r, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, deleted)
+}
+
+// ListWebhookDeliveries is the delivery log, newest first. It is filtered by
+// the webhook_id and status query parameters, so status=dead lists the
+// dead letters.
+func (h *WebhookHandler) ListWebhookDeliveries(w http.ResponseWriter, r *http.Request) {
+	tracer := otel.Tracer("WebhookHandler")
+	ctx, span := tracer.Start(r.Context(), "ListWebhookDeliveries-Handler")
+	defer span.End()
+
+	query := r.URL.Query()
+	filter := models.WebhookDeliveryFilter{
+		WebhookID: query.Get("webhook_id"),
+		Status:    query.Get("status"),
+		Limit:     defaultDeliveryLimit,
+	}
+
+	if limit := query.Get("limit"); limit != "" {
+		var err error
+		if filter.Limit, err = strconv.Atoi(limit); err != nil || filter.Limit == 0 {
+			response.Error(w, r, apperrors.InvalidField("limit", "invalid_format", "limit must be a positive whole number"))
+			return
+		}
+	}
+
+	deliveries, err := h.service.ListWebhookDeliveries(ctx, filter)
+	if err != nil {
+		response.Error(w, r, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, deliveries)
+}
+
+func (h *WebhookHandler) GetWebhookDelivery(w http.ResponseWriter, r *http.Request) {
+	tracer := otel.Tracer("WebhookHandler")
+	ctx, span := tracer.Start(r.Context(), "GetWebhookDelivery-Handler")
+	defer span.End()
+
+	delivery, err := h.service.GetWebhookDelivery(ctx, mux.Vars(r)["id"])
+	if err != nil {
+		response.Error(w, r, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, delivery)
+}
+
+// RedeliverWebhookDelivery queues a delivery to be sent again. It answers
+// 202 since the delivery itself happens in the background.
+func (h *WebhookHandler) RedeliverWebhookDelivery(w http.ResponseWriter, r *http.Request) {
+	tracer := otel.Tracer("WebhookHandler")
+	ctx, span := tracer.Start(r.Context(), "RedeliverWebhookDelivery-Handler")
+	defer span.End()
+
+	delivery, err := h.service.RedeliverWebhookDelivery(ctx, mux.Vars(r)["id"])
+	if err != nil {
+		response.Error(w, r, err)
+		return
+	}
+
+	response.JSON(w, http.StatusAccepted, delivery)
+}
+
+func decode(r *http.Request, dst any) error {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return apperrors.Wrap(apperrors.ErrBadRequest, err, "could not read request body")
+	}
+
+	if err := json.Unmarshal(body, dst); err != nil {
+		return apperrors.Wrap(apperrors.ErrBadRequest, err, "request body is not a valid webhook: %v", err)
+	}
+
+	return nil
+}
@@ -2,20 +2,28 @@ package main
 
 import (
 	"context"
-	"database/sql"
 	"fmt"
 	"log"
+	"net"
 	"net/http"
 	"os"
+	"slices"
+	"strings"
 	"time"
 
+	"github.com/geekAshish/DriveDesk/broker"
 	"github.com/geekAshish/DriveDesk/driver"
+	"github.com/geekAshish/DriveDesk/graphqlserver"
+	"github.com/geekAshish/DriveDesk/grpcserver"
 	"github.com/geekAshish/DriveDesk/middleware"
-	"github.com/gorilla/mux"
+	"github.com/geekAshish/DriveDesk/models"
+	"github.com/geekAshish/DriveDesk/service"
+	"github.com/geekAshish/DriveDesk/store"
+	"github.com/geekAshish/DriveDesk/store/memory"
+	"github.com/geekAshish/DriveDesk/store/rulefile"
 	"github.com/joho/godotenv"
-	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"google.golang.org/grpc"
 
-	"go.opentelemetry.io/contrib/instrumentation/github.com/gorilla/mux/otelmux"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/exporters/otlp/otlptrace"
 	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
@@ -23,15 +31,90 @@ import (
 	"go.opentelemetry.io/otel/sdk/trace"
 	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
 
+	cacheService "github.com/geekAshish/DriveDesk/service/cache"
 	carService "github.com/geekAshish/DriveDesk/service/car"
-	carStore "github.com/geekAshish/DriveDesk/store/car"
 
 	engineService "github.com/geekAshish/DriveDesk/service/engine"
-	engineStore "github.com/geekAshish/DriveDesk/store/engine"
+	outboxService "github.com/geekAshish/DriveDesk/service/outbox"
+	referenceService "github.com/geekAshish/DriveDesk/service/reference"
+	ruleService "github.com/geekAshish/DriveDesk/service/rule"
+	streamService "github.com/geekAshish/DriveDesk/service/stream"
+	syncService "github.com/geekAshish/DriveDesk/service/sync"
+	webhookService "github.com/geekAshish/DriveDesk/service/webhook"
 
 	carHandler "github.com/geekAshish/DriveDesk/handler/car"
 	engineHandler "github.com/geekAshish/DriveDesk/handler/engine"
-	loginHandler "github.com/geekAshish/DriveDesk/handler/login"
+	referenceHandler "github.com/geekAshish/DriveDesk/handler/reference"
+	ruleHandler "github.com/geekAshish/DriveDesk/handler/rule"
+	streamHandler "github.com/geekAshish/DriveDesk/handler/stream"
+	syncHandler "github.com/geekAshish/DriveDesk/handler/sync"
+	handlerV2 "github.com/geekAshish/DriveDesk/handler/v2"
+	webhookHandler "github.com/geekAshish/DriveDesk/handler/webhook"
+)
+
+// referenceCacheTTL bounds how long an instance keeps validating against
+// reference data that another instance has since changed.
+const referenceCacheTTL = time.Minute
+
+// ruleCacheTTL does the same for validation rules.
+const ruleCacheTTL = time.Minute
+
+// Up to inventoryCacheSize cars, and as many engines, read by id are kept
+// in process for inventoryCacheTTL, and ids that were not found for
+// inventoryNegativeCacheTTL. With Postgres, changes made by other instances
+// drop them at once; otherwise the TTL bounds how stale they get.
+const (
+	inventoryCacheSize        = 10000
+	inventoryCacheTTL         = time.Minute
+	inventoryNegativeCacheTTL = 10 * time.Second
+)
+
+// idempotencyKeyTTL is how long a retry with the same Idempotency-Key gets
+// the original response back. Expired keys are purged every
+// idempotencyPurgeInterval.
+const (
+	idempotencyKeyTTL        = 24 * time.Hour
+	idempotencyPurgeInterval = time.Hour
+)
+
+// Rate limit buckets left alone for rateLimitBucketRetention are full again
+// for any sensible limit, and are purged every rateLimitPurgeInterval.
+const (
+	rateLimitBucketRetention = time.Hour
+	rateLimitPurgeInterval   = time.Hour
+)
+
+// Webhook deliveries due are sent every webhookDispatchInterval, and each
+// attempt gives the receiver webhookTimeout to answer.
+const (
+	webhookDispatchInterval = time.Second
+	webhookTimeout          = 10 * time.Second
+)
+
+// Each instance reads the changes made by every instance every
+// eventPollInterval and streams them to its clients. Clients can resume
+// from any change of the last eventRetention.
+const (
+	eventPollInterval  = 500 * time.Millisecond
+	eventRetention     = 24 * time.Hour
+	eventPurgeInterval = time.Hour
+)
+
+// Outbox messages due are relayed to their sinks every outboxRelayInterval.
+// Published messages are kept for outboxRetention, to look into what was
+// sent.
+const (
+	outboxRelayInterval = time.Second
+	outboxRetention     = 7 * 24 * time.Hour
+	outboxPurgeInterval = time.Hour
+)
+
+// Events go to NATS on subjects under defaultNATSSubjectPrefix and to Kafka
+// on defaultKafkaTopic, unless NATS_SUBJECT_PREFIX or KAFKA_TOPIC say
+// otherwise.
+const (
+	defaultNATSSubjectPrefix = "drivedesk."
+	defaultKafkaTopic        = "drivedesk.inventory"
 )
 
 func main() {
@@ -40,6 +123,13 @@ func main() {
 		log.Fatalf("Error loading .env file")
 	}
 
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		if err := runMigrate(os.Args[2:]); err != nil {
+			log.Fatalf("migrate: %v", err)
+		}
+		return
+	}
+
 	traceProvider, err := startTracing()
 	if err != nil {
 		log.Fatalf("Error to start tracing : %v", err)
@@ -53,70 +143,262 @@ func main() {
 
 	otel.SetTracerProvider(traceProvider)
 
-	driver.InitDB()
-	defer driver.CloseDB()
+	stores, err := openStores(os.Getenv("DB_DRIVER"))
+	if err != nil {
+		log.Fatalf("Error opening stores : %v", err)
+	}
+	defer stores.close()
 
-	db := driver.GetDB()
-	carStore := carStore.New(db)
-	carService := carService.NewCarService(carStore)
+	referenceService := referenceService.NewReferenceService(stores.reference, stores.tx, referenceCacheTTL)
+	// rules shipped with the deployment replace the ones kept in the database
+	if path := os.Getenv("VALIDATION_RULES_FILE"); path != "" {
+		stores.rule = rulefile.New(path)
+	}
 
-	engineStore := engineStore.New(db)
-	engineService := engineService.NewEngineService(engineStore)
+	ruleService := ruleService.NewValidationRuleService(stores.rule, ruleCacheTTL)
+
+	// fail on start rather than on the first write when a rule is broken
+	ruleSet, err := ruleService.Rules(context.Background())
+	if err != nil {
+		log.Fatalf("Error loading validation rules : %v", err)
+	}
+	log.Printf("loaded %d validation rules", ruleSet.Len())
+	webhookDispatcher := webhookService.NewDispatcher(stores.webhook, &http.Client{Timeout: webhookTimeout}, webhookService.DefaultRetryPolicy)
+	webhookService := webhookService.NewWebhookService(stores.webhook)
+
+	// every car and engine change is written to the outbox, and the relay
+	// queues it for the subscribed webhooks, appends it to OUTBOX_FILE and
+	// publishes it to the brokers configured
+	sinks := []outboxService.Sink{outboxService.NewWebhookSink(webhookService)}
+	if path := os.Getenv("OUTBOX_FILE"); path != "" {
+		fileSink, err := outboxService.NewFileSink(path)
+		if err != nil {
+			log.Fatalf("Error opening the outbox file : %v", err)
+		}
+		defer fileSink.Close()
+
+		sinks = append(sinks, fileSink)
+	}
+
+	if url := os.Getenv("NATS_URL"); url != "" {
+		publisher, err := broker.NewNATS(url, envOr("NATS_SUBJECT_PREFIX", defaultNATSSubjectPrefix))
+		if err != nil {
+			log.Fatalf("Error connecting to NATS : %v", err)
+		}
+		defer publisher.Close()
+
+		sinks = append(sinks, outboxService.NewBrokerSink("nats", publisher))
+	}
+
+	if brokers := os.Getenv("KAFKA_BROKERS"); brokers != "" {
+		publisher := broker.NewKafka(strings.Split(brokers, ","), envOr("KAFKA_TOPIC", defaultKafkaTopic))
+		defer publisher.Close()
+
+		sinks = append(sinks, outboxService.NewBrokerSink("kafka", publisher))
+	}
+	outboxRelay := outboxService.NewRelay(stores.outbox, outboxService.DefaultRetryPolicy, sinks...)
+
+	// and is kept for the change stream
+	eventHub := streamService.NewHub(stores.event)
+	if err := eventHub.Start(context.Background()); err != nil {
+		log.Fatalf("Error starting the change stream : %v", err)
+	}
+
+	// and versioned for clients that sync deltas
+	events := service.Publishers{outboxService.NewOutbox(stores.outbox, outboxRelay.Sinks()...), eventHub, syncService.NewRecorder(stores.sync)}
+
+	inventoryCache := cacheService.New(cacheService.Config{
+		Size:        inventoryCacheSize,
+		TTL:         inventoryCacheTTL,
+		NegativeTTL: inventoryNegativeCacheTTL,
+	})
+
+	carService := inventoryCache.Cars(carService.NewCarService(stores.car, stores.tx, referenceService, ruleService, events))
+	engineService := inventoryCache.Engines(engineService.NewEngineService(stores.engine, stores.car, stores.tx, ruleService, events))
 
 	carHandler := carHandler.NewCarHandler(carService)
 	engineHandler := engineHandler.NewEngineHandler(engineService)
+	referenceHandler := referenceHandler.NewReferenceHandler(referenceService)
+	ruleHandler := ruleHandler.NewRuleHandler(ruleService, carService, engineService)
+	webhookHandler := webhookHandler.NewWebhookHandler(webhookService)
+	streamHandler := streamHandler.NewStreamHandler(eventHub)
+	syncHandler := syncHandler.NewSyncHandler(syncService.NewSyncService(stores.sync, stores.tx, carService, engineService))
+
+	cacheControl, err := middleware.ParseCacheControl(os.Getenv("HTTP_CACHE_CONTROL"))
+	if err != nil {
+		log.Fatalf("Error reading HTTP_CACHE_CONTROL : %v", err)
+	}
+	for route := range cacheControl {
+		if !slices.Contains(cacheableRoutes, route) {
+			log.Fatalf("HTTP_CACHE_CONTROL names %s, expected one of %s", route, strings.Join(cacheableRoutes, ", "))
+		}
+	}
+
+	rateLimits := models.DefaultRateLimitConfig
+	if path := os.Getenv("RATE_LIMITS_FILE"); path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			log.Fatalf("Error reading RATE_LIMITS_FILE : %v", err)
+		}
+		if rateLimits, err = models.ParseRateLimitConfig(data); err != nil {
+			log.Fatalf("Error reading RATE_LIMITS_FILE : %v", err)
+		}
+	}
+
+	// every instance counts requests on its own, unless they share the
+	// buckets in the database, which costs a round trip per request
+	rateLimitStore := store.RateLimitStoreInterface(memory.NewRateLimitStore(memory.NewDB()))
+	if os.Getenv("RATE_LIMIT_SHARED") == "true" {
+		rateLimitStore = stores.rateLimit
+	}
+
+	rateLimiter := middleware.NewRateLimiter(rateLimitStore, rateLimits)
+
+	router := newRouter(handlers{
+		car:       carHandler,
+		engine:    engineHandler,
+		reference: referenceHandler,
+		rule:      ruleHandler,
+		webhook:   webhookHandler,
+		stream:    streamHandler,
+		sync:      syncHandler,
+
+		carV2:        handlerV2.NewCarHandler(carService),
+		engineV2:     handlerV2.NewEngineHandler(engineService),
+		validationV2: handlerV2.NewValidationHandler(carService, engineService),
+
+		graphql: graphqlserver.NewHandler(carService, engineService, referenceService),
+
+		idempotency: middleware.NewIdempotency(stores.idempotency, idempotencyKeyTTL),
 
-	router := mux.NewRouter()
+		cacheControl: cacheControl,
 
-	// otel middleware for tracing
-	router.Use(otelmux.Middleware("DriveDesk"))
-	router.Use(middleware.MetricMiddleware)
+		rateLimit: rateLimiter,
+	})
 
-	// schemaFile := "store/schema.sql"
-	// if err := executeSchemaFile(db, schemaFile); err != nil {
-	// 	log.Fatal("error while executing the schema file: ", err)
-	// }
-	router.HandleFunc("/login", loginHandler.LoginHandler).Methods("POST")
+	go purgeIdempotencyKeys(stores.idempotency, idempotencyPurgeInterval)
 
-	// Middleware
-	protected := router.PathPrefix("/").Subrouter()
-	protected.Use(middleware.AuthMiddleware)
+	go purgeRateLimitBuckets(rateLimitStore, rateLimitBucketRetention, rateLimitPurgeInterval)
 
-	protected.HandleFunc("/cars/{id}", carHandler.GetCarById).Methods("GET")
-	protected.HandleFunc("/cars", carHandler.GetCarByBrand).Methods("GET")
-	protected.HandleFunc("/cars", carHandler.CreateCar).Methods("POST")
-	protected.HandleFunc("/cars/{id}", carHandler.UpdateCar).Methods("PUT")
-	protected.HandleFunc("/cars/{id}", carHandler.DeleteCar).Methods("DELETE")
+	go outboxRelay.Run(context.Background(), outboxRelayInterval)
 
-	protected.HandleFunc("/engine/{id}", engineHandler.GetEngineById).Methods("GET")
-	protected.HandleFunc("/engine", engineHandler.CreateEngine).Methods("POST")
-	protected.HandleFunc("/engine/{id}", engineHandler.UpdateEngine).Methods("PUT")
-	protected.HandleFunc("/engine/{id}", engineHandler.DeleteEngine).Methods("DELETE")
+	go webhookDispatcher.Run(context.Background(), webhookDispatchInterval)
 
-	router.Handle("/metrics", promhttp.Handler())
+	go eventHub.Run(context.Background(), eventPollInterval)
+
+	// with Postgres the triggers on car and engine say when another instance
+	// changed something, so cached copies are dropped and events, outbox
+	// messages and deliveries go out without waiting for the next poll
+	if stores.changes != nil {
+		stores.changes.Subscribe(func(change driver.Change) {
+			if change.Resync {
+				inventoryCache.Purge()
+			} else {
+				inventoryCache.Invalidate(change.Table, change.ID)
+			}
+
+			eventHub.Wake()
+			outboxRelay.Wake()
+			webhookDispatcher.Wake()
+		})
+
+		go func() {
+			if err := stores.changes.Run(context.Background()); err != nil {
+				log.Printf("Error listening for changes : %v", err)
+			}
+		}()
+	}
+	go purgeChangeEvents(stores.event, eventRetention, eventPurgeInterval)
+
+	go purgeOutbox(stores.outbox, outboxRetention, outboxPurgeInterval)
 
 	port := os.Getenv("PORT")
 	if port == "" {
 		log.Fatalf("INVALID PORT NUMBER")
 	}
 
+	// gRPC is served on its own port, and only when one is configured
+	if grpcPort := os.Getenv("GRPC_PORT"); grpcPort != "" {
+		go serveGRPC(fmt.Sprintf(":%s", grpcPort), grpcserver.New(carService, engineService, rateLimiter))
+	}
+
 	addr := fmt.Sprintf(":%s", port)
 	log.Printf("Server listning on : %s", addr)
 	log.Fatal(http.ListenAndServe(addr, router))
 }
 
-func executeSchemaFile(db *sql.DB, filename string) error {
-	sqlFile, err := os.ReadFile(filename)
+func serveGRPC(addr string, server *grpc.Server) {
+	listener, err := net.Listen("tcp", addr)
 	if err != nil {
-		return err
+		log.Fatalf("Error to listen for grpc : %v", err)
 	}
 
-	_, err = db.Exec(string(sqlFile))
-	if err != nil {
-		return err
+	log.Printf("gRPC server listning on : %s", addr)
+	log.Fatal(server.Serve(listener))
+}
+
+// envOr returns the environment variable key, or fallback when it is unset.
+func envOr(key, fallback string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
 	}
+	return fallback
+}
 
-	return nil
+func purgeIdempotencyKeys(idempotency store.IdempotencyStoreInterface, interval time.Duration) {
+	for range time.Tick(interval) {
+		deleted, err := idempotency.DeleteExpiredIdempotencyKeys(context.Background(), time.Now())
+		if err != nil {
+			log.Printf("Error purging idempotency keys : %v", err)
+			continue
+		}
+
+		if deleted > 0 {
+			log.Printf("purged %d expired idempotency keys", deleted)
+		}
+	}
+}
+
+func purgeRateLimitBuckets(rateLimits store.RateLimitStoreInterface, retention, interval time.Duration) {
+	for range time.Tick(interval) {
+		deleted, err := rateLimits.DeleteRateLimitBucketsBefore(context.Background(), time.Now().Add(-retention))
+		if err != nil {
+			log.Printf("Error purging rate limit buckets : %v", err)
+			continue
+		}
+
+		if deleted > 0 {
+			log.Printf("purged %d idle rate limit buckets", deleted)
+		}
+	}
+}
+
+func purgeChangeEvents(events store.EventStoreInterface, retention, interval time.Duration) {
+	for range time.Tick(interval) {
+		deleted, err := events.DeleteEventsBefore(context.Background(), time.Now().Add(-retention))
+		if err != nil {
+			log.Printf("Error purging change events : %v", err)
+			continue
+		}
+
+		if deleted > 0 {
+			log.Printf("purged %d old change events", deleted)
+		}
+	}
+}
+
+func purgeOutbox(outbox store.OutboxStoreInterface, retention, interval time.Duration) {
+	for range time.Tick(interval) {
+		deleted, err := outbox.DeletePublishedOutbox(context.Background(), time.Now().Add(-retention))
+		if err != nil {
+			log.Printf("Error purging outbox messages : %v", err)
+			continue
+		}
+
+		if deleted > 0 {
+			log.Printf("purged %d published outbox messages", deleted)
+		}
+	}
 }
 
 func startTracing() (*trace.TracerProvider, error) {
@@ -134,7 +416,7 @@ func startTracing() (*trace.TracerProvider, error) {
 	)
 
 	if err != nil {
-		return nil, fmt.Errorf("Error creating new exporter %w", &err)
+		return nil, fmt.Errorf("Error creating new exporter %w", err)
 	}
 
 	tracerProvider := trace.NewTracerProvider(
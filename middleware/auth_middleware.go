@@ -5,44 +5,88 @@ import (
 	"net/http"
 	"strings"
 
+	"github.com/geekAshish/DriveDesk/apperrors"
+	"github.com/geekAshish/DriveDesk/handler/response"
 	"github.com/golang-jwt/jwt/v5"
 )
 
 var jwtKey = []byte("some_value")
 
+// RoleAdmin is the role allowed to change reference data.
+const RoleAdmin = "admin"
+
 type Claims struct {
 	UserName string `json:"username"`
+	Role     string `json:"role,omitempty"`
 	jwt.RegisteredClaims
 }
 
+type userNameKey struct{}
+
+type roleKey struct{}
+
 func AuthMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(
 		func(w http.ResponseWriter, r *http.Request) {
-			authHeader := r.Header.Get("Authorization")
-
-			if authHeader == "" {
-				http.Error(w, "Authorization header required", http.StatusUnauthorized)
+			ctx, err := Authenticate(r.Context(), r.Header.Get("Authorization"))
+			if err != nil {
+				response.Error(w, r, err)
 				return
 			}
 
-			tokenString := strings.TrimPrefix(authHeader, "Bearer")
-
-			claims := &Claims{}
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+}
 
-			token, err := jwt.ParseWithClaims(
-				tokenString,
-				claims,
-				func(t *jwt.Token) (any, error) {
-					return jwtKey, nil
-				})
+// Authenticate checks the bearer token of an Authorization header and
+// returns ctx with the user and role of the token, for UserName and Role.
+func Authenticate(ctx context.Context, authHeader string) (context.Context, error) {
+	if authHeader == "" {
+		return ctx, apperrors.Unauthorized("Authorization header required")
+	}
 
-			if err != nil || !token.Valid {
-				http.Error(w, "Invalid token", http.StatusUnauthorized)
-				return
-			}
+	tokenString := strings.TrimSpace(strings.TrimPrefix(authHeader, "Bearer"))
 
-			ctx := context.WithValue(r.Context(), "username", claims.UserName)
+	claims := &Claims{}
 
-			next.ServeHTTP(w, r.WithContext(ctx))
+	token, err := jwt.ParseWithClaims(
+		tokenString,
+		claims,
+		func(t *jwt.Token) (any, error) {
+			return jwtKey, nil
 		})
+
+	if err != nil || !token.Valid {
+		return ctx, apperrors.Unauthorized("Invalid token")
+	}
+
+	ctx = context.WithValue(ctx, userNameKey{}, claims.UserName)
+	ctx = context.WithValue(ctx, roleKey{}, claims.Role)
+
+	return ctx, nil
+}
+
+// AdminOnly rejects requests whose token does not carry the admin role. It
+// must run after AuthMiddleware.
+func AdminOnly(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if Role(r.Context()) != RoleAdmin {
+			response.Error(w, r, apperrors.Forbidden("admin role required"))
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// UserName returns the user AuthMiddleware authenticated.
+func UserName(ctx context.Context) string {
+	userName, _ := ctx.Value(userNameKey{}).(string)
+	return userName
+}
+
+// Role returns the role in the token AuthMiddleware accepted.
+func Role(ctx context.Context) string {
+	role, _ := ctx.Value(roleKey{}).(string)
+	return role
 }
@@ -0,0 +1,65 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Deprecation announces that a version of the API is on its way out. Since
+// is when it was deprecated and Sunset when it stops being served; Successor
+// maps a request path to the same resource in the version to move to.
+type Deprecation struct {
+	Version   string
+	Since     time.Time
+	Sunset    time.Time
+	Successor func(path string) string
+}
+
+var deprecatedRequestCounter = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "http_deprecated_requests_total",
+		Help: "Total number of http request to deprecated API versions",
+	},
+	[]string{"version", "route", "method"},
+)
+
+func init() {
+	prometheus.MustRegister(deprecatedRequestCounter)
+}
+
+// Deprecated marks every response of the routes it wraps with the
+// Deprecation (RFC 9745) and Sunset (RFC 8594) headers and a Link to the
+// successor version, and counts the requests that still arrive.
+func Deprecated(d Deprecation) mux.MiddlewareFunc {
+	deprecation := fmt.Sprintf("@%d", d.Since.Unix())
+	sunset := d.Sunset.UTC().Format(http.TimeFormat)
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Deprecation", deprecation)
+			w.Header().Set("Sunset", sunset)
+			if d.Successor != nil {
+				w.Header().Add("Link", fmt.Sprintf(`<%s>; rel="successor-version"`, d.Successor(r.URL.Path)))
+			}
+
+			deprecatedRequestCounter.WithLabelValues(d.Version, pathTemplate(r), r.Method).Inc()
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// APIVersion returns the version of the API a request path addresses, such
+// as "v1", or "unversioned" for paths outside any version prefix.
+func APIVersion(path string) string {
+	segment, _, _ := strings.Cut(strings.TrimPrefix(path, "/"), "/")
+	if len(segment) > 1 && segment[0] == 'v' && strings.Trim(segment[1:], "0123456789") == "" {
+		return segment
+	}
+	return "unversioned"
+}
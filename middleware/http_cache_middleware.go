@@ -0,0 +1,139 @@
+package middleware
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// DefaultCacheControl lets clients keep a response but makes them check it
+// is still current, which the ETag turns into a cheap 304, before every
+// use. Authenticated responses are only stored by the client's own cache.
+const DefaultCacheControl = "private, no-cache"
+
+// CacheControl is the Cache-Control header of each cached route, keyed by its
+// path without the version prefix, e.g. "/cars/{id}".
+type CacheControl map[string]string
+
+// ParseCacheControl reads a CacheControl from a JSON object such as
+// {"/cars/{id}": "public, max-age=60"}. An empty string configures nothing.
+func ParseCacheControl(config string) (CacheControl, error) {
+	cacheControl := CacheControl{}
+	if config == "" {
+		return cacheControl, nil
+	}
+
+	if err := json.Unmarshal([]byte(config), &cacheControl); err != nil {
+		return nil, fmt.Errorf("cache control must be a JSON object of route to header value: %w", err)
+	}
+
+	return cacheControl, nil
+}
+
+// For returns the Cache-Control of route, DefaultCacheControl unless one was
+// configured.
+func (c CacheControl) For(route string) string {
+	if value, ok := c[route]; ok {
+		return value
+	}
+	return DefaultCacheControl
+}
+
+// HTTPCache makes successful GET responses cacheable. It sends cacheControl
+// and a strong ETag hashed from the body, and answers 304 Not Modified when
+// If-None-Match holds that ETag or, without If-None-Match, when the
+// Last-Modified set by the handler is no later than If-Modified-Since.
+// Handlers set Last-Modified with response.LastModified.
+func HTTPCache(cacheControl string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method != http.MethodGet {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			buffered := &bufferingWriter{ResponseWriter: w, statusCode: http.StatusOK}
+			next.ServeHTTP(buffered, r)
+
+			if buffered.statusCode != http.StatusOK {
+				w.WriteHeader(buffered.statusCode)
+				_, _ = w.Write(buffered.body.Bytes())
+				return
+			}
+
+			sum := sha256.Sum256(buffered.body.Bytes())
+			etag := `"` + hex.EncodeToString(sum[:16]) + `"`
+
+			header := w.Header()
+			header.Set("ETag", etag)
+			if cacheControl != "" {
+				header.Set("Cache-Control", cacheControl)
+			}
+
+			if notModified(r, etag, header.Get("Last-Modified")) {
+				header.Del("Content-Type")
+				header.Del("Content-Length")
+				w.WriteHeader(http.StatusNotModified)
+				return
+			}
+
+			w.WriteHeader(http.StatusOK)
+			_, _ = w.Write(buffered.body.Bytes())
+		})
+	}
+}
+
+// notModified evaluates the conditional headers of r in the order RFC 9110
+// gives them: If-Modified-Since only counts when If-None-Match is absent.
+func notModified(r *http.Request, etag, lastModified string) bool {
+	if ifNoneMatch := r.Header.Get("If-None-Match"); ifNoneMatch != "" {
+		for _, candidate := range strings.Split(ifNoneMatch, ",") {
+			candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+			if candidate == "*" || candidate == etag {
+				return true
+			}
+		}
+		return false
+	}
+
+	if lastModified == "" {
+		return false
+	}
+
+	since, err := http.ParseTime(r.Header.Get("If-Modified-Since"))
+	if err != nil {
+		return false
+	}
+
+	modified, err := http.ParseTime(lastModified)
+	if err != nil {
+		return false
+	}
+
+	return !modified.After(since)
+}
+
+// bufferingWriter holds back the status and body, so they can be replaced
+// by a 304. Headers go to the underlying writer as they are set.
+type bufferingWriter struct {
+	http.ResponseWriter
+	statusCode  int
+	wroteHeader bool
+	body        bytes.Buffer
+}
+
+func (bw *bufferingWriter) WriteHeader(statusCode int) {
+	if !bw.wroteHeader {
+		bw.statusCode = statusCode
+		bw.wroteHeader = true
+	}
+}
+
+func (bw *bufferingWriter) Write(b []byte) (int, error) {
+	bw.wroteHeader = true
+	return bw.body.Write(b)
+}
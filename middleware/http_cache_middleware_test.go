@@ -0,0 +1,91 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+var lastModified = time.Date(2026, time.October, 19, 10, 0, 0, 0, time.UTC)
+
+func cachedHandler(status int) http.Handler {
+	return HTTPCache("public, max-age=60")(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Last-Modified", lastModified.Format(http.TimeFormat))
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		w.Write([]byte(`{"name":"Civic"}`))
+	}))
+}
+
+func serve(handler http.Handler, header map[string]string) *httptest.ResponseRecorder {
+	r := httptest.NewRequest(http.MethodGet, "/cars/1", nil)
+	for name, value := range header {
+		r.Header.Set(name, value)
+	}
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, r)
+	return recorder
+}
+
+func TestHTTPCacheConditionalRequests(t *testing.T) {
+	handler := cachedHandler(http.StatusOK)
+
+	first := serve(handler, nil)
+	etag := first.Header().Get("ETag")
+	if first.Code != http.StatusOK || first.Body.String() != `{"name":"Civic"}` {
+		t.Fatalf("first response = %d %q", first.Code, first.Body)
+	}
+	if len(etag) < 3 || etag[0] != '"' || first.Header().Get("Cache-Control") != "public, max-age=60" {
+		t.Fatalf("first response has ETag %q and Cache-Control %q", etag, first.Header().Get("Cache-Control"))
+	}
+
+	for name, test := range map[string]struct {
+		header map[string]string
+		want   int
+	}{
+		"matching etag":       {map[string]string{"If-None-Match": etag}, http.StatusNotModified},
+		"weak matching etag":  {map[string]string{"If-None-Match": `"other", W/` + etag}, http.StatusNotModified},
+		"any etag":            {map[string]string{"If-None-Match": "*"}, http.StatusNotModified},
+		"other etag":          {map[string]string{"If-None-Match": `"other"`}, http.StatusOK},
+		"not modified since":  {map[string]string{"If-Modified-Since": lastModified.Format(http.TimeFormat)}, http.StatusNotModified},
+		"modified since":      {map[string]string{"If-Modified-Since": lastModified.Add(-time.Second).Format(http.TimeFormat)}, http.StatusOK},
+		"etag wins over date": {map[string]string{"If-None-Match": `"other"`, "If-Modified-Since": lastModified.Format(http.TimeFormat)}, http.StatusOK},
+		"unreadable date":     {map[string]string{"If-Modified-Since": "yesterday"}, http.StatusOK},
+	} {
+		got := serve(handler, test.header)
+		if got.Code != test.want {
+			t.Errorf("%s: status = %d, want %d", name, got.Code, test.want)
+		}
+		if got.Code == http.StatusNotModified && (got.Body.Len() != 0 || got.Header().Get("ETag") != etag) {
+			t.Errorf("%s: 304 has body %q and ETag %q", name, got.Body, got.Header().Get("ETag"))
+		}
+	}
+}
+
+func TestHTTPCacheLeavesFailuresAlone(t *testing.T) {
+	got := serve(cachedHandler(http.StatusNotFound), map[string]string{"If-None-Match": "*"})
+
+	if got.Code != http.StatusNotFound || got.Header().Get("ETag") != "" || got.Body.Len() == 0 {
+		t.Errorf("response = %d with ETag %q, want the 404 as it was", got.Code, got.Header().Get("ETag"))
+	}
+}
+
+func TestParseCacheControl(t *testing.T) {
+	cacheControl, err := ParseCacheControl(`{"/cars/{id}": "public, max-age=60"}`)
+	if err != nil {
+		t.Fatalf("ParseCacheControl: %v", err)
+	}
+
+	if got := cacheControl.For("/cars/{id}"); got != "public, max-age=60" {
+		t.Errorf(`For("/cars/{id}") = %q`, got)
+	}
+	if got := cacheControl.For("/cars"); got != DefaultCacheControl {
+		t.Errorf(`For("/cars") = %q, want the default`, got)
+	}
+
+	if _, err := ParseCacheControl("public"); err == nil {
+		t.Error("ParseCacheControl accepted a value that is not a JSON object")
+	}
+}
@@ -0,0 +1,179 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"log"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/geekAshish/DriveDesk/apperrors"
+	"github.com/geekAshish/DriveDesk/handler/response"
+	"github.com/geekAshish/DriveDesk/models"
+	"github.com/geekAshish/DriveDesk/store"
+)
+
+const IdempotencyKeyHeader = "Idempotency-Key"
+
+// IdempotentReplayedHeader is set on responses replayed from an earlier
+// request with the same key.
+const IdempotentReplayedHeader = "Idempotent-Replayed"
+
+const maxIdempotencyKeyLength = 255
+
+// idempotencyLockTimeout is how long a key stays reserved for a request that
+// never finishes, e.g. because the instance handling it died.
+const idempotencyLockTimeout = time.Minute
+
+// Idempotency makes retries of a request sent with an Idempotency-Key
+// header safe: the first request runs and its response is stored, retries
+// get the stored response back until the key expires. Keys are scoped to
+// the authenticated user, so it must run after AuthMiddleware. A nil
+// *Idempotency passes every request through.
+type Idempotency struct {
+	store store.IdempotencyStoreInterface
+	ttl   time.Duration
+}
+
+// NewIdempotency keeps the responses in store for ttl.
+func NewIdempotency(store store.IdempotencyStoreInterface, ttl time.Duration) *Idempotency {
+	return &Idempotency{store: store, ttl: ttl}
+}
+
+func (i *Idempotency) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := r.Header.Get(IdempotencyKeyHeader)
+		if i == nil || key == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		if len(key) > maxIdempotencyKeyLength {
+			response.Error(w, r, apperrors.BadRequest("%s must be at most %d characters", IdempotencyKeyHeader, maxIdempotencyKeyLength))
+			return
+		}
+
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			response.Error(w, r, apperrors.Wrap(apperrors.ErrBadRequest, err, "could not read request body"))
+			return
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+
+		now := time.Now()
+		record := models.IdempotencyRecord{
+			Owner:       UserName(r.Context()),
+			Key:         key,
+			RequestHash: requestHash(r, body),
+			CreateAt:    now,
+			ExpireAt:    now.Add(idempotencyLockTimeout),
+		}
+
+		existing, reserved, err := i.store.ReserveIdempotencyKey(r.Context(), record)
+		if err != nil {
+			response.Error(w, r, err)
+			return
+		}
+
+		if !reserved {
+			replay(w, r, existing, record.RequestHash)
+			return
+		}
+
+		// headers set before, e.g. by Deprecated, are set on replays again
+		before := w.Header().Clone()
+
+		recorder := &recordingWriter{ResponseWriter: w, statusCode: http.StatusOK}
+		next.ServeHTTP(recorder, r)
+
+		// the response must be stored even when the client has already given up
+		ctx := context.WithoutCancel(r.Context())
+
+		// server errors are not stored so that the retry gets another chance
+		if recorder.statusCode >= http.StatusInternalServerError {
+			if err := i.store.DeleteIdempotencyKey(ctx, record.Owner, record.Key); err != nil {
+				log.Println("ERROR: ", err)
+			}
+			return
+		}
+
+		record.Status = recorder.statusCode
+		record.ContentType = recorder.Header().Get("Content-Type")
+		record.Header = setHeaders(before, recorder.Header())
+		record.Body = recorder.body.Bytes()
+		record.ExpireAt = time.Now().Add(i.ttl)
+
+		if err := i.store.SaveIdempotencyResponse(ctx, record); err != nil {
+			log.Println("ERROR: ", err)
+		}
+	})
+}
+
+// replay answers a request whose key is already held by existing.
+func replay(w http.ResponseWriter, r *http.Request, existing models.IdempotencyRecord, requestHash string) {
+	switch {
+	case existing.RequestHash != requestHash:
+		response.Error(w, r, apperrors.Conflict("%s %s was already used for a different request", IdempotencyKeyHeader, existing.Key))
+	case existing.Status == 0:
+		response.Error(w, r, apperrors.Conflict("a request with %s %s is still being processed", IdempotencyKeyHeader, existing.Key))
+	default:
+		for name, values := range existing.Header {
+			w.Header()[name] = values
+		}
+		if existing.ContentType != "" {
+			w.Header().Set("Content-Type", existing.ContentType)
+		}
+		w.Header().Set(IdempotentReplayedHeader, "true")
+		w.WriteHeader(existing.Status)
+
+		if _, err := w.Write(existing.Body); err != nil {
+			log.Println("ERROR: ", err)
+		}
+	}
+}
+
+// setHeaders returns the headers of after that are not in before, or that
+// differ from it, except Content-Type, which is stored on its own.
+func setHeaders(before, after http.Header) http.Header {
+	set := http.Header{}
+	for name, values := range after {
+		if name != "Content-Type" && !slices.Equal(before[name], values) {
+			set[name] = slices.Clone(values)
+		}
+	}
+	return set
+}
+
+// requestHash identifies what a request asks for, so that a key reused for
+// another request can be told apart from a retry. The same body sent as
+// another format, or asking for another format back, is another request.
+func requestHash(r *http.Request, body []byte) string {
+	hash := sha256.New()
+	hash.Write([]byte(r.Method + " " + r.URL.Path + "\n"))
+	hash.Write([]byte("Content-Type: " + r.Header.Get("Content-Type") + "\n"))
+	hash.Write([]byte("Accept: " + r.Header.Get("Accept") + "\n"))
+	hash.Write(body)
+
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// recordingWriter passes the response through while keeping a copy of it.
+type recordingWriter struct {
+	http.ResponseWriter
+	statusCode int
+	body       bytes.Buffer
+}
+
+func (rw *recordingWriter) WriteHeader(statusCode int) {
+	rw.statusCode = statusCode
+	rw.ResponseWriter.WriteHeader(statusCode)
+}
+
+func (rw *recordingWriter) Write(b []byte) (int, error) {
+	rw.body.Write(b)
+	return rw.ResponseWriter.Write(b)
+}
@@ -0,0 +1,56 @@
+package middleware
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/geekAshish/DriveDesk/store/memory"
+)
+
+func TestIdempotencyReplaysHeaders(t *testing.T) {
+	created := 0
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		created++
+		w.Header().Set("Location", "/v2/cars/1")
+		w.Header().Set("ETag", `"abc"`)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"id":"1"}`))
+	})
+	idempotency := NewIdempotency(memory.NewIdempotencyStore(memory.NewDB()), time.Hour)
+	router := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Deprecation", "true")
+		idempotency.Middleware(handler).ServeHTTP(w, r)
+	})
+
+	post := func(accept string) func(r *http.Request) {
+		return func(r *http.Request) {
+			asUser("alice", "user")(r)
+			r.Body = http.NoBody
+			r.Header.Set(IdempotencyKeyHeader, "retry-1")
+			r.Header.Set("Content-Type", "application/json")
+			r.Header.Set("Accept", accept)
+		}
+	}
+
+	first := request(router, http.MethodPost, "/v2/cars", post("application/json"))
+	retry := request(router, http.MethodPost, "/v2/cars", post("application/json"))
+	if created != 1 || retry.Code != http.StatusCreated || retry.Header().Get(IdempotentReplayedHeader) != "true" {
+		t.Fatalf("retry = %d with %v after %d requests ran, want the first response replayed", retry.Code, retry.Header(), created)
+	}
+	for _, name := range []string{"Location", "ETag", "Content-Type", "Deprecation"} {
+		if got, want := retry.Header().Get(name), first.Header().Get(name); got != want {
+			t.Errorf("replayed %s = %q, want %q", name, got, want)
+		}
+	}
+	if !strings.Contains(retry.Body.String(), `"id":"1"`) {
+		t.Errorf("replayed body = %q", retry.Body.String())
+	}
+
+	// the same body asking for another format is another request
+	if got := request(router, http.MethodPost, "/v2/cars", post("application/xml")); got.Code != http.StatusConflict {
+		t.Errorf("retry with another Accept = %d, want 409", got.Code)
+	}
+}
@@ -1,9 +1,12 @@
 package middleware
 
 import (
+	"bufio"
+	"net"
 	"net/http"
 	"time"
 
+	"github.com/gorilla/mux"
 	"github.com/prometheus/client_golang/prometheus"
 )
 
@@ -13,7 +16,7 @@ var (
 			Name: "http_requests_total",
 			Help: "Total number of http request",
 		},
-		[]string{"path", "method"},
+		[]string{"version", "path", "method"},
 	)
 
 	requestDuration = prometheus.NewHistogramVec(
@@ -21,7 +24,7 @@ var (
 			Name: "http_requests_duration_seconds",
 			Help: "Duration of http request in seconds",
 		},
-		[]string{"path", "method"},
+		[]string{"version", "path", "method"},
 	)
 
 	statusCounter = prometheus.NewCounterVec(
@@ -29,7 +32,7 @@ var (
 			Name: "http_response_status_total",
 			Help: "Total number of http request by status code",
 		},
-		[]string{"path", "method", "status_code"},
+		[]string{"version", "path", "method", "status_code"},
 	)
 )
 
@@ -54,15 +57,41 @@ func MetricMiddleware(next http.Handler) http.Handler {
 		// duration of the request
 		duration := time.Since(start).Seconds()
 
-		requestCounter.WithLabelValues(r.URL.Path, r.Method).Inc()
+		version := APIVersion(r.URL.Path)
+		path := pathTemplate(r)
 
-		requestDuration.WithLabelValues(r.URL.Path, r.Method).Observe(duration)
+		requestCounter.WithLabelValues(version, path, r.Method).Inc()
 
-		statusCounter.WithLabelValues(r.URL.Path, r.Method, http.StatusText(ww.statusCode)).Inc()
+		requestDuration.WithLabelValues(version, path, r.Method).Observe(duration)
+
+		statusCounter.WithLabelValues(version, path, r.Method, http.StatusText(ww.statusCode)).Inc()
 	})
 }
 
+// pathTemplate is the template of the route r matched, e.g. /v2/cars/{id},
+// which keeps metric labels bounded, unlike the raw path with its ids.
+func pathTemplate(r *http.Request) string {
+	if current := mux.CurrentRoute(r); current != nil {
+		if template, err := current.GetPathTemplate(); err == nil {
+			return template
+		}
+	}
+	return r.URL.Path
+}
+
 func (wr *responseWriter) WriteHeader(statusCode int) {
-	wr.statusCode = statusCode;
+	wr.statusCode = statusCode
 	wr.ResponseWriter.WriteHeader(statusCode)
-}
\ No newline at end of file
+}
+
+// Unwrap lets http.ResponseController reach the connection, which streaming
+// responses need to flush.
+func (wr *responseWriter) Unwrap() http.ResponseWriter {
+	return wr.ResponseWriter
+}
+
+// Hijack hands the connection over to WebSocket upgrades, which look for an
+// http.Hijacker rather than using http.ResponseController.
+func (wr *responseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	return http.NewResponseController(wr.ResponseWriter).Hijack()
+}
@@ -0,0 +1,29 @@
+package middleware
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestMetricsLabelRouteTemplate(t *testing.T) {
+	router := mux.NewRouter()
+	router.Use(MetricMiddleware)
+	router.HandleFunc("/v2/cars/{id}", func(w http.ResponseWriter, r *http.Request) {}).Methods("GET")
+
+	counter := requestCounter.WithLabelValues("v2", "/v2/cars/{id}", http.MethodGet)
+	before := testutil.ToFloat64(counter)
+
+	for _, id := range []string{"c7c1a6d5-1ec4-4c64-a59a-8a2f6f3d2bf3", "9d6a56f8-79c3-4931-a5c0-6b290c84ba2f"} {
+		request(router, http.MethodGet, "/v2/cars/"+id, nil)
+	}
+
+	if got := testutil.ToFloat64(counter) - before; got != 2 {
+		t.Errorf("requests counted under the route template = %v, want 2", got)
+	}
+	if got := testutil.CollectAndCount(requestCounter); got != 1 {
+		t.Errorf("%d request counter series, want one for both ids", got)
+	}
+}
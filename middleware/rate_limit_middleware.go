@@ -0,0 +1,267 @@
+package middleware
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/geekAshish/DriveDesk/apperrors"
+	"github.com/geekAshish/DriveDesk/handler/response"
+	"github.com/geekAshish/DriveDesk/models"
+	"github.com/geekAshish/DriveDesk/store"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var rateLimitedRequestCounter = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "rate_limited_requests_total",
+		Help: "Total number of requests refused with 429 Too Many Requests",
+	},
+	[]string{"route", "method"},
+)
+
+func init() {
+	prometheus.MustRegister(rateLimitedRequestCounter)
+}
+
+// RateLimiter refuses the requests of clients that exceed their limits with
+// 429 Too Many Requests. Every request takes a token from the client's
+// bucket for all its requests and, if its route has a policy, from the
+// client's bucket for that route; see models.RateLimitConfig. Users are only
+// told apart after AuthMiddleware ran, before that they count by IP address.
+// A nil *RateLimiter passes every request through.
+type RateLimiter struct {
+	store  store.RateLimitStoreInterface
+	config models.RateLimitConfig
+	now    func() time.Time
+}
+
+// NewRateLimiter keeps the buckets in store, which is shared by the
+// instances that use the same database.
+func NewRateLimiter(store store.RateLimitStoreInterface, config models.RateLimitConfig) *RateLimiter {
+	return &RateLimiter{store: store, config: config, now: time.Now}
+}
+
+// RateLimitDecision is the outcome of taking the tokens of one request.
+type RateLimitDecision struct {
+	Allowed bool
+	// Limit and Bucket are those of the bucket that refused the request,
+	// or else of the one closest to empty; Limit is zero when nothing
+	// limited the request.
+	Limit  models.RateLimit
+	Bucket models.RateLimitBucket
+	// Policies lists the limits applied, as RateLimit-Policy does.
+	Policies []string
+}
+
+// RetryAfter is how many seconds a refused client should wait.
+func (d RateLimitDecision) RetryAfter() int {
+	return seconds(d.Bucket.RetryAfter(d.Limit))
+}
+
+// Err is the error a refused request is answered with.
+func (d RateLimitDecision) Err() error {
+	return apperrors.TooManyRequests("rate limit exceeded, retry in %d seconds", d.RetryAfter())
+}
+
+// rateLimitCheck is one bucket a request takes a token from.
+type rateLimitCheck struct {
+	key   string
+	limit models.RateLimit
+}
+
+// Take takes the tokens of a request of client, as returned by UserClient or
+// AddressClient, with role to the route with method and path, for the
+// transports that cannot use Middleware.
+func (l *RateLimiter) Take(ctx context.Context, client, role, method, route string) RateLimitDecision {
+	if l == nil {
+		return RateLimitDecision{Allowed: true}
+	}
+
+	// the route goes first: its limit is usually the tighter one, and a
+	// request it refuses should not use up the client's overall limit
+	var checks []rateLimitCheck
+	if key, policy, ok := l.config.Route(method, route); ok {
+		checks = append(checks, rateLimitCheck{key: client + " " + key, limit: policy.For(role)})
+	}
+	checks = append(checks, rateLimitCheck{key: client, limit: l.config.Default.For(role)})
+
+	decision := l.take(ctx, checks)
+	if !decision.Allowed {
+		rateLimitedRequestCounter.WithLabelValues(route, method).Inc()
+	}
+	return decision
+}
+
+// TakePreAuth takes the token of a request of client, by address or API
+// key, before its credentials are checked. See models.RateLimitConfig.
+func (l *RateLimiter) TakePreAuth(ctx context.Context, client, method, route string) RateLimitDecision {
+	if l == nil {
+		return RateLimitDecision{Allowed: true}
+	}
+
+	decision := l.take(ctx, []rateLimitCheck{{key: "preauth " + client, limit: l.config.PreAuth}})
+	if !decision.Allowed {
+		rateLimitedRequestCounter.WithLabelValues(route, method).Inc()
+	}
+	return decision
+}
+
+func (l *RateLimiter) take(ctx context.Context, checks []rateLimitCheck) RateLimitDecision {
+	decision := RateLimitDecision{Allowed: true, Policies: []string{}}
+	now := l.now()
+
+	for _, check := range checks {
+		if check.limit.Unlimited() {
+			continue
+		}
+
+		bucket, taken, err := l.store.TakeRateLimitToken(ctx, check.key, check.limit, now)
+		if err != nil {
+			// an unreachable store must not take the API down with it
+			log.Println("ERROR: ", err)
+			continue
+		}
+		decision.Policies = append(decision.Policies, rateLimitPolicy(check.limit))
+
+		if !taken {
+			decision.Allowed = false
+			decision.Limit, decision.Bucket = check.limit, bucket
+			return decision
+		}
+
+		if decision.Limit.Unlimited() || bucket.Remaining() < decision.Bucket.Remaining() {
+			decision.Limit, decision.Bucket = check.limit, bucket
+		}
+	}
+
+	return decision
+}
+
+func (l *RateLimiter) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if l == nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		decision := l.Take(r.Context(), l.client(r), Role(r.Context()), r.Method, routeTemplate(r))
+		if !serveDecision(w, r, decision) {
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// PreAuth limits the requests of each address or API key before
+// AuthMiddleware checks their token, so that a flood of requests with
+// invalid tokens is refused before costing a signature check each. It goes
+// before AuthMiddleware, and Middleware after it.
+func (l *RateLimiter) PreAuth(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if l == nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		decision := l.TakePreAuth(r.Context(), l.client(r), r.Method, routeTemplate(r))
+		if decision.Allowed {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		serveDecision(w, r, decision)
+	})
+}
+
+// serveDecision sets the RateLimit headers of decision and answers 429 when
+// it refused the request, reporting whether the request may go on.
+func serveDecision(w http.ResponseWriter, r *http.Request, decision RateLimitDecision) bool {
+	if !decision.Limit.Unlimited() {
+		header := w.Header()
+		header.Set("RateLimit-Limit", strconv.Itoa(decision.Limit.Burst))
+		header.Set("RateLimit-Remaining", strconv.Itoa(decision.Bucket.Remaining()))
+		header.Set("RateLimit-Reset", strconv.Itoa(seconds(decision.Bucket.ResetAfter(decision.Limit))))
+		header.Set("RateLimit-Policy", strings.Join(decision.Policies, ", "))
+	}
+
+	if decision.Allowed {
+		return true
+	}
+
+	w.Header().Set("Retry-After", strconv.Itoa(decision.RetryAfter()))
+	response.Error(w, r, decision.Err())
+	return false
+}
+
+// client identifies who sent r: the authenticated user, the API key or the
+// IP address, in that order.
+func (l *RateLimiter) client(r *http.Request) string {
+	if userName := UserName(r.Context()); userName != "" {
+		return UserClient(userName)
+	}
+
+	if l.config.APIKeyHeader != "" {
+		if apiKey := r.Header.Get(l.config.APIKeyHeader); apiKey != "" {
+			// the key itself is a secret and stays out of the store
+			sum := sha256.Sum256([]byte(apiKey))
+			return "key:" + hex.EncodeToString(sum[:16])
+		}
+	}
+
+	if l.config.ClientIPHeader != "" {
+		if forwarded := r.Header.Get(l.config.ClientIPHeader); forwarded != "" {
+			// the proxy in front of us appends the address it saw last
+			entries := strings.Split(forwarded, ",")
+			return "ip:" + strings.TrimSpace(entries[len(entries)-1])
+		}
+	}
+
+	return AddressClient(r.RemoteAddr)
+}
+
+// UserClient is the client key of an authenticated user.
+func UserClient(userName string) string {
+	return "user:" + userName
+}
+
+// AddressClient is the client key of a network address, with or without a
+// port.
+func AddressClient(addr string) string {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		host = addr
+	}
+	return "ip:" + host
+}
+
+// routeTemplate is the route r matched without its version prefix, so that
+// limits apply to every version of a route alike.
+func routeTemplate(r *http.Request) string {
+	route := pathTemplate(r)
+	if version := APIVersion(route); version != "unversioned" {
+		route = strings.TrimPrefix(route, "/"+version)
+	}
+	return route
+}
+
+// rateLimitPolicy describes limit as a quota of Burst requests in the
+// window it takes to refill them.
+func rateLimitPolicy(limit models.RateLimit) string {
+	window := seconds(time.Duration(float64(limit.Burst) / limit.Rate * float64(time.Second)))
+	return fmt.Sprintf("%d;w=%d", limit.Burst, window)
+}
+
+// seconds rounds d up to whole seconds, as the headers count them.
+func seconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
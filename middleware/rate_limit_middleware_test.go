@@ -0,0 +1,184 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/geekAshish/DriveDesk/models"
+	"github.com/geekAshish/DriveDesk/store/memory"
+	"github.com/gorilla/mux"
+)
+
+var testRateLimits = models.RateLimitConfig{
+	Default: models.RateLimitPolicy{
+		RateLimit: models.RateLimit{Rate: 1, Burst: 3},
+		Roles:     map[string]models.RateLimit{RoleAdmin: {Rate: 10, Burst: 10}},
+	},
+	Routes: map[string]models.RateLimitPolicy{
+		"POST /login": {RateLimit: models.RateLimit{Rate: 0.5, Burst: 2}},
+	},
+	APIKeyHeader: "X-API-Key",
+}
+
+// newRateLimitedRouter serves /login and /cars under v1 and v2 like the API
+// does, behind a RateLimiter whose clock is now.
+func newRateLimitedRouter(now *time.Time) *mux.Router {
+	limiter := NewRateLimiter(memory.NewRateLimitStore(memory.NewDB()), testRateLimits)
+	limiter.now = func() time.Time { return *now }
+
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+
+	router := mux.NewRouter()
+	for _, version := range []string{"/v1", "/v2"} {
+		r := router.PathPrefix(version).Subrouter()
+		r.Handle("/login", limiter.Middleware(ok)).Methods("POST")
+		r.Handle("/cars", limiter.Middleware(ok)).Methods("GET")
+	}
+	return router
+}
+
+func request(router http.Handler, method, path string, prepare func(r *http.Request)) *httptest.ResponseRecorder {
+	r := httptest.NewRequest(method, path, nil)
+	r.RemoteAddr = "192.0.2.1:5000"
+	if prepare != nil {
+		prepare(r)
+	}
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, r)
+	return recorder
+}
+
+func asUser(name, role string) func(r *http.Request) {
+	return func(r *http.Request) {
+		ctx := context.WithValue(r.Context(), userNameKey{}, name)
+		*r = *r.WithContext(context.WithValue(ctx, roleKey{}, role))
+	}
+}
+
+func TestRateLimitRoute(t *testing.T) {
+	now := time.Now()
+	router := newRateLimitedRouter(&now)
+
+	// the login limit counts v1 and v2 together
+	for _, path := range []string{"/v1/login", "/v2/login"} {
+		if got := request(router, http.MethodPost, path, nil); got.Code != http.StatusOK {
+			t.Fatalf("POST %s = %d, want 200", path, got.Code)
+		}
+	}
+
+	got := request(router, http.MethodPost, "/v2/login", nil)
+	if got.Code != http.StatusTooManyRequests {
+		t.Fatalf("third login = %d, want 429", got.Code)
+	}
+	for name, want := range map[string]string{
+		"Retry-After":         "2",
+		"RateLimit-Limit":     "2",
+		"RateLimit-Remaining": "0",
+		"RateLimit-Reset":     "4",
+		"RateLimit-Policy":    "2;w=4",
+		"Content-Type":        "application/problem+json",
+	} {
+		if value := got.Header().Get(name); value != want {
+			t.Errorf("%s = %q, want %q", name, value, want)
+		}
+	}
+
+	// the refused login did not count against the overall limit
+	got = request(router, http.MethodGet, "/v1/cars", nil)
+	if got.Code != http.StatusOK || got.Header().Get("RateLimit-Remaining") != "0" || got.Header().Get("RateLimit-Policy") != "3;w=3" {
+		t.Errorf("GET /v1/cars = %d with %v, want the last overall token taken", got.Code, got.Header())
+	}
+
+	// another address has limits of its own
+	if got := request(router, http.MethodPost, "/v1/login", func(r *http.Request) { r.RemoteAddr = "192.0.2.2:5000" }); got.Code != http.StatusOK {
+		t.Errorf("login from another address = %d, want 200", got.Code)
+	}
+
+	now = now.Add(2 * time.Second)
+	if got := request(router, http.MethodPost, "/v1/login", nil); got.Code != http.StatusOK {
+		t.Errorf("login after Retry-After = %d, want 200", got.Code)
+	}
+}
+
+func TestRateLimitClients(t *testing.T) {
+	now := time.Now()
+	router := newRateLimitedRouter(&now)
+
+	exhaust := func(prepare func(r *http.Request)) int {
+		for i := 0; ; i++ {
+			if got := request(router, http.MethodGet, "/v2/cars", prepare); got.Code == http.StatusTooManyRequests {
+				return i
+			}
+		}
+	}
+
+	// in order: the last two come back to clients limited before
+	for _, test := range []struct {
+		name    string
+		prepare func(r *http.Request)
+		want    int
+	}{
+		{"address", nil, 3},
+		{"user", asUser("alice", "user"), 3},
+		{"other user", asUser("bob", "user"), 3},
+		{"admin", asUser("root", RoleAdmin), 10},
+		{"api key", func(r *http.Request) { r.Header.Set("X-API-Key", "secret") }, 3},
+		{"other api key", func(r *http.Request) { r.Header.Set("X-API-Key", "other") }, 3},
+		{"user with a key", func(r *http.Request) { asUser("carol", "user")(r); r.Header.Set("X-API-Key", "secret") }, 3},
+		{"unconfigured ip header", func(r *http.Request) { r.Header.Set("X-Forwarded-For", "203.0.113.9") }, 0},
+		{"same user", asUser("alice", "user"), 0},
+	} {
+		if got := exhaust(test.prepare); got != test.want {
+			t.Errorf("%s: %d requests allowed, want %d", test.name, got, test.want)
+		}
+	}
+}
+
+func TestRateLimitClientIPHeader(t *testing.T) {
+	limiter := NewRateLimiter(memory.NewRateLimitStore(memory.NewDB()), models.RateLimitConfig{ClientIPHeader: "X-Forwarded-For"})
+
+	r := httptest.NewRequest(http.MethodGet, "/cars", nil)
+	r.RemoteAddr = "10.0.0.1:5000"
+	if client := limiter.client(r); client != "ip:10.0.0.1" {
+		t.Errorf("client without the header = %q", client)
+	}
+
+	r.Header.Set("X-Forwarded-For", "198.51.100.7, 203.0.113.9")
+	if client := limiter.client(r); client != "ip:203.0.113.9" {
+		t.Errorf("client = %q, want the address the proxy saw", client)
+	}
+}
+
+func TestRateLimitUnlimited(t *testing.T) {
+	var limiter *RateLimiter
+	handler := limiter.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	for range 100 {
+		if got := request(handler, http.MethodGet, "/cars", nil); got.Code != http.StatusOK || got.Header().Get("RateLimit-Limit") != "" {
+			t.Fatalf("nil limiter answered %d with %v", got.Code, got.Header())
+		}
+	}
+}
+
+func TestRateLimitPreAuth(t *testing.T) {
+	limiter := NewRateLimiter(memory.NewRateLimitStore(memory.NewDB()), models.RateLimitConfig{
+		PreAuth: models.RateLimit{Rate: 1, Burst: 2},
+	})
+	handler := limiter.PreAuth(AuthMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})))
+
+	withToken := func(r *http.Request) { r.Header.Set("Authorization", "Bearer not-a-token") }
+	for range 2 {
+		if got := request(handler, http.MethodGet, "/cars", withToken); got.Code != http.StatusUnauthorized {
+			t.Fatalf("invalid token = %d, want 401", got.Code)
+		}
+	}
+
+	got := request(handler, http.MethodGet, "/cars", withToken)
+	if got.Code != http.StatusTooManyRequests || got.Header().Get("Retry-After") != "1" {
+		t.Errorf("invalid token past the limit = %d with %v, want 429 before the token is checked", got.Code, got.Header())
+	}
+}
@@ -1,125 +1,142 @@
 package models
 
 import (
-	"errors"
-	"slices"
+	"regexp"
 	"strconv"
 	"time"
 
+	"github.com/geekAshish/DriveDesk/validation"
 	"github.com/google/uuid"
 )
 
 type Car struct {
-	ID       uuid.UUID `json:"id"`
-	Name     string    `json:"name"`
-	Year     string    `json:"year"`
-	Brand    string    `json:"brand"`
-	FuelType string    `json:"fuel_type"`
-	Price    float64   `json:"price"`
-	Engine   Engine    `json:"engine"`
-	CreateAt time.Time `json:"created_at"`
-	UpdateAt time.Time `json:"updated_at"`
+	ID       uuid.UUID `json:"id" xml:"id"`
+	Name     string    `json:"name" xml:"name"`
+	Year     string    `json:"year" xml:"year"`
+	Brand    string    `json:"brand" xml:"brand"`
+	FuelType string    `json:"fuel_type" xml:"fuel_type"`
+	Price    float64   `json:"price" xml:"price"`
+	VIN      string    `json:"vin,omitempty" xml:"vin,omitempty"`
+	Engine   Engine    `json:"engine" xml:"engine"`
+	CreateAt time.Time `json:"created_at" xml:"created_at"`
+	UpdateAt time.Time `json:"updated_at" xml:"updated_at"`
 }
 
-type CarRequest struct {
-	Name     string    `json:"name"`
-	Year     string    `json:"year"`
-	Brand    string    `json:"brand"`
-	FuelType string    `json:"fuel_type"`
-	Price    float64   `json:"price"`
-	Engine   Engine    `json:"engine"`
-}
-
-func ValidateRequest(carRequest CarRequest) error {
-	if err := validateName(carRequest.Name); err != nil {
-		return err
-	}
-	if err := validateYear(carRequest.Year); err != nil {
-		return err
+// LastModified is when the car as returned last changed: its own
+// updated_at, or that of its engine when the engine changed later.
+func (c Car) LastModified() time.Time {
+	if c.Engine.UpdateAt.After(c.UpdateAt) {
+		return c.Engine.UpdateAt
 	}
-	if err := validateFuelType(carRequest.FuelType); err != nil {
-		return err
-	}
-	if err := validateEngine(carRequest.Engine); err != nil {
-		return err
-	}
-	if err := validatePrice(carRequest.Price); err != nil {
-		return err
-	}
-	return nil
+	return c.UpdateAt
 }
 
-func validateName(name string) error {
-	if name == "" {
-		return errors.New("name is required")
-	}
-
-	return  nil
+type CarRequest struct {
+	Name     string  `json:"name" xml:"name"`
+	Year     string  `json:"year" xml:"year"`
+	Brand    string  `json:"brand" xml:"brand"`
+	FuelType string  `json:"fuel_type" xml:"fuel_type"`
+	Price    float64 `json:"price" xml:"price"`
+	VIN      string  `json:"vin,omitempty" xml:"vin,omitempty"`
+	Engine   Engine  `json:"engine" xml:"engine"`
 }
 
-func validateYear(year string) error {
-	if year == "" {
-		return errors.New("year is required")
-	}
+// ValidateRequest checks every field of carRequest and reports all the
+// violations it finds together. Enumerated fields such as the fuel type are
+// checked against enums. checks run after the built-in rules, on fields
+// those did not already reject.
+func ValidateRequest(carRequest CarRequest, enums EnumLookup, checks ...validation.Check) error {
+	v := validation.New()
 
-	_, err := strconv.Atoi(year);
-	if err != nil {
-		return errors.New("year must be a valid number")
-	}
+	validateName(v, carRequest.Name)
+	validateYear(v, carRequest.Year)
+	validateFuelType(v, carRequest.FuelType, enums)
+	validateEngine(v.Nested("engine"), carRequest.Engine)
+	validatePrice(v, carRequest.Price)
+	validateVIN(v, carRequest.VIN)
 
-	currentYear := time.Now().Year()
-	yearInt, _ := strconv.Atoi(year)
-	if yearInt < 1886 || yearInt > currentYear {
-		return errors.New("year must be between 1886 and the current year")
+	for _, check := range checks {
+		check(v)
 	}
 
-	return  nil
+	return v.Err()
 }
 
-
-func validateFuelType(fuelType string) error {
-	if fuelType == "" {
-		return errors.New("fuel type is required")
-	}
-
-	validFuelType := []string{"petrol", "diesel", "electric", "hybrid"};
-
-	isValid := slices.Contains(validFuelType, fuelType)
-
-	if isValid {
-		return  nil
-	}
-
-	return errors.New("not a valid fuel type")
+func validateName(v *validation.Validator, name string) {
+	validation.Field(v, "name", name,
+		validation.Required[string]("name is required"),
+	)
 }
 
+func validateYear(v *validation.Validator, year string) {
+	validation.Field(v, "year", year,
+		validation.Required[string]("year is required"),
+		validation.Rule[string]{
+			Code:    "not_a_number",
+			Message: "year must be a valid number",
+			Test: func(year string) bool {
+				_, err := strconv.Atoi(year)
+				return err == nil
+			},
+		},
+		validation.Rule[string]{
+			Code:    "out_of_range",
+			Message: "year must be between 1886 and the current year",
+			Test: func(year string) bool {
+				yearInt, _ := strconv.Atoi(year)
+				return yearInt >= 1886 && yearInt <= time.Now().Year()
+			},
+		},
+	)
+}
 
-func validateEngine(engine Engine) error {
-	if engine.EngineID == uuid.Nil {
-		return errors.New("engine ID is required")
-	}
-
-	if engine.Dispacement <= 0 {
-		return errors.New("engine displacement must be greater than zero")
-	}
-
-	if engine.NoOfCylinders <= 0 {
-		return errors.New("number of cylinders must be greater than zero")
-	}
-
-	if engine.CarRange <= 0 {
-		return errors.New("car range must be greater than zero")
-	}
-
-	return nil
+func validateFuelType(v *validation.Validator, fuelType string, enums EnumLookup) {
+	validation.Field(v, "fuel_type", fuelType,
+		validation.Required[string]("fuel type is required"),
+		validation.Rule[string]{
+			Code:    "invalid_choice",
+			Message: "not a valid fuel type",
+			Test: func(fuelType string) bool {
+				_, ok := enums.Canonical(ReferenceKindFuelType, fuelType)
+				return ok
+			},
+		},
+	)
 }
 
-func validatePrice(price float64) error {
-	if price <= 0 {
-		return errors.New("price must be greater than zero")
-	}
+func validateEngine(v *validation.Validator, engine Engine) {
+	validation.Field(v, "engine_id", engine.EngineID,
+		validation.Required[uuid.UUID]("engine ID is required"),
+	)
+	validation.Field(v, "dispacement", engine.Dispacement,
+		validation.Positive[float64]("engine displacement must be greater than zero"),
+	)
+	validation.Field(v, "no_of_cylinders", engine.NoOfCylinders,
+		validation.Positive[float64]("number of cylinders must be greater than zero"),
+	)
+	validation.Field(v, "car_range", engine.CarRange,
+		validation.Positive[float64]("car range must be greater than zero"),
+	)
+}
 
-	return nil
+// VINs are 17 characters and never use I, O or Q. The VIN is optional unless
+// a deployment rule requires it.
+var vinFormat = regexp.MustCompile(`^[A-HJ-NPR-Z0-9]{17}$`)
+
+func validateVIN(v *validation.Validator, vin string) {
+	validation.Field(v, "vin", vin,
+		validation.Rule[string]{
+			Code:    "invalid_format",
+			Message: "VIN must be 17 letters or digits, without I, O or Q",
+			Test: func(vin string) bool {
+				return vin == "" || vinFormat.MatchString(vin)
+			},
+		},
+	)
 }
 
- 
\ No newline at end of file
+func validatePrice(v *validation.Validator, price float64) {
+	validation.Field(v, "price", price,
+		validation.Positive[float64]("price must be greater than zero"),
+	)
+}
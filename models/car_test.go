@@ -0,0 +1,76 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/geekAshish/DriveDesk/apperrors"
+	"github.com/google/uuid"
+)
+
+func TestValidateRequestReportsAllFields(t *testing.T) {
+	err := ValidateRequest(CarRequest{
+		Name:     "",
+		Year:     "next year",
+		Brand:    "Honda",
+		FuelType: "steam",
+		Price:    0,
+		Engine:   Engine{EngineID: uuid.New(), Dispacement: 2000, NoOfCylinders: 0, CarRange: -1},
+	}, DefaultEnums)
+
+	got := map[string]string{}
+	for _, field := range apperrors.Fields(err) {
+		got[field.Field] = field.Code
+	}
+
+	want := map[string]string{
+		"name":                   "required",
+		"year":                   "not_a_number",
+		"fuel_type":              "invalid_choice",
+		"price":                  "must_be_positive",
+		"engine.no_of_cylinders": "must_be_positive",
+		"engine.car_range":       "must_be_positive",
+	}
+
+	if len(got) != len(want) {
+		t.Fatalf("field errors = %v, want %v", got, want)
+	}
+	for field, code := range want {
+		if got[field] != code {
+			t.Errorf("%s: code = %q, want %q", field, got[field], code)
+		}
+	}
+}
+
+func TestValidateRequestAcceptsValidCar(t *testing.T) {
+	err := ValidateRequest(CarRequest{
+		Name:     "Honda Civic",
+		Year:     "2023",
+		Brand:    "Honda",
+		FuelType: "petrol",
+		Price:    25000,
+		Engine:   Engine{EngineID: uuid.New(), Dispacement: 2000, NoOfCylinders: 4, CarRange: 600},
+	}, DefaultEnums)
+
+	if err != nil {
+		t.Fatalf("ValidateRequest: %v", err)
+	}
+}
+
+func TestValidateRequestAcceptsFuelTypeAliases(t *testing.T) {
+	err := ValidateRequest(CarRequest{
+		Name:     "Honda Civic",
+		Year:     "2023",
+		Brand:    "Honda",
+		FuelType: "Gasoline",
+		Price:    25000,
+		Engine:   Engine{EngineID: uuid.New(), Dispacement: 2000, NoOfCylinders: 4, CarRange: 600},
+	}, DefaultEnums)
+
+	if err != nil {
+		t.Fatalf("ValidateRequest: %v", err)
+	}
+
+	if code, _ := DefaultEnums.Canonical(ReferenceKindFuelType, "Gasoline"); code != "petrol" {
+		t.Fatalf("Canonical(Gasoline) = %q, want petrol", code)
+	}
+}
@@ -0,0 +1,43 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// CloudEvents attributes of the events published to brokers and files. The
+// type is the event type under CloudEventTypePrefix, e.g.
+// "com.drivedesk.car.created".
+const (
+	CloudEventSpecVersion = "1.0"
+	CloudEventContentType = "application/cloudevents+json"
+	CloudEventSource      = "/drivedesk/inventory"
+	CloudEventTypePrefix  = "com.drivedesk."
+)
+
+// CloudEvent is an event in the structured JSON format of CloudEvents 1.0.
+// Subject is the id of the car or engine and Data the resource, as in Event.
+type CloudEvent struct {
+	SpecVersion     string          `json:"specversion"`
+	ID              string          `json:"id"`
+	Source          string          `json:"source"`
+	Type            string          `json:"type"`
+	Subject         string          `json:"subject"`
+	Time            time.Time       `json:"time"`
+	DataContentType string          `json:"datacontenttype"`
+	Data            json.RawMessage `json:"data"`
+}
+
+// CloudEvent is the event the message carries, as a CloudEvent.
+func (m OutboxMessage) CloudEvent() CloudEvent {
+	return CloudEvent{
+		SpecVersion:     CloudEventSpecVersion,
+		ID:              m.EventID.String(),
+		Source:          CloudEventSource,
+		Type:            CloudEventTypePrefix + m.EventType,
+		Subject:         m.AggregateID,
+		Time:            m.CreateAt,
+		DataContentType: "application/json",
+		Data:            m.Payload,
+	}
+}
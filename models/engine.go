@@ -1,55 +1,58 @@
 package models
 
 import (
-	"errors"
 	"time"
 
+	"github.com/geekAshish/DriveDesk/validation"
 	"github.com/google/uuid"
 )
 
 type Engine struct {
-	EngineID      uuid.UUID `json:"engine_id"`
-	Dispacement   float64   `json:"dispacement"`
-	NoOfCylinders float64   `json:"no_of_cylinders"`
-	CarRange      float64   `json:"car_range"`
-	CreateAt      time.Time `json:"created_at"`
-	UpdateAt      time.Time `json:"updated_at"`
+	EngineID      uuid.UUID `json:"engine_id" xml:"engine_id"`
+	Dispacement   float64   `json:"dispacement" xml:"dispacement"`
+	NoOfCylinders float64   `json:"no_of_cylinders" xml:"no_of_cylinders"`
+	CarRange      float64   `json:"car_range" xml:"car_range"`
+	CreateAt      time.Time `json:"created_at" xml:"created_at"`
+	UpdateAt      time.Time `json:"updated_at" xml:"updated_at"`
 }
 
 type EngineRequest struct {
-	Dispacement   float64 `json:"dispacement"`
-	NoOfCylinders float64 `json:"no_of_cylinders"`
-	CarRange      float64 `json:"car_range"`
+	Dispacement   float64 `json:"dispacement" xml:"dispacement"`
+	NoOfCylinders float64 `json:"no_of_cylinders" xml:"no_of_cylinders"`
+	CarRange      float64 `json:"car_range" xml:"car_range"`
 }
 
-func ValidateEngineRequest(engineRequest EngineRequest) error {
-	if err := validateDispacement(engineRequest.Dispacement); err != nil {
-		return err
-	}
-	if err := validateNoOfCylinders(engineRequest.NoOfCylinders); err != nil {
-		return err
-	}
-	if err := validateCarRange(engineRequest.CarRange); err != nil {
-		return err
+// ValidateEngineRequest checks every field of engineRequest and reports all
+// the violations it finds together. checks run after the built-in rules, on
+// fields those did not already reject.
+func ValidateEngineRequest(engineRequest EngineRequest, checks ...validation.Check) error {
+	v := validation.New()
+
+	validateDispacement(v, engineRequest.Dispacement)
+	validateNoOfCylinders(v, engineRequest.NoOfCylinders)
+	validateCarRange(v, engineRequest.CarRange)
+
+	for _, check := range checks {
+		check(v)
 	}
-	return nil
+
+	return v.Err()
 }
 
-func validateDispacement(dispacement float64) error {
-	if dispacement <= 0 {
-		return errors.New("dispacement must be greater than zero")
-	}
-	return nil
+func validateDispacement(v *validation.Validator, dispacement float64) {
+	validation.Field(v, "dispacement", dispacement,
+		validation.Positive[float64]("dispacement must be greater than zero"),
+	)
 }
-func validateNoOfCylinders(noOfCylinders float64) error {
-	if noOfCylinders <= 0 {
-		return errors.New("noOfCylinders must be greater than zero")
-	}
-	return nil
+
+func validateNoOfCylinders(v *validation.Validator, noOfCylinders float64) {
+	validation.Field(v, "no_of_cylinders", noOfCylinders,
+		validation.Positive[float64]("noOfCylinders must be greater than zero"),
+	)
 }
-func validateCarRange(carRange float64) error {
-	if carRange <= 0 {
-		return errors.New("carRange must be greater than zero")
-	}
-	return nil
+
+func validateCarRange(v *validation.Validator, carRange float64) {
+	validation.Field(v, "car_range", carRange,
+		validation.Positive[float64]("carRange must be greater than zero"),
+	)
 }
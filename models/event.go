@@ -0,0 +1,109 @@
+package models
+
+import (
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/geekAshish/DriveDesk/validation"
+
+	"github.com/google/uuid"
+)
+
+// Inventory event types, published for every change to a car or an engine.
+const (
+	EventCarCreated    = "car.created"
+	EventCarUpdated    = "car.updated"
+	EventCarDeleted    = "car.deleted"
+	EventEngineCreated = "engine.created"
+	EventEngineUpdated = "engine.updated"
+	EventEngineDeleted = "engine.deleted"
+)
+
+// EventTypes lists every event type, in the order they are documented.
+var EventTypes = []string{
+	EventCarCreated,
+	EventCarUpdated,
+	EventCarDeleted,
+	EventEngineCreated,
+	EventEngineUpdated,
+	EventEngineDeleted,
+}
+
+// Event is a change to a car or an engine. Subject is the id of the changed
+// resource and Data the resource after the change, or as it was before it
+// was deleted: a Car for car events and an Engine for engine events.
+type Event struct {
+	ID      uuid.UUID
+	Type    string
+	Subject string
+	Time    time.Time
+	Data    any
+}
+
+// NewEvent returns an event of the given type that happened now.
+func NewEvent(eventType, subject string, data any) Event {
+	return Event{
+		ID:      uuid.New(),
+		Type:    eventType,
+		Subject: subject,
+		Time:    time.Now().UTC(),
+		Data:    data,
+	}
+}
+
+// Event entities, the part of the event type before the dot.
+const (
+	EntityCar    = "car"
+	EntityEngine = "engine"
+)
+
+// ChangeEvent is an Event as kept for the change stream. Seq orders the
+// events of all instances and is the id clients resume from. Brand is the
+// brand of the car for car events and empty for engine events.
+type ChangeEvent struct {
+	Seq      int64           `json:"seq"`
+	ID       uuid.UUID       `json:"id"`
+	Type     string          `json:"type"`
+	Subject  string          `json:"subject"`
+	Brand    string          `json:"brand,omitempty"`
+	Data     json.RawMessage `json:"data"`
+	CreateAt time.Time       `json:"created_at"`
+}
+
+// Entity is what the event is about, car or engine.
+func (e ChangeEvent) Entity() string {
+	entity, _, _ := strings.Cut(e.Type, ".")
+	return entity
+}
+
+// EventFilter selects the events a change stream client wants. Empty fields
+// match every event; a brand only matches car events.
+type EventFilter struct {
+	Entity string
+	Brand  string
+}
+
+// Match reports whether event passes the filter.
+func (f EventFilter) Match(event ChangeEvent) bool {
+	if f.Entity != "" && event.Entity() != f.Entity {
+		return false
+	}
+
+	return f.Brand == "" || strings.EqualFold(event.Brand, f.Brand)
+}
+
+// ValidateEventFilter checks the query of a change stream.
+func ValidateEventFilter(filter EventFilter) error {
+	v := validation.New()
+
+	validation.Field(v, "entity", filter.Entity, validation.Rule[string]{
+		Code:    "invalid_choice",
+		Message: "entity must be car or engine",
+		Test: func(entity string) bool {
+			return entity == "" || entity == EntityCar || entity == EntityEngine
+		},
+	})
+
+	return v.Err()
+}
@@ -0,0 +1,26 @@
+package models
+
+import (
+	"net/http"
+	"time"
+)
+
+// IdempotencyRecord is a request sent with an Idempotency-Key and, once it
+// has been handled, the response to replay when the request is retried. Keys
+// are scoped to the user that sent them.
+type IdempotencyRecord struct {
+	Owner       string
+	Key         string
+	RequestHash string
+
+	// Status is 0 while the first request is still being handled.
+	Status      int
+	ContentType string
+	// Header holds the other headers the handler set, such as Location,
+	// ETag or Vary.
+	Header http.Header
+	Body   []byte
+
+	CreateAt time.Time
+	ExpireAt time.Time
+}
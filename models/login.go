@@ -4,5 +4,3 @@ type Credentials struct {
 	UserName string `json:"userName"`
 	Password string `json:"password"`
 }
-
-
@@ -0,0 +1,49 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Outbox message states. Pending messages wait for the relay; dead ones ran
+// out of attempts and no longer hold back the later messages of their
+// entity.
+const (
+	OutboxPending   = "pending"
+	OutboxPublished = "published"
+	OutboxDead      = "dead"
+)
+
+// OutboxMessage is an event waiting to be published to one sink. It is
+// written in the transaction of the change, so it exists exactly when the
+// change was committed. Seq orders the messages; the relay publishes the
+// messages of one entity, AggregateType and AggregateID, to one sink in
+// that order.
+type OutboxMessage struct {
+	Seq           int64           `json:"seq"`
+	Sink          string          `json:"sink"`
+	AggregateType string          `json:"aggregate_type"`
+	AggregateID   string          `json:"aggregate_id"`
+	EventID       uuid.UUID       `json:"event_id"`
+	EventType     string          `json:"event_type"`
+	Payload       json.RawMessage `json:"payload"`
+	Status        string          `json:"status"`
+	Attempts      int             `json:"attempts"`
+	NextAttemptAt time.Time       `json:"next_attempt_at"`
+	LastError     string          `json:"last_error,omitempty"`
+	CreateAt      time.Time       `json:"created_at"`
+	PublishedAt   *time.Time      `json:"published_at,omitempty"`
+}
+
+// Event is the event the message carries, with the payload as its data.
+func (m OutboxMessage) Event() Event {
+	return Event{
+		ID:      m.EventID,
+		Type:    m.EventType,
+		Subject: m.AggregateID,
+		Time:    m.CreateAt,
+		Data:    m.Payload,
+	}
+}
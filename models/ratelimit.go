@@ -0,0 +1,200 @@
+package models
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/geekAshish/DriveDesk/apperrors"
+	"github.com/geekAshish/DriveDesk/validation"
+)
+
+// RateLimit is a token bucket: a client can make Burst requests at once,
+// and gets Rate more every second, up to Burst again. The zero RateLimit
+// does not limit anything.
+type RateLimit struct {
+	Rate  float64 `json:"rate"`
+	Burst int     `json:"burst"`
+}
+
+func (l RateLimit) Unlimited() bool {
+	return l.Burst == 0
+}
+
+// RateLimitPolicy is a limit with overrides for some roles.
+type RateLimitPolicy struct {
+	RateLimit
+	Roles map[string]RateLimit `json:"roles,omitempty"`
+}
+
+// For returns the limit of a client with role.
+func (p RateLimitPolicy) For(role string) RateLimit {
+	if limit, ok := p.Roles[role]; ok {
+		return limit
+	}
+	return p.RateLimit
+}
+
+// RateLimitConfig limits each client to Default over all its requests, and
+// to the policy of a route over its requests to that route. Routes are keyed
+// by "METHOD /path" or by "/path" for every method, with paths as they are
+// registered but without the version prefix, e.g. "GET /cars/{id}".
+//
+// Clients are told apart by user name once authenticated, then by the
+// header named by APIKeyHeader, if any, and otherwise by IP address.
+// ClientIPHeader names the header a reverse proxy puts the client's address
+// in, e.g. X-Forwarded-For, whose last entry is used.
+//
+// PreAuth limits each address or API key before its token is checked, so
+// that requests with invalid tokens are limited too. Users sharing an
+// address, e.g. behind NAT, share it, so it should be generous.
+type RateLimitConfig struct {
+	Default        RateLimitPolicy            `json:"default"`
+	PreAuth        RateLimit                  `json:"pre_auth"`
+	Routes         map[string]RateLimitPolicy `json:"routes,omitempty"`
+	APIKeyHeader   string                     `json:"api_key_header,omitempty"`
+	ClientIPHeader string                     `json:"client_ip_header,omitempty"`
+}
+
+// Route returns the policy of the route with method and path, and the key
+// it is configured under.
+func (c RateLimitConfig) Route(method, path string) (string, RateLimitPolicy, bool) {
+	key := method + " " + path
+	if policy, ok := c.Routes[key]; ok {
+		return key, policy, true
+	}
+
+	policy, ok := c.Routes[path]
+	return path, policy, ok
+}
+
+// DefaultRateLimitConfig applies when no configuration is given: 10
+// requests a second with bursts of 20, five times that for admins and for
+// each address before authentication, and a login attempt every 10 seconds
+// after the first 5.
+var DefaultRateLimitConfig = RateLimitConfig{
+	Default: RateLimitPolicy{
+		RateLimit: RateLimit{Rate: 10, Burst: 20},
+		Roles:     map[string]RateLimit{"admin": {Rate: 50, Burst: 100}},
+	},
+	PreAuth: RateLimit{Rate: 50, Burst: 100},
+	Routes: map[string]RateLimitPolicy{
+		"POST /login": {RateLimit: RateLimit{Rate: 0.1, Burst: 5}},
+	},
+}
+
+// ParseRateLimitConfig reads a RateLimitConfig from JSON, rejecting fields
+// it does not know so that typos do not go unnoticed.
+func ParseRateLimitConfig(data []byte) (RateLimitConfig, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+
+	var config RateLimitConfig
+	if err := decoder.Decode(&config); err != nil {
+		return RateLimitConfig{}, apperrors.Wrap(apperrors.ErrBadRequest, err, "rate limits are not valid JSON: %v", err)
+	}
+
+	return config, ValidateRateLimitConfig(config)
+}
+
+func ValidateRateLimitConfig(config RateLimitConfig) error {
+	v := validation.New()
+
+	validateRateLimitPolicy(v.Nested("default"), config.Default)
+	validateRateLimit(v.Nested("pre_auth"), config.PreAuth)
+
+	for route, policy := range config.Routes {
+		routes := v.Nested(fmt.Sprintf("routes[%s]", route))
+
+		method, path, ok := strings.Cut(route, " ")
+		if !ok {
+			method, path = "", route
+		}
+		if method != "" && !slices.Contains(rateLimitMethods, method) || !strings.HasPrefix(path, "/") {
+			routes.Add("route", "invalid_format", `routes must be keyed by "METHOD /path" or "/path"`)
+		}
+
+		validateRateLimitPolicy(routes, policy)
+	}
+
+	return v.Err()
+}
+
+var rateLimitMethods = []string{http.MethodGet, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete}
+
+func validateRateLimitPolicy(v *validation.Validator, policy RateLimitPolicy) {
+	validateRateLimit(v, policy.RateLimit)
+
+	for role, limit := range policy.Roles {
+		validateRateLimit(v.Nested(fmt.Sprintf("roles[%s]", role)), limit)
+	}
+}
+
+func validateRateLimit(v *validation.Validator, limit RateLimit) {
+	validation.Field(v, "rate", limit.Rate, validation.Rule[float64]{
+		Code:    "out_of_range",
+		Message: "rate must not be negative",
+		Test:    func(rate float64) bool { return rate >= 0 },
+	})
+	validation.Field(v, "burst", limit.Burst, validation.Rule[int]{
+		Code:    "out_of_range",
+		Message: "burst must not be negative",
+		Test:    func(burst int) bool { return burst >= 0 },
+	})
+
+	if limit.Burst > 0 && limit.Rate == 0 && !v.Has("rate") {
+		v.Add("rate", "required", "rate is required with a burst; leave both out for no limit")
+	}
+}
+
+// RateLimitBucket is what is left of one client's token bucket. The zero
+// RateLimitBucket is a full bucket.
+type RateLimitBucket struct {
+	Tokens   float64
+	UpdateAt time.Time
+}
+
+// Take refills b for the time since it was last updated and takes one token
+// from it, if there is one. It returns the bucket as of now and whether a
+// token was taken.
+func (b RateLimitBucket) Take(limit RateLimit, now time.Time) (RateLimitBucket, bool) {
+	tokens := float64(limit.Burst)
+	if !b.UpdateAt.IsZero() {
+		// clocks of different instances may disagree a little
+		elapsed := max(now.Sub(b.UpdateAt).Seconds(), 0)
+		tokens = min(tokens, b.Tokens+elapsed*limit.Rate)
+	}
+
+	if tokens < 1 {
+		return RateLimitBucket{Tokens: tokens, UpdateAt: now}, false
+	}
+
+	return RateLimitBucket{Tokens: tokens - 1, UpdateAt: now}, true
+}
+
+// Remaining is how many requests the bucket allows right away.
+func (b RateLimitBucket) Remaining() int {
+	return int(math.Floor(b.Tokens))
+}
+
+// ResetAfter is how long the bucket takes to fill up again.
+func (b RateLimitBucket) ResetAfter(limit RateLimit) time.Duration {
+	return tokenTime(float64(limit.Burst)-b.Tokens, limit.Rate)
+}
+
+// RetryAfter is how long until the bucket allows the next request.
+func (b RateLimitBucket) RetryAfter(limit RateLimit) time.Duration {
+	return tokenTime(1-b.Tokens, limit.Rate)
+}
+
+func tokenTime(tokens, rate float64) time.Duration {
+	if tokens <= 0 || rate <= 0 {
+		return 0
+	}
+	return time.Duration(tokens / rate * float64(time.Second))
+}
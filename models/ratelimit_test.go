@@ -0,0 +1,87 @@
+package models
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/geekAshish/DriveDesk/apperrors"
+)
+
+func TestParseRateLimitConfigReportsAllFields(t *testing.T) {
+	_, err := ParseRateLimitConfig([]byte(`{
+		"default": {"rate": -1, "burst": 10, "roles": {"admin": {"rate": 0, "burst": 5}}},
+		"routes": {"FETCH /login": {"rate": 1, "burst": -2}, "cars": {"rate": 1, "burst": 1}}
+	}`))
+
+	got := map[string]string{}
+	for _, field := range apperrors.Fields(err) {
+		got[field.Field] = field.Code
+	}
+
+	want := map[string]string{
+		"default.rate":               "out_of_range",
+		"default.roles[admin].rate":  "required",
+		"routes[FETCH /login].route": "invalid_format",
+		"routes[FETCH /login].burst": "out_of_range",
+		"routes[cars].route":         "invalid_format",
+	}
+
+	if len(got) != len(want) {
+		t.Fatalf("field errors = %v, want %v", got, want)
+	}
+	for field, code := range want {
+		if got[field] != code {
+			t.Errorf("%s: code = %q, want %q", field, got[field], code)
+		}
+	}
+}
+
+func TestParseRateLimitConfig(t *testing.T) {
+	config, err := ParseRateLimitConfig([]byte(`{
+		"default": {"rate": 5, "burst": 10},
+		"routes": {"/login": {"rate": 0.1, "burst": 3, "roles": {"admin": {}}}},
+		"client_ip_header": "X-Forwarded-For"
+	}`))
+	if err != nil {
+		t.Fatalf("ParseRateLimitConfig: %v", err)
+	}
+
+	key, policy, ok := config.Route("POST", "/login")
+	if !ok || key != "/login" || policy.For("user").Burst != 3 || !policy.For("admin").Unlimited() {
+		t.Errorf("Route(POST /login) = %q, %+v, %v", key, policy, ok)
+	}
+	if _, _, ok := config.Route("GET", "/cars"); ok {
+		t.Error("Route(GET /cars) found a policy")
+	}
+
+	if _, err := ParseRateLimitConfig([]byte(`{"defaults": {}}`)); !errors.Is(err, apperrors.ErrBadRequest) {
+		t.Errorf("ParseRateLimitConfig with an unknown field = %v, want ErrBadRequest", err)
+	}
+}
+
+func TestRateLimitBucketTake(t *testing.T) {
+	limit := RateLimit{Rate: 2, Burst: 3}
+	now := time.Now()
+
+	var bucket RateLimitBucket
+	for i, want := range []bool{true, true, true, false} {
+		var taken bool
+		if bucket, taken = bucket.Take(limit, now); taken != want {
+			t.Fatalf("Take #%d = %v, want %v", i+1, taken, want)
+		}
+	}
+	if bucket.Remaining() != 0 || bucket.RetryAfter(limit) != 500*time.Millisecond || bucket.ResetAfter(limit) != 1500*time.Millisecond {
+		t.Errorf("empty bucket %+v: retry after %v, reset after %v", bucket, bucket.RetryAfter(limit), bucket.ResetAfter(limit))
+	}
+
+	// it refills at rate, up to burst
+	if bucket, _ = bucket.Take(limit, now.Add(time.Minute)); bucket.Remaining() != 2 {
+		t.Errorf("bucket a minute later = %+v, want full before the take", bucket)
+	}
+
+	// a clock running behind does not drain it
+	if _, taken := bucket.Take(limit, now); !taken {
+		t.Error("Take with an earlier time was refused")
+	}
+}
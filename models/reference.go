@@ -0,0 +1,150 @@
+package models
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/geekAshish/DriveDesk/validation"
+)
+
+// Reference data kinds. Kinds are free-form, these are the ones the API
+// validates against today or expects to soon.
+const (
+	ReferenceKindFuelType     = "fuel_type"
+	ReferenceKindBodyType     = "body_type"
+	ReferenceKindTransmission = "transmission"
+)
+
+// ReferenceValue is one admin-managed value of an enum such as fuel type.
+// Aliases are other spellings accepted in requests, e.g. "gasoline" for
+// "petrol"; they are always resolved to Code before anything is stored.
+type ReferenceValue struct {
+	Kind     string    `json:"kind"`
+	Code     string    `json:"code"`
+	Label    string    `json:"label"`
+	Aliases  []string  `json:"aliases"`
+	Active   bool      `json:"active"`
+	CreateAt time.Time `json:"created_at"`
+	UpdateAt time.Time `json:"updated_at"`
+}
+
+type ReferenceValueRequest struct {
+	Code    string   `json:"code"`
+	Label   string   `json:"label"`
+	Aliases []string `json:"aliases"`
+	Active  *bool    `json:"active"`
+}
+
+var referenceCode = regexp.MustCompile(`^[a-z0-9_]{1,50}$`)
+
+// NormalizeReferenceCode is how codes, aliases and kinds are compared:
+// trimmed and case-insensitive.
+func NormalizeReferenceCode(value string) string {
+	return strings.ToLower(strings.TrimSpace(value))
+}
+
+// ValidateReferenceKind checks the kind segment of a reference data route.
+func ValidateReferenceKind(kind string) error {
+	v := validation.New()
+
+	validation.Field(v, "kind", kind, validation.Rule[string]{
+		Code:    "invalid_format",
+		Message: "kind must be 1-50 lowercase letters, digits or underscores",
+		Test:    referenceCode.MatchString,
+	})
+
+	return v.Err()
+}
+
+// ValidateReferenceValueRequest checks a create or update request. Codes and
+// aliases are normalised in place first.
+func ValidateReferenceValueRequest(req *ReferenceValueRequest) error {
+	req.Code = NormalizeReferenceCode(req.Code)
+	for i, alias := range req.Aliases {
+		req.Aliases[i] = NormalizeReferenceCode(alias)
+	}
+
+	v := validation.New()
+
+	validation.Field(v, "code", req.Code,
+		validation.Required[string]("code is required"),
+		validation.Rule[string]{
+			Code:    "invalid_format",
+			Message: "code must be 1-50 lowercase letters, digits or underscores",
+			Test:    referenceCode.MatchString,
+		},
+	)
+	validation.Field(v, "label", strings.TrimSpace(req.Label),
+		validation.Required[string]("label is required"),
+	)
+
+	seen := map[string]bool{req.Code: true}
+	for i, alias := range req.Aliases {
+		field := "aliases." + strconv.Itoa(i)
+
+		validation.Field(v, field, alias,
+			validation.Required[string]("alias must not be empty"),
+			validation.Rule[string]{
+				Code:    "duplicate",
+				Message: "alias repeats the code or another alias",
+				Test:    func(alias string) bool { return !seen[alias] },
+			},
+		)
+		seen[alias] = true
+	}
+
+	return v.Err()
+}
+
+// EnumLookup resolves a value of a reference data kind, or one of its
+// aliases, to its canonical code.
+type EnumLookup interface {
+	Canonical(kind, value string) (code string, ok bool)
+}
+
+// EnumCatalog is an EnumLookup over a fixed set of reference values, keyed by
+// kind and then by normalised code or alias.
+type EnumCatalog map[string]map[string]string
+
+// NewEnumCatalog indexes the active values among values.
+func NewEnumCatalog(values []ReferenceValue) EnumCatalog {
+	catalog := EnumCatalog{}
+
+	for _, value := range values {
+		if !value.Active {
+			continue
+		}
+
+		codes, ok := catalog[value.Kind]
+		if !ok {
+			codes = map[string]string{}
+			catalog[value.Kind] = codes
+		}
+
+		codes[NormalizeReferenceCode(value.Code)] = value.Code
+		for _, alias := range value.Aliases {
+			codes[NormalizeReferenceCode(alias)] = value.Code
+		}
+	}
+
+	return catalog
+}
+
+func (c EnumCatalog) Canonical(kind, value string) (string, bool) {
+	code, ok := c[kind][NormalizeReferenceCode(value)]
+	return code, ok
+}
+
+// DefaultReferenceValues are the fuel types accepted before reference data
+// existed. The 0002 migration seeds the same values.
+var DefaultReferenceValues = []ReferenceValue{
+	{Kind: ReferenceKindFuelType, Code: "diesel", Label: "Diesel", Active: true},
+	{Kind: ReferenceKindFuelType, Code: "electric", Label: "Electric", Active: true},
+	{Kind: ReferenceKindFuelType, Code: "hybrid", Label: "Hybrid", Active: true},
+	{Kind: ReferenceKindFuelType, Code: "petrol", Label: "Petrol", Aliases: []string{"gas", "gasoline"}, Active: true},
+}
+
+// DefaultEnums is an EnumLookup over DefaultReferenceValues.
+var DefaultEnums = NewEnumCatalog(DefaultReferenceValues)
@@ -0,0 +1,179 @@
+package models
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/geekAshish/DriveDesk/apperrors"
+	"github.com/geekAshish/DriveDesk/validation"
+	"github.com/google/uuid"
+)
+
+// Operations a client can push.
+const (
+	SyncUpsert = "upsert"
+	SyncDelete = "delete"
+)
+
+// Outcomes of a pushed change.
+const (
+	SyncApplied  = "applied"
+	SyncConflict = "conflict"
+	SyncRejected = "rejected"
+)
+
+// DefaultSyncLimit is how many changes a pull returns when the client does
+// not say; MaxSyncLimit is the most it may ask for. MaxSyncChanges bounds
+// the changes of one push.
+const (
+	DefaultSyncLimit = 500
+	MaxSyncLimit     = 1000
+	MaxSyncChanges   = 500
+)
+
+// SyncRow is the version of one car or engine. Versions come from a counter
+// shared by every car and engine and increase with each change, so the
+// highest version a client has seen is its sync token. ParentID is the
+// engine of a car. A deleted row is a tombstone.
+type SyncRow struct {
+	Entity   string
+	ID       string
+	ParentID string
+	Version  int64
+	Deleted  bool
+	UpdateAt time.Time
+}
+
+// SyncItem is one change pulled by a client: the car or engine as it is now,
+// or a tombstone when it was deleted.
+type SyncItem struct {
+	Entity  string  `json:"entity"`
+	ID      string  `json:"id"`
+	Version int64   `json:"version"`
+	Deleted bool    `json:"deleted"`
+	Car     *Car    `json:"car,omitempty"`
+	Engine  *Engine `json:"engine,omitempty"`
+}
+
+// SyncPage is the answer to a pull. Token is passed as since on the next
+// pull; while HasMore is set there are more changes to pull right away.
+type SyncPage struct {
+	Changes []SyncItem `json:"changes"`
+	Token   string     `json:"token"`
+	HasMore bool       `json:"has_more"`
+}
+
+// SyncChange is a change made on a client. BaseVersion is the version of
+// the car or engine the client changed; the change only applies if it is
+// still the current one. Upserts without an id create a new car or engine
+// and have no base version.
+type SyncChange struct {
+	Entity      string         `json:"entity"`
+	Op          string         `json:"op"`
+	ID          string         `json:"id,omitempty"`
+	BaseVersion int64          `json:"base_version,omitempty"`
+	Car         *CarRequest    `json:"car,omitempty"`
+	Engine      *EngineRequest `json:"engine,omitempty"`
+}
+
+type SyncPushRequest struct {
+	Changes []SyncChange `json:"changes"`
+}
+
+// SyncResult is the outcome of one pushed change, in the order they were
+// pushed. Applied changes have the id and new version of the car or engine.
+// Conflicts carry the current server state, and rejected changes the
+// reason.
+type SyncResult struct {
+	Status  string                 `json:"status"`
+	Entity  string                 `json:"entity"`
+	ID      string                 `json:"id,omitempty"`
+	Version int64                  `json:"version,omitempty"`
+	Current *SyncItem              `json:"current,omitempty"`
+	Error   string                 `json:"error,omitempty"`
+	Fields  []apperrors.FieldError `json:"fields,omitempty"`
+}
+
+type SyncPushResponse struct {
+	Results []SyncResult `json:"results"`
+}
+
+// ParseSyncToken reads the since parameter of a pull. An empty token means
+// nothing was synced yet.
+func ParseSyncToken(token string) (int64, error) {
+	if token == "" {
+		return 0, nil
+	}
+
+	version, err := strconv.ParseInt(token, 10, 64)
+	if err != nil || version < 0 {
+		return 0, apperrors.InvalidField("since", "invalid_format", "since must be a token returned by a previous sync")
+	}
+
+	return version, nil
+}
+
+// SyncToken is the token of the changes up to version.
+func SyncToken(version int64) string {
+	return strconv.FormatInt(version, 10)
+}
+
+func ValidateSyncLimit(limit int) error {
+	v := validation.New()
+
+	validation.Field(v, "limit", limit, validation.Between(1, MaxSyncLimit, "limit must be between 1 and "+strconv.Itoa(MaxSyncLimit)))
+
+	return v.Err()
+}
+
+// ValidateSyncPush checks the shape of every change. The car and engine
+// payloads are validated when each change is applied, so one invalid
+// payload only rejects its own change.
+func ValidateSyncPush(req SyncPushRequest) error {
+	v := validation.New()
+
+	if len(req.Changes) == 0 || len(req.Changes) > MaxSyncChanges {
+		v.Add("changes", "out_of_range", "changes must hold between 1 and "+strconv.Itoa(MaxSyncChanges)+" changes")
+	}
+
+	for i, change := range req.Changes {
+		validateSyncChange(v.Nested(fmt.Sprintf("changes[%d]", i)), change)
+	}
+
+	return v.Err()
+}
+
+func validateSyncChange(v *validation.Validator, change SyncChange) {
+	validation.Field(v, "entity", change.Entity, validation.OneOf([]string{EntityCar, EntityEngine}, "entity must be car or engine"))
+	validation.Field(v, "op", change.Op, validation.OneOf([]string{SyncUpsert, SyncDelete}, "op must be upsert or delete"))
+
+	validation.Field(v, "id", change.ID, validation.Rule[string]{
+		Code:    "invalid_format",
+		Message: "id must be a UUID",
+		Test: func(id string) bool {
+			_, err := uuid.Parse(id)
+			return id == "" || err == nil
+		},
+	})
+
+	creates := change.Op == SyncUpsert && change.ID == ""
+	if change.Op == SyncDelete && change.ID == "" {
+		v.Add("id", "required", "id is required to delete")
+	}
+	if creates && change.BaseVersion != 0 {
+		v.Add("base_version", "not_allowed", "base_version must be left out when creating")
+	}
+	if !creates && change.BaseVersion <= 0 {
+		v.Add("base_version", "required", "base_version is required to change an existing "+change.Entity)
+	}
+
+	if change.Op == SyncUpsert {
+		if change.Entity == EntityCar && change.Car == nil {
+			v.Add("car", "required", "car is required to upsert a car")
+		}
+		if change.Entity == EntityEngine && change.Engine == nil {
+			v.Add("engine", "required", "engine is required to upsert an engine")
+		}
+	}
+}
@@ -0,0 +1,109 @@
+package models
+
+import (
+	"encoding/json"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/geekAshish/DriveDesk/validation"
+	"github.com/google/uuid"
+)
+
+// Requests a validation rule can apply to.
+const (
+	RuleTargetCar    = "car"
+	RuleTargetEngine = "engine"
+)
+
+// Rule operators. Value holds the operand: a number for min, max, min_length
+// and max_length, a regular expression for pattern, a list of strings for
+// one_of and nothing for required.
+const (
+	RuleOperatorRequired  = "required"
+	RuleOperatorMin       = "min"
+	RuleOperatorMax       = "max"
+	RuleOperatorMinLength = "min_length"
+	RuleOperatorMaxLength = "max_length"
+	RuleOperatorPattern   = "pattern"
+	RuleOperatorOneOf     = "one_of"
+)
+
+var (
+	ruleTargets   = []string{RuleTargetCar, RuleTargetEngine}
+	ruleOperators = []string{
+		RuleOperatorRequired,
+		RuleOperatorMin,
+		RuleOperatorMax,
+		RuleOperatorMinLength,
+		RuleOperatorMaxLength,
+		RuleOperatorPattern,
+		RuleOperatorOneOf,
+	}
+)
+
+// ValidationRule is a deployment specific check on one field of a car or
+// engine request, e.g. a minimum year for imports. Field is the JSON path of
+// the field in the request, such as "year" or "engine.car_range".
+type ValidationRule struct {
+	ID       uuid.UUID       `json:"id"`
+	Target   string          `json:"target"`
+	Field    string          `json:"field"`
+	Operator string          `json:"operator"`
+	Value    json.RawMessage `json:"value,omitempty"`
+	Code     string          `json:"code"`
+	Message  string          `json:"message"`
+	CreateAt time.Time       `json:"created_at"`
+}
+
+// ValidationRuleRequest creates a rule. Code and Message may be left out, in
+// which case they are derived from the operator.
+type ValidationRuleRequest struct {
+	Target   string          `json:"target"`
+	Field    string          `json:"field"`
+	Operator string          `json:"operator"`
+	Value    json.RawMessage `json:"value,omitempty"`
+	Code     string          `json:"code"`
+	Message  string          `json:"message"`
+}
+
+var ruleField = regexp.MustCompile(`^[a-z_]+(\.[a-z_]+)*$`)
+
+// ValidateRuleRequest checks the shape of a rule request. Whether Value suits
+// the operator is checked when the rule is compiled.
+func ValidateRuleRequest(req *ValidationRuleRequest) error {
+	req.Target = strings.ToLower(strings.TrimSpace(req.Target))
+	req.Operator = strings.ToLower(strings.TrimSpace(req.Operator))
+	req.Field = strings.TrimSpace(req.Field)
+	req.Code = strings.TrimSpace(req.Code)
+
+	v := validation.New()
+
+	validation.Field(v, "target", req.Target,
+		validation.Required[string]("target is required"),
+		validation.OneOf(ruleTargets, "target must be one of "+strings.Join(ruleTargets, ", ")),
+	)
+	validation.Field(v, "field", req.Field,
+		validation.Required[string]("field is required"),
+		validation.Rule[string]{
+			Code:    "invalid_format",
+			Message: `field must be a JSON path such as "year" or "engine.car_range"`,
+			Test:    ruleField.MatchString,
+		},
+	)
+	validation.Field(v, "operator", req.Operator,
+		validation.Required[string]("operator is required"),
+		validation.OneOf(ruleOperators, "operator must be one of "+strings.Join(ruleOperators, ", ")),
+	)
+	validation.Field(v, "code", req.Code,
+		validation.Rule[string]{
+			Code:    "invalid_format",
+			Message: "code must be 1-50 lowercase letters, digits or underscores",
+			Test: func(code string) bool {
+				return code == "" || referenceCode.MatchString(code)
+			},
+		},
+	)
+
+	return v.Err()
+}
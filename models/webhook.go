@@ -0,0 +1,154 @@
+package models
+
+import (
+	"encoding/json"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/geekAshish/DriveDesk/validation"
+	"github.com/google/uuid"
+)
+
+// Webhook is a subscription of a URL to some of the event types. Secret
+// signs the deliveries and is never sent back.
+type Webhook struct {
+	ID       uuid.UUID `json:"id"`
+	URL      string    `json:"url"`
+	Events   []string  `json:"events"`
+	Secret   string    `json:"-"`
+	Active   bool      `json:"active"`
+	CreateAt time.Time `json:"created_at"`
+	UpdateAt time.Time `json:"updated_at"`
+}
+
+// WebhookRequest creates or replaces a webhook. The secret may be left out
+// of an update to keep the current one.
+type WebhookRequest struct {
+	URL    string   `json:"url"`
+	Events []string `json:"events"`
+	Secret string   `json:"secret"`
+	Active *bool    `json:"active"`
+}
+
+// Subscribed reports whether w wants events of the given type.
+func (w Webhook) Subscribed(eventType string) bool {
+	return w.Active && slices.Contains(w.Events, eventType)
+}
+
+// MinWebhookSecretLength keeps secrets long enough that signatures cannot be
+// forged by guessing them.
+const MinWebhookSecretLength = 16
+
+// ValidateWebhookRequest checks a create or, when update is set, an update
+// request.
+func ValidateWebhookRequest(req *WebhookRequest, update bool) error {
+	req.URL = strings.TrimSpace(req.URL)
+
+	v := validation.New()
+
+	validation.Field(v, "url", req.URL,
+		validation.Required[string]("url is required"),
+		validation.Rule[string]{
+			Code:    "invalid_format",
+			Message: "url must be an absolute http or https URL",
+			Test: func(raw string) bool {
+				u, err := url.Parse(raw)
+				return err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+			},
+		},
+	)
+
+	validation.Field(v, "events", req.Events,
+		validation.Rule[[]string]{
+			Code:    "required",
+			Message: "subscribe to at least one event type",
+			Test:    func(events []string) bool { return len(events) > 0 },
+		},
+	)
+	for i, event := range req.Events {
+		validation.Field(v, "events."+strconv.Itoa(i), event, validation.Rule[string]{
+			Code:    "invalid_choice",
+			Message: "not an event type, expected one of " + strings.Join(EventTypes, ", "),
+			Test:    func(event string) bool { return slices.Contains(EventTypes, event) },
+		})
+	}
+
+	if !update || req.Secret != "" {
+		validation.Field(v, "secret", req.Secret,
+			validation.Rule[string]{
+				Code:    "too_short",
+				Message: "secret must be at least " + strconv.Itoa(MinWebhookSecretLength) + " characters",
+				Test:    func(secret string) bool { return len(secret) >= MinWebhookSecretLength },
+			},
+		)
+	}
+
+	return v.Err()
+}
+
+// Webhook delivery states. Pending deliveries wait for their next attempt;
+// dead ones ran out of attempts and are only retried when redelivered.
+const (
+	DeliveryPending   = "pending"
+	DeliverySucceeded = "succeeded"
+	DeliveryDead      = "dead"
+)
+
+// WebhookDelivery is one event sent to one webhook, with the outcome of its
+// latest attempt.
+type WebhookDelivery struct {
+	ID             uuid.UUID       `json:"id"`
+	WebhookID      uuid.UUID       `json:"webhook_id"`
+	EventID        uuid.UUID       `json:"event_id"`
+	EventType      string          `json:"event_type"`
+	Payload        json.RawMessage `json:"payload"`
+	Status         string          `json:"status"`
+	Attempts       int             `json:"attempts"`
+	NextAttemptAt  time.Time       `json:"next_attempt_at"`
+	LastStatusCode int             `json:"last_status_code,omitempty"`
+	LastError      string          `json:"last_error,omitempty"`
+	CreateAt       time.Time       `json:"created_at"`
+	UpdateAt       time.Time       `json:"updated_at"`
+}
+
+// MaxWebhookDeliveryLimit bounds how many deliveries one listing returns.
+const MaxWebhookDeliveryLimit = 1000
+
+// WebhookDeliveryFilter narrows a delivery listing. Empty fields match
+// every delivery and a zero Limit returns them all.
+type WebhookDeliveryFilter struct {
+	WebhookID string
+	Status    string
+	Limit     int
+}
+
+// ValidateWebhookDeliveryFilter checks the query of a delivery listing.
+func ValidateWebhookDeliveryFilter(filter WebhookDeliveryFilter) error {
+	v := validation.New()
+
+	validation.Field(v, "status", filter.Status, validation.Rule[string]{
+		Code:    "invalid_choice",
+		Message: "status must be pending, succeeded or dead",
+		Test: func(status string) bool {
+			return status == "" || status == DeliveryPending || status == DeliverySucceeded || status == DeliveryDead
+		},
+	})
+	validation.Field(v, "webhook_id", filter.WebhookID, validation.Rule[string]{
+		Code:    "invalid_format",
+		Message: "webhook_id must be a UUID",
+		Test: func(id string) bool {
+			_, err := uuid.Parse(id)
+			return id == "" || err == nil
+		},
+	})
+	validation.Field(v, "limit", filter.Limit, validation.Rule[int]{
+		Code:    "out_of_range",
+		Message: "limit must be between 1 and " + strconv.Itoa(MaxWebhookDeliveryLimit),
+		Test:    func(limit int) bool { return limit >= 0 && limit <= MaxWebhookDeliveryLimit },
+	})
+
+	return v.Err()
+}
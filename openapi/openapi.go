@@ -0,0 +1,250 @@
+// Package openapi builds OpenAPI 3.1 documents. Schemas are derived from the
+// Go types the handlers read and write, so the published description cannot
+// drift from the JSON the API actually speaks.
+package openapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+const Version = "3.1.0"
+
+type Document struct {
+	OpenAPI    string                `json:"openapi"`
+	Info       Info                  `json:"info"`
+	Servers    []Server              `json:"servers,omitempty"`
+	Paths      map[string]PathItem   `json:"paths"`
+	Components Components            `json:"components"`
+	Security   []SecurityRequirement `json:"security,omitempty"`
+	Tags       []Tag                 `json:"tags,omitempty"`
+}
+
+type Info struct {
+	Title       string `json:"title"`
+	Version     string `json:"version"`
+	Description string `json:"description,omitempty"`
+}
+
+// Server is a base URL the paths are relative to.
+type Server struct {
+	URL         string `json:"url"`
+	Description string `json:"description,omitempty"`
+}
+
+type Tag struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+}
+
+// PathItem maps lower-case HTTP methods to the operations of one path.
+type PathItem map[string]*Operation
+
+type Operation struct {
+	Tags        []string             `json:"tags,omitempty"`
+	Summary     string               `json:"summary,omitempty"`
+	Description string               `json:"description,omitempty"`
+	OperationID string               `json:"operationId"`
+	Parameters  []Parameter          `json:"parameters,omitempty"`
+	RequestBody *RequestBody         `json:"requestBody,omitempty"`
+	Responses   map[string]*Response `json:"responses"`
+	Deprecated  bool                 `json:"deprecated,omitempty"`
+
+	// Security overrides the document's requirement; an empty, non-nil
+	// slice marks a public operation.
+	Security []SecurityRequirement `json:"-"`
+}
+
+// MarshalJSON writes Security as an empty list rather than leaving it out
+// when it is empty but set, which is how OpenAPI spells "no auth".
+func (o *Operation) MarshalJSON() ([]byte, error) {
+	type operation Operation
+
+	return json.Marshal(struct {
+		*operation
+		Security *[]SecurityRequirement `json:"security,omitempty"`
+	}{
+		operation: (*operation)(o),
+		Security:  securityOf(o.Security),
+	})
+}
+
+func securityOf(security []SecurityRequirement) *[]SecurityRequirement {
+	if security == nil {
+		return nil
+	}
+	return &security
+}
+
+type Parameter struct {
+	Name        string  `json:"name"`
+	In          string  `json:"in"`
+	Description string  `json:"description,omitempty"`
+	Required    bool    `json:"required,omitempty"`
+	Schema      *Schema `json:"schema"`
+}
+
+type RequestBody struct {
+	Required bool                 `json:"required,omitempty"`
+	Content  map[string]MediaType `json:"content"`
+}
+
+type Response struct {
+	Ref         string               `json:"$ref,omitempty"`
+	Description string               `json:"description,omitempty"`
+	Headers     map[string]Header    `json:"headers,omitempty"`
+	Content     map[string]MediaType `json:"content,omitempty"`
+}
+
+type Header struct {
+	Description string  `json:"description,omitempty"`
+	Schema      *Schema `json:"schema"`
+}
+
+type MediaType struct {
+	Schema *Schema `json:"schema"`
+}
+
+type Components struct {
+	Schemas         map[string]*Schema         `json:"schemas"`
+	Responses       map[string]*Response       `json:"responses,omitempty"`
+	SecuritySchemes map[string]*SecurityScheme `json:"securitySchemes,omitempty"`
+}
+
+type SecurityScheme struct {
+	Type         string `json:"type"`
+	Scheme       string `json:"scheme,omitempty"`
+	BearerFormat string `json:"bearerFormat,omitempty"`
+	Description  string `json:"description,omitempty"`
+}
+
+// SecurityRequirement maps security scheme names to required scopes.
+type SecurityRequirement map[string][]string
+
+// Schema is the subset of JSON Schema 2020-12 the API needs. Type is a
+// string, or a list of strings for nullable values.
+type Schema struct {
+	Ref                  string             `json:"$ref,omitempty"`
+	Type                 any                `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Description          string             `json:"description,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+	Enum                 []any              `json:"enum,omitempty"`
+	OneOf                []*Schema          `json:"oneOf,omitempty"`
+}
+
+func New(info Info) *Document {
+	return &Document{
+		OpenAPI: Version,
+		Info:    info,
+		Paths:   map[string]PathItem{},
+		Components: Components{
+			Schemas:         map[string]*Schema{},
+			Responses:       map[string]*Response{},
+			SecuritySchemes: map[string]*SecurityScheme{},
+		},
+	}
+}
+
+// Add documents the operation served for method on path. Paths use the same
+// {name} templates as the router.
+func (d *Document) Add(method, path string, op *Operation) {
+	item, ok := d.Paths[path]
+	if !ok {
+		item = PathItem{}
+		d.Paths[path] = item
+	}
+
+	item[strings.ToLower(method)] = op
+}
+
+// Has reports whether an operation is documented for method on path.
+func (d *Document) Has(method, path string) bool {
+	_, ok := d.Paths[path][strings.ToLower(method)]
+	return ok
+}
+
+// Operations lists every documented operation as "METHOD path".
+func (d *Document) Operations() []string {
+	var operations []string
+	for path, item := range d.Paths {
+		for method := range item {
+			operations = append(operations, strings.ToUpper(method)+" "+path)
+		}
+	}
+	return operations
+}
+
+// Deprecate marks every documented operation as deprecated.
+func (d *Document) Deprecate() {
+	for _, item := range d.Paths {
+		for _, op := range item {
+			op.Deprecated = true
+		}
+	}
+}
+
+// JSON returns a body of the given media type holding v.
+func (d *Document) JSON(mediaType string, v any) map[string]MediaType {
+	return map[string]MediaType{mediaType: {Schema: d.Schema(v)}}
+}
+
+// Body is a required request body of type v.
+func (d *Document) Body(v any) *RequestBody {
+	return &RequestBody{Required: true, Content: d.JSON("application/json", v)}
+}
+
+// Returns describes a response with a JSON body of type v, or no body when
+// v is nil.
+func (d *Document) Returns(description string, v any) *Response {
+	response := &Response{Description: description}
+	if v != nil {
+		response.Content = d.JSON("application/json", v)
+	}
+	return response
+}
+
+// Ref points to a response registered in the components.
+func Ref(name string) *Response {
+	return &Response{Ref: "#/components/responses/" + name}
+}
+
+// PathParam is a required path parameter of type string.
+func PathParam(name, description string) Parameter {
+	return Parameter{Name: name, In: "path", Description: description, Required: true, Schema: &Schema{Type: "string"}}
+}
+
+// QueryParam is an optional query parameter of the given JSON type.
+func QueryParam(name, typ, description string) Parameter {
+	return Parameter{Name: name, In: "query", Description: description, Schema: &Schema{Type: typ}}
+}
+
+// HeaderParam is an optional request header of type string.
+func HeaderParam(name, description string) Parameter {
+	return Parameter{Name: name, In: "header", Description: description, Schema: &Schema{Type: "string"}}
+}
+
+// Status formats an HTTP status as a responses key.
+func Status(code int) string {
+	return strconv.Itoa(code)
+}
+
+// Handler serves the document as JSON.
+func Handler(d *Document) http.Handler {
+	body, err := json.Marshal(d)
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write(body)
+	})
+}
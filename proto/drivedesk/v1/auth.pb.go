@@ -0,0 +1,183 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        (unknown)
+// source: drivedesk/v1/auth.proto
+
+package drivedeskv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type LoginRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserName      string                 `protobuf:"bytes,1,opt,name=user_name,json=userName,proto3" json:"user_name,omitempty"`
+	Password      string                 `protobuf:"bytes,2,opt,name=password,proto3" json:"password,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LoginRequest) Reset() {
+	*x = LoginRequest{}
+	mi := &file_drivedesk_v1_auth_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LoginRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LoginRequest) ProtoMessage() {}
+
+func (x *LoginRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_drivedesk_v1_auth_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LoginRequest.ProtoReflect.Descriptor instead.
+func (*LoginRequest) Descriptor() ([]byte, []int) {
+	return file_drivedesk_v1_auth_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *LoginRequest) GetUserName() string {
+	if x != nil {
+		return x.UserName
+	}
+	return ""
+}
+
+func (x *LoginRequest) GetPassword() string {
+	if x != nil {
+		return x.Password
+	}
+	return ""
+}
+
+type LoginResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// JWT valid for 24 hours, the same as REST POST /login returns.
+	Token         string `protobuf:"bytes,1,opt,name=token,proto3" json:"token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LoginResponse) Reset() {
+	*x = LoginResponse{}
+	mi := &file_drivedesk_v1_auth_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LoginResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LoginResponse) ProtoMessage() {}
+
+func (x *LoginResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_drivedesk_v1_auth_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LoginResponse.ProtoReflect.Descriptor instead.
+func (*LoginResponse) Descriptor() ([]byte, []int) {
+	return file_drivedesk_v1_auth_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *LoginResponse) GetToken() string {
+	if x != nil {
+		return x.Token
+	}
+	return ""
+}
+
+var File_drivedesk_v1_auth_proto protoreflect.FileDescriptor
+
+const file_drivedesk_v1_auth_proto_rawDesc = "" +
+	"\n" +
+	"\x17drivedesk/v1/auth.proto\x12\fdrivedesk.v1\"G\n" +
+	"\fLoginRequest\x12\x1b\n" +
+	"\tuser_name\x18\x01 \x01(\tR\buserName\x12\x1a\n" +
+	"\bpassword\x18\x02 \x01(\tR\bpassword\"%\n" +
+	"\rLoginResponse\x12\x14\n" +
+	"\x05token\x18\x01 \x01(\tR\x05token2O\n" +
+	"\vAuthService\x12@\n" +
+	"\x05Login\x12\x1a.drivedesk.v1.LoginRequest\x1a\x1b.drivedesk.v1.LoginResponseB@Z>github.com/geekAshish/DriveDesk/proto/drivedesk/v1;drivedeskv1b\x06proto3"
+
+var (
+	file_drivedesk_v1_auth_proto_rawDescOnce sync.Once
+	file_drivedesk_v1_auth_proto_rawDescData []byte
+)
+
+func file_drivedesk_v1_auth_proto_rawDescGZIP() []byte {
+	file_drivedesk_v1_auth_proto_rawDescOnce.Do(func() {
+		file_drivedesk_v1_auth_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_drivedesk_v1_auth_proto_rawDesc), len(file_drivedesk_v1_auth_proto_rawDesc)))
+	})
+	return file_drivedesk_v1_auth_proto_rawDescData
+}
+
+var file_drivedesk_v1_auth_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_drivedesk_v1_auth_proto_goTypes = []any{
+	(*LoginRequest)(nil),  // 0: drivedesk.v1.LoginRequest
+	(*LoginResponse)(nil), // 1: drivedesk.v1.LoginResponse
+}
+var file_drivedesk_v1_auth_proto_depIdxs = []int32{
+	0, // 0: drivedesk.v1.AuthService.Login:input_type -> drivedesk.v1.LoginRequest
+	1, // 1: drivedesk.v1.AuthService.Login:output_type -> drivedesk.v1.LoginResponse
+	1, // [1:2] is the sub-list for method output_type
+	0, // [0:1] is the sub-list for method input_type
+	0, // [0:0] is the sub-list for extension type_name
+	0, // [0:0] is the sub-list for extension extendee
+	0, // [0:0] is the sub-list for field type_name
+}
+
+func init() { file_drivedesk_v1_auth_proto_init() }
+func file_drivedesk_v1_auth_proto_init() {
+	if File_drivedesk_v1_auth_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_drivedesk_v1_auth_proto_rawDesc), len(file_drivedesk_v1_auth_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   2,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_drivedesk_v1_auth_proto_goTypes,
+		DependencyIndexes: file_drivedesk_v1_auth_proto_depIdxs,
+		MessageInfos:      file_drivedesk_v1_auth_proto_msgTypes,
+	}.Build()
+	File_drivedesk_v1_auth_proto = out.File
+	file_drivedesk_v1_auth_proto_goTypes = nil
+	file_drivedesk_v1_auth_proto_depIdxs = nil
+}
@@ -0,0 +1,641 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        (unknown)
+// source: drivedesk/v1/car.proto
+
+package drivedeskv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type Car struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// UUID of the car.
+	Id    string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name  string `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Year  int32  `protobuf:"varint,3,opt,name=year,proto3" json:"year,omitempty"`
+	Brand string `protobuf:"bytes,4,opt,name=brand,proto3" json:"brand,omitempty"`
+	// Canonical fuel type code, e.g. petrol.
+	FuelType      string                 `protobuf:"bytes,5,opt,name=fuel_type,json=fuelType,proto3" json:"fuel_type,omitempty"`
+	Price         float64                `protobuf:"fixed64,6,opt,name=price,proto3" json:"price,omitempty"`
+	Vin           string                 `protobuf:"bytes,7,opt,name=vin,proto3" json:"vin,omitempty"`
+	Engine        *Engine                `protobuf:"bytes,8,opt,name=engine,proto3" json:"engine,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,9,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt     *timestamppb.Timestamp `protobuf:"bytes,10,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Car) Reset() {
+	*x = Car{}
+	mi := &file_drivedesk_v1_car_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Car) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Car) ProtoMessage() {}
+
+func (x *Car) ProtoReflect() protoreflect.Message {
+	mi := &file_drivedesk_v1_car_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Car.ProtoReflect.Descriptor instead.
+func (*Car) Descriptor() ([]byte, []int) {
+	return file_drivedesk_v1_car_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Car) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Car) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Car) GetYear() int32 {
+	if x != nil {
+		return x.Year
+	}
+	return 0
+}
+
+func (x *Car) GetBrand() string {
+	if x != nil {
+		return x.Brand
+	}
+	return ""
+}
+
+func (x *Car) GetFuelType() string {
+	if x != nil {
+		return x.FuelType
+	}
+	return ""
+}
+
+func (x *Car) GetPrice() float64 {
+	if x != nil {
+		return x.Price
+	}
+	return 0
+}
+
+func (x *Car) GetVin() string {
+	if x != nil {
+		return x.Vin
+	}
+	return ""
+}
+
+func (x *Car) GetEngine() *Engine {
+	if x != nil {
+		return x.Engine
+	}
+	return nil
+}
+
+func (x *Car) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *Car) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+type CarInput struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Name  string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Year  int32                  `protobuf:"varint,2,opt,name=year,proto3" json:"year,omitempty"`
+	Brand string                 `protobuf:"bytes,3,opt,name=brand,proto3" json:"brand,omitempty"`
+	// Fuel type code or alias, e.g. gasoline.
+	FuelType string  `protobuf:"bytes,4,opt,name=fuel_type,json=fuelType,proto3" json:"fuel_type,omitempty"`
+	Price    float64 `protobuf:"fixed64,5,opt,name=price,proto3" json:"price,omitempty"`
+	Vin      string  `protobuf:"bytes,6,opt,name=vin,proto3" json:"vin,omitempty"`
+	// The engine must already exist; its details are validated like the
+	// engine of a REST car request.
+	Engine        *Engine `protobuf:"bytes,7,opt,name=engine,proto3" json:"engine,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CarInput) Reset() {
+	*x = CarInput{}
+	mi := &file_drivedesk_v1_car_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CarInput) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CarInput) ProtoMessage() {}
+
+func (x *CarInput) ProtoReflect() protoreflect.Message {
+	mi := &file_drivedesk_v1_car_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CarInput.ProtoReflect.Descriptor instead.
+func (*CarInput) Descriptor() ([]byte, []int) {
+	return file_drivedesk_v1_car_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *CarInput) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *CarInput) GetYear() int32 {
+	if x != nil {
+		return x.Year
+	}
+	return 0
+}
+
+func (x *CarInput) GetBrand() string {
+	if x != nil {
+		return x.Brand
+	}
+	return ""
+}
+
+func (x *CarInput) GetFuelType() string {
+	if x != nil {
+		return x.FuelType
+	}
+	return ""
+}
+
+func (x *CarInput) GetPrice() float64 {
+	if x != nil {
+		return x.Price
+	}
+	return 0
+}
+
+func (x *CarInput) GetVin() string {
+	if x != nil {
+		return x.Vin
+	}
+	return ""
+}
+
+func (x *CarInput) GetEngine() *Engine {
+	if x != nil {
+		return x.Engine
+	}
+	return nil
+}
+
+type GetCarRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetCarRequest) Reset() {
+	*x = GetCarRequest{}
+	mi := &file_drivedesk_v1_car_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetCarRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetCarRequest) ProtoMessage() {}
+
+func (x *GetCarRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_drivedesk_v1_car_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetCarRequest.ProtoReflect.Descriptor instead.
+func (*GetCarRequest) Descriptor() ([]byte, []int) {
+	return file_drivedesk_v1_car_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *GetCarRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type ListCarsRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Brand string                 `protobuf:"bytes,1,opt,name=brand,proto3" json:"brand,omitempty"`
+	// Load full engine details instead of only the engine id.
+	IncludeEngine bool `protobuf:"varint,2,opt,name=include_engine,json=includeEngine,proto3" json:"include_engine,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListCarsRequest) Reset() {
+	*x = ListCarsRequest{}
+	mi := &file_drivedesk_v1_car_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListCarsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListCarsRequest) ProtoMessage() {}
+
+func (x *ListCarsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_drivedesk_v1_car_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListCarsRequest.ProtoReflect.Descriptor instead.
+func (*ListCarsRequest) Descriptor() ([]byte, []int) {
+	return file_drivedesk_v1_car_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *ListCarsRequest) GetBrand() string {
+	if x != nil {
+		return x.Brand
+	}
+	return ""
+}
+
+func (x *ListCarsRequest) GetIncludeEngine() bool {
+	if x != nil {
+		return x.IncludeEngine
+	}
+	return false
+}
+
+type ListCarsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Cars          []*Car                 `protobuf:"bytes,1,rep,name=cars,proto3" json:"cars,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListCarsResponse) Reset() {
+	*x = ListCarsResponse{}
+	mi := &file_drivedesk_v1_car_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListCarsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListCarsResponse) ProtoMessage() {}
+
+func (x *ListCarsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_drivedesk_v1_car_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListCarsResponse.ProtoReflect.Descriptor instead.
+func (*ListCarsResponse) Descriptor() ([]byte, []int) {
+	return file_drivedesk_v1_car_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *ListCarsResponse) GetCars() []*Car {
+	if x != nil {
+		return x.Cars
+	}
+	return nil
+}
+
+type CreateCarRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Car           *CarInput              `protobuf:"bytes,1,opt,name=car,proto3" json:"car,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateCarRequest) Reset() {
+	*x = CreateCarRequest{}
+	mi := &file_drivedesk_v1_car_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateCarRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateCarRequest) ProtoMessage() {}
+
+func (x *CreateCarRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_drivedesk_v1_car_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateCarRequest.ProtoReflect.Descriptor instead.
+func (*CreateCarRequest) Descriptor() ([]byte, []int) {
+	return file_drivedesk_v1_car_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *CreateCarRequest) GetCar() *CarInput {
+	if x != nil {
+		return x.Car
+	}
+	return nil
+}
+
+type UpdateCarRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Car           *CarInput              `protobuf:"bytes,2,opt,name=car,proto3" json:"car,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateCarRequest) Reset() {
+	*x = UpdateCarRequest{}
+	mi := &file_drivedesk_v1_car_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateCarRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateCarRequest) ProtoMessage() {}
+
+func (x *UpdateCarRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_drivedesk_v1_car_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateCarRequest.ProtoReflect.Descriptor instead.
+func (*UpdateCarRequest) Descriptor() ([]byte, []int) {
+	return file_drivedesk_v1_car_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *UpdateCarRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *UpdateCarRequest) GetCar() *CarInput {
+	if x != nil {
+		return x.Car
+	}
+	return nil
+}
+
+type DeleteCarRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteCarRequest) Reset() {
+	*x = DeleteCarRequest{}
+	mi := &file_drivedesk_v1_car_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteCarRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteCarRequest) ProtoMessage() {}
+
+func (x *DeleteCarRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_drivedesk_v1_car_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteCarRequest.ProtoReflect.Descriptor instead.
+func (*DeleteCarRequest) Descriptor() ([]byte, []int) {
+	return file_drivedesk_v1_car_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *DeleteCarRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+var File_drivedesk_v1_car_proto protoreflect.FileDescriptor
+
+const file_drivedesk_v1_car_proto_rawDesc = "" +
+	"\n" +
+	"\x16drivedesk/v1/car.proto\x12\fdrivedesk.v1\x1a\x19drivedesk/v1/engine.proto\x1a\x1fgoogle/protobuf/timestamp.proto\"\xbc\x02\n" +
+	"\x03Car\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x12\n" +
+	"\x04year\x18\x03 \x01(\x05R\x04year\x12\x14\n" +
+	"\x05brand\x18\x04 \x01(\tR\x05brand\x12\x1b\n" +
+	"\tfuel_type\x18\x05 \x01(\tR\bfuelType\x12\x14\n" +
+	"\x05price\x18\x06 \x01(\x01R\x05price\x12\x10\n" +
+	"\x03vin\x18\a \x01(\tR\x03vin\x12,\n" +
+	"\x06engine\x18\b \x01(\v2\x14.drivedesk.v1.EngineR\x06engine\x129\n" +
+	"\n" +
+	"created_at\x18\t \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
+	"\n" +
+	"updated_at\x18\n" +
+	" \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\"\xbb\x01\n" +
+	"\bCarInput\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x12\n" +
+	"\x04year\x18\x02 \x01(\x05R\x04year\x12\x14\n" +
+	"\x05brand\x18\x03 \x01(\tR\x05brand\x12\x1b\n" +
+	"\tfuel_type\x18\x04 \x01(\tR\bfuelType\x12\x14\n" +
+	"\x05price\x18\x05 \x01(\x01R\x05price\x12\x10\n" +
+	"\x03vin\x18\x06 \x01(\tR\x03vin\x12,\n" +
+	"\x06engine\x18\a \x01(\v2\x14.drivedesk.v1.EngineR\x06engine\"\x1f\n" +
+	"\rGetCarRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"N\n" +
+	"\x0fListCarsRequest\x12\x14\n" +
+	"\x05brand\x18\x01 \x01(\tR\x05brand\x12%\n" +
+	"\x0einclude_engine\x18\x02 \x01(\bR\rincludeEngine\"9\n" +
+	"\x10ListCarsResponse\x12%\n" +
+	"\x04cars\x18\x01 \x03(\v2\x11.drivedesk.v1.CarR\x04cars\"<\n" +
+	"\x10CreateCarRequest\x12(\n" +
+	"\x03car\x18\x01 \x01(\v2\x16.drivedesk.v1.CarInputR\x03car\"L\n" +
+	"\x10UpdateCarRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12(\n" +
+	"\x03car\x18\x02 \x01(\v2\x16.drivedesk.v1.CarInputR\x03car\"\"\n" +
+	"\x10DeleteCarRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id2\xd1\x02\n" +
+	"\n" +
+	"CarService\x128\n" +
+	"\x06GetCar\x12\x1b.drivedesk.v1.GetCarRequest\x1a\x11.drivedesk.v1.Car\x12I\n" +
+	"\bListCars\x12\x1d.drivedesk.v1.ListCarsRequest\x1a\x1e.drivedesk.v1.ListCarsResponse\x12>\n" +
+	"\tCreateCar\x12\x1e.drivedesk.v1.CreateCarRequest\x1a\x11.drivedesk.v1.Car\x12>\n" +
+	"\tUpdateCar\x12\x1e.drivedesk.v1.UpdateCarRequest\x1a\x11.drivedesk.v1.Car\x12>\n" +
+	"\tDeleteCar\x12\x1e.drivedesk.v1.DeleteCarRequest\x1a\x11.drivedesk.v1.CarB@Z>github.com/geekAshish/DriveDesk/proto/drivedesk/v1;drivedeskv1b\x06proto3"
+
+var (
+	file_drivedesk_v1_car_proto_rawDescOnce sync.Once
+	file_drivedesk_v1_car_proto_rawDescData []byte
+)
+
+func file_drivedesk_v1_car_proto_rawDescGZIP() []byte {
+	file_drivedesk_v1_car_proto_rawDescOnce.Do(func() {
+		file_drivedesk_v1_car_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_drivedesk_v1_car_proto_rawDesc), len(file_drivedesk_v1_car_proto_rawDesc)))
+	})
+	return file_drivedesk_v1_car_proto_rawDescData
+}
+
+var file_drivedesk_v1_car_proto_msgTypes = make([]protoimpl.MessageInfo, 8)
+var file_drivedesk_v1_car_proto_goTypes = []any{
+	(*Car)(nil),                   // 0: drivedesk.v1.Car
+	(*CarInput)(nil),              // 1: drivedesk.v1.CarInput
+	(*GetCarRequest)(nil),         // 2: drivedesk.v1.GetCarRequest
+	(*ListCarsRequest)(nil),       // 3: drivedesk.v1.ListCarsRequest
+	(*ListCarsResponse)(nil),      // 4: drivedesk.v1.ListCarsResponse
+	(*CreateCarRequest)(nil),      // 5: drivedesk.v1.CreateCarRequest
+	(*UpdateCarRequest)(nil),      // 6: drivedesk.v1.UpdateCarRequest
+	(*DeleteCarRequest)(nil),      // 7: drivedesk.v1.DeleteCarRequest
+	(*Engine)(nil),                // 8: drivedesk.v1.Engine
+	(*timestamppb.Timestamp)(nil), // 9: google.protobuf.Timestamp
+}
+var file_drivedesk_v1_car_proto_depIdxs = []int32{
+	8,  // 0: drivedesk.v1.Car.engine:type_name -> drivedesk.v1.Engine
+	9,  // 1: drivedesk.v1.Car.created_at:type_name -> google.protobuf.Timestamp
+	9,  // 2: drivedesk.v1.Car.updated_at:type_name -> google.protobuf.Timestamp
+	8,  // 3: drivedesk.v1.CarInput.engine:type_name -> drivedesk.v1.Engine
+	0,  // 4: drivedesk.v1.ListCarsResponse.cars:type_name -> drivedesk.v1.Car
+	1,  // 5: drivedesk.v1.CreateCarRequest.car:type_name -> drivedesk.v1.CarInput
+	1,  // 6: drivedesk.v1.UpdateCarRequest.car:type_name -> drivedesk.v1.CarInput
+	2,  // 7: drivedesk.v1.CarService.GetCar:input_type -> drivedesk.v1.GetCarRequest
+	3,  // 8: drivedesk.v1.CarService.ListCars:input_type -> drivedesk.v1.ListCarsRequest
+	5,  // 9: drivedesk.v1.CarService.CreateCar:input_type -> drivedesk.v1.CreateCarRequest
+	6,  // 10: drivedesk.v1.CarService.UpdateCar:input_type -> drivedesk.v1.UpdateCarRequest
+	7,  // 11: drivedesk.v1.CarService.DeleteCar:input_type -> drivedesk.v1.DeleteCarRequest
+	0,  // 12: drivedesk.v1.CarService.GetCar:output_type -> drivedesk.v1.Car
+	4,  // 13: drivedesk.v1.CarService.ListCars:output_type -> drivedesk.v1.ListCarsResponse
+	0,  // 14: drivedesk.v1.CarService.CreateCar:output_type -> drivedesk.v1.Car
+	0,  // 15: drivedesk.v1.CarService.UpdateCar:output_type -> drivedesk.v1.Car
+	0,  // 16: drivedesk.v1.CarService.DeleteCar:output_type -> drivedesk.v1.Car
+	12, // [12:17] is the sub-list for method output_type
+	7,  // [7:12] is the sub-list for method input_type
+	7,  // [7:7] is the sub-list for extension type_name
+	7,  // [7:7] is the sub-list for extension extendee
+	0,  // [0:7] is the sub-list for field type_name
+}
+
+func init() { file_drivedesk_v1_car_proto_init() }
+func file_drivedesk_v1_car_proto_init() {
+	if File_drivedesk_v1_car_proto != nil {
+		return
+	}
+	file_drivedesk_v1_engine_proto_init()
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_drivedesk_v1_car_proto_rawDesc), len(file_drivedesk_v1_car_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   8,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_drivedesk_v1_car_proto_goTypes,
+		DependencyIndexes: file_drivedesk_v1_car_proto_depIdxs,
+		MessageInfos:      file_drivedesk_v1_car_proto_msgTypes,
+	}.Build()
+	File_drivedesk_v1_car_proto = out.File
+	file_drivedesk_v1_car_proto_goTypes = nil
+	file_drivedesk_v1_car_proto_depIdxs = nil
+}
@@ -0,0 +1,452 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        (unknown)
+// source: drivedesk/v1/engine.proto
+
+package drivedeskv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type Engine struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// UUID of the engine.
+	EngineId      string  `protobuf:"bytes,1,opt,name=engine_id,json=engineId,proto3" json:"engine_id,omitempty"`
+	Displacement  float64 `protobuf:"fixed64,2,opt,name=displacement,proto3" json:"displacement,omitempty"`
+	NoOfCylinders int32   `protobuf:"varint,3,opt,name=no_of_cylinders,json=noOfCylinders,proto3" json:"no_of_cylinders,omitempty"`
+	CarRange      float64 `protobuf:"fixed64,4,opt,name=car_range,json=carRange,proto3" json:"car_range,omitempty"`
+	// Unset when only the id of a car's engine was loaded.
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt     *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Engine) Reset() {
+	*x = Engine{}
+	mi := &file_drivedesk_v1_engine_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Engine) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Engine) ProtoMessage() {}
+
+func (x *Engine) ProtoReflect() protoreflect.Message {
+	mi := &file_drivedesk_v1_engine_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Engine.ProtoReflect.Descriptor instead.
+func (*Engine) Descriptor() ([]byte, []int) {
+	return file_drivedesk_v1_engine_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Engine) GetEngineId() string {
+	if x != nil {
+		return x.EngineId
+	}
+	return ""
+}
+
+func (x *Engine) GetDisplacement() float64 {
+	if x != nil {
+		return x.Displacement
+	}
+	return 0
+}
+
+func (x *Engine) GetNoOfCylinders() int32 {
+	if x != nil {
+		return x.NoOfCylinders
+	}
+	return 0
+}
+
+func (x *Engine) GetCarRange() float64 {
+	if x != nil {
+		return x.CarRange
+	}
+	return 0
+}
+
+func (x *Engine) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *Engine) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+type EngineInput struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Displacement  float64                `protobuf:"fixed64,1,opt,name=displacement,proto3" json:"displacement,omitempty"`
+	NoOfCylinders int32                  `protobuf:"varint,2,opt,name=no_of_cylinders,json=noOfCylinders,proto3" json:"no_of_cylinders,omitempty"`
+	CarRange      float64                `protobuf:"fixed64,3,opt,name=car_range,json=carRange,proto3" json:"car_range,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *EngineInput) Reset() {
+	*x = EngineInput{}
+	mi := &file_drivedesk_v1_engine_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EngineInput) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EngineInput) ProtoMessage() {}
+
+func (x *EngineInput) ProtoReflect() protoreflect.Message {
+	mi := &file_drivedesk_v1_engine_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EngineInput.ProtoReflect.Descriptor instead.
+func (*EngineInput) Descriptor() ([]byte, []int) {
+	return file_drivedesk_v1_engine_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *EngineInput) GetDisplacement() float64 {
+	if x != nil {
+		return x.Displacement
+	}
+	return 0
+}
+
+func (x *EngineInput) GetNoOfCylinders() int32 {
+	if x != nil {
+		return x.NoOfCylinders
+	}
+	return 0
+}
+
+func (x *EngineInput) GetCarRange() float64 {
+	if x != nil {
+		return x.CarRange
+	}
+	return 0
+}
+
+type GetEngineRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetEngineRequest) Reset() {
+	*x = GetEngineRequest{}
+	mi := &file_drivedesk_v1_engine_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetEngineRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetEngineRequest) ProtoMessage() {}
+
+func (x *GetEngineRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_drivedesk_v1_engine_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetEngineRequest.ProtoReflect.Descriptor instead.
+func (*GetEngineRequest) Descriptor() ([]byte, []int) {
+	return file_drivedesk_v1_engine_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *GetEngineRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type CreateEngineRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Engine        *EngineInput           `protobuf:"bytes,1,opt,name=engine,proto3" json:"engine,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateEngineRequest) Reset() {
+	*x = CreateEngineRequest{}
+	mi := &file_drivedesk_v1_engine_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateEngineRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateEngineRequest) ProtoMessage() {}
+
+func (x *CreateEngineRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_drivedesk_v1_engine_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateEngineRequest.ProtoReflect.Descriptor instead.
+func (*CreateEngineRequest) Descriptor() ([]byte, []int) {
+	return file_drivedesk_v1_engine_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *CreateEngineRequest) GetEngine() *EngineInput {
+	if x != nil {
+		return x.Engine
+	}
+	return nil
+}
+
+type UpdateEngineRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Engine        *EngineInput           `protobuf:"bytes,2,opt,name=engine,proto3" json:"engine,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateEngineRequest) Reset() {
+	*x = UpdateEngineRequest{}
+	mi := &file_drivedesk_v1_engine_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateEngineRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateEngineRequest) ProtoMessage() {}
+
+func (x *UpdateEngineRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_drivedesk_v1_engine_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateEngineRequest.ProtoReflect.Descriptor instead.
+func (*UpdateEngineRequest) Descriptor() ([]byte, []int) {
+	return file_drivedesk_v1_engine_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *UpdateEngineRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *UpdateEngineRequest) GetEngine() *EngineInput {
+	if x != nil {
+		return x.Engine
+	}
+	return nil
+}
+
+type DeleteEngineRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteEngineRequest) Reset() {
+	*x = DeleteEngineRequest{}
+	mi := &file_drivedesk_v1_engine_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteEngineRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteEngineRequest) ProtoMessage() {}
+
+func (x *DeleteEngineRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_drivedesk_v1_engine_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteEngineRequest.ProtoReflect.Descriptor instead.
+func (*DeleteEngineRequest) Descriptor() ([]byte, []int) {
+	return file_drivedesk_v1_engine_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *DeleteEngineRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+var File_drivedesk_v1_engine_proto protoreflect.FileDescriptor
+
+const file_drivedesk_v1_engine_proto_rawDesc = "" +
+	"\n" +
+	"\x19drivedesk/v1/engine.proto\x12\fdrivedesk.v1\x1a\x1fgoogle/protobuf/timestamp.proto\"\x84\x02\n" +
+	"\x06Engine\x12\x1b\n" +
+	"\tengine_id\x18\x01 \x01(\tR\bengineId\x12\"\n" +
+	"\fdisplacement\x18\x02 \x01(\x01R\fdisplacement\x12&\n" +
+	"\x0fno_of_cylinders\x18\x03 \x01(\x05R\rnoOfCylinders\x12\x1b\n" +
+	"\tcar_range\x18\x04 \x01(\x01R\bcarRange\x129\n" +
+	"\n" +
+	"created_at\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
+	"\n" +
+	"updated_at\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\"v\n" +
+	"\vEngineInput\x12\"\n" +
+	"\fdisplacement\x18\x01 \x01(\x01R\fdisplacement\x12&\n" +
+	"\x0fno_of_cylinders\x18\x02 \x01(\x05R\rnoOfCylinders\x12\x1b\n" +
+	"\tcar_range\x18\x03 \x01(\x01R\bcarRange\"\"\n" +
+	"\x10GetEngineRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"H\n" +
+	"\x13CreateEngineRequest\x121\n" +
+	"\x06engine\x18\x01 \x01(\v2\x19.drivedesk.v1.EngineInputR\x06engine\"X\n" +
+	"\x13UpdateEngineRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x121\n" +
+	"\x06engine\x18\x02 \x01(\v2\x19.drivedesk.v1.EngineInputR\x06engine\"%\n" +
+	"\x13DeleteEngineRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id2\xad\x02\n" +
+	"\rEngineService\x12A\n" +
+	"\tGetEngine\x12\x1e.drivedesk.v1.GetEngineRequest\x1a\x14.drivedesk.v1.Engine\x12G\n" +
+	"\fCreateEngine\x12!.drivedesk.v1.CreateEngineRequest\x1a\x14.drivedesk.v1.Engine\x12G\n" +
+	"\fUpdateEngine\x12!.drivedesk.v1.UpdateEngineRequest\x1a\x14.drivedesk.v1.Engine\x12G\n" +
+	"\fDeleteEngine\x12!.drivedesk.v1.DeleteEngineRequest\x1a\x14.drivedesk.v1.EngineB@Z>github.com/geekAshish/DriveDesk/proto/drivedesk/v1;drivedeskv1b\x06proto3"
+
+var (
+	file_drivedesk_v1_engine_proto_rawDescOnce sync.Once
+	file_drivedesk_v1_engine_proto_rawDescData []byte
+)
+
+func file_drivedesk_v1_engine_proto_rawDescGZIP() []byte {
+	file_drivedesk_v1_engine_proto_rawDescOnce.Do(func() {
+		file_drivedesk_v1_engine_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_drivedesk_v1_engine_proto_rawDesc), len(file_drivedesk_v1_engine_proto_rawDesc)))
+	})
+	return file_drivedesk_v1_engine_proto_rawDescData
+}
+
+var file_drivedesk_v1_engine_proto_msgTypes = make([]protoimpl.MessageInfo, 6)
+var file_drivedesk_v1_engine_proto_goTypes = []any{
+	(*Engine)(nil),                // 0: drivedesk.v1.Engine
+	(*EngineInput)(nil),           // 1: drivedesk.v1.EngineInput
+	(*GetEngineRequest)(nil),      // 2: drivedesk.v1.GetEngineRequest
+	(*CreateEngineRequest)(nil),   // 3: drivedesk.v1.CreateEngineRequest
+	(*UpdateEngineRequest)(nil),   // 4: drivedesk.v1.UpdateEngineRequest
+	(*DeleteEngineRequest)(nil),   // 5: drivedesk.v1.DeleteEngineRequest
+	(*timestamppb.Timestamp)(nil), // 6: google.protobuf.Timestamp
+}
+var file_drivedesk_v1_engine_proto_depIdxs = []int32{
+	6, // 0: drivedesk.v1.Engine.created_at:type_name -> google.protobuf.Timestamp
+	6, // 1: drivedesk.v1.Engine.updated_at:type_name -> google.protobuf.Timestamp
+	1, // 2: drivedesk.v1.CreateEngineRequest.engine:type_name -> drivedesk.v1.EngineInput
+	1, // 3: drivedesk.v1.UpdateEngineRequest.engine:type_name -> drivedesk.v1.EngineInput
+	2, // 4: drivedesk.v1.EngineService.GetEngine:input_type -> drivedesk.v1.GetEngineRequest
+	3, // 5: drivedesk.v1.EngineService.CreateEngine:input_type -> drivedesk.v1.CreateEngineRequest
+	4, // 6: drivedesk.v1.EngineService.UpdateEngine:input_type -> drivedesk.v1.UpdateEngineRequest
+	5, // 7: drivedesk.v1.EngineService.DeleteEngine:input_type -> drivedesk.v1.DeleteEngineRequest
+	0, // 8: drivedesk.v1.EngineService.GetEngine:output_type -> drivedesk.v1.Engine
+	0, // 9: drivedesk.v1.EngineService.CreateEngine:output_type -> drivedesk.v1.Engine
+	0, // 10: drivedesk.v1.EngineService.UpdateEngine:output_type -> drivedesk.v1.Engine
+	0, // 11: drivedesk.v1.EngineService.DeleteEngine:output_type -> drivedesk.v1.Engine
+	8, // [8:12] is the sub-list for method output_type
+	4, // [4:8] is the sub-list for method input_type
+	4, // [4:4] is the sub-list for extension type_name
+	4, // [4:4] is the sub-list for extension extendee
+	0, // [0:4] is the sub-list for field type_name
+}
+
+func init() { file_drivedesk_v1_engine_proto_init() }
+func file_drivedesk_v1_engine_proto_init() {
+	if File_drivedesk_v1_engine_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_drivedesk_v1_engine_proto_rawDesc), len(file_drivedesk_v1_engine_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   6,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_drivedesk_v1_engine_proto_goTypes,
+		DependencyIndexes: file_drivedesk_v1_engine_proto_depIdxs,
+		MessageInfos:      file_drivedesk_v1_engine_proto_msgTypes,
+	}.Build()
+	File_drivedesk_v1_engine_proto = out.File
+	file_drivedesk_v1_engine_proto_goTypes = nil
+	file_drivedesk_v1_engine_proto_depIdxs = nil
+}
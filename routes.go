@@ -0,0 +1,214 @@
+package main
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/geekAshish/DriveDesk/graphqlserver"
+	"github.com/geekAshish/DriveDesk/handler/codec"
+	"github.com/geekAshish/DriveDesk/handler/response"
+	"github.com/geekAshish/DriveDesk/middleware"
+	"github.com/geekAshish/DriveDesk/openapi"
+	"github.com/gorilla/mux"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/swaggest/swgui/v5emb"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gorilla/mux/otelmux"
+
+	carHandler "github.com/geekAshish/DriveDesk/handler/car"
+	engineHandler "github.com/geekAshish/DriveDesk/handler/engine"
+	loginHandler "github.com/geekAshish/DriveDesk/handler/login"
+	referenceHandler "github.com/geekAshish/DriveDesk/handler/reference"
+	ruleHandler "github.com/geekAshish/DriveDesk/handler/rule"
+	streamHandler "github.com/geekAshish/DriveDesk/handler/stream"
+	syncHandler "github.com/geekAshish/DriveDesk/handler/sync"
+	handlerV2 "github.com/geekAshish/DriveDesk/handler/v2"
+	webhookHandler "github.com/geekAshish/DriveDesk/handler/webhook"
+)
+
+// handlers are the API handlers the router dispatches to.
+type handlers struct {
+	car       *carHandler.CarHandler
+	engine    *engineHandler.EngineHandler
+	reference *referenceHandler.ReferenceHandler
+	rule      *ruleHandler.RuleHandler
+	webhook   *webhookHandler.WebhookHandler
+	stream    *streamHandler.StreamHandler
+	sync      *syncHandler.SyncHandler
+
+	carV2        *handlerV2.CarHandler
+	engineV2     *handlerV2.EngineHandler
+	validationV2 *handlerV2.ValidationHandler
+
+	graphql *graphqlserver.Handler
+
+	// idempotency replays the responses of retried POSTs that create cars
+	// and engines.
+	idempotency *middleware.Idempotency
+
+	// cacheControl is the Cache-Control of the cacheable GET routes.
+	cacheControl middleware.CacheControl
+
+	// rateLimit limits the requests of each client to login, GraphQL and
+	// the authenticated routes, by address before their token is checked
+	// and by user after.
+	rateLimit *middleware.RateLimiter
+}
+
+// cacheableRoutes are the routes whose Cache-Control can be configured.
+var cacheableRoutes = []string{"/cars/{id}", "/cars", "/engine/{id}"}
+
+// cached makes the responses of a GET route conditional, with the
+// Cache-Control configured for route.
+func (h handlers) cached(route string, next http.Handler) http.Handler {
+	return middleware.HTTPCache(h.cacheControl.For(route))(next)
+}
+
+// The routes without a version prefix are the API as it was before
+// versioning, served as aliases of v1 until their sunset. v1 itself is
+// superseded by v2.
+var (
+	unversionedDeprecation = middleware.Deprecation{
+		Version:   "unversioned",
+		Since:     time.Date(2026, time.October, 19, 0, 0, 0, 0, time.UTC),
+		Sunset:    time.Date(2027, time.January, 31, 0, 0, 0, 0, time.UTC),
+		Successor: func(path string) string { return "/v1" + path },
+	}
+
+	v1Deprecation = middleware.Deprecation{
+		Version:   "v1",
+		Since:     time.Date(2026, time.October, 19, 0, 0, 0, 0, time.UTC),
+		Sunset:    time.Date(2027, time.October, 31, 0, 0, 0, 0, time.UTC),
+		Successor: func(path string) string { return "/v2" + strings.TrimPrefix(path, "/v1") },
+	}
+)
+
+// newRouter registers every route of the API. Routes added here must also be
+// described in apiSpecV1 or apiSpecV2, which TestRoutesAreDocumented checks.
+func newRouter(h handlers) *mux.Router {
+	router := mux.NewRouter()
+	router.NotFoundHandler = middleware.RequestIDMiddleware(http.HandlerFunc(response.NotFound))
+	router.MethodNotAllowedHandler = middleware.RequestIDMiddleware(http.HandlerFunc(response.MethodNotAllowed))
+
+	router.Use(middleware.RequestIDMiddleware)
+
+	// otel middleware for tracing
+	router.Use(otelmux.Middleware("DriveDesk"))
+	router.Use(middleware.MetricMiddleware)
+
+	// the API descriptions and their UI are public, like the login route;
+	// the unversioned ones describe the latest version
+	router.Handle("/openapi.json", openapi.Handler(apiSpecV2())).Methods("GET")
+	router.PathPrefix("/docs").Handler(v5emb.New("DriveDesk API", "/v2/openapi.json", "/docs")).Methods("GET")
+
+	router.Handle("/metrics", promhttp.Handler())
+
+	// GraphQL has its own schema and evolves without URL versions
+	router.Handle("/graphql", h.rateLimit.PreAuth(middleware.AuthMiddleware(h.rateLimit.Middleware(h.graphql)))).Methods("POST")
+
+	v2 := router.PathPrefix("/v2").Subrouter()
+	v2.Handle("/openapi.json", openapi.Handler(apiSpecV2())).Methods("GET")
+	v2.PathPrefix("/docs").Handler(v5emb.New("DriveDesk API v2", "/v2/openapi.json", "/v2/docs")).Methods("GET")
+	registerV2Routes(v2, h)
+
+	v1 := router.PathPrefix("/v1").Subrouter()
+	v1.Use(middleware.Deprecated(v1Deprecation))
+	v1.Handle("/openapi.json", openapi.Handler(apiSpecV1())).Methods("GET")
+	v1.PathPrefix("/docs").Handler(v5emb.New("DriveDesk API v1", "/v1/openapi.json", "/v1/docs")).Methods("GET")
+	registerV1Routes(v1, h)
+
+	unversioned := router.NewRoute().Subrouter()
+	unversioned.Use(middleware.Deprecated(unversionedDeprecation))
+	registerV1Routes(unversioned, h)
+
+	return router
+}
+
+// registerV1Routes registers the v1 API on r.
+func registerV1Routes(r *mux.Router, h handlers) {
+	// logins are limited by address, everything else once the user is known
+	r.Handle("/login", h.rateLimit.Middleware(http.HandlerFunc(loginHandler.LoginHandler))).Methods("POST")
+
+	// Middleware
+	protected := r.PathPrefix("/").Subrouter()
+	protected.Use(h.rateLimit.PreAuth)
+	protected.Use(middleware.AuthMiddleware)
+	protected.Use(h.rateLimit.Middleware)
+
+	protected.Handle("/cars/{id}", h.cached("/cars/{id}", codec.Negotiate(h.car.GetCarById))).Methods("GET")
+	protected.Handle("/cars", h.cached("/cars", codec.NegotiateList(h.car.GetCarByBrand))).Methods("GET")
+	protected.Handle("/cars", h.idempotency.Middleware(codec.Negotiate(h.car.CreateCar))).Methods("POST")
+	protected.Handle("/cars/{id}", codec.Negotiate(h.car.UpdateCar)).Methods("PUT")
+	protected.Handle("/cars/{id}", codec.Negotiate(h.car.DeleteCar)).Methods("DELETE")
+
+	protected.Handle("/engine/{id}", h.cached("/engine/{id}", codec.Negotiate(h.engine.GetEngineById))).Methods("GET")
+	protected.Handle("/engine", h.idempotency.Middleware(codec.Negotiate(h.engine.CreateEngine))).Methods("POST")
+	protected.Handle("/engine/{id}", codec.Negotiate(h.engine.UpdateEngine)).Methods("PUT")
+	protected.Handle("/engine/{id}", codec.Negotiate(h.engine.DeleteEngine)).Methods("DELETE")
+
+	protected.HandleFunc("/validation/{target}/test", h.rule.TestPayload).Methods("POST")
+
+	registerSharedRoutes(protected, h)
+}
+
+// registerV2Routes registers the v2 API on r. Only cars and engines changed
+// shape; reference data and validation rules are shared with v1.
+func registerV2Routes(r *mux.Router, h handlers) {
+	// logins are limited by address, everything else once the user is known
+	r.Handle("/login", h.rateLimit.Middleware(http.HandlerFunc(loginHandler.LoginHandler))).Methods("POST")
+
+	protected := r.PathPrefix("/").Subrouter()
+	protected.Use(h.rateLimit.PreAuth)
+	protected.Use(middleware.AuthMiddleware)
+	protected.Use(h.rateLimit.Middleware)
+
+	protected.Handle("/cars/{id}", h.cached("/cars/{id}", codec.Negotiate(h.carV2.GetCarById))).Methods("GET")
+	protected.Handle("/cars", h.cached("/cars", codec.NegotiateList(h.carV2.GetCarByBrand))).Methods("GET")
+	protected.Handle("/cars", h.idempotency.Middleware(codec.Negotiate(h.carV2.CreateCar))).Methods("POST")
+	protected.Handle("/cars/{id}", codec.Negotiate(h.carV2.UpdateCar)).Methods("PUT")
+	protected.Handle("/cars/{id}", codec.Negotiate(h.carV2.DeleteCar)).Methods("DELETE")
+
+	protected.Handle("/engine/{id}", h.cached("/engine/{id}", codec.Negotiate(h.engineV2.GetEngineById))).Methods("GET")
+	protected.Handle("/engine", h.idempotency.Middleware(codec.Negotiate(h.engineV2.CreateEngine))).Methods("POST")
+	protected.Handle("/engine/{id}", codec.Negotiate(h.engineV2.UpdateEngine)).Methods("PUT")
+	protected.Handle("/engine/{id}", codec.Negotiate(h.engineV2.DeleteEngine)).Methods("DELETE")
+
+	protected.HandleFunc("/validation/{target}/test", h.validationV2.TestPayload).Methods("POST")
+
+	registerSharedRoutes(protected, h)
+}
+
+// registerSharedRoutes registers the authenticated routes every version
+// serves unchanged.
+func registerSharedRoutes(protected *mux.Router, h handlers) {
+	protected.HandleFunc("/reference/{kind}", h.reference.ListReferenceValues).Methods("GET")
+	protected.HandleFunc("/reference/{kind}/{code}", h.reference.GetReferenceValue).Methods("GET")
+
+	protected.HandleFunc("/validation/rules", h.rule.ListValidationRules).Methods("GET")
+
+	protected.HandleFunc("/events", h.stream.Events).Methods("GET")
+	protected.HandleFunc("/events/ws", h.stream.WebSocket).Methods("GET")
+
+	protected.HandleFunc("/sync", h.sync.Pull).Methods("GET")
+	protected.HandleFunc("/sync", h.sync.Push).Methods("POST")
+
+	admin := protected.NewRoute().Subrouter()
+	admin.Use(middleware.AdminOnly)
+
+	admin.HandleFunc("/reference/{kind}", h.reference.CreateReferenceValue).Methods("POST")
+	admin.HandleFunc("/reference/{kind}/{code}", h.reference.UpdateReferenceValue).Methods("PUT")
+	admin.HandleFunc("/reference/{kind}/{code}", h.reference.DeleteReferenceValue).Methods("DELETE")
+
+	admin.HandleFunc("/validation/rules", h.rule.CreateValidationRule).Methods("POST")
+	admin.HandleFunc("/validation/rules/{id}", h.rule.DeleteValidationRule).Methods("DELETE")
+
+	admin.HandleFunc("/webhooks", h.webhook.ListWebhooks).Methods("GET")
+	admin.HandleFunc("/webhooks", h.webhook.CreateWebhook).Methods("POST")
+	admin.HandleFunc("/webhooks/{id}", h.webhook.GetWebhook).Methods("GET")
+	admin.HandleFunc("/webhooks/{id}", h.webhook.UpdateWebhook).Methods("PUT")
+	admin.HandleFunc("/webhooks/{id}", h.webhook.DeleteWebhook).Methods("DELETE")
+
+	admin.HandleFunc("/webhook-deliveries", h.webhook.ListWebhookDeliveries).Methods("GET")
+	admin.HandleFunc("/webhook-deliveries/{id}", h.webhook.GetWebhookDelivery).Methods("GET")
+	admin.HandleFunc("/webhook-deliveries/{id}/redeliver", h.webhook.RedeliverWebhookDelivery).Methods("POST")
+}
@@ -0,0 +1,204 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/geekAshish/DriveDesk/middleware"
+	"github.com/geekAshish/DriveDesk/openapi"
+	"github.com/gorilla/mux"
+)
+
+// infrastructure routes that are deliberately not part of the API description
+var undocumentedRoutes = map[string]bool{
+	"/metrics":      true,
+	"/openapi.json": true,
+	"/docs":         true,
+	// described by its GraphQL schema instead
+	"/graphql": true,
+}
+
+// specs maps each version, as reported by middleware.APIVersion, to the
+// description of its routes. The unversioned routes are aliases of v1.
+var specs = map[string]func() *openapi.Document{
+	"v1":          apiSpecV1,
+	"v2":          apiSpecV2,
+	"unversioned": apiSpecV1,
+}
+
+// registeredOperations lists every route of the router as "METHOD path" by
+// version, with paths relative to the version prefix.
+func registeredOperations(t *testing.T) map[string]map[string]bool {
+	t.Helper()
+
+	operations := map[string]map[string]bool{}
+
+	err := newRouter(handlers{}).Walk(func(route *mux.Route, router *mux.Router, ancestors []*mux.Route) error {
+		path, err := route.GetPathTemplate()
+		if err != nil {
+			return nil
+		}
+
+		version := middleware.APIVersion(path)
+		if version != "unversioned" {
+			path = strings.TrimPrefix(path, "/"+version)
+		}
+
+		// subrouters and prefix matches without methods are not endpoints
+		methods, err := route.GetMethods()
+		if err != nil || undocumentedRoutes[path] {
+			return nil
+		}
+
+		if operations[version] == nil {
+			operations[version] = map[string]bool{}
+		}
+		for _, method := range methods {
+			operations[version][method+" "+path] = true
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("walking routes: %v", err)
+	}
+
+	return operations
+}
+
+func TestRoutesAreDocumented(t *testing.T) {
+	for version, registered := range registeredOperations(t) {
+		spec, ok := specs[version]
+		if !ok {
+			t.Errorf("no API description for version %s", version)
+			continue
+		}
+
+		for operation := range registered {
+			method, path, _ := strings.Cut(operation, " ")
+			if !spec().Has(method, path) {
+				t.Errorf("%s %s is registered in newRouter but missing from its API description", version, operation)
+			}
+		}
+	}
+}
+
+func TestSpecHasNoStaleOperations(t *testing.T) {
+	registered := registeredOperations(t)
+
+	for version, spec := range specs {
+		for _, operation := range spec().Operations() {
+			if !registered[version][operation] {
+				t.Errorf("%s %s is described in its API description but no route serves it", version, operation)
+			}
+		}
+	}
+}
+
+func TestDeprecatedVersionsAreMarked(t *testing.T) {
+	router := newRouter(handlers{})
+
+	for path, successor := range map[string]string{
+		"/login":    "/v1/login",
+		"/v1/login": "/v2/login",
+		"/v2/login": "",
+	} {
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, path, strings.NewReader("{")))
+
+		header := recorder.Header()
+		if successor == "" {
+			if header.Get("Deprecation") != "" || header.Get("Sunset") != "" {
+				t.Errorf("%s: current version answered with Deprecation %q and Sunset %q", path, header.Get("Deprecation"), header.Get("Sunset"))
+			}
+			continue
+		}
+
+		if header.Get("Deprecation") == "" || header.Get("Sunset") == "" {
+			t.Errorf("%s: missing Deprecation or Sunset header", path)
+		}
+		if want := "<" + successor + `>; rel="successor-version"`; header.Get("Link") != want {
+			t.Errorf("%s: Link = %q, want %q", path, header.Get("Link"), want)
+		}
+	}
+}
+
+var pathParam = regexp.MustCompile(`{([^}]+)}`)
+
+func TestSpecDescribesPathParameters(t *testing.T) {
+	for version, spec := range specs {
+		for path, item := range spec().Paths {
+			for method, op := range item {
+				declared := map[string]bool{}
+				for _, param := range op.Parameters {
+					if param.In == "path" {
+						declared[param.Name] = true
+					}
+				}
+
+				for _, match := range pathParam.FindAllStringSubmatch(path, -1) {
+					if !declared[match[1]] {
+						t.Errorf("%s %s %s does not describe path parameter %s", version, strings.ToUpper(method), path, match[1])
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestSpecRefsResolve(t *testing.T) {
+	for version, spec := range specs {
+		t.Run(version, func(t *testing.T) {
+			testRefsResolve(t, spec())
+		})
+	}
+}
+
+func testRefsResolve(t *testing.T, spec *openapi.Document) {
+	body, err := json.Marshal(spec)
+	if err != nil {
+		t.Fatalf("marshalling spec: %v", err)
+	}
+
+	var document map[string]any
+	if err := json.Unmarshal(body, &document); err != nil {
+		t.Fatalf("unmarshalling spec: %v", err)
+	}
+
+	var walk func(node any)
+	walk = func(node any) {
+		switch node := node.(type) {
+		case map[string]any:
+			if ref, ok := node["$ref"].(string); ok && !resolves(document, ref) {
+				t.Errorf("$ref %s does not resolve", ref)
+			}
+			for _, child := range node {
+				walk(child)
+			}
+		case []any:
+			for _, child := range node {
+				walk(child)
+			}
+		}
+	}
+	walk(document)
+}
+
+func resolves(document map[string]any, ref string) bool {
+	var node any = document
+
+	for _, key := range strings.Split(strings.TrimPrefix(ref, "#/"), "/") {
+		object, ok := node.(map[string]any)
+		if !ok {
+			return false
+		}
+		if node, ok = object[key]; !ok {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,294 @@
+// Package rules evaluates validation rules that are configured per
+// deployment, in the database or a file, rather than written in Go. They run
+// on top of the built-in validators in models, so a market can only tighten
+// what is accepted.
+package rules
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/geekAshish/DriveDesk/apperrors"
+	"github.com/geekAshish/DriveDesk/models"
+	"github.com/geekAshish/DriveDesk/validation"
+)
+
+// RuleSet is a compiled set of rules, ready to be evaluated. The nil
+// *RuleSet has no rules.
+type RuleSet struct {
+	byTarget map[string][]compiledRule
+}
+
+type compiledRule struct {
+	field   string
+	code    string
+	message string
+	// test is only called for fields present in the request, except for
+	// required rules, which are given absent fields as nil.
+	test     func(value any) bool
+	required bool
+}
+
+// Compile checks every rule and compiles the ones it accepts into a RuleSet.
+// The first invalid rule is reported as a validation error on its value.
+func Compile(rules []models.ValidationRule) (*RuleSet, error) {
+	set := &RuleSet{byTarget: map[string][]compiledRule{}}
+
+	for _, rule := range rules {
+		compiled, err := compile(rule)
+		if err != nil {
+			return nil, err
+		}
+
+		set.byTarget[rule.Target] = append(set.byTarget[rule.Target], compiled)
+	}
+
+	return set, nil
+}
+
+// WithDefaults checks rule and fills in the code and message derived from its
+// operator when they are left out.
+func WithDefaults(rule models.ValidationRule) (models.ValidationRule, error) {
+	compiled, err := compile(rule)
+	if err != nil {
+		return models.ValidationRule{}, err
+	}
+
+	rule.Code, rule.Message = compiled.code, compiled.message
+	return rule, nil
+}
+
+// Len returns the number of rules in the set.
+func (s *RuleSet) Len() int {
+	if s == nil {
+		return 0
+	}
+
+	n := 0
+	for _, rules := range s.byTarget {
+		n += len(rules)
+	}
+	return n
+}
+
+// Check returns a validation.Check evaluating the rules for target against
+// payload, a request as it is sent over the wire. Fields the built-in
+// validators already rejected are left alone, and only the first rule a
+// field breaks is reported.
+func (s *RuleSet) Check(target string, payload any) validation.Check {
+	return func(v *validation.Validator) {
+		if s == nil || len(s.byTarget[target]) == 0 {
+			return
+		}
+
+		document, err := toDocument(payload)
+		if err != nil {
+			v.Add("", "invalid_payload", "request could not be checked against the validation rules")
+			return
+		}
+
+		for _, rule := range s.byTarget[target] {
+			if v.Has(rule.field) {
+				continue
+			}
+
+			value := lookup(document, rule.field)
+			if value == nil && !rule.required {
+				continue
+			}
+
+			if !rule.test(value) {
+				v.Add(rule.field, rule.code, rule.message)
+			}
+		}
+	}
+}
+
+func compile(rule models.ValidationRule) (compiledRule, error) {
+	compiled := compiledRule{field: rule.Field, code: rule.Code, message: rule.Message}
+
+	var code, message string
+
+	switch rule.Operator {
+	case models.RuleOperatorRequired:
+		compiled.required = true
+		compiled.test = func(value any) bool { return value != nil }
+		code, message = "required", rule.Field+" is required"
+
+	case models.RuleOperatorMin, models.RuleOperatorMax:
+		var bound float64
+		if err := json.Unmarshal(rule.Value, &bound); err != nil {
+			return compiledRule{}, invalidValue("%s needs a number", rule.Operator)
+		}
+
+		atLeast := rule.Operator == models.RuleOperatorMin
+		compiled.test = func(value any) bool {
+			number, ok := toNumber(value)
+			if !ok {
+				// not a number at all, which the built-in validators report
+				return true
+			}
+			if atLeast {
+				return number >= bound
+			}
+			return number <= bound
+		}
+
+		code = "out_of_range"
+		if atLeast {
+			message = fmt.Sprintf("%s must be at least %s", rule.Field, formatNumber(bound))
+		} else {
+			message = fmt.Sprintf("%s must be at most %s", rule.Field, formatNumber(bound))
+		}
+
+	case models.RuleOperatorMinLength, models.RuleOperatorMaxLength:
+		var length int
+		if err := json.Unmarshal(rule.Value, &length); err != nil || length < 0 {
+			return compiledRule{}, invalidValue("%s needs a whole number of characters", rule.Operator)
+		}
+
+		atLeast := rule.Operator == models.RuleOperatorMinLength
+		compiled.test = func(value any) bool {
+			n := utf8.RuneCountInString(toString(value))
+			if atLeast {
+				return n >= length
+			}
+			return n <= length
+		}
+
+		code = "invalid_length"
+		if atLeast {
+			message = fmt.Sprintf("%s must be at least %d characters", rule.Field, length)
+		} else {
+			message = fmt.Sprintf("%s must be at most %d characters", rule.Field, length)
+		}
+
+	case models.RuleOperatorPattern:
+		var pattern string
+		if err := json.Unmarshal(rule.Value, &pattern); err != nil {
+			return compiledRule{}, invalidValue("pattern needs a regular expression")
+		}
+
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return compiledRule{}, invalidValue("pattern is not a valid regular expression: %v", err)
+		}
+
+		compiled.test = func(value any) bool { return re.MatchString(toString(value)) }
+		code, message = "invalid_format", rule.Field+" has an invalid format"
+
+	case models.RuleOperatorOneOf:
+		var allowed []string
+		if err := json.Unmarshal(rule.Value, &allowed); err != nil || len(allowed) == 0 {
+			return compiledRule{}, invalidValue("one_of needs a non-empty list of strings")
+		}
+
+		compiled.test = func(value any) bool {
+			s := toString(value)
+			for _, choice := range allowed {
+				if strings.EqualFold(s, choice) {
+					return true
+				}
+			}
+			return false
+		}
+		code, message = "invalid_choice", rule.Field+" must be one of "+strings.Join(allowed, ", ")
+
+	default:
+		return compiledRule{}, apperrors.InvalidField("operator", "invalid_choice", fmt.Sprintf("unknown operator %q", rule.Operator))
+	}
+
+	if compiled.code == "" {
+		compiled.code = code
+	}
+	if compiled.message == "" {
+		compiled.message = message
+	}
+
+	return compiled, nil
+}
+
+func invalidValue(format string, args ...any) error {
+	return apperrors.InvalidField("value", "invalid_value", fmt.Sprintf(format, args...))
+}
+
+// toDocument turns a request into its JSON form, so rules address fields by
+// the names clients use.
+func toDocument(payload any) (map[string]any, error) {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	var document map[string]any
+	if err := json.Unmarshal(body, &document); err != nil {
+		return nil, err
+	}
+
+	return document, nil
+}
+
+// lookup returns the value at the dotted path, or nil when the field is
+// absent. Empty strings and zero numbers count as absent: requests are Go
+// structs, which cannot tell a zero value from a missing one.
+func lookup(document map[string]any, path string) any {
+	var value any = document
+
+	for _, key := range strings.Split(path, ".") {
+		object, ok := value.(map[string]any)
+		if !ok {
+			return nil
+		}
+		value = object[key]
+	}
+
+	switch value := value.(type) {
+	case string:
+		if strings.TrimSpace(value) == "" {
+			return nil
+		}
+	case float64:
+		if value == 0 {
+			return nil
+		}
+	case bool:
+		if !value {
+			return nil
+		}
+	}
+
+	return value
+}
+
+// toNumber accepts numbers and numeric strings, since some fields such as
+// the car year are sent as strings.
+func toNumber(value any) (float64, bool) {
+	switch value := value.(type) {
+	case float64:
+		return value, true
+	case string:
+		number, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		return number, err == nil
+	default:
+		return 0, false
+	}
+}
+
+func toString(value any) string {
+	switch value := value.(type) {
+	case string:
+		return value
+	case float64:
+		return formatNumber(value)
+	default:
+		return fmt.Sprint(value)
+	}
+}
+
+func formatNumber(number float64) string {
+	return strconv.FormatFloat(number, 'f', -1, 64)
+}
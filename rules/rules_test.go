@@ -0,0 +1,130 @@
+package rules
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/geekAshish/DriveDesk/apperrors"
+	"github.com/geekAshish/DriveDesk/models"
+	"github.com/geekAshish/DriveDesk/validation"
+)
+
+func rule(target, field, operator, value string) models.ValidationRule {
+	return models.ValidationRule{Target: target, Field: field, Operator: operator, Value: json.RawMessage(value)}
+}
+
+func check(t *testing.T, set *RuleSet, target string, payload any, v *validation.Validator) map[string]string {
+	t.Helper()
+
+	set.Check(target, payload)(v)
+
+	got := map[string]string{}
+	for _, field := range v.Errors() {
+		got[field.Field] = field.Code
+	}
+	return got
+}
+
+func TestCheckReportsBrokenRules(t *testing.T) {
+	set, err := Compile([]models.ValidationRule{
+		rule(models.RuleTargetCar, "year", models.RuleOperatorMin, `2005`),
+		rule(models.RuleTargetCar, "price", models.RuleOperatorMax, `100000`),
+		rule(models.RuleTargetCar, "vin", models.RuleOperatorRequired, ``),
+		rule(models.RuleTargetCar, "brand", models.RuleOperatorOneOf, `["Honda", "Toyota"]`),
+		rule(models.RuleTargetCar, "name", models.RuleOperatorMinLength, `3`),
+		rule(models.RuleTargetCar, "engine.car_range", models.RuleOperatorMin, `300`),
+		rule(models.RuleTargetEngine, "car_range", models.RuleOperatorMin, `1000`),
+	})
+	if err != nil {
+		t.Fatalf("Compile: %v", err)
+	}
+
+	got := check(t, set, models.RuleTargetCar, models.CarRequest{
+		Name:   "Ka",
+		Year:   "1999",
+		Brand:  "ford",
+		Price:  250000,
+		Engine: models.Engine{CarRange: 200},
+	}, validation.New())
+
+	want := map[string]string{
+		"year":             "out_of_range",
+		"price":            "out_of_range",
+		"vin":              "required",
+		"brand":            "invalid_choice",
+		"name":             "invalid_length",
+		"engine.car_range": "out_of_range",
+	}
+
+	if len(got) != len(want) {
+		t.Fatalf("field errors = %v, want %v", got, want)
+	}
+	for field, code := range want {
+		if got[field] != code {
+			t.Errorf("%s: code = %q, want %q", field, got[field], code)
+		}
+	}
+}
+
+func TestCheckAcceptsPassingPayload(t *testing.T) {
+	set, err := Compile([]models.ValidationRule{
+		rule(models.RuleTargetCar, "year", models.RuleOperatorMin, `2005`),
+		rule(models.RuleTargetCar, "brand", models.RuleOperatorOneOf, `["Honda", "Toyota"]`),
+		rule(models.RuleTargetCar, "vin", models.RuleOperatorPattern, `"^JH"`),
+	})
+	if err != nil {
+		t.Fatalf("Compile: %v", err)
+	}
+
+	got := check(t, set, models.RuleTargetCar, models.CarRequest{Year: "2020", Brand: "honda", VIN: "JHMCM56557C404453"}, validation.New())
+	if len(got) != 0 {
+		t.Errorf("field errors = %v, want none", got)
+	}
+}
+
+func TestCheckSkipsFieldsAlreadyRejected(t *testing.T) {
+	set, err := Compile([]models.ValidationRule{
+		{Target: models.RuleTargetCar, Field: "year", Operator: models.RuleOperatorMin, Value: json.RawMessage(`2005`), Code: "too_old", Message: "imports must be 2005 or newer"},
+	})
+	if err != nil {
+		t.Fatalf("Compile: %v", err)
+	}
+
+	v := validation.New()
+	v.Add("year", "out_of_range", "year must be between 1886 and the current year")
+
+	got := check(t, set, models.RuleTargetCar, models.CarRequest{Year: "1850"}, v)
+	if got["year"] != "out_of_range" {
+		t.Errorf("year: code = %q, want the built-in out_of_range", got["year"])
+	}
+
+	got = check(t, set, models.RuleTargetCar, models.CarRequest{Year: "2001"}, validation.New())
+	if got["year"] != "too_old" {
+		t.Errorf("year: code = %q, want the configured too_old", got["year"])
+	}
+}
+
+func TestCompileRejectsBadValues(t *testing.T) {
+	tests := []models.ValidationRule{
+		rule(models.RuleTargetCar, "year", models.RuleOperatorMin, `"new"`),
+		rule(models.RuleTargetCar, "name", models.RuleOperatorMaxLength, `-1`),
+		rule(models.RuleTargetCar, "vin", models.RuleOperatorPattern, `"("`),
+		rule(models.RuleTargetCar, "brand", models.RuleOperatorOneOf, `[]`),
+		rule(models.RuleTargetCar, "brand", "like", `"Honda"`),
+	}
+
+	for _, tt := range tests {
+		if _, err := Compile([]models.ValidationRule{tt}); !errors.Is(err, apperrors.ErrValidation) {
+			t.Errorf("Compile(%s %s): got %v, want ErrValidation", tt.Operator, tt.Value, err)
+		}
+	}
+}
+
+func TestNilRuleSetChecksNothing(t *testing.T) {
+	var set *RuleSet
+
+	if got := check(t, set, models.RuleTargetCar, models.CarRequest{}, validation.New()); len(got) != 0 {
+		t.Errorf("field errors = %v, want none", got)
+	}
+}
@@ -0,0 +1,108 @@
+// Package cache keeps the cars and engines read by id in process. CarService
+// and EngineService decorate the services of the same interfaces: reads by
+// id are served from a Cache, and writes made through them drop what they
+// changed. Ids that were not found are remembered too, for a shorter time,
+// and concurrent misses of the same id share one read.
+//
+// Writes made by other instances are only seen once their entries expire,
+// unless the instance calls Invalidate for each change it learns about, as
+// main does with the Postgres change listener.
+package cache
+
+import (
+	"context"
+	"time"
+
+	"github.com/geekAshish/DriveDesk/models"
+	"github.com/geekAshish/DriveDesk/service"
+	"github.com/geekAshish/DriveDesk/store"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	cacheHits = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "cache_hits_total",
+			Help: "Total number of reads served from the cache, not-found answers included",
+		},
+		[]string{"cache"},
+	)
+
+	cacheMisses = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "cache_misses_total",
+			Help: "Total number of reads the cache passed on to the service",
+		},
+		[]string{"cache"},
+	)
+)
+
+func init() {
+	prometheus.MustRegister(cacheHits, cacheMisses)
+}
+
+// Config sizes a Cache. Size is how many cars, and how many engines, it
+// keeps at most; the least recently read go first. Values are kept for TTL
+// and ids that were not found for NegativeTTL. A zero TTL turns that part
+// of the cache off.
+type Config struct {
+	Size        int
+	TTL         time.Duration
+	NegativeTTL time.Duration
+}
+
+// Cache holds the cars and engines of a CarService and an EngineService.
+// They share it because every car carries its engine.
+type Cache struct {
+	cars    *lru[models.Car]
+	engines *lru[models.Engine]
+}
+
+func New(config Config) *Cache {
+	return &Cache{
+		cars:    newLRU[models.Car](models.EntityCar, config),
+		engines: newLRU[models.Engine](models.EntityEngine, config),
+	}
+}
+
+// Cars returns next with its reads by id cached.
+func (c *Cache) Cars(next service.CarServiceInterface) *CarService {
+	return &CarService{CarServiceInterface: next, cache: c}
+}
+
+// Engines returns next with its reads by id cached.
+func (c *Cache) Engines(next service.EngineServiceInterface) *EngineService {
+	return &EngineService{EngineServiceInterface: next, cache: c}
+}
+
+// Invalidate drops the car or engine id, entity being models.EntityCar or
+// models.EntityEngine. Dropping an engine also drops the cars fitted with
+// it, which carry a copy and go with it when it is deleted.
+func (c *Cache) Invalidate(entity, id string) {
+	switch entity {
+	case models.EntityCar:
+		c.cars.invalidate(id)
+	case models.EntityEngine:
+		c.engines.invalidate(id)
+		c.cars.invalidateWhere(func(car models.Car) bool {
+			return car.Engine.EngineID.String() == id
+		})
+	}
+}
+
+// invalidate is Invalidate for writes made with ctx. Inside a unit of work
+// the entry is dropped again once it commits, as a read made meanwhile
+// outside it loaded the row as it was before and must not stay cached.
+func (c *Cache) invalidate(ctx context.Context, entity, id string) {
+	c.Invalidate(entity, id)
+
+	if store.InTx(ctx) {
+		store.AfterCommit(ctx, func() { c.Invalidate(entity, id) })
+	}
+}
+
+// Purge drops everything, for when changes may have been missed.
+func (c *Cache) Purge() {
+	c.cars.purge()
+	c.engines.purge()
+}
@@ -0,0 +1,357 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/geekAshish/DriveDesk/apperrors"
+	"github.com/geekAshish/DriveDesk/models"
+	"github.com/geekAshish/DriveDesk/service"
+	"github.com/geekAshish/DriveDesk/store"
+	"github.com/google/uuid"
+)
+
+// fakeCars serves cars from a map and counts the reads that reach it.
+// Reads wait for release when it is set.
+type fakeCars struct {
+	service.CarServiceInterface
+
+	mu      sync.Mutex
+	cars    map[string]models.Car
+	reads   atomic.Int32
+	release chan struct{}
+}
+
+func (f *fakeCars) GetCarById(ctx context.Context, id string) (*models.Car, error) {
+	f.reads.Add(1)
+	if f.release != nil {
+		<-f.release
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	car, ok := f.cars[id]
+	if !ok {
+		return nil, apperrors.NotFound("car not found")
+	}
+	return &car, nil
+}
+
+func (f *fakeCars) UpdateCar(ctx context.Context, id string, req *models.CarRequest) (*models.Car, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	car := f.cars[id]
+	car.Name = req.Name
+	f.cars[id] = car
+	return &car, nil
+}
+
+type fakeEngines struct {
+	service.EngineServiceInterface
+
+	engines map[string]models.Engine
+	batches [][]string
+}
+
+func (f *fakeEngines) GetEnginesByIds(ctx context.Context, ids []string) ([]models.Engine, error) {
+	f.batches = append(f.batches, ids)
+
+	var engines []models.Engine
+	for _, id := range ids {
+		if engine, ok := f.engines[id]; ok {
+			engines = append(engines, engine)
+		}
+	}
+	return engines, nil
+}
+
+func (f *fakeEngines) UpdateEngine(ctx context.Context, id string, req *models.EngineRequest) (*models.Engine, error) {
+	engine := f.engines[id]
+	engine.CarRange = req.CarRange
+	f.engines[id] = engine
+	return &engine, nil
+}
+
+var testConfig = Config{Size: 2, TTL: time.Minute, NegativeTTL: time.Second}
+
+func newCar(name string, engine models.Engine) models.Car {
+	return models.Car{ID: uuid.New(), Name: name, Engine: engine}
+}
+
+func TestGetCarByIdCached(t *testing.T) {
+	ctx := context.Background()
+	car := newCar("Civic", models.Engine{EngineID: uuid.New()})
+	next := &fakeCars{cars: map[string]models.Car{car.ID.String(): car}}
+	cars := New(testConfig).Cars(next)
+
+	for range 3 {
+		got, err := cars.GetCarById(ctx, car.ID.String())
+		if err != nil || got.Name != "Civic" {
+			t.Fatalf("GetCarById = %+v, %v", got, err)
+		}
+		got.Name = "changed by the caller"
+	}
+
+	if reads := next.reads.Load(); reads != 1 {
+		t.Errorf("the service was read %d times, want once", reads)
+	}
+
+	if _, err := cars.UpdateCar(ctx, car.ID.String(), &models.CarRequest{Name: "Accord"}); err != nil {
+		t.Fatalf("UpdateCar: %v", err)
+	}
+
+	if got, _ := cars.GetCarById(ctx, car.ID.String()); got.Name != "Accord" || next.reads.Load() != 2 {
+		t.Errorf("GetCarById after update = %+v after %d reads, want the updated car read again", got, next.reads.Load())
+	}
+}
+
+func TestNotFoundCachedBriefly(t *testing.T) {
+	ctx := context.Background()
+	next := &fakeCars{cars: map[string]models.Car{}}
+	cache := New(testConfig)
+	now := time.Now()
+	cache.cars.now = func() time.Time { return now }
+	cars := cache.Cars(next)
+
+	id := uuid.NewString()
+	for range 2 {
+		if _, err := cars.GetCarById(ctx, id); !errors.Is(err, apperrors.ErrNotFound) {
+			t.Fatalf("GetCarById = %v, want not found", err)
+		}
+	}
+	if reads := next.reads.Load(); reads != 1 {
+		t.Errorf("the service was read %d times, want once", reads)
+	}
+
+	now = now.Add(testConfig.NegativeTTL)
+	if _, err := cars.GetCarById(ctx, id); !errors.Is(err, apperrors.ErrNotFound) || next.reads.Load() != 2 {
+		t.Errorf("GetCarById after the negative TTL = %v after %d reads, want it read again", err, next.reads.Load())
+	}
+}
+
+func TestLeastRecentlyUsedEvicted(t *testing.T) {
+	ctx := context.Background()
+	engine := models.Engine{EngineID: uuid.New()}
+	a, b, c := newCar("a", engine), newCar("b", engine), newCar("c", engine)
+	next := &fakeCars{cars: map[string]models.Car{a.ID.String(): a, b.ID.String(): b, c.ID.String(): c}}
+	cars := New(testConfig).Cars(next)
+
+	for _, car := range []models.Car{a, b, a, c} {
+		if _, err := cars.GetCarById(ctx, car.ID.String()); err != nil {
+			t.Fatalf("GetCarById: %v", err)
+		}
+	}
+
+	// b was read least recently when c came in
+	reads := next.reads.Load()
+	cars.GetCarById(ctx, a.ID.String())
+	cars.GetCarById(ctx, c.ID.String())
+	if next.reads.Load() != reads {
+		t.Error("a or c was evicted")
+	}
+
+	cars.GetCarById(ctx, b.ID.String())
+	if next.reads.Load() != reads+1 {
+		t.Error("b was still cached")
+	}
+}
+
+func TestConcurrentMissesShareOneRead(t *testing.T) {
+	ctx := context.Background()
+	car := newCar("Civic", models.Engine{EngineID: uuid.New()})
+	next := &fakeCars{cars: map[string]models.Car{car.ID.String(): car}, release: make(chan struct{})}
+	cars := New(testConfig).Cars(next)
+
+	var wg sync.WaitGroup
+	for range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if got, err := cars.GetCarById(ctx, car.ID.String()); err != nil || got.ID != car.ID {
+				t.Errorf("GetCarById = %+v, %v", got, err)
+			}
+		}()
+	}
+
+	// let every goroutine reach the cache before the read returns
+	for next.reads.Load() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	time.Sleep(10 * time.Millisecond)
+	close(next.release)
+	wg.Wait()
+
+	if reads := next.reads.Load(); reads != 1 {
+		t.Errorf("the service was read %d times, want once", reads)
+	}
+}
+
+func TestCanceledCallerFailsAlone(t *testing.T) {
+	car := newCar("Civic", models.Engine{EngineID: uuid.New()})
+	next := &fakeCars{cars: map[string]models.Car{car.ID.String(): car}, release: make(chan struct{})}
+	cars := New(testConfig).Cars(next)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	canceled := make(chan error)
+	go func() {
+		_, err := cars.GetCarById(ctx, car.ID.String())
+		canceled <- err
+	}()
+
+	for next.reads.Load() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+
+	waited := make(chan error)
+	go func() {
+		_, err := cars.GetCarById(context.Background(), car.ID.String())
+		waited <- err
+	}()
+
+	// the caller that started the read gives up before it returns
+	cancel()
+	if err := <-canceled; !errors.Is(err, context.Canceled) {
+		t.Errorf("canceled GetCarById = %v, want context.Canceled", err)
+	}
+
+	close(next.release)
+	if err := <-waited; err != nil {
+		t.Errorf("GetCarById sharing the read = %v, want the car", err)
+	}
+	if reads := next.reads.Load(); reads != 1 {
+		t.Errorf("the service was read %d times, want once", reads)
+	}
+}
+
+func TestReadInUnitOfWorkBypassesCache(t *testing.T) {
+	car := newCar("Civic", models.Engine{EngineID: uuid.New()})
+	next := &fakeCars{cars: map[string]models.Car{car.ID.String(): car}}
+	cars := New(testConfig).Cars(next)
+
+	ctx, _ := store.BeginUnit(context.Background())
+	for range 2 {
+		if _, err := cars.GetCarById(ctx, car.ID.String()); err != nil {
+			t.Fatalf("GetCarById in a unit of work: %v", err)
+		}
+	}
+
+	// nothing read in the unit of work was kept
+	cars.GetCarById(context.Background(), car.ID.String())
+	if reads := next.reads.Load(); reads != 3 {
+		t.Errorf("the service was read %d times, want every read to reach it", reads)
+	}
+}
+
+func TestWriteInUnitOfWorkInvalidatedOnCommit(t *testing.T) {
+	car := newCar("Civic", models.Engine{EngineID: uuid.New()})
+	next := &fakeCars{cars: map[string]models.Car{car.ID.String(): car}}
+	cars := New(testConfig).Cars(next)
+
+	tx, unit := store.BeginUnit(context.Background())
+	if _, err := cars.UpdateCar(tx, car.ID.String(), &models.CarRequest{Name: "Accord"}); err != nil {
+		t.Fatalf("UpdateCar: %v", err)
+	}
+
+	// a read outside the unit of work before it commits caches the old row,
+	// which the fake stands in for by undoing the write
+	next.cars[car.ID.String()] = car
+	cars.GetCarById(context.Background(), car.ID.String())
+
+	next.cars[car.ID.String()] = newCarNamed(car, "Accord")
+	unit.Committed()
+
+	if got, _ := cars.GetCarById(context.Background(), car.ID.String()); got.Name != "Accord" {
+		t.Errorf("GetCarById after the commit = %q, want the committed car", got.Name)
+	}
+}
+
+func newCarNamed(car models.Car, name string) models.Car {
+	car.Name = name
+	return car
+}
+
+func TestReadRacingInvalidationNotStored(t *testing.T) {
+	ctx := context.Background()
+	car := newCar("Civic", models.Engine{EngineID: uuid.New()})
+	next := &fakeCars{cars: map[string]models.Car{car.ID.String(): car}, release: make(chan struct{})}
+	cache := New(testConfig)
+	cars := cache.Cars(next)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		cars.GetCarById(ctx, car.ID.String())
+	}()
+
+	for next.reads.Load() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	cache.Invalidate(models.EntityCar, car.ID.String())
+	close(next.release)
+	<-done
+
+	cars.GetCarById(ctx, car.ID.String())
+	if reads := next.reads.Load(); reads != 2 {
+		t.Errorf("the service was read %d times, want the read before the invalidation forgotten", reads)
+	}
+}
+
+func TestEngineChangeDropsItsCars(t *testing.T) {
+	ctx := context.Background()
+	engine := models.Engine{EngineID: uuid.New(), CarRange: 600}
+	car := newCar("Civic", engine)
+
+	cache := New(testConfig)
+	carsNext := &fakeCars{cars: map[string]models.Car{car.ID.String(): car}}
+	enginesNext := &fakeEngines{engines: map[string]models.Engine{engine.EngineID.String(): engine}}
+	cars, engines := cache.Cars(carsNext), cache.Engines(enginesNext)
+
+	cars.GetCarById(ctx, car.ID.String())
+	if _, err := engines.UpdateEngine(ctx, engine.EngineID.String(), &models.EngineRequest{CarRange: 700}); err != nil {
+		t.Fatalf("UpdateEngine: %v", err)
+	}
+
+	cars.GetCarById(ctx, car.ID.String())
+	if reads := carsNext.reads.Load(); reads != 2 {
+		t.Errorf("the car service was read %d times, want the car read again after its engine changed", reads)
+	}
+
+	cache.Purge()
+	cars.GetCarById(ctx, car.ID.String())
+	if reads := carsNext.reads.Load(); reads != 3 {
+		t.Errorf("the car service was read %d times, want the car read again after a purge", reads)
+	}
+}
+
+func TestGetEnginesByIdsReadsOnlyMisses(t *testing.T) {
+	ctx := context.Background()
+	a, b := models.Engine{EngineID: uuid.New()}, models.Engine{EngineID: uuid.New()}
+	unknown := uuid.NewString()
+	next := &fakeEngines{engines: map[string]models.Engine{a.EngineID.String(): a, b.EngineID.String(): b}}
+	engines := New(Config{Size: 10, TTL: time.Minute, NegativeTTL: time.Minute}).Engines(next)
+
+	if got, err := engines.GetEnginesByIds(ctx, []string{a.EngineID.String()}); err != nil || len(got) != 1 {
+		t.Fatalf("GetEnginesByIds = %+v, %v", got, err)
+	}
+
+	got, err := engines.GetEnginesByIds(ctx, []string{b.EngineID.String(), unknown, a.EngineID.String(), b.EngineID.String()})
+	if err != nil || len(got) != 2 || got[0].EngineID != b.EngineID || got[1].EngineID != a.EngineID {
+		t.Fatalf("GetEnginesByIds = %+v, %v, want b then a", got, err)
+	}
+
+	if len(next.batches) != 2 || len(next.batches[1]) != 2 {
+		t.Fatalf("batches = %v, want the second one without a", next.batches)
+	}
+
+	// everything is known now, the unknown id as not found
+	engines.GetEnginesByIds(ctx, []string{a.EngineID.String(), b.EngineID.String(), unknown})
+	if len(next.batches) != 2 {
+		t.Errorf("batches = %v, want no more reads", next.batches)
+	}
+}
@@ -0,0 +1,64 @@
+package cache
+
+import (
+	"context"
+
+	"github.com/geekAshish/DriveDesk/models"
+	"github.com/geekAshish/DriveDesk/service"
+	"go.opentelemetry.io/otel"
+)
+
+// CarService caches GetCarById of the CarServiceInterface it embeds. The
+// other reads go straight through.
+type CarService struct {
+	service.CarServiceInterface
+	cache *Cache
+}
+
+func (s *CarService) GetCarById(ctx context.Context, id string) (*models.Car, error) {
+	tracer := otel.Tracer("CarCache")
+	ctx, span := tracer.Start(ctx, "GetCarById-Cache")
+	defer span.End()
+
+	car, err := s.cache.cars.get(ctx, id, func(ctx context.Context) (models.Car, error) {
+		car, err := s.CarServiceInterface.GetCarById(ctx, id)
+		if err != nil {
+			return models.Car{}, err
+		}
+		return *car, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &car, nil
+}
+
+func (s *CarService) CreateCar(ctx context.Context, car *models.CarRequest) (*models.Car, error) {
+	created, err := s.CarServiceInterface.CreateCar(ctx, car)
+	if err != nil {
+		return nil, err
+	}
+
+	s.cache.invalidate(ctx, models.EntityCar, created.ID.String())
+
+	return created, nil
+}
+
+func (s *CarService) UpdateCar(ctx context.Context, id string, car *models.CarRequest) (*models.Car, error) {
+	updated, err := s.CarServiceInterface.UpdateCar(ctx, id, car)
+
+	// dropping an entry is never wrong, so it is dropped even when the
+	// update failed, in case the failure came after the write
+	s.cache.invalidate(ctx, models.EntityCar, id)
+
+	return updated, err
+}
+
+func (s *CarService) DeleteCar(ctx context.Context, id string) (*models.Car, error) {
+	deleted, err := s.CarServiceInterface.DeleteCar(ctx, id)
+
+	s.cache.invalidate(ctx, models.EntityCar, id)
+
+	return deleted, err
+}
@@ -0,0 +1,129 @@
+package cache
+
+import (
+	"context"
+
+	"github.com/geekAshish/DriveDesk/apperrors"
+	"github.com/geekAshish/DriveDesk/models"
+	"github.com/geekAshish/DriveDesk/service"
+	"github.com/geekAshish/DriveDesk/store"
+	"go.opentelemetry.io/otel"
+)
+
+// EngineService caches GetEngineById and GetEnginesByIds of the
+// EngineServiceInterface it embeds.
+type EngineService struct {
+	service.EngineServiceInterface
+	cache *Cache
+}
+
+func (s *EngineService) GetEngineById(ctx context.Context, id string) (*models.Engine, error) {
+	tracer := otel.Tracer("EngineCache")
+	ctx, span := tracer.Start(ctx, "GetEngineById-Cache")
+	defer span.End()
+
+	engine, err := s.cache.engines.get(ctx, id, func(ctx context.Context) (models.Engine, error) {
+		engine, err := s.EngineServiceInterface.GetEngineById(ctx, id)
+		if err != nil {
+			return models.Engine{}, err
+		}
+		return *engine, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &engine, nil
+}
+
+// GetEnginesByIds serves the engines it has and reads the others in one
+// batch, in the order of ids. Inside a unit of work it reads them all.
+func (s *EngineService) GetEnginesByIds(ctx context.Context, ids []string) ([]models.Engine, error) {
+	tracer := otel.Tracer("EngineCache")
+	ctx, span := tracer.Start(ctx, "GetEnginesByIds-Cache")
+	defer span.End()
+
+	// see lru.get
+	if store.InTx(ctx) {
+		return s.EngineServiceInterface.GetEnginesByIds(ctx, ids)
+	}
+
+	found := map[string]models.Engine{}
+	seen := map[string]bool{}
+	var missing []string
+
+	for _, id := range ids {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+
+		engine, ok, known := s.cache.engines.peek(id)
+		switch {
+		case ok:
+			found[id] = engine
+		case !known:
+			missing = append(missing, id)
+		}
+	}
+
+	if len(missing) > 0 {
+		epoch := s.cache.engines.currentEpoch()
+
+		loaded, err := s.EngineServiceInterface.GetEnginesByIds(ctx, missing)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, engine := range loaded {
+			found[engine.EngineID.String()] = engine
+		}
+
+		for _, id := range missing {
+			if engine, ok := found[id]; ok {
+				s.cache.engines.put(epoch, id, engine, nil)
+			} else {
+				s.cache.engines.put(epoch, id, models.Engine{}, apperrors.NotFound("engine not found"))
+			}
+		}
+	}
+
+	engines := make([]models.Engine, 0, len(found))
+	for _, id := range ids {
+		if engine, ok := found[id]; ok {
+			engines = append(engines, engine)
+			delete(found, id)
+		}
+	}
+
+	return engines, nil
+}
+
+func (s *EngineService) CreateEngine(ctx context.Context, engineReq *models.EngineRequest) (*models.Engine, error) {
+	created, err := s.EngineServiceInterface.CreateEngine(ctx, engineReq)
+	if err != nil {
+		return nil, err
+	}
+
+	s.cache.invalidate(ctx, models.EntityEngine, created.EngineID.String())
+
+	return created, nil
+}
+
+func (s *EngineService) UpdateEngine(ctx context.Context, id string, engineReq *models.EngineRequest) (*models.Engine, error) {
+	updated, err := s.EngineServiceInterface.UpdateEngine(ctx, id, engineReq)
+
+	// dropping an entry is never wrong, so it is dropped even when the
+	// update failed, in case the failure came after the write
+	s.cache.invalidate(ctx, models.EntityEngine, id)
+
+	return updated, err
+}
+
+func (s *EngineService) DeleteEngine(ctx context.Context, id string) (*models.Engine, error) {
+	deleted, err := s.EngineServiceInterface.DeleteEngine(ctx, id)
+
+	s.cache.invalidate(ctx, models.EntityEngine, id)
+
+	return deleted, err
+}
@@ -0,0 +1,239 @@
+package cache
+
+import (
+	"container/list"
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/geekAshish/DriveDesk/apperrors"
+	"github.com/geekAshish/DriveDesk/store"
+)
+
+// lru keeps up to size values by id, each for ttl, and the ids that were not
+// found for negativeTTL. Concurrent misses of an id share one load.
+type lru[V any] struct {
+	name        string
+	size        int
+	ttl         time.Duration
+	negativeTTL time.Duration
+	now         func() time.Time
+
+	mu       sync.Mutex
+	entries  map[string]*list.Element
+	order    *list.List // most recently used first
+	inflight map[string]*call[V]
+	// epoch changes with every invalidation, so a load that started before
+	// one is not stored after it
+	epoch uint64
+}
+
+type entry[V any] struct {
+	id       string
+	value    V
+	err      error // the not-found error of a negative entry
+	expireAt time.Time
+}
+
+type call[V any] struct {
+	done  chan struct{}
+	value V
+	err   error
+}
+
+func newLRU[V any](name string, config Config) *lru[V] {
+	return &lru[V]{
+		name:        name,
+		size:        config.Size,
+		ttl:         config.TTL,
+		negativeTTL: config.NegativeTTL,
+		now:         time.Now,
+		entries:     map[string]*list.Element{},
+		order:       list.New(),
+		inflight:    map[string]*call[V]{},
+	}
+}
+
+// get returns the value of id, calling load on a miss. Only values and
+// not-found errors are kept; other errors are returned to every caller that
+// shared the load and then forgotten.
+//
+// The shared load runs on ctx without its cancellation, so one caller giving
+// up fails no one else, and each caller waits only as long as its own ctx
+// allows. Inside a unit of work load runs on ctx alone and nothing is kept:
+// it reads through the caller's transaction, whose writes are not committed
+// and which other callers must not wait on.
+func (c *lru[V]) get(ctx context.Context, id string, load func(ctx context.Context) (V, error)) (V, error) {
+	if store.InTx(ctx) {
+		return load(ctx)
+	}
+
+	c.mu.Lock()
+
+	if value, err, ok := c.lookup(id); ok {
+		c.mu.Unlock()
+		cacheHits.WithLabelValues(c.name).Inc()
+		return value, err
+	}
+	cacheMisses.WithLabelValues(c.name).Inc()
+
+	pending, ok := c.inflight[id]
+	if !ok {
+		pending = &call[V]{done: make(chan struct{})}
+		c.inflight[id] = pending
+		go c.load(context.WithoutCancel(ctx), id, pending, c.epoch, load)
+	}
+	c.mu.Unlock()
+
+	select {
+	case <-pending.done:
+		return pending.value, pending.err
+	case <-ctx.Done():
+		var zero V
+		return zero, ctx.Err()
+	}
+}
+
+// load runs the load shared by the callers of pending and stores its
+// outcome, unless an invalidation came after epoch.
+func (c *lru[V]) load(ctx context.Context, id string, pending *call[V], epoch uint64, load func(ctx context.Context) (V, error)) {
+	pending.value, pending.err = load(ctx)
+
+	c.mu.Lock()
+	if c.inflight[id] == pending {
+		delete(c.inflight, id)
+	}
+	if epoch == c.epoch {
+		c.store(id, pending.value, pending.err)
+	}
+	c.mu.Unlock()
+
+	close(pending.done)
+}
+
+// lookup returns the live entry of id, if any, and marks it used. c.mu must
+// be held.
+func (c *lru[V]) lookup(id string) (V, error, bool) {
+	var zero V
+
+	element, ok := c.entries[id]
+	if !ok {
+		return zero, nil, false
+	}
+
+	cached := element.Value.(*entry[V])
+	if !c.now().Before(cached.expireAt) {
+		c.remove(element)
+		return zero, nil, false
+	}
+
+	c.order.MoveToFront(element)
+	return cached.value, cached.err, true
+}
+
+// store keeps the outcome of a load, evicting the least recently used entry
+// when the cache is full. c.mu must be held.
+func (c *lru[V]) store(id string, value V, err error) {
+	ttl := c.ttl
+	if err != nil {
+		if !errors.Is(err, apperrors.ErrNotFound) {
+			return
+		}
+		ttl = c.negativeTTL
+	}
+
+	if ttl <= 0 || c.size <= 0 {
+		return
+	}
+
+	if element, ok := c.entries[id]; ok {
+		c.remove(element)
+	}
+
+	c.entries[id] = c.order.PushFront(&entry[V]{id: id, value: value, err: err, expireAt: c.now().Add(ttl)})
+
+	for c.order.Len() > c.size {
+		c.remove(c.order.Back())
+	}
+}
+
+// peek returns the live value of id without loading it. Negative entries
+// count as found with ok false, so callers can skip loading them.
+func (c *lru[V]) peek(id string) (value V, ok, known bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	value, err, known := c.lookup(id)
+	if known {
+		cacheHits.WithLabelValues(c.name).Inc()
+	} else {
+		cacheMisses.WithLabelValues(c.name).Inc()
+	}
+
+	return value, known && err == nil, known
+}
+
+// put stores values loaded without get, unless an invalidation came after
+// epoch.
+func (c *lru[V]) put(epoch uint64, id string, value V, err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if epoch == c.epoch {
+		c.store(id, value, err)
+	}
+}
+
+// currentEpoch is passed to put by loads made without get.
+func (c *lru[V]) currentEpoch() uint64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.epoch
+}
+
+// invalidate drops id. A load of it in flight is not shared with later
+// callers, and no load in flight is stored.
+func (c *lru[V]) invalidate(id string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.epoch++
+	delete(c.inflight, id)
+
+	if element, ok := c.entries[id]; ok {
+		c.remove(element)
+	}
+}
+
+// invalidateWhere drops every value matching drop.
+func (c *lru[V]) invalidateWhere(drop func(V) bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.epoch++
+
+	for element := c.order.Front(); element != nil; {
+		next := element.Next()
+		if cached := element.Value.(*entry[V]); cached.err == nil && drop(cached.value) {
+			c.remove(element)
+		}
+		element = next
+	}
+}
+
+func (c *lru[V]) purge() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.epoch++
+	clear(c.inflight)
+	clear(c.entries)
+	c.order.Init()
+}
+
+func (c *lru[V]) remove(element *list.Element) {
+	c.order.Remove(element)
+	delete(c.entries, element.Value.(*entry[V]).id)
+}
@@ -2,18 +2,24 @@ package car
 
 import (
 	"context"
+	"strings"
 
 	"github.com/geekAshish/DriveDesk/models"
+	"github.com/geekAshish/DriveDesk/service"
 	"github.com/geekAshish/DriveDesk/store"
 	"go.opentelemetry.io/otel"
 )
 
 type CarService struct {
-	store store.CarStoreInterface
+	store  store.CarStoreInterface
+	tx     store.TxManagerInterface
+	enums  service.EnumProvider
+	rules  service.RuleProvider
+	events service.EventPublisher
 }
 
-func NewCarService(store store.CarStoreInterface) *CarService {
-	return &CarService{store: store}
+func NewCarService(store store.CarStoreInterface, tx store.TxManagerInterface, enums service.EnumProvider, rules service.RuleProvider, events service.EventPublisher) *CarService {
+	return &CarService{store: store, tx: tx, enums: enums, rules: rules, events: events}
 }
 
 func (s *CarService) GetCarById(ctx context.Context, id string) (*models.Car, error) {
@@ -47,11 +53,20 @@ func (s *CarService) CreateCar(ctx context.Context, car *models.CarRequest) (*mo
 	ctx, span := tracer.Start(ctx, "CreateCar-Service")
 	defer span.End()
 
-	if err := models.ValidateRequest(*car); err != nil {
+	if err := s.ValidateCar(ctx, car); err != nil {
 		return nil, err
 	}
 
-	createdCar, err := s.store.CreateCar(ctx, car)
+	var createdCar models.Car
+	err := s.tx.WithTx(ctx, func(ctx context.Context) error {
+		var err error
+		createdCar, err = s.store.CreateCar(ctx, car)
+		if err != nil {
+			return err
+		}
+
+		return s.events.Publish(ctx, models.NewEvent(models.EventCarCreated, createdCar.ID.String(), createdCar))
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -64,11 +79,20 @@ func (s *CarService) UpdateCar(ctx context.Context, id string, car *models.CarRe
 	ctx, span := tracer.Start(ctx, "UpdateCar-Service")
 	defer span.End()
 
-	if err := models.ValidateRequest(*car); err != nil {
+	if err := s.ValidateCar(ctx, car); err != nil {
 		return nil, err
 	}
 
-	updateCar, err := s.store.UpdateCar(ctx, id, car)
+	var updateCar models.Car
+	err := s.tx.WithTx(ctx, func(ctx context.Context) error {
+		var err error
+		updateCar, err = s.store.UpdateCar(ctx, id, car)
+		if err != nil {
+			return err
+		}
+
+		return s.events.Publish(ctx, models.NewEvent(models.EventCarUpdated, updateCar.ID.String(), updateCar))
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -81,10 +105,46 @@ func (s *CarService) DeleteCar(ctx context.Context, id string) (*models.Car, err
 	ctx, span := tracer.Start(ctx, "DeleteCar-Service")
 	defer span.End()
 
-	deleteCar, err := s.store.DeleteCar(ctx, id)
+	var deleteCar models.Car
+	err := s.tx.WithTx(ctx, func(ctx context.Context) error {
+		var err error
+		deleteCar, err = s.store.DeleteCar(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		return s.events.Publish(ctx, models.NewEvent(models.EventCarDeleted, deleteCar.ID.String(), deleteCar))
+	})
 	if err != nil {
 		return nil, err
 	}
 
 	return &deleteCar, nil
 }
+
+// ValidateCar checks car against the built-in rules, the current reference
+// data and the deployment's validation rules. Enum fields are rewritten to
+// their canonical codes first, so aliases are never stored and rules only
+// ever see canonical values.
+func (s *CarService) ValidateCar(ctx context.Context, car *models.CarRequest) error {
+	tracer := otel.Tracer("CarService")
+	ctx, span := tracer.Start(ctx, "ValidateCar-Service")
+	defer span.End()
+
+	enums, err := s.enums.Enums(ctx)
+	if err != nil {
+		return err
+	}
+
+	ruleSet, err := s.rules.Rules(ctx)
+	if err != nil {
+		return err
+	}
+
+	if fuelType, ok := enums.Canonical(models.ReferenceKindFuelType, car.FuelType); ok {
+		car.FuelType = fuelType
+	}
+	car.VIN = strings.ToUpper(strings.TrimSpace(car.VIN))
+
+	return models.ValidateRequest(*car, enums, ruleSet.Check(models.RuleTargetCar, car))
+}
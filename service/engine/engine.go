@@ -4,17 +4,28 @@ import (
 	"context"
 
 	"github.com/geekAshish/DriveDesk/models"
+	"github.com/geekAshish/DriveDesk/service"
 	"github.com/geekAshish/DriveDesk/store"
 	"go.opentelemetry.io/otel"
 )
 
 type EngineService struct {
-	store store.EngineStoreInterface
+	store  store.EngineStoreInterface
+	cars   store.CarStoreInterface
+	tx     store.TxManagerInterface
+	rules  service.RuleProvider
+	events service.EventPublisher
 }
 
-func NewEngineService(store store.EngineStoreInterface) *EngineService {
+// NewEngineService reads the cars of an engine from cars, which its deletion
+// removes with it.
+func NewEngineService(store store.EngineStoreInterface, cars store.CarStoreInterface, tx store.TxManagerInterface, rules service.RuleProvider, events service.EventPublisher) *EngineService {
 	return &EngineService{
-		store: store,
+		store:  store,
+		cars:   cars,
+		tx:     tx,
+		rules:  rules,
+		events: events,
 	}
 }
 
@@ -31,16 +42,35 @@ func (s *EngineService) GetEngineById(ctx context.Context, id string) (*models.E
 	return &engine, nil
 }
 
+// GetEnginesByIds looks up many engines at once, for callers that would
+// otherwise fetch the engine of each car one by one.
+func (s *EngineService) GetEnginesByIds(ctx context.Context, ids []string) ([]models.Engine, error) {
+	tracer := otel.Tracer("EngineService")
+	ctx, span := tracer.Start(ctx, "GetEnginesByIds-Service")
+	defer span.End()
+
+	return s.store.GetEnginesByIds(ctx, ids)
+}
+
 func (s *EngineService) CreateEngine(ctx context.Context, engineReq *models.EngineRequest) (*models.Engine, error) {
 	tracer := otel.Tracer("EngineService")
 	ctx, span := tracer.Start(ctx, "CreateEngine-Service")
 	defer span.End()
 
-	if err := models.ValidateEngineRequest(*engineReq); err != nil {
+	if err := s.ValidateEngine(ctx, engineReq); err != nil {
 		return nil, err
 	}
 
-	createEngine, err := s.store.CreateEngine(ctx, engineReq)
+	var createEngine models.Engine
+	err := s.tx.WithTx(ctx, func(ctx context.Context) error {
+		var err error
+		createEngine, err = s.store.CreateEngine(ctx, engineReq)
+		if err != nil {
+			return err
+		}
+
+		return s.events.Publish(ctx, models.NewEvent(models.EventEngineCreated, createEngine.EngineID.String(), createEngine))
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -53,11 +83,20 @@ func (s *EngineService) UpdateEngine(ctx context.Context, id string, engineReq *
 	ctx, span := tracer.Start(ctx, "UpdateEngine-Service")
 	defer span.End()
 
-	if err := models.ValidateEngineRequest(*engineReq); err != nil {
+	if err := s.ValidateEngine(ctx, engineReq); err != nil {
 		return nil, err
 	}
 
-	updateEngine, err := s.store.UpdateEngine(ctx, id, engineReq)
+	var updateEngine models.Engine
+	err := s.tx.WithTx(ctx, func(ctx context.Context) error {
+		var err error
+		updateEngine, err = s.store.UpdateEngine(ctx, id, engineReq)
+		if err != nil {
+			return err
+		}
+
+		return s.events.Publish(ctx, models.NewEvent(models.EventEngineUpdated, updateEngine.EngineID.String(), updateEngine))
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -70,10 +109,46 @@ func (s *EngineService) DeleteEngine(ctx context.Context, id string) (*models.En
 	ctx, span := tracer.Start(ctx, "DeleteEngine-Service")
 	defer span.End()
 
-	deleteEngine, err := s.store.DeleteEngine(ctx, id)
+	var deleteEngine models.Engine
+	err := s.tx.WithTx(ctx, func(ctx context.Context) error {
+		// the engine's cars are deleted with it, and their subscribers must
+		// hear about it as if each had been deleted on its own
+		cars, err := s.cars.GetCarsByEngine(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		deleteEngine, err = s.store.DeleteEngine(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		for _, car := range cars {
+			if err := s.events.Publish(ctx, models.NewEvent(models.EventCarDeleted, car.ID.String(), car)); err != nil {
+				return err
+			}
+		}
+
+		return s.events.Publish(ctx, models.NewEvent(models.EventEngineDeleted, deleteEngine.EngineID.String(), deleteEngine))
+	})
 	if err != nil {
 		return nil, err
 	}
 
 	return &deleteEngine, nil
 }
+
+// ValidateEngine checks engineReq against the built-in rules and the
+// deployment's validation rules.
+func (s *EngineService) ValidateEngine(ctx context.Context, engineReq *models.EngineRequest) error {
+	tracer := otel.Tracer("EngineService")
+	ctx, span := tracer.Start(ctx, "ValidateEngine-Service")
+	defer span.End()
+
+	ruleSet, err := s.rules.Rules(ctx)
+	if err != nil {
+		return err
+	}
+
+	return models.ValidateEngineRequest(*engineReq, ruleSet.Check(models.RuleTargetEngine, engineReq))
+}
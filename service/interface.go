@@ -4,6 +4,7 @@ import (
 	"context"
 
 	"github.com/geekAshish/DriveDesk/models"
+	"github.com/geekAshish/DriveDesk/rules"
 )
 
 type CarServiceInterface interface {
@@ -12,11 +13,104 @@ type CarServiceInterface interface {
 	CreateCar(ctx context.Context, car *models.CarRequest) (*models.Car, error)
 	UpdateCar(ctx context.Context, id string, car *models.CarRequest) (*models.Car, error)
 	DeleteCar(ctx context.Context, id string) (*models.Car, error)
+	ValidateCar(ctx context.Context, car *models.CarRequest) error
 }
 
 type EngineServiceInterface interface {
 	GetEngineById(ctx context.Context, id string) (*models.Engine, error)
+	GetEnginesByIds(ctx context.Context, ids []string) ([]models.Engine, error)
 	CreateEngine(ctx context.Context, engineReq *models.EngineRequest) (*models.Engine, error)
 	UpdateEngine(ctx context.Context, id string, engineReq *models.EngineRequest) (*models.Engine, error)
 	DeleteEngine(ctx context.Context, id string) (*models.Engine, error)
+	ValidateEngine(ctx context.Context, engineReq *models.EngineRequest) error
+}
+
+type ReferenceServiceInterface interface {
+	ListReferenceValues(ctx context.Context, kind string) ([]models.ReferenceValue, error)
+	GetReferenceValue(ctx context.Context, kind, code string) (*models.ReferenceValue, error)
+	CreateReferenceValue(ctx context.Context, kind string, req *models.ReferenceValueRequest) (*models.ReferenceValue, error)
+	UpdateReferenceValue(ctx context.Context, kind, code string, req *models.ReferenceValueRequest) (*models.ReferenceValue, error)
+	DeleteReferenceValue(ctx context.Context, kind, code string) (*models.ReferenceValue, error)
+	EnumProvider
+}
+
+// EnumProvider gives the services the current reference data to validate
+// and canonicalise enum fields with.
+type EnumProvider interface {
+	Enums(ctx context.Context) (models.EnumLookup, error)
+}
+
+type ValidationRuleServiceInterface interface {
+	ListValidationRules(ctx context.Context) ([]models.ValidationRule, error)
+	CreateValidationRule(ctx context.Context, req *models.ValidationRuleRequest) (*models.ValidationRule, error)
+	DeleteValidationRule(ctx context.Context, id string) (*models.ValidationRule, error)
+	RuleProvider
+}
+
+// RuleProvider gives the services the deployment specific validation rules
+// to evaluate on top of the built-in ones.
+type RuleProvider interface {
+	Rules(ctx context.Context) (*rules.RuleSet, error)
+}
+
+// EventPublisher is told about every change to a car or an engine. The car
+// and engine services publish inside the transaction of the change, so a
+// publisher that writes through the stores commits or rolls back with it.
+type EventPublisher interface {
+	Publish(ctx context.Context, event models.Event) error
+}
+
+// NoEvents is an EventPublisher that drops every event.
+type NoEvents struct{}
+
+func (NoEvents) Publish(ctx context.Context, event models.Event) error {
+	return nil
+}
+
+// Publishers is an EventPublisher that publishes to each of its publishers
+// in turn, stopping at the first error.
+type Publishers []EventPublisher
+
+func (p Publishers) Publish(ctx context.Context, event models.Event) error {
+	for _, publisher := range p {
+		if err := publisher.Publish(ctx, event); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ChangeStream pushes car and engine changes to connected clients.
+type ChangeStream interface {
+	// Subscribe starts a subscription to the events matching filter. With
+	// lastSeq at zero or above, the events after lastSeq that were already
+	// published come first; a negative lastSeq only gets new events.
+	Subscribe(ctx context.Context, filter models.EventFilter, lastSeq int64) (Subscription, error)
+}
+
+// Subscription is one client of a ChangeStream.
+type Subscription interface {
+	// Events delivers the events in order. It is closed when the client
+	// falls too far behind, and should then reconnect from the last event
+	// it got.
+	Events() <-chan models.ChangeEvent
+	// Close ends the subscription.
+	Close()
+}
+
+type WebhookServiceInterface interface {
+	ListWebhooks(ctx context.Context) ([]models.Webhook, error)
+	GetWebhook(ctx context.Context, id string) (*models.Webhook, error)
+	CreateWebhook(ctx context.Context, req *models.WebhookRequest) (*models.Webhook, error)
+	UpdateWebhook(ctx context.Context, id string, req *models.WebhookRequest) (*models.Webhook, error)
+	DeleteWebhook(ctx context.Context, id string) (*models.Webhook, error)
+	ListWebhookDeliveries(ctx context.Context, filter models.WebhookDeliveryFilter) ([]models.WebhookDelivery, error)
+	GetWebhookDelivery(ctx context.Context, id string) (*models.WebhookDelivery, error)
+	RedeliverWebhookDelivery(ctx context.Context, id string) (*models.WebhookDelivery, error)
+}
+
+type SyncServiceInterface interface {
+	Pull(ctx context.Context, since int64, limit int) (*models.SyncPage, error)
+	Push(ctx context.Context, req *models.SyncPushRequest) (*models.SyncPushResponse, error)
 }
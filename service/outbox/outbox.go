@@ -0,0 +1,62 @@
+// Package outbox publishes car and engine events reliably. The Outbox is the
+// car and engine services' EventPublisher: it writes every event as one
+// pending message per sink in the transaction of the change, so an event
+// exists exactly when its change was committed. The Relay then publishes the
+// pending messages to the sinks, at least once and, for each car or engine,
+// in the order the changes were made.
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+
+	"github.com/geekAshish/DriveDesk/models"
+	"github.com/geekAshish/DriveDesk/store"
+	"go.opentelemetry.io/otel"
+)
+
+type Outbox struct {
+	store store.OutboxStoreInterface
+	sinks []string
+}
+
+// NewOutbox writes every event for each of the named sinks. A relay with
+// sinks of the same names publishes them.
+func NewOutbox(store store.OutboxStoreInterface, sinks ...string) *Outbox {
+	return &Outbox{store: store, sinks: sinks}
+}
+
+func (o *Outbox) Publish(ctx context.Context, event models.Event) error {
+	tracer := otel.Tracer("Outbox")
+	ctx, span := tracer.Start(ctx, "Publish-Outbox")
+	defer span.End()
+
+	if len(o.sinks) == 0 {
+		return nil
+	}
+
+	payload, err := json.Marshal(event.Data)
+	if err != nil {
+		return err
+	}
+
+	aggregateType, _, _ := strings.Cut(event.Type, ".")
+
+	messages := make([]models.OutboxMessage, 0, len(o.sinks))
+	for _, sink := range o.sinks {
+		messages = append(messages, models.OutboxMessage{
+			Sink:          sink,
+			AggregateType: aggregateType,
+			AggregateID:   event.Subject,
+			EventID:       event.ID,
+			EventType:     event.Type,
+			Payload:       payload,
+			Status:        models.OutboxPending,
+			NextAttemptAt: event.Time,
+			CreateAt:      event.Time,
+		})
+	}
+
+	return o.store.AppendOutbox(ctx, messages)
+}
@@ -0,0 +1,390 @@
+package outbox
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/geekAshish/DriveDesk/broker"
+	"github.com/geekAshish/DriveDesk/models"
+	"github.com/geekAshish/DriveDesk/service"
+	"github.com/geekAshish/DriveDesk/store"
+	"github.com/geekAshish/DriveDesk/store/memory"
+
+	engineService "github.com/geekAshish/DriveDesk/service/engine"
+	ruleService "github.com/geekAshish/DriveDesk/service/rule"
+	webhookService "github.com/geekAshish/DriveDesk/service/webhook"
+)
+
+// recordingSink keeps what it was sent, failing the first attempts at the
+// events listed in failures.
+type recordingSink struct {
+	mu       sync.Mutex
+	failures map[string]int
+	sent     []models.OutboxMessage
+}
+
+func (s *recordingSink) Name() string {
+	return "recording"
+}
+
+func (s *recordingSink) Send(ctx context.Context, message models.OutboxMessage) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.failures[message.EventID.String()] > 0 {
+		s.failures[message.EventID.String()]--
+		return errors.New("sink unavailable")
+	}
+
+	s.sent = append(s.sent, message)
+	return nil
+}
+
+// subjects lists the subjects of the sent messages with their event types.
+func (s *recordingSink) subjects() []string {
+	var subjects []string
+	for _, message := range s.sent {
+		subjects = append(subjects, message.AggregateID+" "+message.EventType)
+	}
+	return subjects
+}
+
+// noWait retries straight away, so the tests need not sleep.
+var noWait = service.RetryPolicy{MaxAttempts: 3}
+
+type fixture struct {
+	outbox *Outbox
+	relay  *Relay
+	sink   *recordingSink
+	store  *memory.OutboxStore
+}
+
+func newFixture(t *testing.T, policy service.RetryPolicy) *fixture {
+	t.Helper()
+
+	store := memory.NewOutboxStore(memory.NewDB())
+	sink := &recordingSink{failures: map[string]int{}}
+	relay := NewRelay(store, policy, sink)
+
+	return &fixture{outbox: NewOutbox(store, relay.Sinks()...), relay: relay, sink: sink, store: store}
+}
+
+func (f *fixture) publish(t *testing.T, eventType, subject string) models.Event {
+	t.Helper()
+
+	event := models.NewEvent(eventType, subject, map[string]string{"id": subject})
+	if err := f.outbox.Publish(context.Background(), event); err != nil {
+		t.Fatalf("Publish: %v", err)
+	}
+	return event
+}
+
+func TestRelayKeepsEntityOrder(t *testing.T) {
+	f := newFixture(t, noWait)
+
+	first := f.publish(t, models.EventCarCreated, "a")
+	f.publish(t, models.EventCarCreated, "b")
+	f.publish(t, models.EventCarUpdated, "a")
+	f.sink.failures[first.ID.String()] = 1
+
+	if n, err := f.relay.RelayDue(context.Background()); err != nil || n != 4 {
+		t.Fatalf("RelayDue = %d, %v, want four attempts", n, err)
+	}
+
+	// the failed creation of a is retried straight away with noWait, and the
+	// update of a waits for it
+	want := []string{"a car.created", "b car.created", "a car.updated"}
+	if got := f.sink.subjects(); len(got) != len(want) || got[0] != want[0] || got[1] != want[1] || got[2] != want[2] {
+		t.Fatalf("sent %v, want %v", got, want)
+	}
+
+	if n, _ := f.relay.RelayDue(context.Background()); n != 0 {
+		t.Errorf("RelayDue sent %d published messages again", n)
+	}
+}
+
+func TestRelayGivesUp(t *testing.T) {
+	f := newFixture(t, noWait)
+
+	first := f.publish(t, models.EventCarCreated, "a")
+	f.publish(t, models.EventCarUpdated, "a")
+	f.sink.failures[first.ID.String()] = noWait.MaxAttempts
+
+	if _, err := f.relay.RelayDue(context.Background()); err != nil {
+		t.Fatalf("RelayDue: %v", err)
+	}
+
+	if got := f.sink.subjects(); len(got) != 1 || got[0] != "a car.updated" {
+		t.Fatalf("sent %v, want only the update once the creation is dead", got)
+	}
+}
+
+func TestRelayBacksOff(t *testing.T) {
+	f := newFixture(t, service.RetryPolicy{MaxAttempts: 5, BaseDelay: time.Hour, MaxDelay: 2 * time.Hour})
+
+	first := f.publish(t, models.EventCarCreated, "a")
+	f.publish(t, models.EventCarUpdated, "a")
+	f.sink.failures[first.ID.String()] = 1
+
+	if n, err := f.relay.RelayDue(context.Background()); err != nil || n != 1 {
+		t.Fatalf("RelayDue = %d, %v, want one attempt", n, err)
+	}
+	if n, _ := f.relay.RelayDue(context.Background()); n != 0 || len(f.sink.sent) != 0 {
+		t.Errorf("RelayDue sent %d messages before the backoff of the first", n)
+	}
+
+	// the retry is due in an hour and holds the update back until then
+	claimed, err := f.store.ClaimOutbox(context.Background(), f.sink.Name(), time.Now().Add(2*time.Hour), time.Now().Add(3*time.Hour), 10)
+	if err != nil || len(claimed) != 1 || claimed[0].EventID != first.ID || claimed[0].Attempts != 1 || claimed[0].LastError != "sink unavailable" {
+		t.Fatalf("claimed %+v, %v once the retry is due, want the creation", claimed, err)
+	}
+}
+
+// claimRecorder records the claims made of the store it wraps and fails to
+// store the outcome of the messages in failUpdates once each.
+type claimRecorder struct {
+	store.OutboxStoreInterface
+	limits      []int
+	leases      []time.Duration
+	failUpdates map[int64]bool
+}
+
+func (c *claimRecorder) ClaimOutbox(ctx context.Context, sink string, now, leaseUntil time.Time, limit int) ([]models.OutboxMessage, error) {
+	c.limits = append(c.limits, limit)
+	c.leases = append(c.leases, leaseUntil.Sub(now))
+	return c.OutboxStoreInterface.ClaimOutbox(ctx, sink, now, leaseUntil, limit)
+}
+
+func (c *claimRecorder) UpdateOutbox(ctx context.Context, message models.OutboxMessage) error {
+	if c.failUpdates[message.Seq] {
+		delete(c.failUpdates, message.Seq)
+		return errors.New("database unavailable")
+	}
+	return c.OutboxStoreInterface.UpdateOutbox(ctx, message)
+}
+
+func TestRelayClaimsOneAtATime(t *testing.T) {
+	f := newFixture(t, noWait)
+	for _, subject := range []string{"a", "b", "c"} {
+		f.publish(t, models.EventCarCreated, subject)
+	}
+
+	claims := &claimRecorder{OutboxStoreInterface: f.store, failUpdates: map[int64]bool{1: true}}
+	f.relay.store = claims
+
+	if n, err := f.relay.RelayDue(context.Background()); err != nil || n != 3 {
+		t.Fatalf("RelayDue = %d, %v, want three attempts", n, err)
+	}
+
+	// the outcome of a was lost, so a stays leased, but b and c still go out
+	if got := f.sink.subjects(); len(got) != 3 || got[1] != "b car.created" || got[2] != "c car.created" {
+		t.Errorf("sent %v, want b and c after a", got)
+	}
+	for i, limit := range claims.limits {
+		if limit != 1 || claims.leases[i] != lease {
+			t.Errorf("claim %d of %d messages for %v, want one message for %v", i, limit, claims.leases[i], lease)
+		}
+	}
+}
+
+func TestOutboxWrittenWithChange(t *testing.T) {
+	db := memory.NewDB()
+	store := memory.NewOutboxStore(db)
+	sink := &recordingSink{failures: map[string]int{}}
+	relay := NewRelay(store, noWait, sink)
+
+	rules := ruleService.NewValidationRuleService(memory.NewValidationRuleStore(db), time.Minute)
+	engines := engineService.NewEngineService(memory.NewEngineStore(db), memory.NewCarStore(db), memory.NewTxManager(db), rules, NewOutbox(store, relay.Sinks()...))
+
+	engine, err := engines.CreateEngine(context.Background(), &models.EngineRequest{Dispacement: 2000, NoOfCylinders: 4, CarRange: 600})
+	if err != nil {
+		t.Fatalf("CreateEngine: %v", err)
+	}
+	if _, err := engines.DeleteEngine(context.Background(), "not-an-engine"); err == nil {
+		t.Fatal("DeleteEngine of a missing engine succeeded")
+	}
+
+	if _, err := relay.RelayDue(context.Background()); err != nil {
+		t.Fatalf("RelayDue: %v", err)
+	}
+
+	if len(sink.sent) != 1 {
+		t.Fatalf("sent %v, want only the creation", sink.subjects())
+	}
+	got := sink.sent[0]
+	if got.AggregateType != models.EntityEngine || got.AggregateID != engine.EngineID.String() || got.EventType != models.EventEngineCreated {
+		t.Errorf("sent %+v, want the engine.created event of %s", got, engine.EngineID)
+	}
+
+	var payload models.Engine
+	if err := json.Unmarshal(got.Payload, &payload); err != nil || payload.EngineID != engine.EngineID {
+		t.Errorf("payload = %s, want the engine", got.Payload)
+	}
+}
+
+func TestEngineDeletionPublishesCarDeletions(t *testing.T) {
+	db := memory.NewDB()
+	store := memory.NewOutboxStore(db)
+	sink := &recordingSink{failures: map[string]int{}}
+	relay := NewRelay(store, noWait, sink)
+
+	rules := ruleService.NewValidationRuleService(memory.NewValidationRuleStore(db), time.Minute)
+	cars := memory.NewCarStore(db)
+	engines := engineService.NewEngineService(memory.NewEngineStore(db), cars, memory.NewTxManager(db), rules, NewOutbox(store, relay.Sinks()...))
+
+	engine, err := engines.CreateEngine(context.Background(), &models.EngineRequest{Dispacement: 2000, NoOfCylinders: 4, CarRange: 600})
+	if err != nil {
+		t.Fatalf("CreateEngine: %v", err)
+	}
+	car, err := cars.CreateCar(context.Background(), &models.CarRequest{
+		Name: "Honda Civic", Year: "2023", Brand: "Honda", FuelType: "petrol", Price: 25000, Engine: *engine,
+	})
+	if err != nil {
+		t.Fatalf("CreateCar: %v", err)
+	}
+
+	if _, err := engines.DeleteEngine(context.Background(), engine.EngineID.String()); err != nil {
+		t.Fatalf("DeleteEngine: %v", err)
+	}
+	if _, err := relay.RelayDue(context.Background()); err != nil {
+		t.Fatalf("RelayDue: %v", err)
+	}
+
+	id := engine.EngineID.String()
+	want := []string{id + " engine.created", car.ID.String() + " car.deleted", id + " engine.deleted"}
+	if got := sink.subjects(); len(got) != len(want) || got[0] != want[0] || got[1] != want[1] || got[2] != want[2] {
+		t.Errorf("sent %v, want %v", got, want)
+	}
+}
+
+func TestFileSink(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "events.ndjson")
+
+	sink, err := NewFileSink(path)
+	if err != nil {
+		t.Fatalf("NewFileSink: %v", err)
+	}
+	defer sink.Close()
+
+	store := memory.NewOutboxStore(memory.NewDB())
+	relay := NewRelay(store, noWait, sink)
+	outbox := NewOutbox(store, relay.Sinks()...)
+
+	for _, subject := range []string{"a", "b"} {
+		if err := outbox.Publish(context.Background(), models.NewEvent(models.EventCarDeleted, subject, map[string]string{"id": subject})); err != nil {
+			t.Fatalf("Publish: %v", err)
+		}
+	}
+
+	if _, err := relay.RelayDue(context.Background()); err != nil {
+		t.Fatalf("RelayDue: %v", err)
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	defer file.Close()
+
+	var lines []map[string]any
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var line map[string]any
+		if err := json.Unmarshal(scanner.Bytes(), &line); err != nil {
+			t.Fatalf("line %q is not JSON: %v", scanner.Text(), err)
+		}
+		lines = append(lines, line)
+	}
+
+	if len(lines) != 2 || lines[0]["subject"] != "a" || lines[1]["subject"] != "b" {
+		t.Fatalf("lines = %v, want the two deletions in order", lines)
+	}
+	if lines[0]["specversion"] != "1.0" || lines[0]["type"] != "com.drivedesk.car.deleted" || lines[0]["source"] != models.CloudEventSource {
+		t.Errorf("line = %v, want a CloudEvent", lines[0])
+	}
+	if data, _ := lines[1]["data"].(map[string]any); data["id"] != "b" {
+		t.Errorf("data = %v, want the event data", lines[1]["data"])
+	}
+}
+
+// recordingPublisher is a broker that keeps what it was sent.
+type recordingPublisher struct {
+	messages []broker.Message
+}
+
+func (p *recordingPublisher) Publish(ctx context.Context, message broker.Message) error {
+	p.messages = append(p.messages, message)
+	return nil
+}
+
+func (p *recordingPublisher) Close() error {
+	return nil
+}
+
+func TestBrokerSink(t *testing.T) {
+	publisher := &recordingPublisher{}
+	store := memory.NewOutboxStore(memory.NewDB())
+	relay := NewRelay(store, noWait, NewBrokerSink("nats", publisher))
+	event := models.NewEvent(models.EventEngineUpdated, "e1", map[string]int{"dispacement": 1500})
+
+	if err := NewOutbox(store, relay.Sinks()...).Publish(context.Background(), event); err != nil {
+		t.Fatalf("Publish: %v", err)
+	}
+	if _, err := relay.RelayDue(context.Background()); err != nil {
+		t.Fatalf("RelayDue: %v", err)
+	}
+
+	if len(publisher.messages) != 1 {
+		t.Fatalf("published %d messages, want 1", len(publisher.messages))
+	}
+	got := publisher.messages[0]
+	if got.ID != event.ID.String() || got.Type != models.EventEngineUpdated || got.Key != "e1" {
+		t.Errorf("message = %+v, want the engine.updated event of e1", got)
+	}
+
+	var body models.CloudEvent
+	if err := json.Unmarshal(got.Body, &body); err != nil {
+		t.Fatalf("body is not JSON: %v", err)
+	}
+	if body.Type != "com.drivedesk.engine.updated" || body.ID != event.ID.String() || body.Subject != "e1" || string(body.Data) != `{"dispacement":1500}` || !body.Time.Equal(event.Time) {
+		t.Errorf("body = %+v, want the event as a CloudEvent", body)
+	}
+}
+
+func TestWebhookSinkQueuesDeliveries(t *testing.T) {
+	db := memory.NewDB()
+	webhooks := webhookService.NewWebhookService(memory.NewWebhookStore(db))
+
+	_, err := webhooks.CreateWebhook(context.Background(), &models.WebhookRequest{URL: "http://localhost/hook", Events: []string{models.EventCarCreated}, Secret: "0123456789abcdef"})
+	if err != nil {
+		t.Fatalf("CreateWebhook: %v", err)
+	}
+
+	store := memory.NewOutboxStore(db)
+	relay := NewRelay(store, noWait, NewWebhookSink(webhooks))
+	event := models.NewEvent(models.EventCarCreated, "a", map[string]string{"id": "a"})
+
+	if err := NewOutbox(store, relay.Sinks()...).Publish(context.Background(), event); err != nil {
+		t.Fatalf("Publish: %v", err)
+	}
+	if _, err := relay.RelayDue(context.Background()); err != nil {
+		t.Fatalf("RelayDue: %v", err)
+	}
+
+	deliveries, err := webhooks.ListWebhookDeliveries(context.Background(), models.WebhookDeliveryFilter{})
+	if err != nil || len(deliveries) != 1 || deliveries[0].EventID != event.ID {
+		t.Fatalf("deliveries = %+v, %v, want one for the event", deliveries, err)
+	}
+
+	var payload map[string]any
+	if err := json.Unmarshal(deliveries[0].Payload, &payload); err != nil || payload["subject"] != "a" || payload["data"].(map[string]any)["id"] != "a" {
+		t.Errorf("payload = %s, want the event", deliveries[0].Payload)
+	}
+}
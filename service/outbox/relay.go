@@ -0,0 +1,137 @@
+package outbox
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/geekAshish/DriveDesk/models"
+	"github.com/geekAshish/DriveDesk/service"
+	"github.com/geekAshish/DriveDesk/store"
+	"go.opentelemetry.io/otel"
+)
+
+// DefaultRetryPolicy keeps retrying a message for about three hours. A
+// message that still fails is dead, and the later messages of its car or
+// engine go out without it.
+var DefaultRetryPolicy = service.RetryPolicy{
+	MaxAttempts: 25,
+	BaseDelay:   time.Second,
+	MaxDelay:    10 * time.Minute,
+}
+
+// maxSends bounds the messages one RelayDue call sends to a sink, so that a
+// busy sink does not hold up the others for long.
+const maxSends = 2000
+
+// lease is how long a claimed message is kept from other relays. Messages
+// are claimed one at a time, so the lease only has to cover one send; a send
+// taking longer may be repeated by another instance.
+const lease = time.Minute
+
+// Relay publishes the pending outbox messages to their sinks. Messages are
+// claimed before being sent, so every instance can run a relay.
+type Relay struct {
+	store  store.OutboxStoreInterface
+	sinks  []Sink
+	policy service.RetryPolicy
+	wake   chan struct{}
+}
+
+func NewRelay(store store.OutboxStoreInterface, policy service.RetryPolicy, sinks ...Sink) *Relay {
+	return &Relay{store: store, sinks: sinks, policy: policy, wake: make(chan struct{}, 1)}
+}
+
+// Sinks lists the names of the relay's sinks, which the Outbox writes
+// messages for.
+func (r *Relay) Sinks() []string {
+	names := make([]string, 0, len(r.sinks))
+	for _, sink := range r.sinks {
+		names = append(names, sink.Name())
+	}
+	return names
+}
+
+// Wake makes Run look for pending messages now rather than at its next
+// tick, e.g. when the database reports a change that wrote some.
+func (r *Relay) Wake() {
+	select {
+	case r.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Run publishes the pending messages every interval, and whenever woken,
+// until ctx is done.
+func (r *Relay) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := r.RelayDue(ctx); err != nil {
+			log.Printf("Error relaying outbox messages : %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-r.wake:
+		}
+	}
+}
+
+// RelayDue sends the due messages to each sink and records the outcome of
+// each. It returns how many it attempted.
+func (r *Relay) RelayDue(ctx context.Context) (int, error) {
+	tracer := otel.Tracer("OutboxRelay")
+	ctx, span := tracer.Start(ctx, "RelayDue-Relay")
+	defer span.End()
+
+	attempted := 0
+	for _, sink := range r.sinks {
+		for sent := 0; sent < maxSends; sent++ {
+			now := time.Now().UTC()
+
+			due, err := r.store.ClaimOutbox(ctx, sink.Name(), now, now.Add(lease), 1)
+			if err != nil {
+				return attempted, err
+			}
+			if len(due) == 0 {
+				break
+			}
+
+			attempted++
+			if err := r.send(ctx, sink, due[0]); err != nil {
+				log.Printf("Error relaying outbox message %d to %s : %v", due[0].Seq, sink.Name(), err)
+			}
+		}
+	}
+
+	return attempted, nil
+}
+
+// send makes one attempt at message and stores its outcome. Only failing
+// to store the outcome is an error; the message is then sent again once its
+// lease runs out.
+func (r *Relay) send(ctx context.Context, sink Sink, message models.OutboxMessage) error {
+	message.Attempts++
+
+	err := sink.Send(ctx, message)
+	switch {
+	case err == nil:
+		now := time.Now().UTC()
+		message.Status = models.OutboxPublished
+		message.PublishedAt = &now
+		message.LastError = ""
+	case message.Attempts >= r.policy.MaxAttempts:
+		message.Status = models.OutboxDead
+		message.LastError = err.Error()
+		log.Printf("Giving up on outbox message %d for %s : %v", message.Seq, sink.Name(), err)
+	default:
+		message.NextAttemptAt = time.Now().UTC().Add(r.policy.Backoff(message.Attempts))
+		message.LastError = err.Error()
+	}
+
+	return r.store.UpdateOutbox(ctx, message)
+}
@@ -0,0 +1,111 @@
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"sync"
+
+	"github.com/geekAshish/DriveDesk/broker"
+	"github.com/geekAshish/DriveDesk/models"
+	"github.com/geekAshish/DriveDesk/service"
+)
+
+// Sink is where the relay publishes outbox messages. Send may be called
+// again for a message it already took, when the relay could not record
+// that it did; consumers tell repeats apart by the event id.
+type Sink interface {
+	// Name identifies the sink's messages in the outbox; it must not change
+	// while messages for it are pending.
+	Name() string
+	Send(ctx context.Context, message models.OutboxMessage) error
+}
+
+// WebhookSink queues every message as deliveries for the subscribed
+// webhooks, which the webhook dispatcher then sends.
+type WebhookSink struct {
+	webhooks service.EventPublisher
+}
+
+func NewWebhookSink(webhooks service.EventPublisher) *WebhookSink {
+	return &WebhookSink{webhooks: webhooks}
+}
+
+func (s *WebhookSink) Name() string {
+	return "webhooks"
+}
+
+func (s *WebhookSink) Send(ctx context.Context, message models.OutboxMessage) error {
+	return s.webhooks.Publish(ctx, message.Event())
+}
+
+// FileSink appends every message to a file as one line of JSON, a
+// structured CloudEvent.
+type FileSink struct {
+	mu   sync.Mutex
+	file *os.File
+}
+
+// NewFileSink appends to the file at path, creating it if needed.
+func NewFileSink(path string) (*FileSink, error) {
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o644)
+	if err != nil {
+		return nil, err
+	}
+
+	return &FileSink{file: file}, nil
+}
+
+func (s *FileSink) Name() string {
+	return "file"
+}
+
+// Send returns once the line is on disk, so a crash cannot lose a message
+// the outbox no longer holds.
+func (s *FileSink) Send(ctx context.Context, message models.OutboxMessage) error {
+	line, err := json.Marshal(message.CloudEvent())
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, err := s.file.Write(append(line, '\n')); err != nil {
+		return err
+	}
+
+	return s.file.Sync()
+}
+
+func (s *FileSink) Close() error {
+	return s.file.Close()
+}
+
+// BrokerSink publishes every message to a message broker.
+type BrokerSink struct {
+	name      string
+	publisher broker.Publisher
+}
+
+func NewBrokerSink(name string, publisher broker.Publisher) *BrokerSink {
+	return &BrokerSink{name: name, publisher: publisher}
+}
+
+func (s *BrokerSink) Name() string {
+	return s.name
+}
+
+func (s *BrokerSink) Send(ctx context.Context, message models.OutboxMessage) error {
+	body, err := json.Marshal(message.CloudEvent())
+	if err != nil {
+		return err
+	}
+
+	return s.publisher.Publish(ctx, broker.Message{
+		ID:   message.EventID.String(),
+		Type: message.EventType,
+		Key:  message.AggregateID,
+		Body: body,
+	})
+}
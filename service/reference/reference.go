@@ -0,0 +1,178 @@
+package reference
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/geekAshish/DriveDesk/apperrors"
+	"github.com/geekAshish/DriveDesk/models"
+	"github.com/geekAshish/DriveDesk/store"
+	"go.opentelemetry.io/otel"
+)
+
+// ReferenceService manages reference data and serves it to the other
+// services as an EnumLookup. The lookup is cached for cacheTTL; writes made
+// through this instance drop the cache at once, writes made by other
+// instances are picked up when it expires.
+type ReferenceService struct {
+	store    store.ReferenceStoreInterface
+	tx       store.TxManagerInterface
+	cacheTTL time.Duration
+
+	mu       sync.Mutex
+	catalog  models.EnumCatalog
+	loadedAt time.Time
+}
+
+func NewReferenceService(store store.ReferenceStoreInterface, tx store.TxManagerInterface, cacheTTL time.Duration) *ReferenceService {
+	return &ReferenceService{
+		store:    store,
+		tx:       tx,
+		cacheTTL: cacheTTL,
+	}
+}
+
+func (s *ReferenceService) ListReferenceValues(ctx context.Context, kind string) ([]models.ReferenceValue, error) {
+	tracer := otel.Tracer("ReferenceService")
+	ctx, span := tracer.Start(ctx, "ListReferenceValues-Service")
+	defer span.End()
+
+	kind = models.NormalizeReferenceCode(kind)
+	if err := models.ValidateReferenceKind(kind); err != nil {
+		return nil, err
+	}
+
+	return s.store.ListReferenceValues(ctx, kind)
+}
+
+func (s *ReferenceService) GetReferenceValue(ctx context.Context, kind, code string) (*models.ReferenceValue, error) {
+	tracer := otel.Tracer("ReferenceService")
+	ctx, span := tracer.Start(ctx, "GetReferenceValue-Service")
+	defer span.End()
+
+	value, err := s.store.GetReferenceValue(ctx, models.NormalizeReferenceCode(kind), models.NormalizeReferenceCode(code))
+	if err != nil {
+		return nil, err
+	}
+
+	return &value, nil
+}
+
+func (s *ReferenceService) CreateReferenceValue(ctx context.Context, kind string, req *models.ReferenceValueRequest) (*models.ReferenceValue, error) {
+	tracer := otel.Tracer("ReferenceService")
+	ctx, span := tracer.Start(ctx, "CreateReferenceValue-Service")
+	defer span.End()
+
+	kind = models.NormalizeReferenceCode(kind)
+	if err := models.ValidateReferenceKind(kind); err != nil {
+		return nil, err
+	}
+	if err := models.ValidateReferenceValueRequest(req); err != nil {
+		return nil, err
+	}
+
+	var created models.ReferenceValue
+	err := s.tx.WithTx(ctx, func(ctx context.Context) error {
+		var err error
+		created, err = s.store.CreateReferenceValue(ctx, kind, req)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.invalidate()
+
+	return &created, nil
+}
+
+// UpdateReferenceValue replaces a value's label, aliases and active flag.
+// The code may be left out of the body but cannot be changed, because cars
+// store it.
+func (s *ReferenceService) UpdateReferenceValue(ctx context.Context, kind, code string, req *models.ReferenceValueRequest) (*models.ReferenceValue, error) {
+	tracer := otel.Tracer("ReferenceService")
+	ctx, span := tracer.Start(ctx, "UpdateReferenceValue-Service")
+	defer span.End()
+
+	kind = models.NormalizeReferenceCode(kind)
+	code = models.NormalizeReferenceCode(code)
+
+	if req.Code == "" {
+		req.Code = code
+	}
+	if err := models.ValidateReferenceValueRequest(req); err != nil {
+		return nil, err
+	}
+	if req.Code != code {
+		return nil, apperrors.InvalidField("code", "immutable", "code cannot be changed")
+	}
+
+	var updated models.ReferenceValue
+	err := s.tx.WithTx(ctx, func(ctx context.Context) error {
+		var err error
+		updated, err = s.store.UpdateReferenceValue(ctx, kind, code, req)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.invalidate()
+
+	return &updated, nil
+}
+
+// DeleteReferenceValue removes a value. Cars already using it keep it, so
+// retiring a value that is in use is better done by deactivating it.
+func (s *ReferenceService) DeleteReferenceValue(ctx context.Context, kind, code string) (*models.ReferenceValue, error) {
+	tracer := otel.Tracer("ReferenceService")
+	ctx, span := tracer.Start(ctx, "DeleteReferenceValue-Service")
+	defer span.End()
+
+	var deleted models.ReferenceValue
+	err := s.tx.WithTx(ctx, func(ctx context.Context) error {
+		var err error
+		deleted, err = s.store.DeleteReferenceValue(ctx, models.NormalizeReferenceCode(kind), models.NormalizeReferenceCode(code))
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.invalidate()
+
+	return &deleted, nil
+}
+
+// Enums returns the active reference values, loading them from the store
+// when the cached copy is missing or older than cacheTTL.
+func (s *ReferenceService) Enums(ctx context.Context) (models.EnumLookup, error) {
+	tracer := otel.Tracer("ReferenceService")
+	ctx, span := tracer.Start(ctx, "Enums-Service")
+	defer span.End()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.catalog != nil && time.Since(s.loadedAt) < s.cacheTTL {
+		return s.catalog, nil
+	}
+
+	values, err := s.store.ListReferenceValues(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+
+	s.catalog = models.NewEnumCatalog(values)
+	s.loadedAt = time.Now()
+
+	return s.catalog, nil
+}
+
+func (s *ReferenceService) invalidate() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.catalog = nil
+}
@@ -0,0 +1,22 @@
+package service
+
+import "time"
+
+// RetryPolicy decides when failed work is tried again. The n-th retry waits
+// BaseDelay·2^(n-1), at most MaxDelay; after MaxAttempts attempts the work
+// is given up.
+type RetryPolicy struct {
+	MaxAttempts int
+	BaseDelay   time.Duration
+	MaxDelay    time.Duration
+}
+
+// Backoff is the wait after the given number of failed attempts.
+func (p RetryPolicy) Backoff(attempts int) time.Duration {
+	delay := p.BaseDelay
+	for i := 1; i < attempts && delay < p.MaxDelay; i++ {
+		delay *= 2
+	}
+
+	return min(delay, p.MaxDelay)
+}
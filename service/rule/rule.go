@@ -0,0 +1,124 @@
+package rule
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/geekAshish/DriveDesk/models"
+	"github.com/geekAshish/DriveDesk/rules"
+	"github.com/geekAshish/DriveDesk/store"
+	"go.opentelemetry.io/otel"
+)
+
+// ValidationRuleService manages the deployment specific validation rules and
+// serves them, compiled, to the car and engine services. Like reference data
+// the compiled set is cached for cacheTTL and dropped on local writes.
+type ValidationRuleService struct {
+	store    store.ValidationRuleStoreInterface
+	cacheTTL time.Duration
+
+	mu       sync.Mutex
+	ruleSet  *rules.RuleSet
+	loadedAt time.Time
+}
+
+func NewValidationRuleService(store store.ValidationRuleStoreInterface, cacheTTL time.Duration) *ValidationRuleService {
+	return &ValidationRuleService{
+		store:    store,
+		cacheTTL: cacheTTL,
+	}
+}
+
+func (s *ValidationRuleService) ListValidationRules(ctx context.Context) ([]models.ValidationRule, error) {
+	tracer := otel.Tracer("ValidationRuleService")
+	ctx, span := tracer.Start(ctx, "ListValidationRules-Service")
+	defer span.End()
+
+	return s.store.ListValidationRules(ctx)
+}
+
+func (s *ValidationRuleService) CreateValidationRule(ctx context.Context, req *models.ValidationRuleRequest) (*models.ValidationRule, error) {
+	tracer := otel.Tracer("ValidationRuleService")
+	ctx, span := tracer.Start(ctx, "CreateValidationRule-Service")
+	defer span.End()
+
+	if err := models.ValidateRuleRequest(req); err != nil {
+		return nil, err
+	}
+
+	// store the code and message clients will actually see
+	rule, err := rules.WithDefaults(models.ValidationRule{
+		Target:   req.Target,
+		Field:    req.Field,
+		Operator: req.Operator,
+		Value:    req.Value,
+		Code:     req.Code,
+		Message:  req.Message,
+	})
+	if err != nil {
+		return nil, err
+	}
+	req.Code, req.Message = rule.Code, rule.Message
+
+	created, err := s.store.CreateValidationRule(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	s.invalidate()
+
+	return &created, nil
+}
+
+func (s *ValidationRuleService) DeleteValidationRule(ctx context.Context, id string) (*models.ValidationRule, error) {
+	tracer := otel.Tracer("ValidationRuleService")
+	ctx, span := tracer.Start(ctx, "DeleteValidationRule-Service")
+	defer span.End()
+
+	deleted, err := s.store.DeleteValidationRule(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	s.invalidate()
+
+	return &deleted, nil
+}
+
+// Rules returns the compiled rules, loading them from the store when the
+// cached set is missing or older than cacheTTL.
+func (s *ValidationRuleService) Rules(ctx context.Context) (*rules.RuleSet, error) {
+	tracer := otel.Tracer("ValidationRuleService")
+	ctx, span := tracer.Start(ctx, "Rules-Service")
+	defer span.End()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.ruleSet != nil && time.Since(s.loadedAt) < s.cacheTTL {
+		return s.ruleSet, nil
+	}
+
+	stored, err := s.store.ListValidationRules(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	ruleSet, err := rules.Compile(stored)
+	if err != nil {
+		return nil, err
+	}
+
+	s.ruleSet = ruleSet
+	s.loadedAt = time.Now()
+
+	return s.ruleSet, nil
+}
+
+func (s *ValidationRuleService) invalidate() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.ruleSet = nil
+}
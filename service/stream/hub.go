@@ -0,0 +1,260 @@
+package stream
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/geekAshish/DriveDesk/models"
+	"github.com/geekAshish/DriveDesk/service"
+	"github.com/geekAshish/DriveDesk/store"
+	"go.opentelemetry.io/otel"
+)
+
+// Live is the lastSeq of a subscription that only wants new events.
+const Live int64 = -1
+
+const (
+	// pollBatch is how many events one poll reads at most.
+	pollBatch = 500
+
+	// subscriberBuffer is how many events a subscriber may fall behind
+	// before it is dropped.
+	subscriberBuffer = 256
+
+	// gapTimeout is how long a poll waits for a missing sequence number.
+	// Postgres hands out sequence numbers before commit, so a number can
+	// show up after higher ones or, if its transaction rolled back, never.
+	gapTimeout = 2 * time.Second
+)
+
+// Hub is the change stream of one instance. Every change is appended to
+// the event store in the transaction of the change, and each instance polls
+// the store and fans the new events out to its own subscribers, so clients
+// get every change whichever instance made it. The sequence numbers of the
+// store are the ids clients resume from.
+type Hub struct {
+	store store.EventStoreInterface
+	wake  chan struct{}
+
+	mu          sync.Mutex
+	cursor      int64
+	gapSince    time.Time
+	subscribers map[*subscription]struct{}
+}
+
+func NewHub(store store.EventStoreInterface) *Hub {
+	return &Hub{store: store, wake: make(chan struct{}, 1), subscribers: map[*subscription]struct{}{}}
+}
+
+// Wake makes Run poll now rather than at its next tick, e.g. when the
+// database reports a change.
+func (h *Hub) Wake() {
+	select {
+	case h.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Publish appends event to the store, to be sent to the subscribers once it
+// is committed.
+func (h *Hub) Publish(ctx context.Context, event models.Event) error {
+	tracer := otel.Tracer("EventHub")
+	ctx, span := tracer.Start(ctx, "Publish-Hub")
+	defer span.End()
+
+	data, err := json.Marshal(event.Data)
+	if err != nil {
+		return err
+	}
+
+	change := models.ChangeEvent{
+		ID:       event.ID,
+		Type:     event.Type,
+		Subject:  event.Subject,
+		Data:     data,
+		CreateAt: event.Time,
+	}
+	if car, ok := event.Data.(models.Car); ok {
+		change.Brand = car.Brand
+	}
+
+	_, err = h.store.AppendEvent(ctx, change)
+	return err
+}
+
+// Start makes the hub stream the events published from now on. It must be
+// called before the first Subscribe.
+func (h *Hub) Start(ctx context.Context) error {
+	seq, err := h.store.LatestEventSeq(ctx)
+	if err != nil {
+		return err
+	}
+
+	h.mu.Lock()
+	h.cursor = seq
+	h.mu.Unlock()
+
+	return nil
+}
+
+// Run polls the store for new events every interval, and whenever woken,
+// until ctx is done, then closes every subscription.
+func (h *Hub) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			h.closeAll()
+			return
+		case <-ticker.C:
+		case <-h.wake:
+		}
+
+		if err := h.Poll(ctx); err != nil {
+			log.Printf("Error polling change events : %v", err)
+		}
+	}
+}
+
+// Poll sends the events committed since the last poll to the subscribers.
+func (h *Hub) Poll(ctx context.Context) error {
+	h.mu.Lock()
+	cursor := h.cursor
+	h.mu.Unlock()
+
+	// only Poll moves the cursor, so it is still current after the read
+	events, err := h.store.ListEventsSince(ctx, cursor, pollBatch)
+	if err != nil {
+		return err
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	now := time.Now()
+	for _, event := range events {
+		if event.Seq != h.cursor+1 {
+			if h.gapSince.IsZero() {
+				h.gapSince = now
+			}
+			if now.Sub(h.gapSince) < gapTimeout {
+				// wait for the missing events, they may still commit
+				break
+			}
+		}
+
+		h.gapSince = time.Time{}
+		h.cursor = event.Seq
+
+		for sub := range h.subscribers {
+			sub.send(event)
+		}
+	}
+
+	return nil
+}
+
+// Subscribe implements service.ChangeStream.
+func (h *Hub) Subscribe(ctx context.Context, filter models.EventFilter, lastSeq int64) (service.Subscription, error) {
+	tracer := otel.Tracer("EventHub")
+	ctx, span := tracer.Start(ctx, "Subscribe-Hub")
+	defer span.End()
+
+	// the cursor cannot move while the backlog is read, so the backlog and
+	// the live events join up without a gap or a duplicate
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	var backlog []models.ChangeEvent
+	for after := lastSeq; after >= 0 && after < h.cursor; {
+		events, err := h.store.ListEventsSince(ctx, after, pollBatch)
+		if err != nil {
+			return nil, err
+		}
+		if len(events) == 0 {
+			break
+		}
+
+		for _, event := range events {
+			if event.Seq > h.cursor {
+				break
+			}
+			if filter.Match(event) {
+				backlog = append(backlog, event)
+			}
+		}
+		after = events[len(events)-1].Seq
+	}
+
+	sub := &subscription{
+		hub:    h,
+		filter: filter,
+		after:  lastSeq,
+		events: make(chan models.ChangeEvent, len(backlog)+subscriberBuffer),
+	}
+	for _, event := range backlog {
+		sub.events <- event
+	}
+
+	h.subscribers[sub] = struct{}{}
+
+	return sub, nil
+}
+
+// closeAll ends every subscription, e.g. on shutdown.
+func (h *Hub) closeAll() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for sub := range h.subscribers {
+		sub.drop()
+	}
+}
+
+// subscription is a service.Subscription. Its fields are guarded by the
+// hub's mutex.
+type subscription struct {
+	hub    *Hub
+	filter models.EventFilter
+	// after skips the events a client resuming from another instance, one
+	// that polled sooner than this one, already has
+	after  int64
+	events chan models.ChangeEvent
+}
+
+func (s *subscription) Events() <-chan models.ChangeEvent {
+	return s.events
+}
+
+func (s *subscription) Close() {
+	s.hub.mu.Lock()
+	defer s.hub.mu.Unlock()
+
+	if _, ok := s.hub.subscribers[s]; ok {
+		s.drop()
+	}
+}
+
+// send queues event for the subscriber, dropping a subscriber that is too
+// far behind rather than holding up the others.
+func (s *subscription) send(event models.ChangeEvent) {
+	if event.Seq <= s.after || !s.filter.Match(event) {
+		return
+	}
+
+	select {
+	case s.events <- event:
+	default:
+		s.drop()
+	}
+}
+
+func (s *subscription) drop() {
+	delete(s.hub.subscribers, s)
+	close(s.events)
+}
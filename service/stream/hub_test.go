@@ -0,0 +1,207 @@
+package stream
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/geekAshish/DriveDesk/models"
+	"github.com/geekAshish/DriveDesk/service"
+	"github.com/geekAshish/DriveDesk/store/memory"
+	"github.com/google/uuid"
+)
+
+func newHub(t *testing.T) *Hub {
+	t.Helper()
+
+	hub := NewHub(memory.NewEventStore(memory.NewDB()))
+	if err := hub.Start(context.Background()); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	return hub
+}
+
+func publishCar(t *testing.T, hub *Hub, eventType, brand string) {
+	t.Helper()
+
+	car := models.Car{ID: uuid.New(), Brand: brand}
+	if err := hub.Publish(context.Background(), models.NewEvent(eventType, car.ID.String(), car)); err != nil {
+		t.Fatalf("Publish: %v", err)
+	}
+}
+
+func publishEngine(t *testing.T, hub *Hub) {
+	t.Helper()
+
+	engine := models.Engine{EngineID: uuid.New()}
+	if err := hub.Publish(context.Background(), models.NewEvent(models.EventEngineCreated, engine.EngineID.String(), engine)); err != nil {
+		t.Fatalf("Publish: %v", err)
+	}
+}
+
+func poll(t *testing.T, hub *Hub) {
+	t.Helper()
+
+	if err := hub.Poll(context.Background()); err != nil {
+		t.Fatalf("Poll: %v", err)
+	}
+}
+
+func subscribe(t *testing.T, hub *Hub, filter models.EventFilter, lastSeq int64) service.Subscription {
+	t.Helper()
+
+	sub, err := hub.Subscribe(context.Background(), filter, lastSeq)
+	if err != nil {
+		t.Fatalf("Subscribe: %v", err)
+	}
+	t.Cleanup(sub.Close)
+	return sub
+}
+
+// received drains the events queued for sub.
+func received(sub service.Subscription) []models.ChangeEvent {
+	var events []models.ChangeEvent
+	for {
+		select {
+		case event, ok := <-sub.Events():
+			if !ok {
+				return events
+			}
+			events = append(events, event)
+		default:
+			return events
+		}
+	}
+}
+
+func seqs(events []models.ChangeEvent) []int64 {
+	seqs := []int64{}
+	for _, event := range events {
+		seqs = append(seqs, event.Seq)
+	}
+	return seqs
+}
+
+func TestHubFilters(t *testing.T) {
+	hub := newHub(t)
+
+	all := subscribe(t, hub, models.EventFilter{}, Live)
+	honda := subscribe(t, hub, models.EventFilter{Brand: "honda"}, Live)
+	engines := subscribe(t, hub, models.EventFilter{Entity: models.EntityEngine}, Live)
+
+	publishCar(t, hub, models.EventCarCreated, "Honda")
+	publishCar(t, hub, models.EventCarUpdated, "Toyota")
+	publishEngine(t, hub)
+
+	if got := received(all); len(got) != 0 {
+		t.Fatalf("events before the poll = %v, want none", seqs(got))
+	}
+
+	poll(t, hub)
+
+	if got := seqs(received(all)); len(got) != 3 {
+		t.Errorf("unfiltered subscriber got %v, want every event", got)
+	}
+	if got := received(honda); len(got) != 1 || got[0].Brand != "Honda" || got[0].Type != models.EventCarCreated {
+		t.Errorf("brand subscriber got %+v, want the Honda car", got)
+	}
+	if got := received(engines); len(got) != 1 || got[0].Entity() != models.EntityEngine {
+		t.Errorf("engine subscriber got %+v, want the engine", got)
+	}
+}
+
+func TestHubResume(t *testing.T) {
+	hub := newHub(t)
+
+	for range 3 {
+		publishCar(t, hub, models.EventCarCreated, "Honda")
+	}
+	poll(t, hub)
+
+	// a fourth event is committed but not yet polled
+	publishCar(t, hub, models.EventCarDeleted, "Honda")
+
+	sub := subscribe(t, hub, models.EventFilter{}, 1)
+	if got := seqs(received(sub)); len(got) != 2 || got[0] != 2 || got[1] != 3 {
+		t.Fatalf("backlog = %v, want [2 3]", got)
+	}
+
+	poll(t, hub)
+	if got := seqs(received(sub)); len(got) != 1 || got[0] != 4 {
+		t.Errorf("live events = %v, want [4]", got)
+	}
+
+	// a client that already saw event 5 on a faster instance does not get it twice
+	publishCar(t, hub, models.EventCarCreated, "Honda")
+	publishCar(t, hub, models.EventCarCreated, "Honda")
+	ahead := subscribe(t, hub, models.EventFilter{}, 5)
+	poll(t, hub)
+	if got := seqs(received(ahead)); len(got) != 1 || got[0] != 6 {
+		t.Errorf("events after resuming ahead = %v, want [6]", got)
+	}
+}
+
+func TestHubDropsSlowSubscriber(t *testing.T) {
+	hub := newHub(t)
+	sub := subscribe(t, hub, models.EventFilter{}, Live)
+
+	for range subscriberBuffer + 1 {
+		publishEngine(t, hub)
+	}
+	poll(t, hub)
+
+	if got := received(sub); len(got) != subscriberBuffer {
+		t.Fatalf("slow subscriber got %d events, want %d", len(got), subscriberBuffer)
+	}
+	if _, ok := <-sub.Events(); ok {
+		t.Error("slow subscriber was not dropped")
+	}
+}
+
+// gappyStore returns events with a hole in their sequence numbers, as
+// Postgres does while a transaction holding a lower number is open.
+type gappyStore struct {
+	*memory.EventStore
+	events []models.ChangeEvent
+}
+
+func (s *gappyStore) ListEventsSince(ctx context.Context, after int64, limit int) ([]models.ChangeEvent, error) {
+	var events []models.ChangeEvent
+	for _, event := range s.events {
+		if event.Seq > after {
+			events = append(events, event)
+		}
+	}
+	return events, nil
+}
+
+func TestHubWaitsForGaps(t *testing.T) {
+	store := &gappyStore{events: []models.ChangeEvent{{Seq: 1, Type: models.EventCarCreated}, {Seq: 3, Type: models.EventCarCreated}}}
+	hub := NewHub(store)
+	sub := subscribe(t, hub, models.EventFilter{}, Live)
+
+	poll(t, hub)
+	if got := seqs(received(sub)); len(got) != 1 || got[0] != 1 {
+		t.Fatalf("events with a gap = %v, want [1]", got)
+	}
+
+	// the missing event commits
+	store.events = []models.ChangeEvent{store.events[0], {Seq: 2, Type: models.EventCarCreated}, store.events[1]}
+	poll(t, hub)
+	if got := seqs(received(sub)); len(got) != 2 || got[0] != 2 || got[1] != 3 {
+		t.Fatalf("events once the gap filled = %v, want [2 3]", got)
+	}
+
+	// a gap that never fills is skipped after gapTimeout
+	store.events = append(store.events, models.ChangeEvent{Seq: 5, Type: models.EventCarCreated})
+	poll(t, hub)
+	if got := received(sub); len(got) != 0 {
+		t.Fatalf("events during the gap = %v, want none", seqs(got))
+	}
+
+	hub.gapSince = time.Now().Add(-gapTimeout)
+	poll(t, hub)
+	if got := seqs(received(sub)); len(got) != 1 || got[0] != 5 {
+		t.Errorf("events after the gap timed out = %v, want [5]", got)
+	}
+}
@@ -0,0 +1,294 @@
+// Package sync lets offline clients exchange deltas with the server. The
+// Recorder is one of the car and engine services' EventPublishers: it gives
+// every changed car or engine the next version of a counter they all share,
+// in the transaction of the change. A client pulls the changes above the
+// highest version it has seen, and pushes its own changes with the version
+// they were based on, so a change made on top of a stale copy is reported as
+// a conflict instead of overwriting the newer one.
+package sync
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/geekAshish/DriveDesk/apperrors"
+	"github.com/geekAshish/DriveDesk/models"
+	"github.com/geekAshish/DriveDesk/service"
+	"github.com/geekAshish/DriveDesk/store"
+	"go.opentelemetry.io/otel"
+)
+
+type Recorder struct {
+	store store.SyncStoreInterface
+}
+
+func NewRecorder(store store.SyncStoreInterface) *Recorder {
+	return &Recorder{store: store}
+}
+
+func (r *Recorder) Publish(ctx context.Context, event models.Event) error {
+	tracer := otel.Tracer("SyncRecorder")
+	ctx, span := tracer.Start(ctx, "Publish-SyncRecorder")
+	defer span.End()
+
+	entity, action, _ := strings.Cut(event.Type, ".")
+
+	row := models.SyncRow{Entity: entity, ID: event.Subject, Deleted: action == "deleted", UpdateAt: event.Time}
+	if car, ok := event.Data.(models.Car); ok {
+		row.ParentID = car.Engine.EngineID.String()
+	}
+
+	if _, err := r.store.RecordSyncChange(ctx, row); err != nil {
+		return err
+	}
+
+	// the cars of a deleted engine have events of their own, but a car
+	// added while the engine was being deleted goes with it without one
+	if event.Type == models.EventEngineDeleted {
+		return r.store.DeleteSyncChildren(ctx, models.EntityCar, event.Subject)
+	}
+
+	return nil
+}
+
+type SyncService struct {
+	store   store.SyncStoreInterface
+	tx      store.TxManagerInterface
+	cars    service.CarServiceInterface
+	engines service.EngineServiceInterface
+}
+
+// NewSyncService applies pushed changes through cars and engines, so they
+// are validated and published like any other change. Their events must reach
+// a Recorder on store.
+func NewSyncService(store store.SyncStoreInterface, tx store.TxManagerInterface, cars service.CarServiceInterface, engines service.EngineServiceInterface) *SyncService {
+	return &SyncService{store: store, tx: tx, cars: cars, engines: engines}
+}
+
+func (s *SyncService) Pull(ctx context.Context, since int64, limit int) (*models.SyncPage, error) {
+	tracer := otel.Tracer("SyncService")
+	ctx, span := tracer.Start(ctx, "Pull-Service")
+	defer span.End()
+
+	if err := models.ValidateSyncLimit(limit); err != nil {
+		return nil, err
+	}
+
+	rows, err := s.store.ListSyncRows(ctx, since, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	engines, err := s.liveEngines(ctx, rows)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &models.SyncPage{Changes: make([]models.SyncItem, 0, len(rows)), Token: models.SyncToken(since), HasMore: len(rows) == limit}
+	for _, row := range rows {
+		item := tombstone(row)
+
+		switch {
+		case row.Deleted:
+		case row.Entity == models.EntityEngine:
+			if engine, ok := engines[row.ID]; ok {
+				item = models.SyncItem{Entity: row.Entity, ID: row.ID, Version: row.Version, Engine: &engine}
+			}
+		default:
+			item, err = s.item(ctx, row)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		page.Changes = append(page.Changes, item)
+		page.Token = models.SyncToken(row.Version)
+	}
+
+	return page, nil
+}
+
+func (s *SyncService) Push(ctx context.Context, req *models.SyncPushRequest) (*models.SyncPushResponse, error) {
+	tracer := otel.Tracer("SyncService")
+	ctx, span := tracer.Start(ctx, "Push-Service")
+	defer span.End()
+
+	if err := models.ValidateSyncPush(*req); err != nil {
+		return nil, err
+	}
+
+	resp := &models.SyncPushResponse{Results: make([]models.SyncResult, 0, len(req.Changes))}
+	for _, change := range req.Changes {
+		result, err := s.apply(ctx, change)
+		if err != nil {
+			return nil, err
+		}
+
+		resp.Results = append(resp.Results, result)
+	}
+
+	return resp, nil
+}
+
+// apply applies one change in a transaction of its own, so the changes
+// before it stay applied when it fails. Changes the client can fix are
+// rejected rather than failing the whole push.
+func (s *SyncService) apply(ctx context.Context, change models.SyncChange) (models.SyncResult, error) {
+	result := models.SyncResult{Status: models.SyncApplied, Entity: change.Entity, ID: change.ID}
+
+	err := s.tx.WithTx(ctx, func(ctx context.Context) error {
+		if change.ID == "" {
+			id, err := s.create(ctx, change)
+			if err != nil {
+				return err
+			}
+			result.ID = id
+		} else {
+			// the row stays locked until the change is committed, so no
+			// other change can slip in after the version check
+			row, err := s.store.GetSyncRow(ctx, change.Entity, change.ID)
+			if err != nil {
+				return err
+			}
+
+			if row.Version != change.BaseVersion || row.Deleted {
+				current, err := s.item(ctx, row)
+				if err != nil {
+					return err
+				}
+
+				result.Status = models.SyncConflict
+				result.Version = row.Version
+				result.Current = &current
+				return nil
+			}
+
+			if err := s.change(ctx, change); err != nil {
+				return err
+			}
+		}
+
+		row, err := s.store.GetSyncRow(ctx, change.Entity, result.ID)
+		if err != nil {
+			return err
+		}
+		result.Version = row.Version
+
+		return nil
+	})
+
+	if err != nil {
+		if !rejected(err) {
+			return models.SyncResult{}, err
+		}
+
+		return models.SyncResult{
+			Status: models.SyncRejected,
+			Entity: change.Entity,
+			ID:     change.ID,
+			Error:  apperrors.Message(err),
+			Fields: apperrors.Fields(err),
+		}, nil
+	}
+
+	return result, nil
+}
+
+func (s *SyncService) create(ctx context.Context, change models.SyncChange) (string, error) {
+	if change.Entity == models.EntityCar {
+		car, err := s.cars.CreateCar(ctx, change.Car)
+		if err != nil {
+			return "", err
+		}
+		return car.ID.String(), nil
+	}
+
+	engine, err := s.engines.CreateEngine(ctx, change.Engine)
+	if err != nil {
+		return "", err
+	}
+	return engine.EngineID.String(), nil
+}
+
+func (s *SyncService) change(ctx context.Context, change models.SyncChange) error {
+	var err error
+
+	switch {
+	case change.Entity == models.EntityCar && change.Op == models.SyncDelete:
+		_, err = s.cars.DeleteCar(ctx, change.ID)
+	case change.Entity == models.EntityCar:
+		_, err = s.cars.UpdateCar(ctx, change.ID, change.Car)
+	case change.Op == models.SyncDelete:
+		_, err = s.engines.DeleteEngine(ctx, change.ID)
+	default:
+		_, err = s.engines.UpdateEngine(ctx, change.ID, change.Engine)
+	}
+
+	return err
+}
+
+// item returns the car or engine of row as it is now, or its tombstone when
+// it was deleted.
+func (s *SyncService) item(ctx context.Context, row models.SyncRow) (models.SyncItem, error) {
+	if row.Deleted {
+		return tombstone(row), nil
+	}
+
+	item := models.SyncItem{Entity: row.Entity, ID: row.ID, Version: row.Version}
+
+	var err error
+	if row.Entity == models.EntityCar {
+		item.Car, err = s.cars.GetCarById(ctx, row.ID)
+	} else {
+		item.Engine, err = s.engines.GetEngineById(ctx, row.ID)
+	}
+
+	// deleted since the row was read; its tombstone comes with a later version
+	if errors.Is(err, apperrors.ErrNotFound) {
+		return tombstone(row), nil
+	}
+
+	return item, err
+}
+
+// liveEngines fetches the engines of rows that are not tombstones in one go.
+func (s *SyncService) liveEngines(ctx context.Context, rows []models.SyncRow) (map[string]models.Engine, error) {
+	var ids []string
+	for _, row := range rows {
+		if row.Entity == models.EntityEngine && !row.Deleted {
+			ids = append(ids, row.ID)
+		}
+	}
+
+	engines := map[string]models.Engine{}
+	if len(ids) == 0 {
+		return engines, nil
+	}
+
+	found, err := s.engines.GetEnginesByIds(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, engine := range found {
+		engines[engine.EngineID.String()] = engine
+	}
+
+	return engines, nil
+}
+
+// rejected reports whether err is about the change itself, and so only
+// rejects it, rather than a failure of the server.
+func rejected(err error) bool {
+	for _, kind := range []error{apperrors.ErrBadRequest, apperrors.ErrNotFound, apperrors.ErrValidation, apperrors.ErrConflict} {
+		if errors.Is(err, kind) {
+			return true
+		}
+	}
+	return false
+}
+
+func tombstone(row models.SyncRow) models.SyncItem {
+	return models.SyncItem{Entity: row.Entity, ID: row.ID, Version: row.Version, Deleted: true}
+}
@@ -0,0 +1,218 @@
+package sync
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/geekAshish/DriveDesk/models"
+	"github.com/geekAshish/DriveDesk/service"
+	"github.com/geekAshish/DriveDesk/store/memory"
+
+	carService "github.com/geekAshish/DriveDesk/service/car"
+	engineService "github.com/geekAshish/DriveDesk/service/engine"
+	referenceService "github.com/geekAshish/DriveDesk/service/reference"
+	ruleService "github.com/geekAshish/DriveDesk/service/rule"
+)
+
+type fixture struct {
+	sync    *SyncService
+	cars    service.CarServiceInterface
+	engines service.EngineServiceInterface
+}
+
+func newFixture(t *testing.T) *fixture {
+	t.Helper()
+
+	db := memory.NewDB()
+	tx := memory.NewTxManager(db)
+	store := memory.NewSyncStore(db)
+	rules := ruleService.NewValidationRuleService(memory.NewValidationRuleStore(db), time.Minute)
+	references := referenceService.NewReferenceService(memory.NewReferenceStore(db), tx, time.Minute)
+
+	recorder := NewRecorder(store)
+	engines := engineService.NewEngineService(memory.NewEngineStore(db), memory.NewCarStore(db), tx, rules, recorder)
+	cars := carService.NewCarService(memory.NewCarStore(db), tx, references, rules, recorder)
+
+	return &fixture{sync: NewSyncService(store, tx, cars, engines), cars: cars, engines: engines}
+}
+
+func (f *fixture) createEngine(t *testing.T) *models.Engine {
+	t.Helper()
+
+	engine, err := f.engines.CreateEngine(context.Background(), &models.EngineRequest{Dispacement: 2000, NoOfCylinders: 4, CarRange: 600})
+	if err != nil {
+		t.Fatalf("CreateEngine: %v", err)
+	}
+	return engine
+}
+
+func (f *fixture) createCar(t *testing.T, engine *models.Engine) *models.Car {
+	t.Helper()
+
+	car, err := f.cars.CreateCar(context.Background(), carRequest("Civic", engine))
+	if err != nil {
+		t.Fatalf("CreateCar: %v", err)
+	}
+	return car
+}
+
+func (f *fixture) pull(t *testing.T, since int64) *models.SyncPage {
+	t.Helper()
+
+	page, err := f.sync.Pull(context.Background(), since, models.DefaultSyncLimit)
+	if err != nil {
+		t.Fatalf("Pull: %v", err)
+	}
+	return page
+}
+
+func carRequest(name string, engine *models.Engine) *models.CarRequest {
+	return &models.CarRequest{Name: name, Year: "2020", Brand: "Honda", FuelType: "petrol", Price: 10000, Engine: *engine}
+}
+
+func TestPullReturnsChangesSinceToken(t *testing.T) {
+	f := newFixture(t)
+
+	engine := f.createEngine(t)
+	car := f.createCar(t, engine)
+
+	first := f.pull(t, 0)
+	if len(first.Changes) != 2 || first.Changes[0].Engine == nil || first.Changes[1].Car == nil || first.Changes[1].Car.ID != car.ID {
+		t.Fatalf("first pull = %+v, want the engine then the car", first.Changes)
+	}
+
+	since, err := models.ParseSyncToken(first.Token)
+	if err != nil {
+		t.Fatalf("ParseSyncToken(%q): %v", first.Token, err)
+	}
+
+	if again := f.pull(t, since); len(again.Changes) != 0 || again.Token != first.Token {
+		t.Errorf("pull with nothing new = %+v, want no changes and the same token", again)
+	}
+
+	// deleting the engine takes its car along, and both become tombstones
+	if _, err := f.engines.DeleteEngine(context.Background(), engine.EngineID.String()); err != nil {
+		t.Fatalf("DeleteEngine: %v", err)
+	}
+
+	deleted := f.pull(t, since)
+	if len(deleted.Changes) != 2 {
+		t.Fatalf("pull after delete = %+v, want two tombstones", deleted.Changes)
+	}
+	for _, item := range deleted.Changes {
+		if !item.Deleted || item.Car != nil || item.Engine != nil || item.Version <= since {
+			t.Errorf("change %+v, want a tombstone newer than %d", item, since)
+		}
+	}
+}
+
+func TestPullPages(t *testing.T) {
+	f := newFixture(t)
+
+	for range 3 {
+		f.createEngine(t)
+	}
+
+	page, err := f.sync.Pull(context.Background(), 0, 2)
+	if err != nil {
+		t.Fatalf("Pull: %v", err)
+	}
+	if len(page.Changes) != 2 || !page.HasMore {
+		t.Fatalf("first page = %+v, want two changes and more to come", page)
+	}
+
+	since, _ := models.ParseSyncToken(page.Token)
+	page, err = f.sync.Pull(context.Background(), since, 2)
+	if err != nil {
+		t.Fatalf("Pull: %v", err)
+	}
+	if len(page.Changes) != 1 || page.HasMore {
+		t.Errorf("second page = %+v, want the last change", page)
+	}
+
+	if _, err := f.sync.Pull(context.Background(), 0, models.MaxSyncLimit+1); err == nil {
+		t.Error("Pull accepted a limit above the maximum")
+	}
+}
+
+func TestPushAppliesAndDetectsConflicts(t *testing.T) {
+	f := newFixture(t)
+	ctx := context.Background()
+
+	engine := f.createEngine(t)
+	car := f.createCar(t, engine)
+	base := f.pull(t, 0).Changes[1].Version
+
+	resp, err := f.sync.Push(ctx, &models.SyncPushRequest{Changes: []models.SyncChange{
+		{Entity: models.EntityCar, Op: models.SyncUpsert, ID: car.ID.String(), BaseVersion: base, Car: carRequest("Accord", engine)},
+		// made on the same stale copy, so it loses to the change before it
+		{Entity: models.EntityCar, Op: models.SyncUpsert, ID: car.ID.String(), BaseVersion: base, Car: carRequest("City", engine)},
+		{Entity: models.EntityCar, Op: models.SyncUpsert, Car: carRequest("Jazz", engine)},
+		{Entity: models.EntityEngine, Op: models.SyncUpsert, Engine: &models.EngineRequest{Dispacement: -1, NoOfCylinders: 4, CarRange: 600}},
+	}})
+	if err != nil {
+		t.Fatalf("Push: %v", err)
+	}
+
+	updated, conflict, created, rejected := resp.Results[0], resp.Results[1], resp.Results[2], resp.Results[3]
+
+	if updated.Status != models.SyncApplied || updated.Version <= base {
+		t.Errorf("update = %+v, want it applied with a newer version", updated)
+	}
+
+	if conflict.Status != models.SyncConflict || conflict.Version != updated.Version || conflict.Current == nil || conflict.Current.Car.Name != "Accord" {
+		t.Errorf("stale update = %+v, want a conflict with the updated car", conflict)
+	}
+
+	if created.Status != models.SyncApplied || created.ID == "" || created.Version <= updated.Version {
+		t.Errorf("create = %+v, want it applied with an id and the newest version", created)
+	}
+
+	if rejected.Status != models.SyncRejected || len(rejected.Fields) == 0 {
+		t.Errorf("invalid engine = %+v, want it rejected with its field errors", rejected)
+	}
+
+	if got, err := f.cars.GetCarById(ctx, car.ID.String()); err != nil || got.Name != "Accord" {
+		t.Errorf("GetCarById = %+v, %v, want the car pushed first", got, err)
+	}
+
+	// the rejected change left no version behind
+	if changes := f.pull(t, updated.Version).Changes; len(changes) != 1 || changes[0].ID != created.ID {
+		t.Errorf("pull after push = %+v, want only the created car", changes)
+	}
+}
+
+func TestPushDeleteOfDeletedConflicts(t *testing.T) {
+	f := newFixture(t)
+	ctx := context.Background()
+
+	engine := f.createEngine(t)
+	base := f.pull(t, 0).Changes[0].Version
+
+	deleteEngine := models.SyncChange{Entity: models.EntityEngine, Op: models.SyncDelete, ID: engine.EngineID.String(), BaseVersion: base}
+
+	resp, err := f.sync.Push(ctx, &models.SyncPushRequest{Changes: []models.SyncChange{deleteEngine, deleteEngine}})
+	if err != nil {
+		t.Fatalf("Push: %v", err)
+	}
+
+	if resp.Results[0].Status != models.SyncApplied {
+		t.Errorf("delete = %+v, want it applied", resp.Results[0])
+	}
+	if second := resp.Results[1]; second.Status != models.SyncConflict || second.Current == nil || !second.Current.Deleted {
+		t.Errorf("second delete = %+v, want a conflict with the tombstone", second)
+	}
+}
+
+func TestPushValidatesShape(t *testing.T) {
+	f := newFixture(t)
+
+	_, err := f.sync.Push(context.Background(), &models.SyncPushRequest{Changes: []models.SyncChange{
+		{Entity: "truck", Op: models.SyncUpsert},
+		{Entity: models.EntityCar, Op: models.SyncDelete},
+	}})
+	if err == nil {
+		t.Fatal("Push accepted changes of an unknown entity and a delete without an id")
+	}
+}
@@ -0,0 +1,215 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/geekAshish/DriveDesk/apperrors"
+	"github.com/geekAshish/DriveDesk/models"
+	"github.com/geekAshish/DriveDesk/service"
+	"github.com/geekAshish/DriveDesk/store"
+	"go.opentelemetry.io/otel"
+)
+
+// Headers sent with every delivery. The signature is
+// "sha256=" followed by the hex HMAC-SHA256, keyed with the webhook's secret,
+// of the timestamp header, a dot and the body.
+const (
+	EventHeader     = "X-DriveDesk-Event"
+	DeliveryHeader  = "X-DriveDesk-Delivery"
+	TimestampHeader = "X-DriveDesk-Timestamp"
+	SignatureHeader = "X-DriveDesk-Signature"
+)
+
+// Sign returns the signature header value of body sent at timestamp, a unix
+// time in seconds.
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10) + "."))
+	mac.Write(body)
+
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify reports whether signature is the one Sign gives for body and the
+// timestamp header. Receivers should also reject timestamps too far in the
+// past, so that recorded deliveries cannot be replayed.
+func Verify(secret, timestamp string, body []byte, signature string) bool {
+	seconds, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return false
+	}
+
+	return hmac.Equal([]byte(Sign(secret, seconds, body)), []byte(signature))
+}
+
+// DefaultRetryPolicy retries for a little over a day; a delivery that still
+// fails is dead and waits for a manual redelivery.
+var DefaultRetryPolicy = service.RetryPolicy{
+	MaxAttempts: 10,
+	BaseDelay:   time.Minute,
+	MaxDelay:    6 * time.Hour,
+}
+
+// claimBatch is how many deliveries one DeliverDue call sends at most.
+const claimBatch = 50
+
+// leaseMargin is added to the client timeout when claiming a delivery, so
+// the claim outlives the attempt and no other instance sends it meanwhile.
+const leaseMargin = 30 * time.Second
+
+// Dispatcher sends the pending webhook deliveries. Deliveries are claimed
+// before being sent, so every instance can run a dispatcher.
+type Dispatcher struct {
+	store  store.WebhookStoreInterface
+	client *http.Client
+	policy service.RetryPolicy
+	wake   chan struct{}
+}
+
+// NewDispatcher sends deliveries with client, whose Timeout bounds each
+// attempt.
+func NewDispatcher(store store.WebhookStoreInterface, client *http.Client, policy service.RetryPolicy) *Dispatcher {
+	return &Dispatcher{store: store, client: client, policy: policy, wake: make(chan struct{}, 1)}
+}
+
+// Wake makes Run look for due deliveries now rather than at its next tick,
+// e.g. when the database reports a change that queued some.
+func (d *Dispatcher) Wake() {
+	select {
+	case d.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Run sends the due deliveries every interval, and whenever woken, until
+// ctx is done.
+func (d *Dispatcher) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := d.DeliverDue(ctx); err != nil {
+			log.Printf("Error delivering webhooks : %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-d.wake:
+		}
+	}
+}
+
+// DeliverDue sends the deliveries whose next attempt is due and records the
+// outcome of each. It returns how many it attempted. Deliveries are claimed
+// one at a time, each for as long as its attempt can take, so a slow
+// receiver never lets the claim on the next ones run out before they are
+// sent. A delivery that could not be attempted is logged and retried once
+// its claim runs out.
+func (d *Dispatcher) DeliverDue(ctx context.Context) (int, error) {
+	tracer := otel.Tracer("WebhookDispatcher")
+	ctx, span := tracer.Start(ctx, "DeliverDue-Dispatcher")
+	defer span.End()
+
+	attempted := 0
+
+	for attempted < claimBatch {
+		now := time.Now().UTC()
+
+		due, err := d.store.ClaimWebhookDeliveries(ctx, now, now.Add(d.client.Timeout+leaseMargin), 1)
+		if err != nil {
+			return attempted, err
+		}
+		if len(due) == 0 {
+			break
+		}
+
+		attempted++
+		if err := d.deliver(ctx, due[0]); err != nil {
+			log.Printf("Error delivering webhook delivery %s : %v", due[0].ID, err)
+		}
+	}
+
+	return attempted, nil
+}
+
+// deliver makes one attempt at delivery and stores its outcome.
+func (d *Dispatcher) deliver(ctx context.Context, delivery models.WebhookDelivery) error {
+	webhook, err := d.store.GetWebhook(ctx, delivery.WebhookID.String())
+	if errors.Is(err, apperrors.ErrNotFound) {
+		// deleted while the delivery was claimed, its deliveries went with it
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	delivery.Attempts++
+
+	if !webhook.Active {
+		delivery.Status = models.DeliveryDead
+		delivery.LastStatusCode = 0
+		delivery.LastError = "webhook is inactive"
+	} else {
+		delivery.LastStatusCode, err = d.post(ctx, webhook, delivery)
+		switch {
+		case err == nil:
+			delivery.Status = models.DeliverySucceeded
+			delivery.LastError = ""
+		case delivery.Attempts >= d.policy.MaxAttempts:
+			delivery.Status = models.DeliveryDead
+			delivery.LastError = err.Error()
+		default:
+			delivery.Status = models.DeliveryPending
+			delivery.NextAttemptAt = time.Now().UTC().Add(d.policy.Backoff(delivery.Attempts))
+			delivery.LastError = err.Error()
+		}
+	}
+
+	return d.store.UpdateWebhookDelivery(ctx, delivery)
+}
+
+// post sends delivery to webhook and returns the status code of the
+// receiver, with an error unless it is a 2xx.
+func (d *Dispatcher) post(ctx context.Context, webhook models.Webhook, delivery models.WebhookDelivery) (int, error) {
+	timestamp := time.Now().Unix()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook.URL, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return 0, err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "DriveDesk-Webhooks")
+	req.Header.Set(EventHeader, delivery.EventType)
+	req.Header.Set(DeliveryHeader, delivery.ID.String())
+	req.Header.Set(TimestampHeader, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(SignatureHeader, Sign(webhook.Secret, timestamp, delivery.Payload))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	// drain a little of the body so the connection can be reused
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("receiver answered %s", resp.Status)
+	}
+
+	return resp.StatusCode, nil
+}
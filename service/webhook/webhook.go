@@ -0,0 +1,199 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/geekAshish/DriveDesk/models"
+	"github.com/geekAshish/DriveDesk/store"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel"
+)
+
+// WebhookService manages the webhook subscriptions and their delivery log.
+// It is also an EventPublisher: every event is queued as one pending
+// delivery per subscribed webhook, and the Dispatcher sends them from there.
+// The outbox relay publishes to it through outbox.WebhookSink.
+type WebhookService struct {
+	store store.WebhookStoreInterface
+}
+
+func NewWebhookService(store store.WebhookStoreInterface) *WebhookService {
+	return &WebhookService{store: store}
+}
+
+func (s *WebhookService) ListWebhooks(ctx context.Context) ([]models.Webhook, error) {
+	tracer := otel.Tracer("WebhookService")
+	ctx, span := tracer.Start(ctx, "ListWebhooks-Service")
+	defer span.End()
+
+	return s.store.ListWebhooks(ctx)
+}
+
+func (s *WebhookService) GetWebhook(ctx context.Context, id string) (*models.Webhook, error) {
+	tracer := otel.Tracer("WebhookService")
+	ctx, span := tracer.Start(ctx, "GetWebhook-Service")
+	defer span.End()
+
+	webhook, err := s.store.GetWebhook(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return &webhook, nil
+}
+
+func (s *WebhookService) CreateWebhook(ctx context.Context, req *models.WebhookRequest) (*models.Webhook, error) {
+	tracer := otel.Tracer("WebhookService")
+	ctx, span := tracer.Start(ctx, "CreateWebhook-Service")
+	defer span.End()
+
+	if err := models.ValidateWebhookRequest(req, false); err != nil {
+		return nil, err
+	}
+
+	created, err := s.store.CreateWebhook(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	return &created, nil
+}
+
+func (s *WebhookService) UpdateWebhook(ctx context.Context, id string, req *models.WebhookRequest) (*models.Webhook, error) {
+	tracer := otel.Tracer("WebhookService")
+	ctx, span := tracer.Start(ctx, "UpdateWebhook-Service")
+	defer span.End()
+
+	if err := models.ValidateWebhookRequest(req, true); err != nil {
+		return nil, err
+	}
+
+	updated, err := s.store.UpdateWebhook(ctx, id, req)
+	if err != nil {
+		return nil, err
+	}
+
+	return &updated, nil
+}
+
+func (s *WebhookService) DeleteWebhook(ctx context.Context, id string) (*models.Webhook, error) {
+	tracer := otel.Tracer("WebhookService")
+	ctx, span := tracer.Start(ctx, "DeleteWebhook-Service")
+	defer span.End()
+
+	deleted, err := s.store.DeleteWebhook(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return &deleted, nil
+}
+
+func (s *WebhookService) ListWebhookDeliveries(ctx context.Context, filter models.WebhookDeliveryFilter) ([]models.WebhookDelivery, error) {
+	tracer := otel.Tracer("WebhookService")
+	ctx, span := tracer.Start(ctx, "ListWebhookDeliveries-Service")
+	defer span.End()
+
+	if err := models.ValidateWebhookDeliveryFilter(filter); err != nil {
+		return nil, err
+	}
+
+	return s.store.ListWebhookDeliveries(ctx, filter)
+}
+
+func (s *WebhookService) GetWebhookDelivery(ctx context.Context, id string) (*models.WebhookDelivery, error) {
+	tracer := otel.Tracer("WebhookService")
+	ctx, span := tracer.Start(ctx, "GetWebhookDelivery-Service")
+	defer span.End()
+
+	delivery, err := s.store.GetWebhookDelivery(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return &delivery, nil
+}
+
+// RedeliverWebhookDelivery queues a delivery again with a fresh set of
+// attempts, whether it succeeded, is still pending or is dead.
+func (s *WebhookService) RedeliverWebhookDelivery(ctx context.Context, id string) (*models.WebhookDelivery, error) {
+	tracer := otel.Tracer("WebhookService")
+	ctx, span := tracer.Start(ctx, "RedeliverWebhookDelivery-Service")
+	defer span.End()
+
+	delivery, err := s.store.GetWebhookDelivery(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	delivery.Status = models.DeliveryPending
+	delivery.Attempts = 0
+	delivery.NextAttemptAt = time.Now().UTC()
+
+	if err := s.store.UpdateWebhookDelivery(ctx, delivery); err != nil {
+		return nil, err
+	}
+
+	return s.GetWebhookDelivery(ctx, id)
+}
+
+// payload is the body posted to the webhooks.
+type payload struct {
+	ID       uuid.UUID `json:"id"`
+	Type     string    `json:"type"`
+	Subject  string    `json:"subject"`
+	CreateAt time.Time `json:"created_at"`
+	Data     any       `json:"data"`
+}
+
+// Publish queues event for every active webhook subscribed to its type.
+func (s *WebhookService) Publish(ctx context.Context, event models.Event) error {
+	tracer := otel.Tracer("WebhookService")
+	ctx, span := tracer.Start(ctx, "Publish-Service")
+	defer span.End()
+
+	webhooks, err := s.store.ListWebhooks(ctx)
+	if err != nil {
+		return err
+	}
+
+	body, err := json.Marshal(payload{
+		ID:       event.ID,
+		Type:     event.Type,
+		Subject:  event.Subject,
+		CreateAt: event.Time,
+		Data:     event.Data,
+	})
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().UTC()
+
+	var deliveries []models.WebhookDelivery
+	for _, webhook := range webhooks {
+		if !webhook.Subscribed(event.Type) {
+			continue
+		}
+
+		deliveries = append(deliveries, models.WebhookDelivery{
+			ID:            uuid.New(),
+			WebhookID:     webhook.ID,
+			EventID:       event.ID,
+			EventType:     event.Type,
+			Payload:       body,
+			Status:        models.DeliveryPending,
+			NextAttemptAt: now,
+			CreateAt:      now,
+			UpdateAt:      now,
+		})
+	}
+
+	if len(deliveries) == 0 {
+		return nil
+	}
+
+	return s.store.CreateWebhookDeliveries(ctx, deliveries)
+}
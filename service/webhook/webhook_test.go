@@ -0,0 +1,266 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/geekAshish/DriveDesk/models"
+	"github.com/geekAshish/DriveDesk/service"
+	"github.com/geekAshish/DriveDesk/store"
+	"github.com/geekAshish/DriveDesk/store/memory"
+
+	engineService "github.com/geekAshish/DriveDesk/service/engine"
+	ruleService "github.com/geekAshish/DriveDesk/service/rule"
+)
+
+const testSecret = "0123456789abcdef"
+
+// receiver is a local webhook endpoint that answers with the queued status
+// codes, then 200, and keeps the deliveries it verified.
+type receiver struct {
+	mu       sync.Mutex
+	statuses []int
+	received []map[string]any
+	invalid  int
+}
+
+func (rc *receiver) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+
+	if !Verify(testSecret, r.Header.Get(TimestampHeader), body, r.Header.Get(SignatureHeader)) {
+		rc.invalid++
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	status := http.StatusOK
+	if len(rc.statuses) > 0 {
+		status, rc.statuses = rc.statuses[0], rc.statuses[1:]
+	}
+
+	if status == http.StatusOK {
+		var payload map[string]any
+		_ = json.Unmarshal(body, &payload)
+		payload["event_header"] = r.Header.Get(EventHeader)
+		rc.received = append(rc.received, payload)
+	}
+	w.WriteHeader(status)
+}
+
+type fixture struct {
+	webhooks   *WebhookService
+	engines    *engineService.EngineService
+	dispatcher *Dispatcher
+	receiver   *receiver
+	url        string
+}
+
+func newFixture(t *testing.T, policy service.RetryPolicy) *fixture {
+	t.Helper()
+
+	db := memory.NewDB()
+	store := memory.NewWebhookStore(db)
+	webhooks := NewWebhookService(store)
+	rules := ruleService.NewValidationRuleService(memory.NewValidationRuleStore(db), time.Minute)
+
+	rc := &receiver{}
+	server := httptest.NewServer(rc)
+	t.Cleanup(server.Close)
+
+	return &fixture{
+		webhooks:   webhooks,
+		engines:    engineService.NewEngineService(memory.NewEngineStore(db), memory.NewCarStore(db), memory.NewTxManager(db), rules, webhooks),
+		dispatcher: NewDispatcher(store, &http.Client{Timeout: 5 * time.Second}, policy),
+		receiver:   rc,
+		url:        server.URL,
+	}
+}
+
+func (f *fixture) subscribe(t *testing.T, events ...string) *models.Webhook {
+	t.Helper()
+
+	webhook, err := f.webhooks.CreateWebhook(context.Background(), &models.WebhookRequest{URL: f.url, Events: events, Secret: testSecret})
+	if err != nil {
+		t.Fatalf("CreateWebhook: %v", err)
+	}
+	return webhook
+}
+
+func (f *fixture) createEngine(t *testing.T) *models.Engine {
+	t.Helper()
+
+	engine, err := f.engines.CreateEngine(context.Background(), &models.EngineRequest{Dispacement: 2000, NoOfCylinders: 4, CarRange: 600})
+	if err != nil {
+		t.Fatalf("CreateEngine: %v", err)
+	}
+	return engine
+}
+
+func (f *fixture) deliveries(t *testing.T, status string) []models.WebhookDelivery {
+	t.Helper()
+
+	deliveries, err := f.webhooks.ListWebhookDeliveries(context.Background(), models.WebhookDeliveryFilter{Status: status})
+	if err != nil {
+		t.Fatalf("ListWebhookDeliveries: %v", err)
+	}
+	return deliveries
+}
+
+// noWait retries straight away, so the tests need not sleep.
+var noWait = service.RetryPolicy{MaxAttempts: 3}
+
+func TestDeliverySigned(t *testing.T) {
+	f := newFixture(t, noWait)
+	f.subscribe(t, models.EventEngineCreated)
+	f.subscribe(t, models.EventCarCreated)
+
+	engine := f.createEngine(t)
+
+	if n, err := f.dispatcher.DeliverDue(context.Background()); err != nil || n != 1 {
+		t.Fatalf("DeliverDue = %d, %v, want one delivery", n, err)
+	}
+
+	if f.receiver.invalid != 0 || len(f.receiver.received) != 1 {
+		t.Fatalf("receiver got %d valid and %d invalid deliveries, want one valid", len(f.receiver.received), f.receiver.invalid)
+	}
+
+	got := f.receiver.received[0]
+	if got["type"] != models.EventEngineCreated || got["event_header"] != models.EventEngineCreated || got["subject"] != engine.EngineID.String() {
+		t.Errorf("payload = %v, want the engine.created event of %s", got, engine.EngineID)
+	}
+
+	if succeeded := f.deliveries(t, models.DeliverySucceeded); len(succeeded) != 1 || succeeded[0].Attempts != 1 || succeeded[0].LastStatusCode != http.StatusOK {
+		t.Errorf("succeeded deliveries = %+v, want one after one attempt", succeeded)
+	}
+}
+
+func TestDeliveryRetriedThenDead(t *testing.T) {
+	f := newFixture(t, noWait)
+	f.subscribe(t, models.EventEngineCreated)
+	f.receiver.statuses = []int{http.StatusInternalServerError, http.StatusBadGateway, http.StatusServiceUnavailable}
+
+	f.createEngine(t)
+
+	for range noWait.MaxAttempts {
+		if _, err := f.dispatcher.DeliverDue(context.Background()); err != nil {
+			t.Fatalf("DeliverDue: %v", err)
+		}
+	}
+
+	dead := f.deliveries(t, models.DeliveryDead)
+	if len(dead) != 1 || dead[0].Attempts != 3 || dead[0].LastStatusCode != http.StatusServiceUnavailable {
+		t.Fatalf("dead deliveries = %+v, want one after three attempts", dead)
+	}
+
+	if n, _ := f.dispatcher.DeliverDue(context.Background()); n != 0 {
+		t.Errorf("DeliverDue sent %d dead deliveries", n)
+	}
+
+	redelivered, err := f.webhooks.RedeliverWebhookDelivery(context.Background(), dead[0].ID.String())
+	if err != nil {
+		t.Fatalf("RedeliverWebhookDelivery: %v", err)
+	}
+	if redelivered.Status != models.DeliveryPending || redelivered.Attempts != 0 {
+		t.Errorf("redelivered = %+v, want it pending with no attempts", redelivered)
+	}
+
+	if n, err := f.dispatcher.DeliverDue(context.Background()); err != nil || n != 1 {
+		t.Fatalf("DeliverDue after redelivery = %d, %v", n, err)
+	}
+	if len(f.receiver.received) != 1 {
+		t.Errorf("receiver got %d deliveries, want the redelivered one", len(f.receiver.received))
+	}
+}
+
+func TestDeliveryBacksOff(t *testing.T) {
+	f := newFixture(t, service.RetryPolicy{MaxAttempts: 5, BaseDelay: time.Hour, MaxDelay: 2 * time.Hour})
+	f.subscribe(t, models.EventEngineCreated)
+	f.receiver.statuses = []int{http.StatusInternalServerError}
+
+	f.createEngine(t)
+
+	if _, err := f.dispatcher.DeliverDue(context.Background()); err != nil {
+		t.Fatalf("DeliverDue: %v", err)
+	}
+
+	pending := f.deliveries(t, models.DeliveryPending)
+	if len(pending) != 1 || pending[0].Attempts != 1 || time.Until(pending[0].NextAttemptAt) < 59*time.Minute {
+		t.Fatalf("pending deliveries = %+v, want one waiting an hour", pending)
+	}
+
+	if n, _ := f.dispatcher.DeliverDue(context.Background()); n != 0 {
+		t.Errorf("DeliverDue sent %d deliveries before their backoff", n)
+	}
+}
+
+// claimRecorder records the claims the dispatcher makes.
+type claimRecorder struct {
+	store.WebhookStoreInterface
+	limits []int
+	leases []time.Duration
+}
+
+func (c *claimRecorder) ClaimWebhookDeliveries(ctx context.Context, now, leaseUntil time.Time, limit int) ([]models.WebhookDelivery, error) {
+	c.limits = append(c.limits, limit)
+	c.leases = append(c.leases, leaseUntil.Sub(now))
+	return c.WebhookStoreInterface.ClaimWebhookDeliveries(ctx, now, leaseUntil, limit)
+}
+
+func TestDeliveriesClaimedOneAtATime(t *testing.T) {
+	f := newFixture(t, noWait)
+	for range 3 {
+		f.subscribe(t, models.EventEngineCreated)
+	}
+	f.createEngine(t)
+
+	claims := &claimRecorder{WebhookStoreInterface: f.dispatcher.store}
+	f.dispatcher.store = claims
+
+	if n, err := f.dispatcher.DeliverDue(context.Background()); err != nil || n != 3 {
+		t.Fatalf("DeliverDue = %d, %v, want three deliveries", n, err)
+	}
+
+	// three deliveries and the claim that found nothing left
+	if len(claims.limits) != 4 {
+		t.Fatalf("claims = %v, want one per delivery", claims.limits)
+	}
+	for i, limit := range claims.limits {
+		if limit != 1 || claims.leases[i] != f.dispatcher.client.Timeout+leaseMargin {
+			t.Errorf("claim %d of %d deliveries for %v, want one for a single attempt", i, limit, claims.leases[i])
+		}
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	policy := service.RetryPolicy{MaxAttempts: 10, BaseDelay: time.Minute, MaxDelay: 10 * time.Minute}
+
+	for attempts, want := range map[int]time.Duration{1: time.Minute, 2: 2 * time.Minute, 3: 4 * time.Minute, 4: 8 * time.Minute, 5: 10 * time.Minute, 9: 10 * time.Minute} {
+		if got := policy.Backoff(attempts); got != want {
+			t.Errorf("Backoff(%d) = %v, want %v", attempts, got, want)
+		}
+	}
+}
+
+func TestVerify(t *testing.T) {
+	body := []byte(`{"type":"car.created"}`)
+	signature := Sign(testSecret, 1700000000, body)
+
+	if !Verify(testSecret, "1700000000", body, signature) {
+		t.Error("Verify rejected its own signature")
+	}
+	if Verify(testSecret, "1700000001", body, signature) {
+		t.Error("Verify accepted another timestamp")
+	}
+	if Verify("another-secret-value", "1700000000", body, signature) {
+		t.Error("Verify accepted another secret")
+	}
+}
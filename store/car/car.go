@@ -4,19 +4,26 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"fmt"
 	"time"
 
+	"github.com/geekAshish/DriveDesk/apperrors"
 	"github.com/geekAshish/DriveDesk/models"
+	"github.com/geekAshish/DriveDesk/store"
 	"github.com/google/uuid"
 	"go.opentelemetry.io/otel"
 )
 
+const carColumns = `c.id, c.name, c.year, c.brand, c.fuel_type, c.price, c.vin, c.created_at, c.updated_at,
+	e.id, e.displacement, e.no_of_cylinders, e.car_range, e.created_at, e.updated_at`
+
 type Store struct {
 	db *sql.DB
+	tx *store.TxManager
 }
 
 func New(db *sql.DB) Store {
-	return Store{db: db}
+	return Store{db: db, tx: store.NewTxManager(db)}
 }
 
 func (s Store) GetCarById(ctx context.Context, id string) (models.Car, error) {
@@ -24,63 +31,72 @@ func (s Store) GetCarById(ctx context.Context, id string) (models.Car, error) {
 	ctx, span := tracer.Start(ctx, "GetCarById-Store")
 	defer span.End()
 
-	car := models.Car{}
+	return getCar(ctx, store.Conn(ctx, s.db), id)
+}
 
-	query := `SELECT c.id, c.brand, c.model, c.year, c.color, c.price, c.is_engine c.created_at, c.updated_at e.id e.displacement e.no_of_cylinders e.fuel_type e.car_range FROM cars c LEFT JOIN engines e ON c.engine_id = e.id WHERE c.id = $1`
+func (s Store) GetCarByBrand(ctx context.Context, brand string, isEngine bool) ([]models.Car, error) {
+	tracer := otel.Tracer("CarStore")
+	ctx, span := tracer.Start(ctx, "GetCarByBrand-Store")
+	defer span.End()
 
-	row := s.db.QueryRowContext(ctx, query, id)
+	query := `
+	SELECT ` + carColumns + `
+	FROM car c
+	JOIN engine e ON c.engine_id = e.id
+	WHERE c.brand = $1
+	ORDER BY c.created_at, c.id
+	`
 
-	err := row.Scan(
-		&car.ID,
-		&car.Name,
-		&car.Year,
-		&car.Brand,
-		&car.FuelType,
-		&car.Price,
-		&car.CreateAt,
-		&car.UpdateAt,
+	rows, err := store.Conn(ctx, s.db).QueryContext(ctx, query, brand)
+	if err != nil {
+		return nil, err
+	}
 
-		&car.Engine.EngineID,
-		&car.Engine.CarRange,
-		&car.Engine.Dispacement,
-		&car.Engine.NoOfCylinders,
-	)
+	defer rows.Close()
 
-	if err != nil {
-		if err == sql.ErrNoRows {
-			return car, nil // No car found
+	var cars = []models.Car{}
+
+	for rows.Next() {
+		car, err := scanCar(rows)
+		if err != nil {
+			return nil, err
+		}
 
+		// without isEngine callers only get the engine id, as before
+		if !isEngine {
+			car.Engine = models.Engine{EngineID: car.Engine.EngineID}
 		}
-		return car, err
+
+		cars = append(cars, car)
 	}
 
-	return car, nil
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return cars, nil
 }
 
-func (s Store) GetCarByBrand(ctx context.Context, brand string, isEngine bool) ([]models.Car, error) {
+func (s Store) GetCarsByEngine(ctx context.Context, engineID string) ([]models.Car, error) {
 	tracer := otel.Tracer("CarStore")
-	ctx, span := tracer.Start(ctx, "GetCarByBrand-Store")
+	ctx, span := tracer.Start(ctx, "GetCarsByEngine-Store")
 	defer span.End()
 
 	var cars = []models.Car{}
-	var query string
-
-	if isEngine {
-		query = `
-		SELECT c.id, c.name, c.year, c.brand, c.fuel_type, c.engine_id, c.price, c.created_at, c.updated_at, e.id, e.displacement, e.no_of_cylinders, e.car_range
-		FROM cars c
-		LEFT JOIN engines e ON c.engine_id = e.id
-		WHERE c.brand = $1
-		`
-	} else {
-		query = `
-		SELECT id, name, year, brand, fuel_type, engine_id, price, created_at, updated_at
-		FROM car
-		WHERE brand = $1
-		`
+
+	if !store.ValidID(engineID) {
+		return cars, nil
 	}
 
-	rows, err := s.db.QueryContext(ctx, query, brand)
+	query := `
+	SELECT ` + carColumns + `
+	FROM car c
+	JOIN engine e ON c.engine_id = e.id
+	WHERE c.engine_id = $1
+	ORDER BY c.created_at, c.id
+	`
+
+	rows, err := store.Conn(ctx, s.db).QueryContext(ctx, query, engineID)
 	if err != nil {
 		return nil, err
 	}
@@ -88,46 +104,9 @@ func (s Store) GetCarByBrand(ctx context.Context, brand string, isEngine bool) (
 	defer rows.Close()
 
 	for rows.Next() {
-		var car models.Car
-
-		if isEngine {
-			var engine models.Engine
-			err := rows.Scan(
-				&car.ID,
-				&car.Name,
-				&car.Year,
-				&car.Brand,
-				&car.FuelType,
-				&car.Engine.EngineID,
-				&car.Price,
-				&car.CreateAt,
-				&car.UpdateAt,
-				&car.Engine.Dispacement,
-				&car.Engine.NoOfCylinders,
-				&car.Engine.CarRange,
-			)
-
-			if err != nil {
-				return nil, err
-			}
-
-			car.Engine = engine
-		} else {
-			err = rows.Scan(
-				&car.ID,
-				&car.Name,
-				&car.Year,
-				&car.Brand,
-				&car.FuelType,
-				&car.Engine.EngineID,
-				&car.Price,
-				&car.CreateAt,
-				&car.UpdateAt,
-			)
-
-			if err != nil {
-				return nil, err
-			}
+		car, err := scanCar(rows)
+		if err != nil {
+			return nil, err
 		}
 
 		cars = append(cars, car)
@@ -146,78 +125,47 @@ func (s Store) CreateCar(ctx context.Context, carReq *models.CarRequest) (models
 	defer span.End()
 
 	var createdCar models.Car
-	var engineId uuid.UUID
-
-	err := s.db.QueryRowContext(ctx, `SLECT id FROM engine WHERE id=$1`, carReq.Engine.EngineID).Scan(&engineId)
-	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			return createdCar, errors.New("engine not found")
-		}
-		return createdCar, err
-	}
 
 	carID := uuid.New()
 
 	create_at := time.Now()
 	updated_at := time.Now()
 
-	newCar := models.Car{
-		ID:       carID,
-		Name:     carReq.Name,
-		Year:     carReq.Year,
-		Brand:    carReq.Brand,
-		FuelType: carReq.FuelType,
-		Price:    carReq.Price,
-		Engine:   carReq.Engine,
-		CreateAt: create_at,
-		UpdateAt: updated_at,
-	}
-
-	// begin the transaction , atomic [if we have any error in the middle, we will rollback]
-	tx, err := s.db.BeginTx(ctx, nil)
-	if err != nil {
-		return createdCar, err
-	}
+	// the engine lookup and the insert run in one unit of work, so the engine cannot disappear in between
+	err := s.tx.WithTx(ctx, func(ctx context.Context) error {
+		conn := store.Conn(ctx, s.db)
 
-	defer func() {
-		if err != nil {
-			tx.Rollback()
-			return
+		if err := engineExists(ctx, conn, carReq.Engine.EngineID); err != nil {
+			return err
 		}
 
-		err = tx.Commit()
-	}()
+		query := `
+		INSERT INTO car (id, name, year, brand, fuel_type, engine_id, price, vin, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		`
 
-	query := `
-	INSERT INTO cars (id, name, year, brand, fuel_type, price, engine_id, created_at, updated_at)
-	VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
-	RETURNING id name year brand fuel_type price created_at updated_at
-	`
+		_, err := conn.ExecContext(ctx, query,
+			carID,
+			carReq.Name,
+			carReq.Year,
+			carReq.Brand,
+			carReq.FuelType,
+			carReq.Engine.EngineID,
+			carReq.Price,
+			store.NullString(carReq.VIN),
+			create_at,
+			updated_at,
+		)
+		if err != nil {
+			return carError(err, carReq)
+		}
 
-	err = tx.QueryRowContext(ctx, query,
-		newCar.ID,
-		newCar.Name,
-		newCar.Year,
-		newCar.Brand,
-		newCar.FuelType,
-		newCar.Engine.EngineID,
-		newCar.Price,
-		newCar.CreateAt,
-		newCar.UpdateAt,
-	).Scan(
-		&createdCar.ID,
-		&createdCar.Name,
-		&createdCar.Year,
-		&createdCar.Brand,
-		&createdCar.FuelType,
-		&createdCar.Engine.EngineID,
-		&createdCar.Price,
-		&createdCar.CreateAt,
-		&createdCar.UpdateAt,
-	)
+		createdCar, err = getCar(ctx, conn, carID.String())
+		return err
+	})
 
 	if err != nil {
-		return createdCar, err
+		return models.Car{}, err
 	}
 
 	return createdCar, nil
@@ -230,51 +178,49 @@ func (s Store) UpdateCar(ctx context.Context, id string, carReq *models.CarReque
 
 	var updatedCar models.Car
 
-	// begin the transaction , atomic [if we have any error in the middle, we will rollback]
-	tx, err := s.db.BeginTx(ctx, nil)
-	if err != nil {
-		return updatedCar, err
-	}
+	err := s.tx.WithTx(ctx, func(ctx context.Context) error {
+		conn := store.Conn(ctx, s.db)
 
-	defer func() {
+		if err := engineExists(ctx, conn, carReq.Engine.EngineID); err != nil {
+			return err
+		}
+
+		query := `
+		UPDATE car
+		SET name = $2, year = $3, brand = $4, fuel_type = $5, price = $6, engine_id = $7, vin = $8, updated_at = $9
+		WHERE id = $1
+		`
+
+		result, err := conn.ExecContext(ctx, query,
+			id,
+			carReq.Name,
+			carReq.Year,
+			carReq.Brand,
+			carReq.FuelType,
+			carReq.Price,
+			carReq.Engine.EngineID,
+			store.NullString(carReq.VIN),
+			time.Now(),
+		)
 		if err != nil {
-			tx.Rollback()
-			return
+			return carError(err, carReq)
 		}
 
-		err = tx.Commit()
-	}()
+		rowsAffected, err := result.RowsAffected()
+		if err != nil {
+			return err
+		}
 
-	query := `
-	UPDATE cars
-	SET name = $2, year = $3, brand = $4, fuel_type = $5, price = $6, engine_id = $7, updated_at = $8
-	WHERE id = $1
-	RETURNING id, name, year, brand, fuel_type, price, created_at, updated_at
-	`
+		if rowsAffected == 0 {
+			return apperrors.NotFound("car not found")
+		}
 
-	err = tx.QueryRowContext(ctx, query,
-		id,
-		carReq.Name,
-		carReq.Year,
-		carReq.Brand,
-		carReq.FuelType,
-		carReq.Engine.EngineID,
-		carReq.Price,
-		time.Now(),
-	).Scan(
-		&updatedCar.ID,
-		&updatedCar.Name,
-		&updatedCar.Year,
-		&updatedCar.Brand,
-		&updatedCar.FuelType,
-		&updatedCar.Engine.EngineID,
-		&updatedCar.Price,
-		&updatedCar.CreateAt,
-		&updatedCar.UpdateAt,
-	)
+		updatedCar, err = getCar(ctx, conn, id)
+		return err
+	})
 
 	if err != nil {
-		return updatedCar, err
+		return models.Car{}, err
 	}
 
 	return updatedCar, nil
@@ -287,58 +233,108 @@ func (s Store) DeleteCar(ctx context.Context, id string) (models.Car, error) {
 
 	var deletedCar models.Car
 
-	// begin the transaction , atomic [if we have any error in the middle, we will rollback]
-	tx, err := s.db.BeginTx(ctx, nil)
-	if err != nil {
-		return deletedCar, err
-	}
+	// the lookup and the delete run in one unit of work, so we return exactly the row we removed
+	err := s.tx.WithTx(ctx, func(ctx context.Context) error {
+		conn := store.Conn(ctx, s.db)
 
-	defer func() {
+		var err error
+		deletedCar, err = getCar(ctx, conn, id)
 		if err != nil {
-			tx.Rollback()
-			return
+			return err
 		}
 
-		err = tx.Commit()
-	}()
+		result, err := conn.ExecContext(ctx, `DELETE FROM car WHERE id = $1`, id)
+		if err != nil {
+			return store.DBError(err)
+		}
 
-	query := `
-	SELECT id, name, year, brand, fuel_type, price, created_at, updated_at
-	FROM car
-	WHERE id = $1
-	`
+		rowsAffected, err := result.RowsAffected()
+		if err != nil {
+			return err
+		}
 
-	err = tx.QueryRowContext(ctx, query, id).Scan(
-		&deletedCar.ID,
-		&deletedCar.Name,
-		&deletedCar.Year,
-		&deletedCar.Brand,
-		&deletedCar.FuelType,
-		&deletedCar.Price,
-		&deletedCar.CreateAt,
-		&deletedCar.UpdateAt,
-	)
+		if rowsAffected == 0 {
+			return apperrors.NotFound("car not found")
+		}
+
+		return nil
+	})
 
 	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			return models.Car{}, errors.New("car not found")
-		}
 		return models.Car{}, err
 	}
 
-	result, err := tx.ExecContext(ctx, `DELETE FROM cars WHERE id = $1`, id)
-	if err != nil {
-		return models.Car{}, err
+	return deletedCar, nil
+}
+
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+func getCar(ctx context.Context, conn store.DBTX, id string) (models.Car, error) {
+	if !store.ValidID(id) {
+		return models.Car{}, apperrors.NotFound("car not found")
 	}
 
-	rowsAffected, err := result.RowsAffected()
+	query := `SELECT ` + carColumns + ` FROM car c JOIN engine e ON c.engine_id = e.id WHERE c.id = $1`
+
+	car, err := scanCar(conn.QueryRowContext(ctx, query, id))
 	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.Car{}, apperrors.NotFound("car not found")
+		}
 		return models.Car{}, err
 	}
 
-	if rowsAffected == 0 {
-		return models.Car{}, errors.New("no car deleted")
+	return car, nil
+}
+
+func scanCar(row scanner) (models.Car, error) {
+	var car models.Car
+	var vin sql.NullString
+
+	err := row.Scan(
+		&car.ID,
+		&car.Name,
+		&car.Year,
+		&car.Brand,
+		&car.FuelType,
+		&car.Price,
+		&vin,
+		&car.CreateAt,
+		&car.UpdateAt,
+		&car.Engine.EngineID,
+		&car.Engine.Dispacement,
+		&car.Engine.NoOfCylinders,
+		&car.Engine.CarRange,
+		&car.Engine.CreateAt,
+		&car.Engine.UpdateAt,
+	)
+	car.VIN = vin.String
+
+	return car, err
+}
+
+// carError reports a clash on the unique VIN as such rather than as a
+// generic conflict.
+func carError(err error, carReq *models.CarRequest) error {
+	err = store.DBError(err)
+	if errors.Is(err, apperrors.ErrConflict) {
+		return apperrors.Wrap(apperrors.ErrConflict, err, "a car with VIN %s already exists", carReq.VIN)
+	}
+	return err
+}
+
+func engineExists(ctx context.Context, conn store.DBTX, engineID uuid.UUID) error {
+	var id uuid.UUID
+
+	err := conn.QueryRowContext(ctx, `SELECT id FROM engine WHERE id = $1`, engineID).Scan(&id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return apperrors.InvalidField("engine.engine_id", "not_found", fmt.Sprintf("engine %s does not exist", engineID))
+		}
+		return err
 	}
 
-	return deletedCar, nil
+	return nil
 }
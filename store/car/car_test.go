@@ -0,0 +1,71 @@
+package car_test
+
+import (
+	"context"
+	"database/sql"
+	"os"
+	"testing"
+
+	_ "github.com/lib/pq"
+
+	"github.com/geekAshish/DriveDesk/store"
+	"github.com/geekAshish/DriveDesk/store/car"
+	"github.com/geekAshish/DriveDesk/store/engine"
+	"github.com/geekAshish/DriveDesk/store/event"
+	"github.com/geekAshish/DriveDesk/store/idempotency"
+	"github.com/geekAshish/DriveDesk/store/migrations"
+	"github.com/geekAshish/DriveDesk/store/outbox"
+	"github.com/geekAshish/DriveDesk/store/ratelimit"
+	"github.com/geekAshish/DriveDesk/store/reference"
+	"github.com/geekAshish/DriveDesk/store/rule"
+	"github.com/geekAshish/DriveDesk/store/storetest"
+	"github.com/geekAshish/DriveDesk/store/sync"
+	"github.com/geekAshish/DriveDesk/store/webhook"
+)
+
+// TestConformance runs the store suite against the Postgres car, engine,
+// reference and validation rule stores. It needs a disposable database, e.g.
+//
+//	TEST_POSTGRES_DSN="host=localhost user=postgres password=12345 dbname=drivedesk_test sslmode=disable" go test ./store/car
+func TestConformance(t *testing.T) {
+	dsn := os.Getenv("TEST_POSTGRES_DSN")
+	if dsn == "" {
+		t.Skip("TEST_POSTGRES_DSN not set")
+	}
+
+	db, err := sql.Open("postgres", dsn)
+	if err != nil {
+		t.Fatalf("opening postgres: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	migrator, err := migrations.NewPostgres(db)
+	if err != nil {
+		t.Fatalf("loading migrations: %v", err)
+	}
+	if _, err := migrator.Up(context.Background()); err != nil {
+		t.Fatalf("applying migrations: %v", err)
+	}
+
+	storetest.Run(t, func(t *testing.T) storetest.Stores {
+		// reference data tests only write kinds other than fuel_type, so the seeded fuel types survive
+		if _, err := db.Exec(`TRUNCATE car, engine, validation_rule, idempotency_key, webhook_delivery, webhook, change_event, outbox, sync_row, rate_limit_bucket RESTART IDENTITY; DELETE FROM reference_value WHERE kind <> 'fuel_type'`); err != nil {
+			t.Fatalf("emptying tables: %v", err)
+		}
+
+		return storetest.Stores{
+			Car:       car.New(db),
+			Engine:    engine.New(db),
+			Tx:        store.NewTxManager(db),
+			Reference: reference.New(db),
+			Rule:      rule.New(db),
+
+			Idempotency: idempotency.New(db),
+			Webhook:     webhook.New(db),
+			Event:       event.New(db),
+			Outbox:      outbox.New(db),
+			Sync:        sync.New(db),
+			RateLimit:   ratelimit.New(db),
+		}
+	})
+}
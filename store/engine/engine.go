@@ -3,20 +3,23 @@ package engine
 import (
 	"context"
 	"database/sql"
-	"errors"
-	"fmt"
+	"time"
 
+	"github.com/geekAshish/DriveDesk/apperrors"
 	"github.com/geekAshish/DriveDesk/models"
+	"github.com/geekAshish/DriveDesk/store"
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 	"go.opentelemetry.io/otel"
 )
 
 type EnginStore struct {
 	db *sql.DB
+	tx *store.TxManager
 }
 
 func New(db *sql.DB) *EnginStore {
-	return &EnginStore{db: db}
+	return &EnginStore{db: db, tx: store.NewTxManager(db)}
 }
 
 func (e EnginStore) GetEngineById(ctx context.Context, id string) (models.Engine, error) {
@@ -24,43 +27,53 @@ func (e EnginStore) GetEngineById(ctx context.Context, id string) (models.Engine
 	ctx, span := tracer.Start(ctx, "GetEngineById-Store")
 	defer span.End()
 
-	var engine models.Engine
+	return getEngine(ctx, store.Conn(ctx, e.db), id)
+}
 
-	// begin the transaction , atomic [if we have any error in the middle, we will rollback]
-	tx, err := e.db.BeginTx(ctx, nil)
-	if err != nil {
-		return engine, err
-	}
+func (e EnginStore) GetEnginesByIds(ctx context.Context, ids []string) ([]models.Engine, error) {
+	tracer := otel.Tracer("EngineStore")
+	ctx, span := tracer.Start(ctx, "GetEnginesByIds-Store")
+	defer span.End()
 
-	defer func() {
-		if err != nil {
-			if rbErr := tx.Rollback(); rbErr != nil {
-				fmt.Printf("transaction rollback error: %v/n", rbErr)
-			}
-		} else {
-			if cmErr := tx.Commit(); cmErr != nil {
-				fmt.Printf("transaction commit error: %v/n", cmErr)
-			}
+	engineIDs := make([]string, 0, len(ids))
+	for _, id := range ids {
+		if store.ValidID(id) {
+			engineIDs = append(engineIDs, id)
 		}
+	}
 
-		err = tx.Commit()
-	}()
+	engines := []models.Engine{}
+	if len(engineIDs) == 0 {
+		return engines, nil
+	}
 
-	err = tx.QueryRowContext(ctx, `SELECT id, displacement, no_of_cyclinder, car_range FROM engine WHERE id=$1`, id).Scan(
-		&engine.EngineID,
-		&engine.Dispacement,
-		&engine.NoOfCylinders,
-		&engine.CarRange,
+	rows, err := store.Conn(ctx, e.db).QueryContext(
+		ctx,
+		`SELECT id, displacement, no_of_cylinders, car_range, created_at, updated_at FROM engine WHERE id = ANY($1)`,
+		pq.Array(engineIDs),
 	)
-
 	if err != nil {
-		if err == sql.ErrNoRows {
-			return engine, errors.New("engine with id not found")
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var engine models.Engine
+		err := rows.Scan(
+			&engine.EngineID,
+			&engine.Dispacement,
+			&engine.NoOfCylinders,
+			&engine.CarRange,
+			&engine.CreateAt,
+			&engine.UpdateAt,
+		)
+		if err != nil {
+			return nil, err
 		}
-		return engine, err
+		engines = append(engines, engine)
 	}
 
-	return engine, nil
+	return engines, rows.Err()
 }
 
 func (e EnginStore) CreateEngine(ctx context.Context, engineReq *models.EngineRequest) (models.Engine, error) {
@@ -68,35 +81,17 @@ func (e EnginStore) CreateEngine(ctx context.Context, engineReq *models.EngineRe
 	ctx, span := tracer.Start(ctx, "CreateEngine-Store")
 	defer span.End()
 
-	tx, err := e.db.BeginTx(ctx, nil)
-	if err != nil {
-		return models.Engine{}, err
-	}
-
-	defer func() {
-		if err != nil {
-			if rbErr := tx.Rollback(); rbErr != nil {
-				fmt.Printf("transaction rollback error: %v/n", rbErr)
-			}
-		} else {
-			if cmErr := tx.Commit(); cmErr != nil {
-				fmt.Printf("transaction commit error: %v/n", cmErr)
-			}
-		}
-
-		err = tx.Commit()
-	}()
-
 	engineID := uuid.New()
+	now := time.Now()
 
-	_, err = tx.ExecContext(
+	_, err := store.Conn(ctx, e.db).ExecContext(
 		ctx,
-		`INSERT INTO engine (id, displacement, no_of_cyclinder, car_range) VALUES ($1, $2, $3, $4)`,
-		engineID, engineReq.Dispacement, engineReq.NoOfCylinders, engineReq.CarRange,
+		`INSERT INTO engine (id, displacement, no_of_cylinders, car_range, created_at, updated_at) VALUES ($1, $2, $3, $4, $5, $6)`,
+		engineID, engineReq.Dispacement, engineReq.NoOfCylinders, engineReq.CarRange, now, now,
 	)
 
 	if err != nil {
-		return models.Engine{}, nil
+		return models.Engine{}, store.DBError(err)
 	}
 
 	engine := models.Engine{
@@ -104,6 +99,8 @@ func (e EnginStore) CreateEngine(ctx context.Context, engineReq *models.EngineRe
 		Dispacement:   engineReq.Dispacement,
 		NoOfCylinders: engineReq.NoOfCylinders,
 		CarRange:      engineReq.CarRange,
+		CreateAt:      now,
+		UpdateAt:      now,
 	}
 
 	return engine, nil
@@ -114,57 +111,45 @@ func (e EnginStore) UpdateEngine(ctx context.Context, id string, engineReq *mode
 	ctx, span := tracer.Start(ctx, "UpdateEngine-Store")
 	defer span.End()
 
-	enginID, err := uuid.Parse(id)
-	if err != nil {
-		return models.Engine{}, fmt.Errorf("invalid engine id format: %s", id)
+	if !store.ValidID(id) {
+		return models.Engine{}, apperrors.NotFound("engine not found")
 	}
 
-	tx, err := e.db.BeginTx(ctx, nil)
-	if err != nil {
-		return models.Engine{}, err
-	}
+	var engine models.Engine
+
+	err := e.tx.WithTx(ctx, func(ctx context.Context) error {
+		conn := store.Conn(ctx, e.db)
+
+		result, err := conn.ExecContext(
+			ctx,
+			`UPDATE engine SET displacement=$1, no_of_cylinders=$2, car_range=$3, updated_at=$4 WHERE id=$5`,
+			engineReq.Dispacement,
+			engineReq.NoOfCylinders,
+			engineReq.CarRange,
+			time.Now(),
+			id,
+		)
 
-	defer func() {
 		if err != nil {
-			if rbErr := tx.Rollback(); rbErr != nil {
-				fmt.Printf("transaction rollback error: %v/n", rbErr)
-			}
-		} else {
-			if cmErr := tx.Commit(); cmErr != nil {
-				fmt.Printf("transaction commit error: %v/n", cmErr)
-			}
+			return store.DBError(err)
 		}
 
-		err = tx.Commit()
-	}()
-
-	result, err := tx.ExecContext(
-		ctx,
-		`UPDATE engine SET displacement=$1, no_of_cyclinder=$2, car_range=$3 WHERE id=$4`,
-		engineReq.Dispacement,
-		engineReq.NoOfCylinders,
-		engineReq.CarRange,
-	)
+		rowAffected, err := result.RowsAffected()
 
-	if err != nil {
-		return models.Engine{}, err
-	}
+		if err != nil {
+			return err
+		}
+		if rowAffected == 0 {
+			return apperrors.NotFound("engine not found")
+		}
 
-	rowAffected, err := result.RowsAffected()
+		engine, err = getEngine(ctx, conn, id)
+		return err
+	})
 
 	if err != nil {
 		return models.Engine{}, err
 	}
-	if rowAffected == 0 {
-		return models.Engine{}, errors.New("engine with id not found")
-	}
-
-	engine := models.Engine{
-		EngineID:      enginID,
-		Dispacement:   engineReq.Dispacement,
-		NoOfCylinders: engineReq.NoOfCylinders,
-		CarRange:      engineReq.CarRange,
-	}
 
 	return engine, nil
 }
@@ -176,53 +161,64 @@ func (e EnginStore) DeleteEngine(ctx context.Context, id string) (models.Engine,
 
 	var engine models.Engine
 
-	tx, err := e.db.BeginTx(ctx, nil)
+	// the lookup and the delete run in one unit of work, so we return exactly the row we removed
+	err := e.tx.WithTx(ctx, func(ctx context.Context) error {
+		conn := store.Conn(ctx, e.db)
+
+		var err error
+		engine, err = getEngine(ctx, conn, id)
+		if err != nil {
+			return err
+		}
+
+		// cars using this engine go with it through ON DELETE CASCADE
+		result, err := conn.ExecContext(ctx, `DELETE FROM engine WHERE id=$1`, id)
+		if err != nil {
+			return store.DBError(err)
+		}
+
+		rowAffected, err := result.RowsAffected()
+		if err != nil {
+			return err
+		}
+		if rowAffected == 0 {
+			return apperrors.NotFound("engine not found")
+		}
+
+		return nil
+	})
+
 	if err != nil {
 		return models.Engine{}, err
 	}
 
-	defer func() {
-		if err != nil {
-			if rbErr := tx.Rollback(); rbErr != nil {
-				fmt.Printf("transaction rollback error: %v/n", rbErr)
-			}
-		} else {
-			if cmErr := tx.Commit(); cmErr != nil {
-				fmt.Printf("transaction commit error: %v/n", cmErr)
-			}
-		}
+	return engine, nil
+}
 
-		err = tx.Commit()
-	}()
+func getEngine(ctx context.Context, conn store.DBTX, id string) (models.Engine, error) {
+	var engine models.Engine
 
-	err = tx.QueryRowContext(
+	if !store.ValidID(id) {
+		return engine, apperrors.NotFound("engine not found")
+	}
+
+	err := conn.QueryRowContext(
 		ctx,
-		`SELECT id, displacement, no_of_cyclinder, car_range FROM engine WHERE id=$1`,
+		`SELECT id, displacement, no_of_cylinders, car_range, created_at, updated_at FROM engine WHERE id=$1`,
 		id).Scan(
 		&engine.EngineID,
 		&engine.Dispacement,
 		&engine.NoOfCylinders,
 		&engine.CarRange,
+		&engine.CreateAt,
+		&engine.UpdateAt,
 	)
 
 	if err != nil {
 		if err == sql.ErrNoRows {
-			return models.Engine{}, errors.New("engine with id not found")
+			return engine, apperrors.NotFound("engine not found")
 		}
-		return models.Engine{}, err
-	}
-
-	result, err := tx.ExecContext(ctx, `DELETE FROM engine WHERE id=$1`, id)
-	if err != nil {
-		return models.Engine{}, err
-	}
-
-	rowAffected, err := result.RowsAffected()
-	if err != nil {
-		return models.Engine{}, err
-	}
-	if rowAffected == 0 {
-		return models.Engine{}, errors.New("engine with id not found")
+		return engine, err
 	}
 
 	return engine, nil
@@ -0,0 +1,47 @@
+package store
+
+import (
+	"errors"
+
+	"github.com/geekAshish/DriveDesk/apperrors"
+)
+
+const (
+	pgUniqueViolation     = "23505"
+	pgForeignKeyViolation = "23503"
+
+	sqlitePrimaryKeyViolation = 1555
+	sqliteUniqueViolation     = 2067
+	sqliteForeignKeyViolation = 787
+)
+
+// DBError turns constraint violations reported by Postgres or SQLite into
+// apperrors kinds and returns any other error unchanged. Drivers are matched
+// through their error methods so the store package does not import them.
+func DBError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	var pgErr interface{ SQLState() string }
+	if errors.As(err, &pgErr) {
+		switch pgErr.SQLState() {
+		case pgUniqueViolation:
+			return apperrors.Wrap(apperrors.ErrConflict, err, "resource already exists")
+		case pgForeignKeyViolation:
+			return apperrors.Wrap(apperrors.ErrValidation, err, "referenced resource does not exist")
+		}
+	}
+
+	var sqliteErr interface{ Code() int }
+	if errors.As(err, &sqliteErr) {
+		switch sqliteErr.Code() {
+		case sqlitePrimaryKeyViolation, sqliteUniqueViolation:
+			return apperrors.Wrap(apperrors.ErrConflict, err, "resource already exists")
+		case sqliteForeignKeyViolation:
+			return apperrors.Wrap(apperrors.ErrValidation, err, "referenced resource does not exist")
+		}
+	}
+
+	return err
+}
@@ -0,0 +1,92 @@
+package event
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/geekAshish/DriveDesk/models"
+	"github.com/geekAshish/DriveDesk/store"
+	"go.opentelemetry.io/otel"
+)
+
+const eventColumns = `seq, id, type, subject, brand, data, created_at`
+
+type Store struct {
+	db *sql.DB
+}
+
+func New(db *sql.DB) *Store {
+	return &Store{db: db}
+}
+
+func (s *Store) AppendEvent(ctx context.Context, event models.ChangeEvent) (models.ChangeEvent, error) {
+	tracer := otel.Tracer("EventStore")
+	ctx, span := tracer.Start(ctx, "AppendEvent-Store")
+	defer span.End()
+
+	err := store.Conn(ctx, s.db).QueryRowContext(ctx, `
+	INSERT INTO change_event (id, type, subject, brand, data, created_at)
+	VALUES ($1, $2, $3, $4, $5, $6)
+	RETURNING seq`,
+		event.ID, event.Type, event.Subject, event.Brand, string(event.Data), event.CreateAt.UTC(),
+	).Scan(&event.Seq)
+	if err != nil {
+		return models.ChangeEvent{}, store.DBError(err)
+	}
+
+	return event, nil
+}
+
+func (s *Store) ListEventsSince(ctx context.Context, after int64, limit int) ([]models.ChangeEvent, error) {
+	tracer := otel.Tracer("EventStore")
+	ctx, span := tracer.Start(ctx, "ListEventsSince-Store")
+	defer span.End()
+
+	rows, err := store.Conn(ctx, s.db).QueryContext(ctx,
+		`SELECT `+eventColumns+` FROM change_event WHERE seq > $1 ORDER BY seq LIMIT $2`, after, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	events := []models.ChangeEvent{}
+	for rows.Next() {
+		var event models.ChangeEvent
+		var data string
+
+		err := rows.Scan(&event.Seq, &event.ID, &event.Type, &event.Subject, &event.Brand, &data, &event.CreateAt)
+		if err != nil {
+			return nil, err
+		}
+
+		event.Data = []byte(data)
+		events = append(events, event)
+	}
+
+	return events, rows.Err()
+}
+
+func (s *Store) LatestEventSeq(ctx context.Context) (int64, error) {
+	tracer := otel.Tracer("EventStore")
+	ctx, span := tracer.Start(ctx, "LatestEventSeq-Store")
+	defer span.End()
+
+	var seq int64
+	err := store.Conn(ctx, s.db).QueryRowContext(ctx, `SELECT COALESCE(MAX(seq), 0) FROM change_event`).Scan(&seq)
+
+	return seq, err
+}
+
+func (s *Store) DeleteEventsBefore(ctx context.Context, t time.Time) (int64, error) {
+	tracer := otel.Tracer("EventStore")
+	ctx, span := tracer.Start(ctx, "DeleteEventsBefore-Store")
+	defer span.End()
+
+	result, err := store.Conn(ctx, s.db).ExecContext(ctx, `DELETE FROM change_event WHERE created_at < $1`, t.UTC())
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected()
+}
@@ -0,0 +1,11 @@
+package store
+
+import "github.com/google/uuid"
+
+// ValidID reports whether id can be the id of a row. Ids are UUIDs, and
+// stores answer any other id as not found without querying: it can never
+// match, and Postgres would reject it with a cast error.
+func ValidID(id string) bool {
+	_, err := uuid.Parse(id)
+	return err == nil
+}
@@ -0,0 +1,157 @@
+package idempotency
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"github.com/geekAshish/DriveDesk/models"
+	"github.com/geekAshish/DriveDesk/store"
+	"go.opentelemetry.io/otel"
+)
+
+const idempotencyColumns = `owner, key, request_hash, status, content_type, header, body, created_at, expires_at`
+
+type Store struct {
+	db *sql.DB
+	tx *store.TxManager
+}
+
+func New(db *sql.DB) *Store {
+	return &Store{db: db, tx: store.NewTxManager(db)}
+}
+
+func (s *Store) ReserveIdempotencyKey(ctx context.Context, record models.IdempotencyRecord) (models.IdempotencyRecord, bool, error) {
+	tracer := otel.Tracer("IdempotencyStore")
+	ctx, span := tracer.Start(ctx, "ReserveIdempotencyKey-Store")
+	defer span.End()
+
+	header, err := json.Marshal(record.Header)
+	if err != nil {
+		return models.IdempotencyRecord{}, false, err
+	}
+
+	var existing models.IdempotencyRecord
+	reserved := false
+
+	err = s.tx.WithTx(ctx, func(ctx context.Context) error {
+		conn := store.Conn(ctx, s.db)
+
+		_, err := conn.ExecContext(ctx, `DELETE FROM idempotency_key WHERE owner = $1 AND key = $2 AND expires_at <= $3`,
+			record.Owner, record.Key, time.Now())
+		if err != nil {
+			return err
+		}
+
+		// a concurrent retry waits here for the first insert to commit and then inserts nothing
+		result, err := conn.ExecContext(ctx, `
+		INSERT INTO idempotency_key (`+idempotencyColumns+`)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		ON CONFLICT (owner, key) DO NOTHING`,
+			record.Owner,
+			record.Key,
+			record.RequestHash,
+			record.Status,
+			record.ContentType,
+			header,
+			record.Body,
+			record.CreateAt,
+			record.ExpireAt,
+		)
+		if err != nil {
+			return store.DBError(err)
+		}
+
+		if inserted, err := result.RowsAffected(); err != nil || inserted == 1 {
+			reserved = true
+			return err
+		}
+
+		existing, err = scanIdempotencyRecord(conn.QueryRowContext(ctx,
+			`SELECT `+idempotencyColumns+` FROM idempotency_key WHERE owner = $1 AND key = $2`, record.Owner, record.Key))
+		return err
+	})
+
+	if err != nil {
+		return models.IdempotencyRecord{}, false, err
+	}
+	if reserved {
+		return record, true, nil
+	}
+
+	return existing, false, nil
+}
+
+func (s *Store) SaveIdempotencyResponse(ctx context.Context, record models.IdempotencyRecord) error {
+	tracer := otel.Tracer("IdempotencyStore")
+	ctx, span := tracer.Start(ctx, "SaveIdempotencyResponse-Store")
+	defer span.End()
+
+	header, err := json.Marshal(record.Header)
+	if err != nil {
+		return err
+	}
+
+	_, err = store.Conn(ctx, s.db).ExecContext(ctx, `
+	UPDATE idempotency_key SET status = $1, content_type = $2, header = $3, body = $4, expires_at = $5
+	WHERE owner = $6 AND key = $7`,
+		record.Status,
+		record.ContentType,
+		header,
+		record.Body,
+		record.ExpireAt,
+		record.Owner,
+		record.Key,
+	)
+
+	return err
+}
+
+func (s *Store) DeleteIdempotencyKey(ctx context.Context, owner, key string) error {
+	tracer := otel.Tracer("IdempotencyStore")
+	ctx, span := tracer.Start(ctx, "DeleteIdempotencyKey-Store")
+	defer span.End()
+
+	_, err := store.Conn(ctx, s.db).ExecContext(ctx, `DELETE FROM idempotency_key WHERE owner = $1 AND key = $2`, owner, key)
+	return err
+}
+
+func (s *Store) DeleteExpiredIdempotencyKeys(ctx context.Context, now time.Time) (int64, error) {
+	tracer := otel.Tracer("IdempotencyStore")
+	ctx, span := tracer.Start(ctx, "DeleteExpiredIdempotencyKeys-Store")
+	defer span.End()
+
+	result, err := store.Conn(ctx, s.db).ExecContext(ctx, `DELETE FROM idempotency_key WHERE expires_at <= $1`, now)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected()
+}
+
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+func scanIdempotencyRecord(row scanner) (models.IdempotencyRecord, error) {
+	var record models.IdempotencyRecord
+	var header string
+
+	err := row.Scan(
+		&record.Owner,
+		&record.Key,
+		&record.RequestHash,
+		&record.Status,
+		&record.ContentType,
+		&header,
+		&record.Body,
+		&record.CreateAt,
+		&record.ExpireAt,
+	)
+	if err != nil {
+		return models.IdempotencyRecord{}, err
+	}
+
+	return record, json.Unmarshal([]byte(header), &record.Header)
+}
@@ -2,6 +2,7 @@ package store
 
 import (
 	"context"
+	"time"
 
 	"github.com/geekAshish/DriveDesk/models"
 )
@@ -9,6 +10,9 @@ import (
 type CarStoreInterface interface {
 	GetCarById(ctx context.Context, id string) (models.Car, error)
 	GetCarByBrand(ctx context.Context, brand string, isEngine bool) ([]models.Car, error)
+	// GetCarsByEngine returns the cars using the engine with engineID, with
+	// their engine details, oldest first.
+	GetCarsByEngine(ctx context.Context, engineID string) ([]models.Car, error)
 	CreateCar(ctx context.Context, carReq *models.CarRequest) (models.Car, error)
 	UpdateCar(ctx context.Context, id string, carReq *models.CarRequest) (models.Car, error)
 	DeleteCar(ctx context.Context, id string) (models.Car, error)
@@ -16,7 +20,139 @@ type CarStoreInterface interface {
 
 type EngineStoreInterface interface {
 	GetEngineById(ctx context.Context, id string) (models.Engine, error)
+	// GetEnginesByIds returns the engines with the given ids in one lookup,
+	// in no particular order. Ids that match no engine are left out.
+	GetEnginesByIds(ctx context.Context, ids []string) ([]models.Engine, error)
 	CreateEngine(ctx context.Context, engineReq *models.EngineRequest) (models.Engine, error)
 	UpdateEngine(ctx context.Context, id string, engineReq *models.EngineRequest) (models.Engine, error)
 	DeleteEngine(ctx context.Context, id string) (models.Engine, error)
-}
\ No newline at end of file
+}
+
+// TxManagerInterface lets services group calls to several stores into a
+// single unit of work.
+type TxManagerInterface interface {
+	WithTx(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+// ReferenceStoreInterface stores admin-managed reference data. An empty kind
+// passed to ListReferenceValues lists every kind.
+type ReferenceStoreInterface interface {
+	ListReferenceValues(ctx context.Context, kind string) ([]models.ReferenceValue, error)
+	GetReferenceValue(ctx context.Context, kind, code string) (models.ReferenceValue, error)
+	CreateReferenceValue(ctx context.Context, kind string, req *models.ReferenceValueRequest) (models.ReferenceValue, error)
+	UpdateReferenceValue(ctx context.Context, kind, code string, req *models.ReferenceValueRequest) (models.ReferenceValue, error)
+	DeleteReferenceValue(ctx context.Context, kind, code string) (models.ReferenceValue, error)
+}
+
+// ValidationRuleStoreInterface stores the deployment specific validation
+// rules, listed in the order they were created.
+type ValidationRuleStoreInterface interface {
+	ListValidationRules(ctx context.Context) ([]models.ValidationRule, error)
+	CreateValidationRule(ctx context.Context, req *models.ValidationRuleRequest) (models.ValidationRule, error)
+	DeleteValidationRule(ctx context.Context, id string) (models.ValidationRule, error)
+}
+
+// IdempotencyStoreInterface keeps the responses to requests sent with an
+// Idempotency-Key until they expire. Expired records are treated as absent.
+type IdempotencyStoreInterface interface {
+	// ReserveIdempotencyKey stores record unless its owner already holds the
+	// key, in which case the record holding it is returned with false.
+	ReserveIdempotencyKey(ctx context.Context, record models.IdempotencyRecord) (models.IdempotencyRecord, bool, error)
+	// SaveIdempotencyResponse sets the response and expiry of a reserved key.
+	SaveIdempotencyResponse(ctx context.Context, record models.IdempotencyRecord) error
+	// DeleteIdempotencyKey releases a key so the request can be tried again.
+	DeleteIdempotencyKey(ctx context.Context, owner, key string) error
+	// DeleteExpiredIdempotencyKeys removes the records expired at now and
+	// returns how many there were.
+	DeleteExpiredIdempotencyKeys(ctx context.Context, now time.Time) (int64, error)
+}
+
+// WebhookStoreInterface stores webhook subscriptions and their deliveries.
+// Deleting a webhook deletes its deliveries.
+type WebhookStoreInterface interface {
+	ListWebhooks(ctx context.Context) ([]models.Webhook, error)
+	GetWebhook(ctx context.Context, id string) (models.Webhook, error)
+	CreateWebhook(ctx context.Context, req *models.WebhookRequest) (models.Webhook, error)
+	UpdateWebhook(ctx context.Context, id string, req *models.WebhookRequest) (models.Webhook, error)
+	DeleteWebhook(ctx context.Context, id string) (models.Webhook, error)
+
+	CreateWebhookDeliveries(ctx context.Context, deliveries []models.WebhookDelivery) error
+	// ListWebhookDeliveries lists the deliveries matching filter, newest
+	// first.
+	ListWebhookDeliveries(ctx context.Context, filter models.WebhookDeliveryFilter) ([]models.WebhookDelivery, error)
+	GetWebhookDelivery(ctx context.Context, id string) (models.WebhookDelivery, error)
+	// ClaimWebhookDeliveries returns up to limit pending deliveries due at
+	// now, oldest first, and moves their next attempt to leaseUntil so that
+	// no other instance picks them up meanwhile.
+	ClaimWebhookDeliveries(ctx context.Context, now, leaseUntil time.Time, limit int) ([]models.WebhookDelivery, error)
+	// UpdateWebhookDelivery records the outcome of an attempt: status,
+	// attempts, next attempt and last status code and error.
+	UpdateWebhookDelivery(ctx context.Context, delivery models.WebhookDelivery) error
+}
+
+// EventStoreInterface keeps recent change events for the change stream, in
+// the order of their sequence numbers.
+type EventStoreInterface interface {
+	// AppendEvent stores event and returns it with its sequence number,
+	// which is higher than that of every event committed before.
+	AppendEvent(ctx context.Context, event models.ChangeEvent) (models.ChangeEvent, error)
+	// ListEventsSince returns up to limit events with a sequence number
+	// above after, in order.
+	ListEventsSince(ctx context.Context, after int64, limit int) ([]models.ChangeEvent, error)
+	// LatestEventSeq is the highest sequence number stored, 0 when there
+	// are no events.
+	LatestEventSeq(ctx context.Context) (int64, error)
+	// DeleteEventsBefore removes the events created before t and returns
+	// how many it removed.
+	DeleteEventsBefore(ctx context.Context, t time.Time) (int64, error)
+}
+
+// OutboxStoreInterface keeps the events waiting to be published to each
+// sink.
+type OutboxStoreInterface interface {
+	// AppendOutbox stores messages with increasing sequence numbers, in
+	// the order given.
+	AppendOutbox(ctx context.Context, messages []models.OutboxMessage) error
+	// ClaimOutbox returns up to limit pending messages of sink that are due
+	// at now and are the oldest pending message of their entity, in
+	// sequence order, and sets their next attempt to leaseUntil so no one
+	// else claims them meanwhile.
+	ClaimOutbox(ctx context.Context, sink string, now, leaseUntil time.Time, limit int) ([]models.OutboxMessage, error)
+	// UpdateOutbox records the outcome of an attempt: status, attempts,
+	// next_attempt_at, last_error and published_at.
+	UpdateOutbox(ctx context.Context, message models.OutboxMessage) error
+	// DeletePublishedOutbox removes the messages published before t and
+	// returns how many it removed.
+	DeletePublishedOutbox(ctx context.Context, t time.Time) (int64, error)
+}
+
+// SyncStoreInterface keeps the version of every car and engine, and
+// tombstones for the deleted ones, for clients that sync deltas.
+type SyncStoreInterface interface {
+	// RecordSyncChange gives the car or engine of row the next version and
+	// returns it. It is called in the transaction of the change, and
+	// versions become visible in the order they were given.
+	RecordSyncChange(ctx context.Context, row models.SyncRow) (int64, error)
+	// DeleteSyncChildren turns the live rows of entity whose parent is
+	// parentID into tombstones, each with a version of its own.
+	DeleteSyncChildren(ctx context.Context, entity, parentID string) error
+	// GetSyncRow returns the row of a car or engine. Inside a transaction
+	// the row stays locked until it ends, where the backend can lock rows.
+	GetSyncRow(ctx context.Context, entity, id string) (models.SyncRow, error)
+	// ListSyncRows returns up to limit rows with a version above since, in
+	// version order.
+	ListSyncRows(ctx context.Context, since int64, limit int) ([]models.SyncRow, error)
+}
+
+// RateLimitStoreInterface keeps the token buckets of rate limited clients,
+// so instances sharing a database share their limits.
+type RateLimitStoreInterface interface {
+	// TakeRateLimitToken takes a token from the bucket of key as of now, see
+	// models.RateLimitBucket.Take, and returns the bucket left and whether a
+	// token was taken. Concurrent calls for the same key do not both take
+	// the last token.
+	TakeRateLimitToken(ctx context.Context, key string, limit models.RateLimit, now time.Time) (models.RateLimitBucket, bool, error)
+	// DeleteRateLimitBucketsBefore removes the buckets last updated before
+	// t, which are full again by then, and returns how many there were.
+	DeleteRateLimitBucketsBefore(ctx context.Context, t time.Time) (int64, error)
+}
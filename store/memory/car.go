@@ -0,0 +1,218 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/geekAshish/DriveDesk/apperrors"
+	"github.com/geekAshish/DriveDesk/models"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel"
+)
+
+type CarStore struct {
+	db *DB
+}
+
+func NewCarStore(db *DB) *CarStore {
+	return &CarStore{db: db}
+}
+
+func (s *CarStore) GetCarById(ctx context.Context, id string) (models.Car, error) {
+	tracer := otel.Tracer("CarStore")
+	ctx, span := tracer.Start(ctx, "GetCarById-MemoryStore")
+	defer span.End()
+
+	unlock := s.db.lock(ctx, false)
+	defer unlock()
+
+	car, ok := s.db.car(id)
+	if !ok {
+		return models.Car{}, apperrors.NotFound("car not found")
+	}
+
+	return s.db.withEngine(car), nil
+}
+
+func (s *CarStore) GetCarByBrand(ctx context.Context, brand string, isEngine bool) ([]models.Car, error) {
+	tracer := otel.Tracer("CarStore")
+	ctx, span := tracer.Start(ctx, "GetCarByBrand-MemoryStore")
+	defer span.End()
+
+	unlock := s.db.lock(ctx, false)
+	defer unlock()
+
+	cars := []models.Car{}
+
+	for _, car := range s.db.cars {
+		if car.Brand != brand {
+			continue
+		}
+
+		if isEngine {
+			car = s.db.withEngine(car)
+		}
+
+		cars = append(cars, car)
+	}
+
+	// map iteration order is random, keep listings stable between calls
+	sort.Slice(cars, func(i, j int) bool {
+		if !cars[i].CreateAt.Equal(cars[j].CreateAt) {
+			return cars[i].CreateAt.Before(cars[j].CreateAt)
+		}
+		return cars[i].ID.String() < cars[j].ID.String()
+	})
+
+	return cars, nil
+}
+
+func (s *CarStore) GetCarsByEngine(ctx context.Context, engineID string) ([]models.Car, error) {
+	tracer := otel.Tracer("CarStore")
+	ctx, span := tracer.Start(ctx, "GetCarsByEngine-MemoryStore")
+	defer span.End()
+
+	unlock := s.db.lock(ctx, false)
+	defer unlock()
+
+	cars := []models.Car{}
+
+	for _, car := range s.db.cars {
+		if car.Engine.EngineID.String() == engineID {
+			cars = append(cars, s.db.withEngine(car))
+		}
+	}
+
+	sort.Slice(cars, func(i, j int) bool {
+		if !cars[i].CreateAt.Equal(cars[j].CreateAt) {
+			return cars[i].CreateAt.Before(cars[j].CreateAt)
+		}
+		return cars[i].ID.String() < cars[j].ID.String()
+	})
+
+	return cars, nil
+}
+
+func (s *CarStore) CreateCar(ctx context.Context, carReq *models.CarRequest) (models.Car, error) {
+	tracer := otel.Tracer("CarStore")
+	ctx, span := tracer.Start(ctx, "CreateCar-MemoryStore")
+	defer span.End()
+
+	unlock := s.db.lock(ctx, true)
+	defer unlock()
+
+	if _, ok := s.db.engines[carReq.Engine.EngineID]; !ok {
+		return models.Car{}, apperrors.InvalidField("engine.engine_id", "not_found", fmt.Sprintf("engine %s does not exist", carReq.Engine.EngineID))
+	}
+	if err := s.db.checkVIN(uuid.Nil, carReq.VIN); err != nil {
+		return models.Car{}, err
+	}
+
+	now := time.Now()
+
+	car := models.Car{
+		ID:       uuid.New(),
+		Name:     carReq.Name,
+		Year:     carReq.Year,
+		Brand:    carReq.Brand,
+		FuelType: carReq.FuelType,
+		Price:    carReq.Price,
+		VIN:      carReq.VIN,
+		Engine:   models.Engine{EngineID: carReq.Engine.EngineID},
+		CreateAt: now,
+		UpdateAt: now,
+	}
+
+	s.db.cars[car.ID] = car
+
+	return s.db.withEngine(car), nil
+}
+
+func (s *CarStore) UpdateCar(ctx context.Context, id string, carReq *models.CarRequest) (models.Car, error) {
+	tracer := otel.Tracer("CarStore")
+	ctx, span := tracer.Start(ctx, "UpdateCar-MemoryStore")
+	defer span.End()
+
+	unlock := s.db.lock(ctx, true)
+	defer unlock()
+
+	car, ok := s.db.car(id)
+	if !ok {
+		return models.Car{}, apperrors.NotFound("car not found")
+	}
+
+	if _, ok := s.db.engines[carReq.Engine.EngineID]; !ok {
+		return models.Car{}, apperrors.InvalidField("engine.engine_id", "not_found", fmt.Sprintf("engine %s does not exist", carReq.Engine.EngineID))
+	}
+	if err := s.db.checkVIN(car.ID, carReq.VIN); err != nil {
+		return models.Car{}, err
+	}
+
+	car.Name = carReq.Name
+	car.Year = carReq.Year
+	car.Brand = carReq.Brand
+	car.FuelType = carReq.FuelType
+	car.Price = carReq.Price
+	car.VIN = carReq.VIN
+	car.Engine = models.Engine{EngineID: carReq.Engine.EngineID}
+	car.UpdateAt = time.Now()
+
+	s.db.cars[car.ID] = car
+
+	return s.db.withEngine(car), nil
+}
+
+func (s *CarStore) DeleteCar(ctx context.Context, id string) (models.Car, error) {
+	tracer := otel.Tracer("CarStore")
+	ctx, span := tracer.Start(ctx, "DeleteCar-MemoryStore")
+	defer span.End()
+
+	unlock := s.db.lock(ctx, true)
+	defer unlock()
+
+	car, ok := s.db.car(id)
+	if !ok {
+		return models.Car{}, apperrors.NotFound("car not found")
+	}
+
+	delete(s.db.cars, car.ID)
+
+	return s.db.withEngine(car), nil
+}
+
+// car looks a car up by its string id; malformed ids simply match nothing.
+func (db *DB) car(id string) (models.Car, bool) {
+	carID, err := uuid.Parse(id)
+	if err != nil {
+		return models.Car{}, false
+	}
+
+	car, ok := db.cars[carID]
+	return car, ok
+}
+
+// withEngine fills in the engine columns, like the LEFT JOIN on engine does.
+func (db *DB) withEngine(car models.Car) models.Car {
+	if engine, ok := db.engines[car.Engine.EngineID]; ok {
+		car.Engine = engine
+	}
+	return car
+}
+
+// checkVIN enforces the unique index on car.vin: no other car than id may
+// already have vin.
+func (db *DB) checkVIN(id uuid.UUID, vin string) error {
+	if vin == "" {
+		return nil
+	}
+
+	for _, car := range db.cars {
+		if car.VIN == vin && car.ID != id {
+			return apperrors.Conflict("a car with VIN %s already exists", vin)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,173 @@
+package memory
+
+import (
+	"context"
+	"maps"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/geekAshish/DriveDesk/models"
+	"github.com/geekAshish/DriveDesk/store"
+	"github.com/google/uuid"
+)
+
+// DB holds the rows shared by the in-memory stores, so cars can reference
+// engines the same way the car table references the engine table.
+type DB struct {
+	mu         sync.RWMutex
+	cars       map[uuid.UUID]models.Car
+	engines    map[uuid.UUID]models.Engine
+	references map[referenceKey]models.ReferenceValue
+	rules      map[uuid.UUID]models.ValidationRule
+	idempotent map[idempotencyKey]models.IdempotencyRecord
+	webhooks   map[uuid.UUID]models.Webhook
+	deliveries map[uuid.UUID]models.WebhookDelivery
+
+	// events are kept in sequence order; eventSeq is the last number given
+	events   []models.ChangeEvent
+	eventSeq int64
+
+	// outbox messages are kept in sequence order, like events
+	outbox    []models.OutboxMessage
+	outboxSeq int64
+
+	// syncRows hold the version of every car and engine; syncClock is the
+	// last version given
+	syncRows  map[syncKey]models.SyncRow
+	syncClock int64
+
+	rateLimits map[string]models.RateLimitBucket
+}
+
+type referenceKey struct {
+	kind, code string
+}
+
+type idempotencyKey struct {
+	owner, key string
+}
+
+type syncKey struct {
+	entity, id string
+}
+
+// NewDB returns an empty database holding only the default reference data,
+// as a freshly migrated SQL database does.
+func NewDB() *DB {
+	db := &DB{
+		cars:       map[uuid.UUID]models.Car{},
+		engines:    map[uuid.UUID]models.Engine{},
+		references: map[referenceKey]models.ReferenceValue{},
+		rules:      map[uuid.UUID]models.ValidationRule{},
+		idempotent: map[idempotencyKey]models.IdempotencyRecord{},
+		webhooks:   map[uuid.UUID]models.Webhook{},
+		deliveries: map[uuid.UUID]models.WebhookDelivery{},
+		syncRows:   map[syncKey]models.SyncRow{},
+		rateLimits: map[string]models.RateLimitBucket{},
+	}
+
+	now := time.Now()
+	for _, value := range models.DefaultReferenceValues {
+		value.Aliases = append([]string{}, value.Aliases...)
+		value.CreateAt, value.UpdateAt = now, now
+		db.references[referenceKey{kind: value.Kind, code: value.Code}] = value
+	}
+
+	return db
+}
+
+// snapshot returns a function that puts the current rows back. Rows are
+// replaced, never modified in place, so copying the maps and slices is
+// enough.
+func (db *DB) snapshot() (restore func()) {
+	cars := maps.Clone(db.cars)
+	engines := maps.Clone(db.engines)
+	references := maps.Clone(db.references)
+	rules := maps.Clone(db.rules)
+	idempotent := maps.Clone(db.idempotent)
+	webhooks := maps.Clone(db.webhooks)
+	deliveries := maps.Clone(db.deliveries)
+	events, eventSeq := slices.Clone(db.events), db.eventSeq
+	outbox, outboxSeq := slices.Clone(db.outbox), db.outboxSeq
+	syncRows, syncClock := maps.Clone(db.syncRows), db.syncClock
+	rateLimits := maps.Clone(db.rateLimits)
+
+	return func() {
+		db.cars, db.engines, db.references, db.rules, db.idempotent = cars, engines, references, rules, idempotent
+		db.webhooks, db.deliveries = webhooks, deliveries
+		db.events, db.eventSeq = events, eventSeq
+		db.outbox, db.outboxSeq = outbox, outboxSeq
+		db.syncRows, db.syncClock = syncRows, syncClock
+		db.rateLimits = rateLimits
+	}
+}
+
+type txKey struct {
+	db *DB
+}
+
+// lock takes the read or write lock for a single store call. Calls made
+// inside WithTx already run under the transaction's write lock.
+func (db *DB) lock(ctx context.Context, write bool) func() {
+	if inTx, _ := ctx.Value(txKey{db: db}).(bool); inTx {
+		return func() {}
+	}
+
+	if write {
+		db.mu.Lock()
+		return db.mu.Unlock
+	}
+
+	db.mu.RLock()
+	return db.mu.RUnlock
+}
+
+type TxManager struct {
+	db *DB
+}
+
+func NewTxManager(db *DB) *TxManager {
+	return &TxManager{db: db}
+}
+
+// WithTx runs fn while holding the write lock and restores the previous
+// contents of the database if fn fails, which gives callers the same
+// all-or-nothing behaviour as a SQL transaction. The AfterCommit callbacks
+// run once fn succeeded and the lock is released, as they may read the
+// database again.
+func (m *TxManager) WithTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	if inTx, _ := ctx.Value(txKey{db: m.db}).(bool); inTx {
+		return fn(ctx)
+	}
+
+	ctx, unit := store.BeginUnit(ctx)
+
+	if err := m.run(ctx, fn); err != nil {
+		return err
+	}
+
+	unit.Committed()
+	return nil
+}
+
+func (m *TxManager) run(ctx context.Context, fn func(ctx context.Context) error) (err error) {
+	m.db.mu.Lock()
+	defer m.db.mu.Unlock()
+
+	restore := m.db.snapshot()
+
+	defer func() {
+		if p := recover(); p != nil {
+			restore()
+			panic(p)
+		}
+	}()
+
+	if err := fn(context.WithValue(ctx, txKey{db: m.db}, true)); err != nil {
+		restore()
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,142 @@
+package memory
+
+import (
+	"context"
+	"time"
+
+	"github.com/geekAshish/DriveDesk/apperrors"
+	"github.com/geekAshish/DriveDesk/models"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel"
+)
+
+type EngineStore struct {
+	db *DB
+}
+
+func NewEngineStore(db *DB) *EngineStore {
+	return &EngineStore{db: db}
+}
+
+func (s *EngineStore) GetEngineById(ctx context.Context, id string) (models.Engine, error) {
+	tracer := otel.Tracer("EngineStore")
+	ctx, span := tracer.Start(ctx, "GetEngineById-MemoryStore")
+	defer span.End()
+
+	unlock := s.db.lock(ctx, false)
+	defer unlock()
+
+	engine, ok := s.db.engine(id)
+	if !ok {
+		return models.Engine{}, apperrors.NotFound("engine not found")
+	}
+
+	return engine, nil
+}
+
+func (s *EngineStore) GetEnginesByIds(ctx context.Context, ids []string) ([]models.Engine, error) {
+	tracer := otel.Tracer("EngineStore")
+	ctx, span := tracer.Start(ctx, "GetEnginesByIds-MemoryStore")
+	defer span.End()
+
+	unlock := s.db.lock(ctx, false)
+	defer unlock()
+
+	engines := []models.Engine{}
+	seen := map[string]bool{}
+	for _, id := range ids {
+		if engine, ok := s.db.engine(id); ok && !seen[id] {
+			seen[id] = true
+			engines = append(engines, engine)
+		}
+	}
+
+	return engines, nil
+}
+
+func (s *EngineStore) CreateEngine(ctx context.Context, engineReq *models.EngineRequest) (models.Engine, error) {
+	tracer := otel.Tracer("EngineStore")
+	ctx, span := tracer.Start(ctx, "CreateEngine-MemoryStore")
+	defer span.End()
+
+	unlock := s.db.lock(ctx, true)
+	defer unlock()
+
+	now := time.Now()
+
+	engine := models.Engine{
+		EngineID:      uuid.New(),
+		Dispacement:   engineReq.Dispacement,
+		NoOfCylinders: engineReq.NoOfCylinders,
+		CarRange:      engineReq.CarRange,
+		CreateAt:      now,
+		UpdateAt:      now,
+	}
+
+	s.db.engines[engine.EngineID] = engine
+
+	return engine, nil
+}
+
+func (s *EngineStore) UpdateEngine(ctx context.Context, id string, engineReq *models.EngineRequest) (models.Engine, error) {
+	tracer := otel.Tracer("EngineStore")
+	ctx, span := tracer.Start(ctx, "UpdateEngine-MemoryStore")
+	defer span.End()
+
+	if _, err := uuid.Parse(id); err != nil {
+		return models.Engine{}, apperrors.NotFound("engine not found")
+	}
+
+	unlock := s.db.lock(ctx, true)
+	defer unlock()
+
+	engine, ok := s.db.engine(id)
+	if !ok {
+		return models.Engine{}, apperrors.NotFound("engine not found")
+	}
+
+	engine.Dispacement = engineReq.Dispacement
+	engine.NoOfCylinders = engineReq.NoOfCylinders
+	engine.CarRange = engineReq.CarRange
+	engine.UpdateAt = time.Now()
+
+	s.db.engines[engine.EngineID] = engine
+
+	return engine, nil
+}
+
+func (s *EngineStore) DeleteEngine(ctx context.Context, id string) (models.Engine, error) {
+	tracer := otel.Tracer("EngineStore")
+	ctx, span := tracer.Start(ctx, "DeleteEngine-MemoryStore")
+	defer span.End()
+
+	unlock := s.db.lock(ctx, true)
+	defer unlock()
+
+	engine, ok := s.db.engine(id)
+	if !ok {
+		return models.Engine{}, apperrors.NotFound("engine not found")
+	}
+
+	delete(s.db.engines, engine.EngineID)
+
+	// mirror ON DELETE CASCADE on car.engine_id
+	for carID, car := range s.db.cars {
+		if car.Engine.EngineID == engine.EngineID {
+			delete(s.db.cars, carID)
+		}
+	}
+
+	return engine, nil
+}
+
+// engine looks an engine up by its string id; malformed ids simply match nothing.
+func (db *DB) engine(id string) (models.Engine, bool) {
+	engineID, err := uuid.Parse(id)
+	if err != nil {
+		return models.Engine{}, false
+	}
+
+	engine, ok := db.engines[engineID]
+	return engine, ok
+}
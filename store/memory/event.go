@@ -0,0 +1,87 @@
+package memory
+
+import (
+	"context"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/geekAshish/DriveDesk/models"
+	"go.opentelemetry.io/otel"
+)
+
+type EventStore struct {
+	db *DB
+}
+
+func NewEventStore(db *DB) *EventStore {
+	return &EventStore{db: db}
+}
+
+func (s *EventStore) AppendEvent(ctx context.Context, event models.ChangeEvent) (models.ChangeEvent, error) {
+	tracer := otel.Tracer("EventStore")
+	ctx, span := tracer.Start(ctx, "AppendEvent-MemoryStore")
+	defer span.End()
+
+	unlock := s.db.lock(ctx, true)
+	defer unlock()
+
+	s.db.eventSeq++
+	event.Seq = s.db.eventSeq
+	event.Data = append([]byte(nil), event.Data...)
+	s.db.events = append(s.db.events, event)
+
+	return event, nil
+}
+
+func (s *EventStore) ListEventsSince(ctx context.Context, after int64, limit int) ([]models.ChangeEvent, error) {
+	tracer := otel.Tracer("EventStore")
+	ctx, span := tracer.Start(ctx, "ListEventsSince-MemoryStore")
+	defer span.End()
+
+	unlock := s.db.lock(ctx, false)
+	defer unlock()
+
+	start := sort.Search(len(s.db.events), func(i int) bool { return s.db.events[i].Seq > after })
+	end := min(start+limit, len(s.db.events))
+
+	events := make([]models.ChangeEvent, 0, end-start)
+	for _, event := range s.db.events[start:end] {
+		event.Data = append([]byte(nil), event.Data...)
+		events = append(events, event)
+	}
+
+	return events, nil
+}
+
+func (s *EventStore) LatestEventSeq(ctx context.Context) (int64, error) {
+	tracer := otel.Tracer("EventStore")
+	ctx, span := tracer.Start(ctx, "LatestEventSeq-MemoryStore")
+	defer span.End()
+
+	unlock := s.db.lock(ctx, false)
+	defer unlock()
+
+	if len(s.db.events) == 0 {
+		return 0, nil
+	}
+
+	return s.db.events[len(s.db.events)-1].Seq, nil
+}
+
+func (s *EventStore) DeleteEventsBefore(ctx context.Context, t time.Time) (int64, error) {
+	tracer := otel.Tracer("EventStore")
+	ctx, span := tracer.Start(ctx, "DeleteEventsBefore-MemoryStore")
+	defer span.End()
+
+	unlock := s.db.lock(ctx, true)
+	defer unlock()
+
+	kept := slices.DeleteFunc(slices.Clone(s.db.events), func(event models.ChangeEvent) bool {
+		return event.CreateAt.Before(t)
+	})
+	deleted := int64(len(s.db.events) - len(kept))
+	s.db.events = kept
+
+	return deleted, nil
+}
@@ -0,0 +1,95 @@
+package memory
+
+import (
+	"context"
+	"time"
+
+	"github.com/geekAshish/DriveDesk/models"
+	"go.opentelemetry.io/otel"
+)
+
+type IdempotencyStore struct {
+	db *DB
+}
+
+func NewIdempotencyStore(db *DB) *IdempotencyStore {
+	return &IdempotencyStore{db: db}
+}
+
+func (s *IdempotencyStore) ReserveIdempotencyKey(ctx context.Context, record models.IdempotencyRecord) (models.IdempotencyRecord, bool, error) {
+	tracer := otel.Tracer("IdempotencyStore")
+	ctx, span := tracer.Start(ctx, "ReserveIdempotencyKey-MemoryStore")
+	defer span.End()
+
+	unlock := s.db.lock(ctx, true)
+	defer unlock()
+
+	key := idempotencyKey{owner: record.Owner, key: record.Key}
+
+	if existing, ok := s.db.idempotent[key]; ok && existing.ExpireAt.After(time.Now()) {
+		return existing, false, nil
+	}
+
+	record.Header = record.Header.Clone()
+	record.Body = append([]byte(nil), record.Body...)
+	s.db.idempotent[key] = record
+
+	return record, true, nil
+}
+
+func (s *IdempotencyStore) SaveIdempotencyResponse(ctx context.Context, record models.IdempotencyRecord) error {
+	tracer := otel.Tracer("IdempotencyStore")
+	ctx, span := tracer.Start(ctx, "SaveIdempotencyResponse-MemoryStore")
+	defer span.End()
+
+	unlock := s.db.lock(ctx, true)
+	defer unlock()
+
+	key := idempotencyKey{owner: record.Owner, key: record.Key}
+
+	stored, ok := s.db.idempotent[key]
+	if !ok {
+		return nil
+	}
+
+	stored.Status = record.Status
+	stored.ContentType = record.ContentType
+	stored.Header = record.Header.Clone()
+	stored.Body = append([]byte(nil), record.Body...)
+	stored.ExpireAt = record.ExpireAt
+	s.db.idempotent[key] = stored
+
+	return nil
+}
+
+func (s *IdempotencyStore) DeleteIdempotencyKey(ctx context.Context, owner, key string) error {
+	tracer := otel.Tracer("IdempotencyStore")
+	ctx, span := tracer.Start(ctx, "DeleteIdempotencyKey-MemoryStore")
+	defer span.End()
+
+	unlock := s.db.lock(ctx, true)
+	defer unlock()
+
+	delete(s.db.idempotent, idempotencyKey{owner: owner, key: key})
+
+	return nil
+}
+
+func (s *IdempotencyStore) DeleteExpiredIdempotencyKeys(ctx context.Context, now time.Time) (int64, error) {
+	tracer := otel.Tracer("IdempotencyStore")
+	ctx, span := tracer.Start(ctx, "DeleteExpiredIdempotencyKeys-MemoryStore")
+	defer span.End()
+
+	unlock := s.db.lock(ctx, true)
+	defer unlock()
+
+	var deleted int64
+	for key, record := range s.db.idempotent {
+		if !record.ExpireAt.After(now) {
+			delete(s.db.idempotent, key)
+			deleted++
+		}
+	}
+
+	return deleted, nil
+}
@@ -0,0 +1,29 @@
+package memory_test
+
+import (
+	"testing"
+
+	"github.com/geekAshish/DriveDesk/store/memory"
+	"github.com/geekAshish/DriveDesk/store/storetest"
+)
+
+func TestConformance(t *testing.T) {
+	storetest.Run(t, func(t *testing.T) storetest.Stores {
+		db := memory.NewDB()
+
+		return storetest.Stores{
+			Car:       memory.NewCarStore(db),
+			Engine:    memory.NewEngineStore(db),
+			Tx:        memory.NewTxManager(db),
+			Reference: memory.NewReferenceStore(db),
+			Rule:      memory.NewValidationRuleStore(db),
+
+			Idempotency: memory.NewIdempotencyStore(db),
+			Webhook:     memory.NewWebhookStore(db),
+			Event:       memory.NewEventStore(db),
+			Outbox:      memory.NewOutboxStore(db),
+			Sync:        memory.NewSyncStore(db),
+			RateLimit:   memory.NewRateLimitStore(db),
+		}
+	})
+}
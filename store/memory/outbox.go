@@ -0,0 +1,119 @@
+package memory
+
+import (
+	"context"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/geekAshish/DriveDesk/apperrors"
+	"github.com/geekAshish/DriveDesk/models"
+	"go.opentelemetry.io/otel"
+)
+
+type OutboxStore struct {
+	db *DB
+}
+
+func NewOutboxStore(db *DB) *OutboxStore {
+	return &OutboxStore{db: db}
+}
+
+func (s *OutboxStore) AppendOutbox(ctx context.Context, messages []models.OutboxMessage) error {
+	tracer := otel.Tracer("OutboxStore")
+	ctx, span := tracer.Start(ctx, "AppendOutbox-MemoryStore")
+	defer span.End()
+
+	unlock := s.db.lock(ctx, true)
+	defer unlock()
+
+	for _, message := range messages {
+		s.db.outboxSeq++
+		message.Seq = s.db.outboxSeq
+		message.Payload = append([]byte(nil), message.Payload...)
+		s.db.outbox = append(s.db.outbox, message)
+	}
+
+	return nil
+}
+
+func (s *OutboxStore) ClaimOutbox(ctx context.Context, sink string, now, leaseUntil time.Time, limit int) ([]models.OutboxMessage, error) {
+	tracer := otel.Tracer("OutboxStore")
+	ctx, span := tracer.Start(ctx, "ClaimOutbox-MemoryStore")
+	defer span.End()
+
+	unlock := s.db.lock(ctx, true)
+	defer unlock()
+
+	type entity struct{ aggregateType, aggregateID string }
+	seen := map[entity]bool{}
+
+	claimed := []models.OutboxMessage{}
+	for i, message := range s.db.outbox {
+		if len(claimed) == limit {
+			break
+		}
+		if message.Sink != sink || message.Status != models.OutboxPending {
+			continue
+		}
+
+		// only the oldest pending message of an entity may go out
+		key := entity{message.AggregateType, message.AggregateID}
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+
+		if message.NextAttemptAt.After(now) {
+			continue
+		}
+
+		message.NextAttemptAt = leaseUntil
+		s.db.outbox[i] = message
+		message.Payload = append([]byte(nil), message.Payload...)
+		claimed = append(claimed, message)
+	}
+
+	return claimed, nil
+}
+
+func (s *OutboxStore) UpdateOutbox(ctx context.Context, message models.OutboxMessage) error {
+	tracer := otel.Tracer("OutboxStore")
+	ctx, span := tracer.Start(ctx, "UpdateOutbox-MemoryStore")
+	defer span.End()
+
+	unlock := s.db.lock(ctx, true)
+	defer unlock()
+
+	i := sort.Search(len(s.db.outbox), func(i int) bool { return s.db.outbox[i].Seq >= message.Seq })
+	if i == len(s.db.outbox) || s.db.outbox[i].Seq != message.Seq {
+		return apperrors.NotFound("outbox message not found")
+	}
+
+	stored := s.db.outbox[i]
+	stored.Status = message.Status
+	stored.Attempts = message.Attempts
+	stored.NextAttemptAt = message.NextAttemptAt
+	stored.LastError = message.LastError
+	stored.PublishedAt = message.PublishedAt
+	s.db.outbox[i] = stored
+
+	return nil
+}
+
+func (s *OutboxStore) DeletePublishedOutbox(ctx context.Context, t time.Time) (int64, error) {
+	tracer := otel.Tracer("OutboxStore")
+	ctx, span := tracer.Start(ctx, "DeletePublishedOutbox-MemoryStore")
+	defer span.End()
+
+	unlock := s.db.lock(ctx, true)
+	defer unlock()
+
+	kept := slices.DeleteFunc(slices.Clone(s.db.outbox), func(message models.OutboxMessage) bool {
+		return message.PublishedAt != nil && message.PublishedAt.Before(t)
+	})
+	deleted := int64(len(s.db.outbox) - len(kept))
+	s.db.outbox = kept
+
+	return deleted, nil
+}
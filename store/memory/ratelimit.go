@@ -0,0 +1,50 @@
+package memory
+
+import (
+	"context"
+	"time"
+
+	"github.com/geekAshish/DriveDesk/models"
+	"go.opentelemetry.io/otel"
+)
+
+type RateLimitStore struct {
+	db *DB
+}
+
+func NewRateLimitStore(db *DB) *RateLimitStore {
+	return &RateLimitStore{db: db}
+}
+
+func (s *RateLimitStore) TakeRateLimitToken(ctx context.Context, key string, limit models.RateLimit, now time.Time) (models.RateLimitBucket, bool, error) {
+	tracer := otel.Tracer("RateLimitStore")
+	ctx, span := tracer.Start(ctx, "TakeRateLimitToken-MemoryStore")
+	defer span.End()
+
+	unlock := s.db.lock(ctx, true)
+	defer unlock()
+
+	bucket, taken := s.db.rateLimits[key].Take(limit, now)
+	s.db.rateLimits[key] = bucket
+
+	return bucket, taken, nil
+}
+
+func (s *RateLimitStore) DeleteRateLimitBucketsBefore(ctx context.Context, t time.Time) (int64, error) {
+	tracer := otel.Tracer("RateLimitStore")
+	ctx, span := tracer.Start(ctx, "DeleteRateLimitBucketsBefore-MemoryStore")
+	defer span.End()
+
+	unlock := s.db.lock(ctx, true)
+	defer unlock()
+
+	var deleted int64
+	for key, bucket := range s.db.rateLimits {
+		if bucket.UpdateAt.Before(t) {
+			delete(s.db.rateLimits, key)
+			deleted++
+		}
+	}
+
+	return deleted, nil
+}
@@ -0,0 +1,182 @@
+package memory
+
+import (
+	"context"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/geekAshish/DriveDesk/apperrors"
+	"github.com/geekAshish/DriveDesk/models"
+	"go.opentelemetry.io/otel"
+)
+
+type ReferenceStore struct {
+	db *DB
+}
+
+func NewReferenceStore(db *DB) *ReferenceStore {
+	return &ReferenceStore{db: db}
+}
+
+func (s *ReferenceStore) ListReferenceValues(ctx context.Context, kind string) ([]models.ReferenceValue, error) {
+	tracer := otel.Tracer("ReferenceStore")
+	ctx, span := tracer.Start(ctx, "ListReferenceValues-MemoryStore")
+	defer span.End()
+
+	unlock := s.db.lock(ctx, false)
+	defer unlock()
+
+	values := []models.ReferenceValue{}
+	for key, value := range s.db.references {
+		if kind == "" || key.kind == kind {
+			values = append(values, copyReferenceValue(value))
+		}
+	}
+
+	sort.Slice(values, func(i, j int) bool {
+		if values[i].Kind != values[j].Kind {
+			return values[i].Kind < values[j].Kind
+		}
+		return values[i].Code < values[j].Code
+	})
+
+	return values, nil
+}
+
+func (s *ReferenceStore) GetReferenceValue(ctx context.Context, kind, code string) (models.ReferenceValue, error) {
+	tracer := otel.Tracer("ReferenceStore")
+	ctx, span := tracer.Start(ctx, "GetReferenceValue-MemoryStore")
+	defer span.End()
+
+	unlock := s.db.lock(ctx, false)
+	defer unlock()
+
+	value, ok := s.db.references[referenceKey{kind: kind, code: code}]
+	if !ok {
+		return models.ReferenceValue{}, apperrors.NotFound("%s %s not found", kind, code)
+	}
+
+	return copyReferenceValue(value), nil
+}
+
+func (s *ReferenceStore) CreateReferenceValue(ctx context.Context, kind string, req *models.ReferenceValueRequest) (models.ReferenceValue, error) {
+	tracer := otel.Tracer("ReferenceStore")
+	ctx, span := tracer.Start(ctx, "CreateReferenceValue-MemoryStore")
+	defer span.End()
+
+	unlock := s.db.lock(ctx, true)
+	defer unlock()
+
+	if _, ok := s.db.references[referenceKey{kind: kind, code: req.Code}]; ok {
+		return models.ReferenceValue{}, apperrors.Conflict("%s %s already exists", kind, req.Code)
+	}
+	if _, ok := s.db.aliasOwner(kind, req.Code); ok {
+		return models.ReferenceValue{}, apperrors.Conflict("%s is already an alias of another %s", req.Code, kind)
+	}
+	if err := s.db.checkAliases(kind, req.Code, req.Aliases); err != nil {
+		return models.ReferenceValue{}, err
+	}
+
+	now := time.Now()
+
+	value := models.ReferenceValue{
+		Kind:     kind,
+		Code:     req.Code,
+		Label:    req.Label,
+		Aliases:  sortedAliases(req.Aliases),
+		Active:   req.Active == nil || *req.Active,
+		CreateAt: now,
+		UpdateAt: now,
+	}
+
+	s.db.references[referenceKey{kind: kind, code: req.Code}] = value
+
+	return copyReferenceValue(value), nil
+}
+
+func (s *ReferenceStore) UpdateReferenceValue(ctx context.Context, kind, code string, req *models.ReferenceValueRequest) (models.ReferenceValue, error) {
+	tracer := otel.Tracer("ReferenceStore")
+	ctx, span := tracer.Start(ctx, "UpdateReferenceValue-MemoryStore")
+	defer span.End()
+
+	unlock := s.db.lock(ctx, true)
+	defer unlock()
+
+	key := referenceKey{kind: kind, code: code}
+
+	value, ok := s.db.references[key]
+	if !ok {
+		return models.ReferenceValue{}, apperrors.NotFound("%s %s not found", kind, code)
+	}
+	if err := s.db.checkAliases(kind, code, req.Aliases); err != nil {
+		return models.ReferenceValue{}, err
+	}
+
+	value.Label = req.Label
+	value.Aliases = sortedAliases(req.Aliases)
+	if req.Active != nil {
+		value.Active = *req.Active
+	}
+	value.UpdateAt = time.Now()
+
+	s.db.references[key] = value
+
+	return copyReferenceValue(value), nil
+}
+
+func (s *ReferenceStore) DeleteReferenceValue(ctx context.Context, kind, code string) (models.ReferenceValue, error) {
+	tracer := otel.Tracer("ReferenceStore")
+	ctx, span := tracer.Start(ctx, "DeleteReferenceValue-MemoryStore")
+	defer span.End()
+
+	unlock := s.db.lock(ctx, true)
+	defer unlock()
+
+	key := referenceKey{kind: kind, code: code}
+
+	value, ok := s.db.references[key]
+	if !ok {
+		return models.ReferenceValue{}, apperrors.NotFound("%s %s not found", kind, code)
+	}
+
+	delete(s.db.references, key)
+
+	return copyReferenceValue(value), nil
+}
+
+// aliasOwner returns the code of the value of kind that has alias.
+func (db *DB) aliasOwner(kind, alias string) (string, bool) {
+	for key, value := range db.references {
+		if key.kind == kind && slices.Contains(value.Aliases, alias) {
+			return key.code, true
+		}
+	}
+	return "", false
+}
+
+// checkAliases enforces what the reference_alias table does in SQL: an alias
+// is never a code of its kind and belongs to a single value.
+func (db *DB) checkAliases(kind, code string, aliases []string) error {
+	for _, alias := range aliases {
+		if _, ok := db.references[referenceKey{kind: kind, code: alias}]; ok {
+			return apperrors.Conflict("alias %s is already a %s code", alias, kind)
+		}
+		if owner, ok := db.aliasOwner(kind, alias); ok && owner != code {
+			return apperrors.Conflict("alias %s is already used by another %s", alias, kind)
+		}
+	}
+	return nil
+}
+
+func sortedAliases(aliases []string) []string {
+	sorted := append([]string{}, aliases...)
+	sort.Strings(sorted)
+	return sorted
+}
+
+// copyReferenceValue keeps callers from modifying the stored aliases slice.
+func copyReferenceValue(value models.ReferenceValue) models.ReferenceValue {
+	value.Aliases = append([]string{}, value.Aliases...)
+	return value
+}
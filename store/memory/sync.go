@@ -0,0 +1,106 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/geekAshish/DriveDesk/apperrors"
+	"github.com/geekAshish/DriveDesk/models"
+	"go.opentelemetry.io/otel"
+)
+
+type SyncStore struct {
+	db *DB
+}
+
+func NewSyncStore(db *DB) *SyncStore {
+	return &SyncStore{db: db}
+}
+
+func (s *SyncStore) RecordSyncChange(ctx context.Context, row models.SyncRow) (int64, error) {
+	tracer := otel.Tracer("SyncStore")
+	ctx, span := tracer.Start(ctx, "RecordSyncChange-MemoryStore")
+	defer span.End()
+
+	unlock := s.db.lock(ctx, true)
+	defer unlock()
+
+	return s.record(row), nil
+}
+
+func (s *SyncStore) record(row models.SyncRow) int64 {
+	s.db.syncClock++
+	row.Version = s.db.syncClock
+	row.UpdateAt = time.Now().UTC()
+	s.db.syncRows[syncKey{entity: row.Entity, id: row.ID}] = row
+
+	return row.Version
+}
+
+func (s *SyncStore) DeleteSyncChildren(ctx context.Context, entity, parentID string) error {
+	tracer := otel.Tracer("SyncStore")
+	ctx, span := tracer.Start(ctx, "DeleteSyncChildren-MemoryStore")
+	defer span.End()
+
+	unlock := s.db.lock(ctx, true)
+	defer unlock()
+
+	var children []models.SyncRow
+	for _, row := range s.db.syncRows {
+		if row.Entity == entity && row.ParentID == parentID && !row.Deleted {
+			children = append(children, row)
+		}
+	}
+	sortSyncRows(children)
+
+	for _, row := range children {
+		row.Deleted = true
+		s.record(row)
+	}
+
+	return nil
+}
+
+func (s *SyncStore) GetSyncRow(ctx context.Context, entity, id string) (models.SyncRow, error) {
+	tracer := otel.Tracer("SyncStore")
+	ctx, span := tracer.Start(ctx, "GetSyncRow-MemoryStore")
+	defer span.End()
+
+	unlock := s.db.lock(ctx, false)
+	defer unlock()
+
+	row, ok := s.db.syncRows[syncKey{entity: entity, id: id}]
+	if !ok {
+		return models.SyncRow{}, apperrors.NotFound("%s not found", entity)
+	}
+
+	return row, nil
+}
+
+func (s *SyncStore) ListSyncRows(ctx context.Context, since int64, limit int) ([]models.SyncRow, error) {
+	tracer := otel.Tracer("SyncStore")
+	ctx, span := tracer.Start(ctx, "ListSyncRows-MemoryStore")
+	defer span.End()
+
+	unlock := s.db.lock(ctx, false)
+	defer unlock()
+
+	rows := []models.SyncRow{}
+	for _, row := range s.db.syncRows {
+		if row.Version > since {
+			rows = append(rows, row)
+		}
+	}
+
+	sortSyncRows(rows)
+	if len(rows) > limit {
+		rows = rows[:limit]
+	}
+
+	return rows, nil
+}
+
+func sortSyncRows(rows []models.SyncRow) {
+	sort.Slice(rows, func(i, j int) bool { return rows[i].Version < rows[j].Version })
+}
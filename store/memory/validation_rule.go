@@ -0,0 +1,90 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/geekAshish/DriveDesk/apperrors"
+	"github.com/geekAshish/DriveDesk/models"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel"
+)
+
+type ValidationRuleStore struct {
+	db *DB
+}
+
+func NewValidationRuleStore(db *DB) *ValidationRuleStore {
+	return &ValidationRuleStore{db: db}
+}
+
+func (s *ValidationRuleStore) ListValidationRules(ctx context.Context) ([]models.ValidationRule, error) {
+	tracer := otel.Tracer("ValidationRuleStore")
+	ctx, span := tracer.Start(ctx, "ListValidationRules-MemoryStore")
+	defer span.End()
+
+	unlock := s.db.lock(ctx, false)
+	defer unlock()
+
+	rules := make([]models.ValidationRule, 0, len(s.db.rules))
+	for _, rule := range s.db.rules {
+		rules = append(rules, rule)
+	}
+
+	sort.Slice(rules, func(i, j int) bool {
+		if !rules[i].CreateAt.Equal(rules[j].CreateAt) {
+			return rules[i].CreateAt.Before(rules[j].CreateAt)
+		}
+		return rules[i].ID.String() < rules[j].ID.String()
+	})
+
+	return rules, nil
+}
+
+func (s *ValidationRuleStore) CreateValidationRule(ctx context.Context, req *models.ValidationRuleRequest) (models.ValidationRule, error) {
+	tracer := otel.Tracer("ValidationRuleStore")
+	ctx, span := tracer.Start(ctx, "CreateValidationRule-MemoryStore")
+	defer span.End()
+
+	unlock := s.db.lock(ctx, true)
+	defer unlock()
+
+	rule := models.ValidationRule{
+		ID:       uuid.New(),
+		Target:   req.Target,
+		Field:    req.Field,
+		Operator: req.Operator,
+		Value:    append([]byte(nil), req.Value...),
+		Code:     req.Code,
+		Message:  req.Message,
+		CreateAt: time.Now(),
+	}
+
+	s.db.rules[rule.ID] = rule
+
+	return rule, nil
+}
+
+func (s *ValidationRuleStore) DeleteValidationRule(ctx context.Context, id string) (models.ValidationRule, error) {
+	tracer := otel.Tracer("ValidationRuleStore")
+	ctx, span := tracer.Start(ctx, "DeleteValidationRule-MemoryStore")
+	defer span.End()
+
+	unlock := s.db.lock(ctx, true)
+	defer unlock()
+
+	ruleID, err := uuid.Parse(id)
+	if err != nil {
+		return models.ValidationRule{}, apperrors.NotFound("validation rule not found")
+	}
+
+	rule, ok := s.db.rules[ruleID]
+	if !ok {
+		return models.ValidationRule{}, apperrors.NotFound("validation rule not found")
+	}
+
+	delete(s.db.rules, ruleID)
+
+	return rule, nil
+}
@@ -0,0 +1,302 @@
+package memory
+
+import (
+	"context"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/geekAshish/DriveDesk/apperrors"
+	"github.com/geekAshish/DriveDesk/models"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel"
+)
+
+type WebhookStore struct {
+	db *DB
+}
+
+func NewWebhookStore(db *DB) *WebhookStore {
+	return &WebhookStore{db: db}
+}
+
+func (s *WebhookStore) ListWebhooks(ctx context.Context) ([]models.Webhook, error) {
+	tracer := otel.Tracer("WebhookStore")
+	ctx, span := tracer.Start(ctx, "ListWebhooks-MemoryStore")
+	defer span.End()
+
+	unlock := s.db.lock(ctx, false)
+	defer unlock()
+
+	webhooks := make([]models.Webhook, 0, len(s.db.webhooks))
+	for _, webhook := range s.db.webhooks {
+		webhooks = append(webhooks, copyWebhook(webhook))
+	}
+
+	sort.Slice(webhooks, func(i, j int) bool {
+		if !webhooks[i].CreateAt.Equal(webhooks[j].CreateAt) {
+			return webhooks[i].CreateAt.Before(webhooks[j].CreateAt)
+		}
+		return webhooks[i].ID.String() < webhooks[j].ID.String()
+	})
+
+	return webhooks, nil
+}
+
+func (s *WebhookStore) GetWebhook(ctx context.Context, id string) (models.Webhook, error) {
+	tracer := otel.Tracer("WebhookStore")
+	ctx, span := tracer.Start(ctx, "GetWebhook-MemoryStore")
+	defer span.End()
+
+	unlock := s.db.lock(ctx, false)
+	defer unlock()
+
+	webhook, err := s.db.webhook(id)
+	if err != nil {
+		return models.Webhook{}, err
+	}
+
+	return copyWebhook(webhook), nil
+}
+
+func (s *WebhookStore) CreateWebhook(ctx context.Context, req *models.WebhookRequest) (models.Webhook, error) {
+	tracer := otel.Tracer("WebhookStore")
+	ctx, span := tracer.Start(ctx, "CreateWebhook-MemoryStore")
+	defer span.End()
+
+	unlock := s.db.lock(ctx, true)
+	defer unlock()
+
+	now := time.Now()
+	webhook := models.Webhook{
+		ID:       uuid.New(),
+		URL:      req.URL,
+		Events:   slices.Clone(req.Events),
+		Secret:   req.Secret,
+		Active:   req.Active == nil || *req.Active,
+		CreateAt: now,
+		UpdateAt: now,
+	}
+
+	s.db.webhooks[webhook.ID] = webhook
+
+	return copyWebhook(webhook), nil
+}
+
+func (s *WebhookStore) UpdateWebhook(ctx context.Context, id string, req *models.WebhookRequest) (models.Webhook, error) {
+	tracer := otel.Tracer("WebhookStore")
+	ctx, span := tracer.Start(ctx, "UpdateWebhook-MemoryStore")
+	defer span.End()
+
+	unlock := s.db.lock(ctx, true)
+	defer unlock()
+
+	webhook, err := s.db.webhook(id)
+	if err != nil {
+		return models.Webhook{}, err
+	}
+
+	webhook.URL = req.URL
+	webhook.Events = slices.Clone(req.Events)
+	if req.Secret != "" {
+		webhook.Secret = req.Secret
+	}
+	if req.Active != nil {
+		webhook.Active = *req.Active
+	}
+	webhook.UpdateAt = time.Now()
+
+	s.db.webhooks[webhook.ID] = webhook
+
+	return copyWebhook(webhook), nil
+}
+
+func (s *WebhookStore) DeleteWebhook(ctx context.Context, id string) (models.Webhook, error) {
+	tracer := otel.Tracer("WebhookStore")
+	ctx, span := tracer.Start(ctx, "DeleteWebhook-MemoryStore")
+	defer span.End()
+
+	unlock := s.db.lock(ctx, true)
+	defer unlock()
+
+	webhook, err := s.db.webhook(id)
+	if err != nil {
+		return models.Webhook{}, err
+	}
+
+	delete(s.db.webhooks, webhook.ID)
+
+	// the deliveries go with the webhook, as ON DELETE CASCADE does in SQL
+	for deliveryID, delivery := range s.db.deliveries {
+		if delivery.WebhookID == webhook.ID {
+			delete(s.db.deliveries, deliveryID)
+		}
+	}
+
+	return webhook, nil
+}
+
+func (s *WebhookStore) CreateWebhookDeliveries(ctx context.Context, deliveries []models.WebhookDelivery) error {
+	tracer := otel.Tracer("WebhookStore")
+	ctx, span := tracer.Start(ctx, "CreateWebhookDeliveries-MemoryStore")
+	defer span.End()
+
+	unlock := s.db.lock(ctx, true)
+	defer unlock()
+
+	for _, delivery := range deliveries {
+		if _, ok := s.db.webhooks[delivery.WebhookID]; !ok {
+			return apperrors.Validation("referenced resource does not exist")
+		}
+	}
+
+	for _, delivery := range deliveries {
+		s.db.deliveries[delivery.ID] = copyDelivery(delivery)
+	}
+
+	return nil
+}
+
+func (s *WebhookStore) ListWebhookDeliveries(ctx context.Context, filter models.WebhookDeliveryFilter) ([]models.WebhookDelivery, error) {
+	tracer := otel.Tracer("WebhookStore")
+	ctx, span := tracer.Start(ctx, "ListWebhookDeliveries-MemoryStore")
+	defer span.End()
+
+	unlock := s.db.lock(ctx, false)
+	defer unlock()
+
+	deliveries := []models.WebhookDelivery{}
+	for _, delivery := range s.db.deliveries {
+		if filter.WebhookID != "" && delivery.WebhookID.String() != filter.WebhookID {
+			continue
+		}
+		if filter.Status != "" && delivery.Status != filter.Status {
+			continue
+		}
+		deliveries = append(deliveries, copyDelivery(delivery))
+	}
+
+	sort.Slice(deliveries, func(i, j int) bool {
+		if !deliveries[i].CreateAt.Equal(deliveries[j].CreateAt) {
+			return deliveries[i].CreateAt.After(deliveries[j].CreateAt)
+		}
+		return deliveries[i].ID.String() > deliveries[j].ID.String()
+	})
+
+	if filter.Limit > 0 && len(deliveries) > filter.Limit {
+		deliveries = deliveries[:filter.Limit]
+	}
+
+	return deliveries, nil
+}
+
+func (s *WebhookStore) GetWebhookDelivery(ctx context.Context, id string) (models.WebhookDelivery, error) {
+	tracer := otel.Tracer("WebhookStore")
+	ctx, span := tracer.Start(ctx, "GetWebhookDelivery-MemoryStore")
+	defer span.End()
+
+	unlock := s.db.lock(ctx, false)
+	defer unlock()
+
+	deliveryID, err := uuid.Parse(id)
+	if err != nil {
+		return models.WebhookDelivery{}, apperrors.NotFound("webhook delivery not found")
+	}
+
+	delivery, ok := s.db.deliveries[deliveryID]
+	if !ok {
+		return models.WebhookDelivery{}, apperrors.NotFound("webhook delivery not found")
+	}
+
+	return copyDelivery(delivery), nil
+}
+
+func (s *WebhookStore) ClaimWebhookDeliveries(ctx context.Context, now, leaseUntil time.Time, limit int) ([]models.WebhookDelivery, error) {
+	tracer := otel.Tracer("WebhookStore")
+	ctx, span := tracer.Start(ctx, "ClaimWebhookDeliveries-MemoryStore")
+	defer span.End()
+
+	unlock := s.db.lock(ctx, true)
+	defer unlock()
+
+	due := []models.WebhookDelivery{}
+	for _, delivery := range s.db.deliveries {
+		if delivery.Status == models.DeliveryPending && !delivery.NextAttemptAt.After(now) {
+			due = append(due, delivery)
+		}
+	}
+
+	sortDue(due)
+	if len(due) > limit {
+		due = due[:limit]
+	}
+
+	for i, delivery := range due {
+		delivery.NextAttemptAt = leaseUntil
+		s.db.deliveries[delivery.ID] = delivery
+		due[i] = copyDelivery(delivery)
+	}
+
+	return due, nil
+}
+
+func (s *WebhookStore) UpdateWebhookDelivery(ctx context.Context, delivery models.WebhookDelivery) error {
+	tracer := otel.Tracer("WebhookStore")
+	ctx, span := tracer.Start(ctx, "UpdateWebhookDelivery-MemoryStore")
+	defer span.End()
+
+	unlock := s.db.lock(ctx, true)
+	defer unlock()
+
+	stored, ok := s.db.deliveries[delivery.ID]
+	if !ok {
+		return apperrors.NotFound("webhook delivery not found")
+	}
+
+	stored.Status = delivery.Status
+	stored.Attempts = delivery.Attempts
+	stored.NextAttemptAt = delivery.NextAttemptAt
+	stored.LastStatusCode = delivery.LastStatusCode
+	stored.LastError = delivery.LastError
+	stored.UpdateAt = time.Now()
+	s.db.deliveries[stored.ID] = stored
+
+	return nil
+}
+
+// webhook returns the webhook with the given id. The caller must hold the
+// lock.
+func (db *DB) webhook(id string) (models.Webhook, error) {
+	webhookID, err := uuid.Parse(id)
+	if err != nil {
+		return models.Webhook{}, apperrors.NotFound("webhook not found")
+	}
+
+	webhook, ok := db.webhooks[webhookID]
+	if !ok {
+		return models.Webhook{}, apperrors.NotFound("webhook not found")
+	}
+
+	return webhook, nil
+}
+
+// sortDue orders deliveries the way they are attempted: the longest waiting
+// first, then in the order their events happened.
+func sortDue(deliveries []models.WebhookDelivery) {
+	sort.Slice(deliveries, func(i, j int) bool {
+		if !deliveries[i].NextAttemptAt.Equal(deliveries[j].NextAttemptAt) {
+			return deliveries[i].NextAttemptAt.Before(deliveries[j].NextAttemptAt)
+		}
+		return deliveries[i].CreateAt.Before(deliveries[j].CreateAt)
+	})
+}
+
+func copyWebhook(webhook models.Webhook) models.Webhook {
+	webhook.Events = slices.Clone(webhook.Events)
+	return webhook
+}
+
+func copyDelivery(delivery models.WebhookDelivery) models.WebhookDelivery {
+	delivery.Payload = slices.Clone(delivery.Payload)
+	return delivery
+}
@@ -0,0 +1,168 @@
+package migrations_test
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"testing"
+
+	"github.com/geekAshish/DriveDesk/driver"
+	"github.com/geekAshish/DriveDesk/store/migrations"
+	"github.com/geekAshish/DriveDesk/store/sqlite"
+)
+
+// newSQLite returns a migrator for an empty SQLite database.
+func newSQLite(t *testing.T) (*migrations.Migrator, *sql.DB) {
+	t.Helper()
+
+	db, err := driver.OpenSQLite(filepath.Join(t.TempDir(), "drivedesk.db"))
+	if err != nil {
+		t.Fatalf("opening sqlite: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	migrator, err := migrations.NewSQLite(db)
+	if err != nil {
+		t.Fatalf("loading migrations: %v", err)
+	}
+	return migrator, db
+}
+
+// recordedVersion is the latest version recorded in schema_migrations.
+func recordedVersion(t *testing.T, db *sql.DB) int {
+	t.Helper()
+
+	var version sql.NullInt64
+	if err := db.QueryRow(`SELECT MAX(version) FROM schema_migrations`).Scan(&version); err != nil {
+		t.Fatalf("reading schema_migrations: %v", err)
+	}
+	return int(version.Int64)
+}
+
+// applied lists the versions Status reports as applied.
+func applied(t *testing.T, migrator *migrations.Migrator) []int {
+	t.Helper()
+
+	statuses, err := migrator.Status(context.Background())
+	if err != nil {
+		t.Fatalf("Status: %v", err)
+	}
+
+	var versions []int
+	for _, status := range statuses {
+		if status.Applied {
+			if status.AppliedAt.IsZero() {
+				t.Errorf("migration %d is applied without a time", status.Version)
+			}
+			versions = append(versions, status.Version)
+		}
+	}
+	return versions
+}
+
+func TestUpIsIdempotent(t *testing.T) {
+	ctx := context.Background()
+	migrator, db := newSQLite(t)
+
+	statuses, err := migrator.Status(ctx)
+	if err != nil || len(statuses) == 0 {
+		t.Fatalf("Status of an empty database = %v, %v, want every migration pending", statuses, err)
+	}
+	if versions := applied(t, migrator); len(versions) != 0 {
+		t.Fatalf("applied on an empty database: %v", versions)
+	}
+	latest := statuses[len(statuses)-1].Version
+
+	ran, err := migrator.Up(ctx)
+	if err != nil || len(ran) != len(statuses) {
+		t.Fatalf("Up = %d migrations, %v, want all %d", len(ran), err, len(statuses))
+	}
+	if version := recordedVersion(t, db); version != latest {
+		t.Errorf("recorded version = %d, want %d", version, latest)
+	}
+
+	ran, err = migrator.Up(ctx)
+	if err != nil || len(ran) != 0 {
+		t.Fatalf("second Up = %v, %v, want nothing to apply", ran, err)
+	}
+	if versions := applied(t, migrator); len(versions) != len(statuses) {
+		t.Errorf("applied %v, want all %d migrations", versions, len(statuses))
+	}
+}
+
+func TestDownAndStatus(t *testing.T) {
+	ctx := context.Background()
+	migrator, db := newSQLite(t)
+
+	all, err := migrator.Up(ctx)
+	if err != nil {
+		t.Fatalf("Up: %v", err)
+	}
+
+	// down rolls back the newest first and status reports them pending
+	reverted, err := migrator.Down(ctx, 2)
+	if err != nil || len(reverted) != 2 || reverted[0].Version != all[len(all)-1].Version || reverted[1].Version != all[len(all)-2].Version {
+		t.Fatalf("Down(2) = %v, %v, want the two newest migrations", reverted, err)
+	}
+	if versions := applied(t, migrator); len(versions) != len(all)-2 || versions[len(versions)-1] != all[len(all)-3].Version {
+		t.Errorf("applied after Down(2) = %v", versions)
+	}
+	if version := recordedVersion(t, db); version != all[len(all)-3].Version {
+		t.Errorf("recorded version after Down(2) = %d, want %d", version, all[len(all)-3].Version)
+	}
+
+	ran, err := migrator.Up(ctx)
+	if err != nil || len(ran) != 2 {
+		t.Fatalf("Up after Down(2) = %v, %v, want the two rolled back", ran, err)
+	}
+
+	// rolling back more than was applied stops at an empty schema, from
+	// which every migration applies a second time
+	reverted, err = migrator.Down(ctx, len(all)+1)
+	if err != nil || len(reverted) != len(all) {
+		t.Fatalf("Down(%d) = %d migrations, %v, want all %d", len(all)+1, len(reverted), err, len(all))
+	}
+	if versions := applied(t, migrator); len(versions) != 0 {
+		t.Errorf("applied after rolling back everything: %v", versions)
+	}
+	var tables int
+	if err := db.QueryRow(`SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name IN ('car', 'engine', 'sync_row')`).Scan(&tables); err != nil || tables != 0 {
+		t.Errorf("%d tables left after rolling back everything, %v", tables, err)
+	}
+
+	if ran, err := migrator.Up(ctx); err != nil || len(ran) != len(all) {
+		t.Fatalf("Up after rolling back everything = %d migrations, %v, want all %d", len(ran), err, len(all))
+	}
+	if version := recordedVersion(t, db); version != all[len(all)-1].Version {
+		t.Errorf("recorded version = %d, want %d", version, all[len(all)-1].Version)
+	}
+}
+
+func TestSeedRecordsSyncRows(t *testing.T) {
+	ctx := context.Background()
+	migrator, db := newSQLite(t)
+	if _, err := migrator.Up(ctx); err != nil {
+		t.Fatalf("Up: %v", err)
+	}
+
+	// seeding again must not move the versions of what is already seeded
+	for range 2 {
+		if err := migrator.Seed(ctx); err != nil {
+			t.Fatalf("Seed: %v", err)
+		}
+	}
+
+	rows, err := sqlite.NewSyncStore(db).ListSyncRows(ctx, 0, 100)
+	if err != nil {
+		t.Fatalf("ListSyncRows: %v", err)
+	}
+	if len(rows) != 8 || rows[0].Entity != "engine" || rows[7].Entity != "car" || rows[7].Version != 8 {
+		t.Fatalf("sync rows = %+v, want the 4 seeded engines and then the 4 seeded cars", rows)
+	}
+
+	// the next change continues from the seeded versions
+	version, err := sqlite.NewSyncStore(db).RecordSyncChange(ctx, rows[0])
+	if err != nil || version != 9 {
+		t.Errorf("RecordSyncChange = %d, %v, want version 9", version, err)
+	}
+}
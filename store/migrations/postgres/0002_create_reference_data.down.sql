@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS reference_alias;
+
+DROP TABLE IF EXISTS reference_value;
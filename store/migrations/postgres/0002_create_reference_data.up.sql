@@ -0,0 +1,36 @@
+CREATE TABLE IF NOT EXISTS reference_value (
+    kind VARCHAR(50) NOT NULL,
+    code VARCHAR(50) NOT NULL,
+    label VARCHAR(255) NOT NULL,
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (kind, code)
+);
+
+-- an alias belongs to one value of its kind and is never also a code of that kind
+CREATE TABLE IF NOT EXISTS reference_alias (
+    kind VARCHAR(50) NOT NULL,
+    alias VARCHAR(50) NOT NULL,
+    code VARCHAR(50) NOT NULL,
+    PRIMARY KEY (kind, alias),
+    CONSTRAINT fk_reference_alias_value FOREIGN KEY (kind, code) REFERENCES reference_value(kind, code) ON DELETE CASCADE ON UPDATE CASCADE
+);
+
+INSERT INTO reference_value (kind, code, label)
+VALUES
+    ('fuel_type', 'petrol', 'Petrol'),
+    ('fuel_type', 'diesel', 'Diesel'),
+    ('fuel_type', 'electric', 'Electric'),
+    ('fuel_type', 'hybrid', 'Hybrid')
+ON CONFLICT (kind, code) DO NOTHING;
+
+INSERT INTO reference_alias (kind, alias, code)
+VALUES
+    ('fuel_type', 'gasoline', 'petrol'),
+    ('fuel_type', 'gas', 'petrol')
+ON CONFLICT (kind, alias) DO NOTHING;
+
+-- cars stored before fuel types were reference data may use an alias
+UPDATE car SET fuel_type = 'petrol' WHERE LOWER(fuel_type) IN ('gasoline', 'gas');
+UPDATE car SET fuel_type = LOWER(fuel_type) WHERE LOWER(fuel_type) IN ('petrol', 'diesel', 'electric', 'hybrid');
@@ -0,0 +1,4 @@
+-- optional; NULLs do not clash, so the index only applies to cars that have one
+ALTER TABLE car ADD COLUMN IF NOT EXISTS vin VARCHAR(17);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_car_vin ON car (vin);
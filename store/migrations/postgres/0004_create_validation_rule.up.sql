@@ -0,0 +1,11 @@
+-- deployment specific validation rules, evaluated on top of the built-in ones
+CREATE TABLE IF NOT EXISTS validation_rule (
+    id UUID PRIMARY KEY,
+    target VARCHAR(50) NOT NULL,
+    field VARCHAR(255) NOT NULL,
+    operator VARCHAR(50) NOT NULL,
+    value TEXT,
+    code VARCHAR(50) NOT NULL,
+    message VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
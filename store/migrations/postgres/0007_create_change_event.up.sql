@@ -0,0 +1,12 @@
+-- recent car and engine changes for the change stream; seq is the id clients resume from
+CREATE TABLE IF NOT EXISTS change_event (
+    seq BIGSERIAL PRIMARY KEY,
+    id UUID NOT NULL,
+    type VARCHAR(50) NOT NULL,
+    subject VARCHAR(255) NOT NULL,
+    brand VARCHAR(255) NOT NULL DEFAULT '',
+    data TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_change_event_created_at ON change_event (created_at);
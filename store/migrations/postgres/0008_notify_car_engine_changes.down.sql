@@ -0,0 +1,3 @@
+DROP TRIGGER IF EXISTS trg_engine_notify_change ON engine;
+DROP TRIGGER IF EXISTS trg_car_notify_change ON car;
+DROP FUNCTION IF EXISTS drivedesk_notify_change();
@@ -0,0 +1,32 @@
+-- tells every listening instance about each committed change to a car or an engine;
+-- the payload is {"table": "car", "op": "insert|update|delete", "id": "...", "brand": "..."}
+CREATE OR REPLACE FUNCTION drivedesk_notify_change() RETURNS trigger AS $$
+DECLARE
+    changed JSONB;
+BEGIN
+    IF TG_OP = 'DELETE' THEN
+        changed := to_jsonb(OLD);
+    ELSE
+        changed := to_jsonb(NEW);
+    END IF;
+
+    PERFORM pg_notify('drivedesk_changes', json_build_object(
+        'table', TG_TABLE_NAME,
+        'op', lower(TG_OP),
+        'id', changed->>'id',
+        'brand', COALESCE(changed->>'brand', '')
+    )::text);
+
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS trg_car_notify_change ON car;
+CREATE TRIGGER trg_car_notify_change
+    AFTER INSERT OR UPDATE OR DELETE ON car
+    FOR EACH ROW EXECUTE FUNCTION drivedesk_notify_change();
+
+DROP TRIGGER IF EXISTS trg_engine_notify_change ON engine;
+CREATE TRIGGER trg_engine_notify_change
+    AFTER INSERT OR UPDATE OR DELETE ON engine
+    FOR EACH ROW EXECUTE FUNCTION drivedesk_notify_change();
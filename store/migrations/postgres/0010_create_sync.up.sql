@@ -0,0 +1,34 @@
+-- a single counter gives every car and engine change the next version; the
+-- row lock taken by the increment makes versions commit in order
+CREATE TABLE IF NOT EXISTS sync_clock (
+    id INT PRIMARY KEY CHECK (id = 1),
+    version BIGINT NOT NULL
+);
+
+INSERT INTO sync_clock (id, version) VALUES (1, 0) ON CONFLICT DO NOTHING;
+
+-- the current version of every car and engine, and tombstones for the deleted ones
+CREATE TABLE IF NOT EXISTS sync_row (
+    entity VARCHAR(20) NOT NULL,
+    id VARCHAR(64) NOT NULL,
+    parent_id VARCHAR(64) NOT NULL DEFAULT '',
+    version BIGINT NOT NULL,
+    deleted BOOLEAN NOT NULL DEFAULT FALSE,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (entity, id)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_sync_row_version ON sync_row (version);
+CREATE INDEX IF NOT EXISTS idx_sync_row_parent ON sync_row (entity, parent_id) WHERE NOT deleted;
+
+-- existing engines and cars get the first versions
+INSERT INTO sync_row (entity, id, parent_id, version)
+SELECT entity, id, parent_id, ROW_NUMBER() OVER (ORDER BY kind, created_at, id)
+FROM (
+    SELECT 'engine' AS entity, id::text AS id, '' AS parent_id, 0 AS kind, created_at FROM engine
+    UNION ALL
+    SELECT 'car', id::text, engine_id::text, 1, created_at FROM car
+) existing
+ON CONFLICT DO NOTHING;
+
+UPDATE sync_clock SET version = (SELECT COALESCE(MAX(version), 0) FROM sync_row) WHERE id = 1;
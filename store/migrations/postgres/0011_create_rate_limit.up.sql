@@ -0,0 +1,8 @@
+-- token buckets of rate limited clients, shared by every instance
+CREATE TABLE IF NOT EXISTS rate_limit_bucket (
+    key VARCHAR(255) PRIMARY KEY,
+    tokens DOUBLE PRECISION NOT NULL,
+    updated_at TIMESTAMP NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_rate_limit_bucket_updated_at ON rate_limit_bucket (updated_at);
@@ -0,0 +1,33 @@
+-- Demo inventory, applied with `migrate seed`. Safe to run more than once.
+INSERT OR IGNORE INTO engine (id, displacement, no_of_cylinders, car_range)
+VALUES
+    ('e1f86b1a-0873-4c19-bae2-fc60329d0140', 2000, 4, 600),
+    ('f4a9c66b-8e38-419b-93c4-215d5cefb318', 1600, 4, 550),
+    ('cc2c2a7d-2e21-4f59-b7b8-bd9e5e4cf04c', 3000, 6, 700),
+    ('9746be12-07b7-42a3-b8ab-7d1f209b63d7', 1800, 4, 500);
+
+INSERT OR IGNORE INTO car (id, name, year, brand, fuel_type, engine_id, price)
+VALUES
+    ('c7c1a6d5-1ec4-4c64-a59a-8a2f6f3d2bf3', 'Honda Civic', '2023', 'Honda', 'petrol', 'e1f86b1a-0873-4c19-bae2-fc60329d0140', 25000.00),
+    ('9d6a56f8-79c3-4931-a5c0-6b290c84ba2f', 'Toyota Corolla', '2022', 'Toyota', 'petrol', 'f4a9c66b-8e38-419b-93c4-215d5cefb318', 22000.00),
+    ('9b9437c4-3ed1-45a5-b240-0fe3e24e0e4e', 'Ford Mustang', '2024', 'Ford', 'petrol', 'cc2c2a7d-2e21-4f59-b7b8-bd9e5e4cf04c', 40000.00),
+    ('5e9df51a-8d7a-4d84-9c58-4ccfe5c7db06', 'BMW 3 Series', '2023', 'BMW', 'petrol', '9746be12-07b7-42a3-b8ab-7d1f209b63d7', 35000.00);
+
+-- seeded engines and cars get the next sync versions, as if they had been
+-- created through the API, so that offline clients pull them
+INSERT INTO sync_row (entity, id, parent_id, version, deleted, updated_at)
+SELECT entity, id, parent_id,
+    (SELECT version FROM sync_clock WHERE id = 1) + ROW_NUMBER() OVER (ORDER BY kind, created_at, id),
+    0, CAST(strftime('%s', 'now') AS INTEGER) * 1000
+FROM (
+    SELECT 'engine' AS entity, id, '' AS parent_id, 0 AS kind, created_at FROM engine
+    UNION ALL
+    SELECT 'car', id, engine_id, 1, created_at FROM car
+) seeded
+WHERE NOT EXISTS (
+    SELECT 1 FROM sync_row s WHERE s.entity = seeded.entity AND s.id = seeded.id AND s.deleted = 0
+)
+ON CONFLICT (entity, id) DO UPDATE
+SET parent_id = excluded.parent_id, version = excluded.version, deleted = excluded.deleted, updated_at = excluded.updated_at;
+
+UPDATE sync_clock SET version = (SELECT COALESCE(MAX(version), 0) FROM sync_row) WHERE id = 1;
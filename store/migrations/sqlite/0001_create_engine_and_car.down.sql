@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS car;
+
+DROP TABLE IF EXISTS engine;
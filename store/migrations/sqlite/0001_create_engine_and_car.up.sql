@@ -0,0 +1,24 @@
+-- SQLite has no UUID type, ids are stored in their canonical text form
+CREATE TABLE IF NOT EXISTS engine (
+    id TEXT PRIMARY KEY,
+    displacement INTEGER NOT NULL,
+    no_of_cylinders INTEGER NOT NULL,
+    car_range INTEGER NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS car (
+    id TEXT PRIMARY KEY,
+    name TEXT NOT NULL,
+    year TEXT NOT NULL,
+    brand TEXT NOT NULL,
+    fuel_type TEXT NOT NULL,
+    engine_id TEXT NOT NULL REFERENCES engine(id) ON DELETE CASCADE,
+    price REAL NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_car_brand ON car (brand);
+CREATE INDEX IF NOT EXISTS idx_car_engine_id ON car (engine_id);
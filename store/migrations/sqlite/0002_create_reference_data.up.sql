@@ -0,0 +1,34 @@
+CREATE TABLE IF NOT EXISTS reference_value (
+    kind TEXT NOT NULL,
+    code TEXT NOT NULL,
+    label TEXT NOT NULL,
+    active INTEGER NOT NULL DEFAULT 1,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (kind, code)
+);
+
+-- an alias belongs to one value of its kind and is never also a code of that kind
+CREATE TABLE IF NOT EXISTS reference_alias (
+    kind TEXT NOT NULL,
+    alias TEXT NOT NULL,
+    code TEXT NOT NULL,
+    PRIMARY KEY (kind, alias),
+    FOREIGN KEY (kind, code) REFERENCES reference_value(kind, code) ON DELETE CASCADE ON UPDATE CASCADE
+);
+
+INSERT OR IGNORE INTO reference_value (kind, code, label)
+VALUES
+    ('fuel_type', 'petrol', 'Petrol'),
+    ('fuel_type', 'diesel', 'Diesel'),
+    ('fuel_type', 'electric', 'Electric'),
+    ('fuel_type', 'hybrid', 'Hybrid');
+
+INSERT OR IGNORE INTO reference_alias (kind, alias, code)
+VALUES
+    ('fuel_type', 'gasoline', 'petrol'),
+    ('fuel_type', 'gas', 'petrol');
+
+-- cars stored before fuel types were reference data may use an alias
+UPDATE car SET fuel_type = 'petrol' WHERE LOWER(fuel_type) IN ('gasoline', 'gas');
+UPDATE car SET fuel_type = LOWER(fuel_type) WHERE LOWER(fuel_type) IN ('petrol', 'diesel', 'electric', 'hybrid');
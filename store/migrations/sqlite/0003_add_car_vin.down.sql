@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_car_vin;
+
+ALTER TABLE car DROP COLUMN vin;
@@ -0,0 +1,11 @@
+-- deployment specific validation rules, evaluated on top of the built-in ones
+CREATE TABLE IF NOT EXISTS validation_rule (
+    id TEXT PRIMARY KEY,
+    target TEXT NOT NULL,
+    field TEXT NOT NULL,
+    operator TEXT NOT NULL,
+    value TEXT,
+    code TEXT NOT NULL,
+    message TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
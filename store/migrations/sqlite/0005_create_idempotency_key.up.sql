@@ -0,0 +1,15 @@
+-- responses replayed to clients retrying a POST with the same Idempotency-Key
+CREATE TABLE IF NOT EXISTS idempotency_key (
+    owner TEXT NOT NULL,
+    key TEXT NOT NULL,
+    request_hash TEXT NOT NULL,
+    status INTEGER NOT NULL DEFAULT 0,
+    content_type TEXT NOT NULL DEFAULT '',
+    body BLOB,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    -- unix milliseconds, so expiry compares as a number rather than as text
+    expires_at INTEGER NOT NULL,
+    PRIMARY KEY (owner, key)
+);
+
+CREATE INDEX IF NOT EXISTS idx_idempotency_key_expires_at ON idempotency_key (expires_at);
@@ -0,0 +1,13 @@
+-- recent car and engine changes for the change stream; seq is the id clients resume from
+CREATE TABLE IF NOT EXISTS change_event (
+    seq INTEGER PRIMARY KEY AUTOINCREMENT,
+    id TEXT NOT NULL,
+    type TEXT NOT NULL,
+    subject TEXT NOT NULL,
+    brand TEXT NOT NULL DEFAULT '',
+    data TEXT NOT NULL,
+    -- unix milliseconds, so old events can be purged by comparing numbers
+    created_at INTEGER NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_change_event_created_at ON change_event (created_at);
@@ -0,0 +1,21 @@
+-- events waiting to be published, one row per sink, written in the transaction of the change
+CREATE TABLE IF NOT EXISTS outbox (
+    seq INTEGER PRIMARY KEY AUTOINCREMENT,
+    sink TEXT NOT NULL,
+    aggregate_type TEXT NOT NULL,
+    aggregate_id TEXT NOT NULL,
+    event_id TEXT NOT NULL,
+    event_type TEXT NOT NULL,
+    payload TEXT NOT NULL,
+    status TEXT NOT NULL,
+    attempts INTEGER NOT NULL DEFAULT 0,
+    -- times are unix milliseconds, so they compare as numbers
+    next_attempt_at INTEGER NOT NULL,
+    last_error TEXT NOT NULL DEFAULT '',
+    created_at INTEGER NOT NULL,
+    published_at INTEGER
+);
+
+CREATE INDEX IF NOT EXISTS idx_outbox_sink_status ON outbox (sink, status, next_attempt_at);
+CREATE INDEX IF NOT EXISTS idx_outbox_aggregate ON outbox (sink, aggregate_type, aggregate_id, seq);
+CREATE INDEX IF NOT EXISTS idx_outbox_published_at ON outbox (published_at);
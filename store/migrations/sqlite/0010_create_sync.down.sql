@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS sync_row;
+DROP TABLE IF EXISTS sync_clock;
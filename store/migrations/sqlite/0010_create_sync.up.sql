@@ -0,0 +1,33 @@
+-- a single counter gives every car and engine change the next version
+CREATE TABLE IF NOT EXISTS sync_clock (
+    id INTEGER PRIMARY KEY CHECK (id = 1),
+    version INTEGER NOT NULL
+);
+
+INSERT OR IGNORE INTO sync_clock (id, version) VALUES (1, 0);
+
+-- the current version of every car and engine, and tombstones for the deleted ones
+CREATE TABLE IF NOT EXISTS sync_row (
+    entity TEXT NOT NULL,
+    id TEXT NOT NULL,
+    parent_id TEXT NOT NULL DEFAULT '',
+    version INTEGER NOT NULL,
+    deleted INTEGER NOT NULL DEFAULT 0,
+    -- unix milliseconds
+    updated_at INTEGER NOT NULL DEFAULT 0,
+    PRIMARY KEY (entity, id)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_sync_row_version ON sync_row (version);
+CREATE INDEX IF NOT EXISTS idx_sync_row_parent ON sync_row (entity, parent_id) WHERE deleted = 0;
+
+-- existing engines and cars get the first versions
+INSERT OR IGNORE INTO sync_row (entity, id, parent_id, version)
+SELECT entity, id, parent_id, ROW_NUMBER() OVER (ORDER BY kind, created_at, id)
+FROM (
+    SELECT 'engine' AS entity, id, '' AS parent_id, 0 AS kind, created_at FROM engine
+    UNION ALL
+    SELECT 'car', id, engine_id, 1, created_at FROM car
+);
+
+UPDATE sync_clock SET version = (SELECT COALESCE(MAX(version), 0) FROM sync_row) WHERE id = 1;
@@ -0,0 +1,9 @@
+-- token buckets of rate limited clients, shared by every instance
+CREATE TABLE IF NOT EXISTS rate_limit_bucket (
+    key TEXT PRIMARY KEY,
+    tokens REAL NOT NULL,
+    -- unix milliseconds, like idempotency_key.expires_at
+    updated_at INTEGER NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_rate_limit_bucket_updated_at ON rate_limit_bucket (updated_at);
@@ -0,0 +1,2 @@
+-- the response headers replayed besides content_type, as a JSON object
+ALTER TABLE idempotency_key ADD COLUMN header TEXT NOT NULL DEFAULT '{}';
@@ -0,0 +1,180 @@
+package outbox
+
+import (
+	"context"
+	"database/sql"
+	"sort"
+	"time"
+
+	"github.com/geekAshish/DriveDesk/apperrors"
+	"github.com/geekAshish/DriveDesk/models"
+	"github.com/geekAshish/DriveDesk/store"
+	"go.opentelemetry.io/otel"
+)
+
+const outboxColumns = `seq, sink, aggregate_type, aggregate_id, event_id, event_type, payload,
+	status, attempts, next_attempt_at, last_error, created_at, published_at`
+
+type Store struct {
+	db *sql.DB
+}
+
+func New(db *sql.DB) *Store {
+	return &Store{db: db}
+}
+
+func (s *Store) AppendOutbox(ctx context.Context, messages []models.OutboxMessage) error {
+	tracer := otel.Tracer("OutboxStore")
+	ctx, span := tracer.Start(ctx, "AppendOutbox-Store")
+	defer span.End()
+
+	conn := store.Conn(ctx, s.db)
+
+	for _, message := range messages {
+		_, err := conn.ExecContext(ctx, `
+		INSERT INTO outbox (sink, aggregate_type, aggregate_id, event_id, event_type, payload, status, attempts, next_attempt_at, last_error, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)`,
+			message.Sink,
+			message.AggregateType,
+			message.AggregateID,
+			message.EventID,
+			message.EventType,
+			string(message.Payload),
+			message.Status,
+			message.Attempts,
+			message.NextAttemptAt.UTC(),
+			message.LastError,
+			message.CreateAt.UTC(),
+		)
+		if err != nil {
+			return store.DBError(err)
+		}
+	}
+
+	return nil
+}
+
+func (s *Store) ClaimOutbox(ctx context.Context, sink string, now, leaseUntil time.Time, limit int) ([]models.OutboxMessage, error) {
+	tracer := otel.Tracer("OutboxStore")
+	ctx, span := tracer.Start(ctx, "ClaimOutbox-Store")
+	defer span.End()
+
+	// SKIP LOCKED lets instances polling at the same time claim different
+	// messages; a later message of an entity waits until the earlier one is
+	// no longer pending, so each entity goes out in order
+	rows, err := store.Conn(ctx, s.db).QueryContext(ctx, `
+	UPDATE outbox SET next_attempt_at = $3
+	WHERE seq IN (
+		SELECT o.seq FROM outbox o
+		WHERE o.sink = $1 AND o.status = $4 AND o.next_attempt_at <= $2
+		AND NOT EXISTS (
+			SELECT 1 FROM outbox e
+			WHERE e.sink = o.sink AND e.aggregate_type = o.aggregate_type AND e.aggregate_id = o.aggregate_id
+			AND e.status = $4 AND e.seq < o.seq
+		)
+		ORDER BY o.seq
+		LIMIT $5
+		FOR UPDATE SKIP LOCKED
+	)
+	RETURNING `+outboxColumns, sink, now.UTC(), leaseUntil.UTC(), models.OutboxPending, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	messages, err := scanOutbox(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	// RETURNING has no order
+	sort.Slice(messages, func(i, j int) bool { return messages[i].Seq < messages[j].Seq })
+
+	return messages, nil
+}
+
+func (s *Store) UpdateOutbox(ctx context.Context, message models.OutboxMessage) error {
+	tracer := otel.Tracer("OutboxStore")
+	ctx, span := tracer.Start(ctx, "UpdateOutbox-Store")
+	defer span.End()
+
+	var publishedAt *time.Time
+	if message.PublishedAt != nil {
+		published := message.PublishedAt.UTC()
+		publishedAt = &published
+	}
+
+	result, err := store.Conn(ctx, s.db).ExecContext(ctx, `
+	UPDATE outbox
+	SET status = $1, attempts = $2, next_attempt_at = $3, last_error = $4, published_at = $5
+	WHERE seq = $6`,
+		message.Status,
+		message.Attempts,
+		message.NextAttemptAt.UTC(),
+		message.LastError,
+		publishedAt,
+		message.Seq,
+	)
+	if err != nil {
+		return err
+	}
+
+	if updated, err := result.RowsAffected(); err != nil || updated == 0 {
+		if err != nil {
+			return err
+		}
+		return apperrors.NotFound("outbox message not found")
+	}
+
+	return nil
+}
+
+func (s *Store) DeletePublishedOutbox(ctx context.Context, t time.Time) (int64, error) {
+	tracer := otel.Tracer("OutboxStore")
+	ctx, span := tracer.Start(ctx, "DeletePublishedOutbox-Store")
+	defer span.End()
+
+	result, err := store.Conn(ctx, s.db).ExecContext(ctx, `DELETE FROM outbox WHERE published_at < $1`, t.UTC())
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected()
+}
+
+func scanOutbox(rows *sql.Rows) ([]models.OutboxMessage, error) {
+	defer rows.Close()
+
+	messages := []models.OutboxMessage{}
+	for rows.Next() {
+		var message models.OutboxMessage
+		var payload string
+		var publishedAt sql.NullTime
+
+		err := rows.Scan(
+			&message.Seq,
+			&message.Sink,
+			&message.AggregateType,
+			&message.AggregateID,
+			&message.EventID,
+			&message.EventType,
+			&payload,
+			&message.Status,
+			&message.Attempts,
+			&message.NextAttemptAt,
+			&message.LastError,
+			&message.CreateAt,
+			&publishedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		message.Payload = []byte(payload)
+		if publishedAt.Valid {
+			message.PublishedAt = &publishedAt.Time
+		}
+		messages = append(messages, message)
+	}
+
+	return messages, rows.Err()
+}
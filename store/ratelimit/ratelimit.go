@@ -0,0 +1,76 @@
+package ratelimit
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/geekAshish/DriveDesk/models"
+	"github.com/geekAshish/DriveDesk/store"
+	"go.opentelemetry.io/otel"
+)
+
+type Store struct {
+	db *sql.DB
+	tx *store.TxManager
+}
+
+func New(db *sql.DB) *Store {
+	return &Store{db: db, tx: store.NewTxManager(db)}
+}
+
+func (s *Store) TakeRateLimitToken(ctx context.Context, key string, limit models.RateLimit, now time.Time) (models.RateLimitBucket, bool, error) {
+	tracer := otel.Tracer("RateLimitStore")
+	ctx, span := tracer.Start(ctx, "TakeRateLimitToken-Store")
+	defer span.End()
+
+	// updated_at has no time zone and lib/pq reads it back as UTC
+	now = now.UTC()
+
+	var bucket models.RateLimitBucket
+	taken := false
+
+	err := s.tx.WithTx(ctx, func(ctx context.Context) error {
+		conn := store.Conn(ctx, s.db)
+
+		// a new client starts with a full bucket
+		_, err := conn.ExecContext(ctx, `
+		INSERT INTO rate_limit_bucket (key, tokens, updated_at) VALUES ($1, $2, $3)
+		ON CONFLICT (key) DO NOTHING`, key, float64(limit.Burst), now)
+		if err != nil {
+			return store.DBError(err)
+		}
+
+		// concurrent requests of the client wait here for each other
+		err = conn.QueryRowContext(ctx, `SELECT tokens, updated_at FROM rate_limit_bucket WHERE key = $1 FOR UPDATE`, key).
+			Scan(&bucket.Tokens, &bucket.UpdateAt)
+		if err != nil {
+			return err
+		}
+
+		bucket, taken = bucket.Take(limit, now)
+
+		_, err = conn.ExecContext(ctx, `UPDATE rate_limit_bucket SET tokens = $1, updated_at = $2 WHERE key = $3`,
+			bucket.Tokens, bucket.UpdateAt, key)
+		return err
+	})
+
+	if err != nil {
+		return models.RateLimitBucket{}, false, err
+	}
+
+	return bucket, taken, nil
+}
+
+func (s *Store) DeleteRateLimitBucketsBefore(ctx context.Context, t time.Time) (int64, error) {
+	tracer := otel.Tracer("RateLimitStore")
+	ctx, span := tracer.Start(ctx, "DeleteRateLimitBucketsBefore-Store")
+	defer span.End()
+
+	result, err := store.Conn(ctx, s.db).ExecContext(ctx, `DELETE FROM rate_limit_bucket WHERE updated_at < $1`, t.UTC())
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected()
+}
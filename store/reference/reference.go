@@ -0,0 +1,280 @@
+package reference
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/geekAshish/DriveDesk/apperrors"
+	"github.com/geekAshish/DriveDesk/models"
+	"github.com/geekAshish/DriveDesk/store"
+	"go.opentelemetry.io/otel"
+)
+
+type Store struct {
+	db *sql.DB
+	tx *store.TxManager
+}
+
+func New(db *sql.DB) *Store {
+	return &Store{db: db, tx: store.NewTxManager(db)}
+}
+
+func (s *Store) ListReferenceValues(ctx context.Context, kind string) ([]models.ReferenceValue, error) {
+	tracer := otel.Tracer("ReferenceStore")
+	ctx, span := tracer.Start(ctx, "ListReferenceValues-Store")
+	defer span.End()
+
+	conn := store.Conn(ctx, s.db)
+
+	rows, err := conn.QueryContext(ctx, `
+	SELECT kind, code, label, active, created_at, updated_at
+	FROM reference_value
+	WHERE $1 = '' OR kind = $1
+	ORDER BY kind, code`, kind)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	values := []models.ReferenceValue{}
+	for rows.Next() {
+		var value models.ReferenceValue
+		if err := rows.Scan(&value.Kind, &value.Code, &value.Label, &value.Active, &value.CreateAt, &value.UpdateAt); err != nil {
+			return nil, err
+		}
+		value.Aliases = []string{}
+		values = append(values, value)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	aliasRows, err := conn.QueryContext(ctx, `
+	SELECT kind, code, alias FROM reference_alias
+	WHERE $1 = '' OR kind = $1
+	ORDER BY alias`, kind)
+	if err != nil {
+		return nil, err
+	}
+	defer aliasRows.Close()
+
+	index := make(map[[2]string]int, len(values))
+	for i, value := range values {
+		index[[2]string{value.Kind, value.Code}] = i
+	}
+
+	for aliasRows.Next() {
+		var aliasKind, code, alias string
+		if err := aliasRows.Scan(&aliasKind, &code, &alias); err != nil {
+			return nil, err
+		}
+		if i, ok := index[[2]string{aliasKind, code}]; ok {
+			values[i].Aliases = append(values[i].Aliases, alias)
+		}
+	}
+
+	return values, aliasRows.Err()
+}
+
+func (s *Store) GetReferenceValue(ctx context.Context, kind, code string) (models.ReferenceValue, error) {
+	tracer := otel.Tracer("ReferenceStore")
+	ctx, span := tracer.Start(ctx, "GetReferenceValue-Store")
+	defer span.End()
+
+	return getReferenceValue(ctx, store.Conn(ctx, s.db), kind, code)
+}
+
+func (s *Store) CreateReferenceValue(ctx context.Context, kind string, req *models.ReferenceValueRequest) (models.ReferenceValue, error) {
+	tracer := otel.Tracer("ReferenceStore")
+	ctx, span := tracer.Start(ctx, "CreateReferenceValue-Store")
+	defer span.End()
+
+	var created models.ReferenceValue
+
+	err := s.tx.WithTx(ctx, func(ctx context.Context) error {
+		conn := store.Conn(ctx, s.db)
+
+		var clash bool
+		err := conn.QueryRowContext(ctx,
+			`SELECT EXISTS (SELECT 1 FROM reference_alias WHERE kind = $1 AND alias = $2)`,
+			kind, req.Code).Scan(&clash)
+		if err != nil {
+			return err
+		}
+		if clash {
+			return apperrors.Conflict("%s is already an alias of another %s", req.Code, kind)
+		}
+
+		active := req.Active == nil || *req.Active
+		now := time.Now()
+
+		_, err = conn.ExecContext(ctx, `
+		INSERT INTO reference_value (kind, code, label, active, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6)`,
+			kind, req.Code, req.Label, active, now, now)
+		if err != nil {
+			if err := store.DBError(err); errors.Is(err, apperrors.ErrConflict) {
+				return apperrors.Conflict("%s %s already exists", kind, req.Code)
+			}
+			return err
+		}
+
+		if err := insertAliases(ctx, conn, kind, req.Code, req.Aliases); err != nil {
+			return err
+		}
+
+		created, err = getReferenceValue(ctx, conn, kind, req.Code)
+		return err
+	})
+
+	if err != nil {
+		return models.ReferenceValue{}, err
+	}
+
+	return created, nil
+}
+
+// UpdateReferenceValue replaces the label, aliases and, when set, the active
+// flag of a value. Codes are immutable because stored rows refer to them.
+func (s *Store) UpdateReferenceValue(ctx context.Context, kind, code string, req *models.ReferenceValueRequest) (models.ReferenceValue, error) {
+	tracer := otel.Tracer("ReferenceStore")
+	ctx, span := tracer.Start(ctx, "UpdateReferenceValue-Store")
+	defer span.End()
+
+	var updated models.ReferenceValue
+
+	err := s.tx.WithTx(ctx, func(ctx context.Context) error {
+		conn := store.Conn(ctx, s.db)
+
+		existing, err := getReferenceValue(ctx, conn, kind, code)
+		if err != nil {
+			return err
+		}
+
+		active := existing.Active
+		if req.Active != nil {
+			active = *req.Active
+		}
+
+		_, err = conn.ExecContext(ctx, `
+		UPDATE reference_value SET label = $1, active = $2, updated_at = $3
+		WHERE kind = $4 AND code = $5`,
+			req.Label, active, time.Now(), kind, code)
+		if err != nil {
+			return store.DBError(err)
+		}
+
+		_, err = conn.ExecContext(ctx, `DELETE FROM reference_alias WHERE kind = $1 AND code = $2`, kind, code)
+		if err != nil {
+			return err
+		}
+
+		if err := insertAliases(ctx, conn, kind, code, req.Aliases); err != nil {
+			return err
+		}
+
+		updated, err = getReferenceValue(ctx, conn, kind, code)
+		return err
+	})
+
+	if err != nil {
+		return models.ReferenceValue{}, err
+	}
+
+	return updated, nil
+}
+
+func (s *Store) DeleteReferenceValue(ctx context.Context, kind, code string) (models.ReferenceValue, error) {
+	tracer := otel.Tracer("ReferenceStore")
+	ctx, span := tracer.Start(ctx, "DeleteReferenceValue-Store")
+	defer span.End()
+
+	var deleted models.ReferenceValue
+
+	err := s.tx.WithTx(ctx, func(ctx context.Context) error {
+		conn := store.Conn(ctx, s.db)
+
+		var err error
+		deleted, err = getReferenceValue(ctx, conn, kind, code)
+		if err != nil {
+			return err
+		}
+
+		// aliases go with the value through ON DELETE CASCADE
+		_, err = conn.ExecContext(ctx, `DELETE FROM reference_value WHERE kind = $1 AND code = $2`, kind, code)
+		return store.DBError(err)
+	})
+
+	if err != nil {
+		return models.ReferenceValue{}, err
+	}
+
+	return deleted, nil
+}
+
+func insertAliases(ctx context.Context, conn store.DBTX, kind, code string, aliases []string) error {
+	for _, alias := range aliases {
+		var clash bool
+		err := conn.QueryRowContext(ctx,
+			`SELECT EXISTS (SELECT 1 FROM reference_value WHERE kind = $1 AND code = $2)`,
+			kind, alias).Scan(&clash)
+		if err != nil {
+			return err
+		}
+		if clash {
+			return apperrors.Conflict("alias %s is already a %s code", alias, kind)
+		}
+
+		_, err = conn.ExecContext(ctx,
+			`INSERT INTO reference_alias (kind, alias, code) VALUES ($1, $2, $3)`,
+			kind, alias, code)
+		if err != nil {
+			if err := store.DBError(err); errors.Is(err, apperrors.ErrConflict) {
+				return apperrors.Conflict("alias %s is already used by another %s", alias, kind)
+			}
+			return err
+		}
+	}
+
+	return nil
+}
+
+func getReferenceValue(ctx context.Context, conn store.DBTX, kind, code string) (models.ReferenceValue, error) {
+	value := models.ReferenceValue{Aliases: []string{}}
+
+	err := conn.QueryRowContext(ctx, `
+	SELECT kind, code, label, active, created_at, updated_at
+	FROM reference_value WHERE kind = $1 AND code = $2`, kind, code).Scan(
+		&value.Kind,
+		&value.Code,
+		&value.Label,
+		&value.Active,
+		&value.CreateAt,
+		&value.UpdateAt,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.ReferenceValue{}, apperrors.NotFound("%s %s not found", kind, code)
+		}
+		return models.ReferenceValue{}, err
+	}
+
+	rows, err := conn.QueryContext(ctx,
+		`SELECT alias FROM reference_alias WHERE kind = $1 AND code = $2 ORDER BY alias`, kind, code)
+	if err != nil {
+		return models.ReferenceValue{}, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var alias string
+		if err := rows.Scan(&alias); err != nil {
+			return models.ReferenceValue{}, err
+		}
+		value.Aliases = append(value.Aliases, alias)
+	}
+
+	return value, rows.Err()
+}
@@ -0,0 +1,142 @@
+package rule
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/geekAshish/DriveDesk/apperrors"
+	"github.com/geekAshish/DriveDesk/models"
+	"github.com/geekAshish/DriveDesk/store"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel"
+)
+
+const ruleColumns = `id, target, field, operator, value, code, message, created_at`
+
+type Store struct {
+	db *sql.DB
+	tx *store.TxManager
+}
+
+func New(db *sql.DB) *Store {
+	return &Store{db: db, tx: store.NewTxManager(db)}
+}
+
+func (s *Store) ListValidationRules(ctx context.Context) ([]models.ValidationRule, error) {
+	tracer := otel.Tracer("ValidationRuleStore")
+	ctx, span := tracer.Start(ctx, "ListValidationRules-Store")
+	defer span.End()
+
+	rows, err := store.Conn(ctx, s.db).QueryContext(ctx, `SELECT `+ruleColumns+` FROM validation_rule ORDER BY created_at, id`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	rules := []models.ValidationRule{}
+	for rows.Next() {
+		rule, err := scanRule(rows)
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, rule)
+	}
+
+	return rules, rows.Err()
+}
+
+func (s *Store) CreateValidationRule(ctx context.Context, req *models.ValidationRuleRequest) (models.ValidationRule, error) {
+	tracer := otel.Tracer("ValidationRuleStore")
+	ctx, span := tracer.Start(ctx, "CreateValidationRule-Store")
+	defer span.End()
+
+	rule := models.ValidationRule{
+		ID:       uuid.New(),
+		Target:   req.Target,
+		Field:    req.Field,
+		Operator: req.Operator,
+		Value:    req.Value,
+		Code:     req.Code,
+		Message:  req.Message,
+		CreateAt: time.Now(),
+	}
+
+	_, err := store.Conn(ctx, s.db).ExecContext(ctx, `
+	INSERT INTO validation_rule (`+ruleColumns+`)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`,
+		rule.ID,
+		rule.Target,
+		rule.Field,
+		rule.Operator,
+		store.NullString(string(rule.Value)),
+		rule.Code,
+		rule.Message,
+		rule.CreateAt,
+	)
+	if err != nil {
+		return models.ValidationRule{}, store.DBError(err)
+	}
+
+	return rule, nil
+}
+
+func (s *Store) DeleteValidationRule(ctx context.Context, id string) (models.ValidationRule, error) {
+	tracer := otel.Tracer("ValidationRuleStore")
+	ctx, span := tracer.Start(ctx, "DeleteValidationRule-Store")
+	defer span.End()
+
+	var deleted models.ValidationRule
+
+	err := s.tx.WithTx(ctx, func(ctx context.Context) error {
+		conn := store.Conn(ctx, s.db)
+
+		if !store.ValidID(id) {
+			return apperrors.NotFound("validation rule not found")
+		}
+
+		var err error
+		deleted, err = scanRule(conn.QueryRowContext(ctx, `SELECT `+ruleColumns+` FROM validation_rule WHERE id = $1`, id))
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return apperrors.NotFound("validation rule not found")
+			}
+			return err
+		}
+
+		_, err = conn.ExecContext(ctx, `DELETE FROM validation_rule WHERE id = $1`, id)
+		return store.DBError(err)
+	})
+
+	if err != nil {
+		return models.ValidationRule{}, err
+	}
+
+	return deleted, nil
+}
+
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+func scanRule(row scanner) (models.ValidationRule, error) {
+	var rule models.ValidationRule
+	var value sql.NullString
+
+	err := row.Scan(
+		&rule.ID,
+		&rule.Target,
+		&rule.Field,
+		&rule.Operator,
+		&value,
+		&rule.Code,
+		&rule.Message,
+		&rule.CreateAt,
+	)
+	if value.Valid {
+		rule.Value = []byte(value.String)
+	}
+
+	return rule, err
+}
@@ -0,0 +1,86 @@
+// Package rulefile serves validation rules from a JSON file shipped with a
+// deployment, for markets whose rules are part of the release rather than
+// edited at runtime. The file is a list of rules in the same shape the rules
+// API accepts:
+//
+//	[
+//	  {"target": "car", "field": "year", "operator": "min", "value": 2005},
+//	  {"target": "car", "field": "vin", "operator": "required"}
+//	]
+package rulefile
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/geekAshish/DriveDesk/apperrors"
+	"github.com/geekAshish/DriveDesk/models"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel"
+)
+
+type Store struct {
+	path string
+}
+
+func New(path string) *Store {
+	return &Store{path: path}
+}
+
+// ListValidationRules reads the file on every call, so edits are picked up
+// once the caller's cache expires. Ids are derived from the file path and
+// the rule's position, so they are stable as long as the file is.
+func (s *Store) ListValidationRules(ctx context.Context) ([]models.ValidationRule, error) {
+	tracer := otel.Tracer("ValidationRuleStore")
+	_, span := tracer.Start(ctx, "ListValidationRules-FileStore")
+	defer span.End()
+
+	info, err := os.Stat(s.path)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := os.ReadFile(s.path)
+	if err != nil {
+		return nil, err
+	}
+
+	var requests []models.ValidationRuleRequest
+	if err := json.Unmarshal(body, &requests); err != nil {
+		return nil, fmt.Errorf("reading validation rules from %s: %w", s.path, err)
+	}
+
+	rules := make([]models.ValidationRule, 0, len(requests))
+	for i, req := range requests {
+		if err := models.ValidateRuleRequest(&req); err != nil {
+			return nil, fmt.Errorf("validation rule %d in %s: %w", i, s.path, err)
+		}
+
+		rules = append(rules, models.ValidationRule{
+			ID:       uuid.NewSHA1(uuid.NameSpaceURL, fmt.Appendf(nil, "file://%s#%d", s.path, i)),
+			Target:   req.Target,
+			Field:    req.Field,
+			Operator: req.Operator,
+			Value:    req.Value,
+			Code:     req.Code,
+			Message:  req.Message,
+			CreateAt: info.ModTime(),
+		})
+	}
+
+	return rules, nil
+}
+
+func (s *Store) CreateValidationRule(ctx context.Context, req *models.ValidationRuleRequest) (models.ValidationRule, error) {
+	return models.ValidationRule{}, s.readOnly()
+}
+
+func (s *Store) DeleteValidationRule(ctx context.Context, id string) (models.ValidationRule, error) {
+	return models.ValidationRule{}, s.readOnly()
+}
+
+func (s *Store) readOnly() error {
+	return apperrors.Conflict("validation rules are read from %s and cannot be changed through the API", s.path)
+}
@@ -0,0 +1,293 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/geekAshish/DriveDesk/apperrors"
+	"github.com/geekAshish/DriveDesk/models"
+	"github.com/geekAshish/DriveDesk/store"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel"
+)
+
+const carColumns = `c.id, c.name, c.year, c.brand, c.fuel_type, c.price, c.vin, c.created_at, c.updated_at,
+	e.id, e.displacement, e.no_of_cylinders, e.car_range, e.created_at, e.updated_at`
+
+type CarStore struct {
+	db *sql.DB
+	tx *store.TxManager
+}
+
+func NewCarStore(db *sql.DB) *CarStore {
+	return &CarStore{db: db, tx: store.NewTxManager(db)}
+}
+
+func (s *CarStore) GetCarById(ctx context.Context, id string) (models.Car, error) {
+	tracer := otel.Tracer("CarStore")
+	ctx, span := tracer.Start(ctx, "GetCarById-SQLiteStore")
+	defer span.End()
+
+	return getCar(ctx, store.Conn(ctx, s.db), id)
+}
+
+func (s *CarStore) GetCarByBrand(ctx context.Context, brand string, isEngine bool) ([]models.Car, error) {
+	tracer := otel.Tracer("CarStore")
+	ctx, span := tracer.Start(ctx, "GetCarByBrand-SQLiteStore")
+	defer span.End()
+
+	query := `SELECT ` + carColumns + ` FROM car c JOIN engine e ON c.engine_id = e.id WHERE c.brand = ? ORDER BY c.created_at, c.id`
+
+	rows, err := store.Conn(ctx, s.db).QueryContext(ctx, query, brand)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	cars := []models.Car{}
+
+	for rows.Next() {
+		car, err := scanCar(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		if !isEngine {
+			car.Engine = models.Engine{EngineID: car.Engine.EngineID}
+		}
+
+		cars = append(cars, car)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return cars, nil
+}
+
+func (s *CarStore) GetCarsByEngine(ctx context.Context, engineID string) ([]models.Car, error) {
+	tracer := otel.Tracer("CarStore")
+	ctx, span := tracer.Start(ctx, "GetCarsByEngine-SQLiteStore")
+	defer span.End()
+
+	query := `SELECT ` + carColumns + ` FROM car c JOIN engine e ON c.engine_id = e.id WHERE c.engine_id = ? ORDER BY c.created_at, c.id`
+
+	rows, err := store.Conn(ctx, s.db).QueryContext(ctx, query, engineID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	cars := []models.Car{}
+
+	for rows.Next() {
+		car, err := scanCar(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		cars = append(cars, car)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return cars, nil
+}
+
+func (s *CarStore) CreateCar(ctx context.Context, carReq *models.CarRequest) (models.Car, error) {
+	tracer := otel.Tracer("CarStore")
+	ctx, span := tracer.Start(ctx, "CreateCar-SQLiteStore")
+	defer span.End()
+
+	var createdCar models.Car
+
+	err := s.tx.WithTx(ctx, func(ctx context.Context) error {
+		conn := store.Conn(ctx, s.db)
+
+		if err := engineExists(ctx, conn, carReq.Engine.EngineID); err != nil {
+			return err
+		}
+
+		carID := uuid.New()
+		now := time.Now().UTC()
+
+		_, err := conn.ExecContext(ctx, `
+		INSERT INTO car (id, name, year, brand, fuel_type, engine_id, price, vin, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+			carID.String(),
+			carReq.Name,
+			carReq.Year,
+			carReq.Brand,
+			carReq.FuelType,
+			carReq.Engine.EngineID.String(),
+			carReq.Price,
+			store.NullString(carReq.VIN),
+			now,
+			now,
+		)
+		if err != nil {
+			return carError(err, carReq)
+		}
+
+		createdCar, err = getCar(ctx, conn, carID.String())
+		return err
+	})
+
+	if err != nil {
+		return models.Car{}, err
+	}
+
+	return createdCar, nil
+}
+
+func (s *CarStore) UpdateCar(ctx context.Context, id string, carReq *models.CarRequest) (models.Car, error) {
+	tracer := otel.Tracer("CarStore")
+	ctx, span := tracer.Start(ctx, "UpdateCar-SQLiteStore")
+	defer span.End()
+
+	var updatedCar models.Car
+
+	err := s.tx.WithTx(ctx, func(ctx context.Context) error {
+		conn := store.Conn(ctx, s.db)
+
+		if err := engineExists(ctx, conn, carReq.Engine.EngineID); err != nil {
+			return err
+		}
+
+		result, err := conn.ExecContext(ctx, `
+		UPDATE car
+		SET name = ?, year = ?, brand = ?, fuel_type = ?, price = ?, engine_id = ?, vin = ?, updated_at = ?
+		WHERE id = ?`,
+			carReq.Name,
+			carReq.Year,
+			carReq.Brand,
+			carReq.FuelType,
+			carReq.Price,
+			carReq.Engine.EngineID.String(),
+			store.NullString(carReq.VIN),
+			time.Now().UTC(),
+			id,
+		)
+		if err != nil {
+			return carError(err, carReq)
+		}
+
+		rowsAffected, err := result.RowsAffected()
+		if err != nil {
+			return err
+		}
+		if rowsAffected == 0 {
+			return apperrors.NotFound("car not found")
+		}
+
+		updatedCar, err = getCar(ctx, conn, id)
+		return err
+	})
+
+	if err != nil {
+		return models.Car{}, err
+	}
+
+	return updatedCar, nil
+}
+
+func (s *CarStore) DeleteCar(ctx context.Context, id string) (models.Car, error) {
+	tracer := otel.Tracer("CarStore")
+	ctx, span := tracer.Start(ctx, "DeleteCar-SQLiteStore")
+	defer span.End()
+
+	var deletedCar models.Car
+
+	err := s.tx.WithTx(ctx, func(ctx context.Context) error {
+		conn := store.Conn(ctx, s.db)
+
+		var err error
+		deletedCar, err = getCar(ctx, conn, id)
+		if err != nil {
+			return err
+		}
+
+		_, err = conn.ExecContext(ctx, `DELETE FROM car WHERE id = ?`, id)
+		return store.DBError(err)
+	})
+
+	if err != nil {
+		return models.Car{}, err
+	}
+
+	return deletedCar, nil
+}
+
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+func getCar(ctx context.Context, conn store.DBTX, id string) (models.Car, error) {
+	row := conn.QueryRowContext(ctx, `SELECT `+carColumns+` FROM car c JOIN engine e ON c.engine_id = e.id WHERE c.id = ?`, id)
+
+	car, err := scanCar(row)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.Car{}, apperrors.NotFound("car not found")
+		}
+		return models.Car{}, err
+	}
+
+	return car, nil
+}
+
+func scanCar(row scanner) (models.Car, error) {
+	var car models.Car
+	var vin sql.NullString
+
+	err := row.Scan(
+		&car.ID,
+		&car.Name,
+		&car.Year,
+		&car.Brand,
+		&car.FuelType,
+		&car.Price,
+		&vin,
+		&car.CreateAt,
+		&car.UpdateAt,
+		&car.Engine.EngineID,
+		&car.Engine.Dispacement,
+		&car.Engine.NoOfCylinders,
+		&car.Engine.CarRange,
+		&car.Engine.CreateAt,
+		&car.Engine.UpdateAt,
+	)
+	car.VIN = vin.String
+
+	return car, err
+}
+
+// carError reports a clash on the unique VIN as such rather than as a
+// generic conflict.
+func carError(err error, carReq *models.CarRequest) error {
+	err = store.DBError(err)
+	if errors.Is(err, apperrors.ErrConflict) {
+		return apperrors.Wrap(apperrors.ErrConflict, err, "a car with VIN %s already exists", carReq.VIN)
+	}
+	return err
+}
+
+func engineExists(ctx context.Context, conn store.DBTX, engineID uuid.UUID) error {
+	var id string
+
+	err := conn.QueryRowContext(ctx, `SELECT id FROM engine WHERE id = ?`, engineID.String()).Scan(&id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return apperrors.InvalidField("engine.engine_id", "not_found", fmt.Sprintf("engine %s does not exist", engineID))
+		}
+		return err
+	}
+
+	return nil
+}
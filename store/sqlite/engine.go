@@ -0,0 +1,205 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/geekAshish/DriveDesk/apperrors"
+	"github.com/geekAshish/DriveDesk/models"
+	"github.com/geekAshish/DriveDesk/store"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel"
+)
+
+type EngineStore struct {
+	db *sql.DB
+	tx *store.TxManager
+}
+
+func NewEngineStore(db *sql.DB) *EngineStore {
+	return &EngineStore{db: db, tx: store.NewTxManager(db)}
+}
+
+func (s *EngineStore) GetEngineById(ctx context.Context, id string) (models.Engine, error) {
+	tracer := otel.Tracer("EngineStore")
+	ctx, span := tracer.Start(ctx, "GetEngineById-SQLiteStore")
+	defer span.End()
+
+	return getEngine(ctx, store.Conn(ctx, s.db), id)
+}
+
+func (s *EngineStore) GetEnginesByIds(ctx context.Context, ids []string) ([]models.Engine, error) {
+	tracer := otel.Tracer("EngineStore")
+	ctx, span := tracer.Start(ctx, "GetEnginesByIds-SQLiteStore")
+	defer span.End()
+
+	engines := []models.Engine{}
+	if len(ids) == 0 {
+		return engines, nil
+	}
+
+	args := make([]any, len(ids))
+	for i, id := range ids {
+		args[i] = id
+	}
+
+	rows, err := store.Conn(ctx, s.db).QueryContext(ctx, `
+	SELECT id, displacement, no_of_cylinders, car_range, created_at, updated_at
+	FROM engine WHERE id IN (?`+strings.Repeat(", ?", len(ids)-1)+`)`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var engine models.Engine
+		err := rows.Scan(
+			&engine.EngineID,
+			&engine.Dispacement,
+			&engine.NoOfCylinders,
+			&engine.CarRange,
+			&engine.CreateAt,
+			&engine.UpdateAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		engines = append(engines, engine)
+	}
+
+	return engines, rows.Err()
+}
+
+func (s *EngineStore) CreateEngine(ctx context.Context, engineReq *models.EngineRequest) (models.Engine, error) {
+	tracer := otel.Tracer("EngineStore")
+	ctx, span := tracer.Start(ctx, "CreateEngine-SQLiteStore")
+	defer span.End()
+
+	now := time.Now().UTC()
+
+	engine := models.Engine{
+		EngineID:      uuid.New(),
+		Dispacement:   engineReq.Dispacement,
+		NoOfCylinders: engineReq.NoOfCylinders,
+		CarRange:      engineReq.CarRange,
+		CreateAt:      now,
+		UpdateAt:      now,
+	}
+
+	_, err := store.Conn(ctx, s.db).ExecContext(ctx, `
+	INSERT INTO engine (id, displacement, no_of_cylinders, car_range, created_at, updated_at)
+	VALUES (?, ?, ?, ?, ?, ?)`,
+		engine.EngineID.String(),
+		engine.Dispacement,
+		engine.NoOfCylinders,
+		engine.CarRange,
+		engine.CreateAt,
+		engine.UpdateAt,
+	)
+	if err != nil {
+		return models.Engine{}, store.DBError(err)
+	}
+
+	return engine, nil
+}
+
+func (s *EngineStore) UpdateEngine(ctx context.Context, id string, engineReq *models.EngineRequest) (models.Engine, error) {
+	tracer := otel.Tracer("EngineStore")
+	ctx, span := tracer.Start(ctx, "UpdateEngine-SQLiteStore")
+	defer span.End()
+
+	if !store.ValidID(id) {
+		return models.Engine{}, apperrors.NotFound("engine not found")
+	}
+
+	var updatedEngine models.Engine
+
+	err := s.tx.WithTx(ctx, func(ctx context.Context) error {
+		conn := store.Conn(ctx, s.db)
+
+		result, err := conn.ExecContext(ctx, `
+		UPDATE engine SET displacement = ?, no_of_cylinders = ?, car_range = ?, updated_at = ?
+		WHERE id = ?`,
+			engineReq.Dispacement,
+			engineReq.NoOfCylinders,
+			engineReq.CarRange,
+			time.Now().UTC(),
+			id,
+		)
+		if err != nil {
+			return store.DBError(err)
+		}
+
+		rowsAffected, err := result.RowsAffected()
+		if err != nil {
+			return err
+		}
+		if rowsAffected == 0 {
+			return apperrors.NotFound("engine not found")
+		}
+
+		updatedEngine, err = getEngine(ctx, conn, id)
+		return err
+	})
+
+	if err != nil {
+		return models.Engine{}, err
+	}
+
+	return updatedEngine, nil
+}
+
+func (s *EngineStore) DeleteEngine(ctx context.Context, id string) (models.Engine, error) {
+	tracer := otel.Tracer("EngineStore")
+	ctx, span := tracer.Start(ctx, "DeleteEngine-SQLiteStore")
+	defer span.End()
+
+	var deletedEngine models.Engine
+
+	err := s.tx.WithTx(ctx, func(ctx context.Context) error {
+		conn := store.Conn(ctx, s.db)
+
+		var err error
+		deletedEngine, err = getEngine(ctx, conn, id)
+		if err != nil {
+			return err
+		}
+
+		// cars using this engine go with it through ON DELETE CASCADE
+		_, err = conn.ExecContext(ctx, `DELETE FROM engine WHERE id = ?`, id)
+		return store.DBError(err)
+	})
+
+	if err != nil {
+		return models.Engine{}, err
+	}
+
+	return deletedEngine, nil
+}
+
+func getEngine(ctx context.Context, conn store.DBTX, id string) (models.Engine, error) {
+	var engine models.Engine
+
+	err := conn.QueryRowContext(ctx, `
+	SELECT id, displacement, no_of_cylinders, car_range, created_at, updated_at
+	FROM engine WHERE id = ?`, id).Scan(
+		&engine.EngineID,
+		&engine.Dispacement,
+		&engine.NoOfCylinders,
+		&engine.CarRange,
+		&engine.CreateAt,
+		&engine.UpdateAt,
+	)
+
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.Engine{}, apperrors.NotFound("engine not found")
+		}
+		return models.Engine{}, err
+	}
+
+	return engine, nil
+}
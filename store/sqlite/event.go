@@ -0,0 +1,97 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/geekAshish/DriveDesk/models"
+	"github.com/geekAshish/DriveDesk/store"
+	"go.opentelemetry.io/otel"
+)
+
+const eventColumns = `seq, id, type, subject, brand, data, created_at`
+
+type EventStore struct {
+	db *sql.DB
+}
+
+func NewEventStore(db *sql.DB) *EventStore {
+	return &EventStore{db: db}
+}
+
+func (s *EventStore) AppendEvent(ctx context.Context, event models.ChangeEvent) (models.ChangeEvent, error) {
+	tracer := otel.Tracer("EventStore")
+	ctx, span := tracer.Start(ctx, "AppendEvent-SQLiteStore")
+	defer span.End()
+
+	result, err := store.Conn(ctx, s.db).ExecContext(ctx, `
+	INSERT INTO change_event (id, type, subject, brand, data, created_at)
+	VALUES (?, ?, ?, ?, ?, ?)`,
+		event.ID.String(), event.Type, event.Subject, event.Brand, string(event.Data), event.CreateAt.UnixMilli())
+	if err != nil {
+		return models.ChangeEvent{}, store.DBError(err)
+	}
+
+	event.Seq, err = result.LastInsertId()
+	if err != nil {
+		return models.ChangeEvent{}, err
+	}
+
+	return event, nil
+}
+
+func (s *EventStore) ListEventsSince(ctx context.Context, after int64, limit int) ([]models.ChangeEvent, error) {
+	tracer := otel.Tracer("EventStore")
+	ctx, span := tracer.Start(ctx, "ListEventsSince-SQLiteStore")
+	defer span.End()
+
+	rows, err := store.Conn(ctx, s.db).QueryContext(ctx,
+		`SELECT `+eventColumns+` FROM change_event WHERE seq > ? ORDER BY seq LIMIT ?`, after, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	events := []models.ChangeEvent{}
+	for rows.Next() {
+		var event models.ChangeEvent
+		var data string
+		var createdAt int64
+
+		err := rows.Scan(&event.Seq, &event.ID, &event.Type, &event.Subject, &event.Brand, &data, &createdAt)
+		if err != nil {
+			return nil, err
+		}
+
+		event.Data = []byte(data)
+		event.CreateAt = time.UnixMilli(createdAt).UTC()
+		events = append(events, event)
+	}
+
+	return events, rows.Err()
+}
+
+func (s *EventStore) LatestEventSeq(ctx context.Context) (int64, error) {
+	tracer := otel.Tracer("EventStore")
+	ctx, span := tracer.Start(ctx, "LatestEventSeq-SQLiteStore")
+	defer span.End()
+
+	var seq int64
+	err := store.Conn(ctx, s.db).QueryRowContext(ctx, `SELECT COALESCE(MAX(seq), 0) FROM change_event`).Scan(&seq)
+
+	return seq, err
+}
+
+func (s *EventStore) DeleteEventsBefore(ctx context.Context, t time.Time) (int64, error) {
+	tracer := otel.Tracer("EventStore")
+	ctx, span := tracer.Start(ctx, "DeleteEventsBefore-SQLiteStore")
+	defer span.End()
+
+	result, err := store.Conn(ctx, s.db).ExecContext(ctx, `DELETE FROM change_event WHERE created_at < ?`, t.UnixMilli())
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected()
+}
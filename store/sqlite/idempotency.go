@@ -0,0 +1,156 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"github.com/geekAshish/DriveDesk/models"
+	"github.com/geekAshish/DriveDesk/store"
+	"go.opentelemetry.io/otel"
+)
+
+const idempotencyColumns = `owner, key, request_hash, status, content_type, header, body, created_at, expires_at`
+
+type IdempotencyStore struct {
+	db *sql.DB
+	tx *store.TxManager
+}
+
+func NewIdempotencyStore(db *sql.DB) *IdempotencyStore {
+	return &IdempotencyStore{db: db, tx: store.NewTxManager(db)}
+}
+
+func (s *IdempotencyStore) ReserveIdempotencyKey(ctx context.Context, record models.IdempotencyRecord) (models.IdempotencyRecord, bool, error) {
+	tracer := otel.Tracer("IdempotencyStore")
+	ctx, span := tracer.Start(ctx, "ReserveIdempotencyKey-SQLiteStore")
+	defer span.End()
+
+	record.CreateAt = record.CreateAt.UTC()
+
+	header, err := json.Marshal(record.Header)
+	if err != nil {
+		return models.IdempotencyRecord{}, false, err
+	}
+
+	var existing models.IdempotencyRecord
+	reserved := false
+
+	err = s.tx.WithTx(ctx, func(ctx context.Context) error {
+		conn := store.Conn(ctx, s.db)
+
+		_, err := conn.ExecContext(ctx, `DELETE FROM idempotency_key WHERE owner = ? AND key = ? AND expires_at <= ?`,
+			record.Owner, record.Key, time.Now().UnixMilli())
+		if err != nil {
+			return err
+		}
+
+		result, err := conn.ExecContext(ctx, `
+		INSERT INTO idempotency_key (`+idempotencyColumns+`)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (owner, key) DO NOTHING`,
+			record.Owner,
+			record.Key,
+			record.RequestHash,
+			record.Status,
+			record.ContentType,
+			header,
+			record.Body,
+			record.CreateAt,
+			record.ExpireAt.UnixMilli(),
+		)
+		if err != nil {
+			return store.DBError(err)
+		}
+
+		if inserted, err := result.RowsAffected(); err != nil || inserted == 1 {
+			reserved = true
+			return err
+		}
+
+		existing, err = scanIdempotencyRecord(conn.QueryRowContext(ctx,
+			`SELECT `+idempotencyColumns+` FROM idempotency_key WHERE owner = ? AND key = ?`, record.Owner, record.Key))
+		return err
+	})
+
+	if err != nil {
+		return models.IdempotencyRecord{}, false, err
+	}
+	if reserved {
+		return record, true, nil
+	}
+
+	return existing, false, nil
+}
+
+func (s *IdempotencyStore) SaveIdempotencyResponse(ctx context.Context, record models.IdempotencyRecord) error {
+	tracer := otel.Tracer("IdempotencyStore")
+	ctx, span := tracer.Start(ctx, "SaveIdempotencyResponse-SQLiteStore")
+	defer span.End()
+
+	header, err := json.Marshal(record.Header)
+	if err != nil {
+		return err
+	}
+
+	_, err = store.Conn(ctx, s.db).ExecContext(ctx, `
+	UPDATE idempotency_key SET status = ?, content_type = ?, header = ?, body = ?, expires_at = ?
+	WHERE owner = ? AND key = ?`,
+		record.Status,
+		record.ContentType,
+		header,
+		record.Body,
+		record.ExpireAt.UnixMilli(),
+		record.Owner,
+		record.Key,
+	)
+
+	return err
+}
+
+func (s *IdempotencyStore) DeleteIdempotencyKey(ctx context.Context, owner, key string) error {
+	tracer := otel.Tracer("IdempotencyStore")
+	ctx, span := tracer.Start(ctx, "DeleteIdempotencyKey-SQLiteStore")
+	defer span.End()
+
+	_, err := store.Conn(ctx, s.db).ExecContext(ctx, `DELETE FROM idempotency_key WHERE owner = ? AND key = ?`, owner, key)
+	return err
+}
+
+func (s *IdempotencyStore) DeleteExpiredIdempotencyKeys(ctx context.Context, now time.Time) (int64, error) {
+	tracer := otel.Tracer("IdempotencyStore")
+	ctx, span := tracer.Start(ctx, "DeleteExpiredIdempotencyKeys-SQLiteStore")
+	defer span.End()
+
+	result, err := store.Conn(ctx, s.db).ExecContext(ctx, `DELETE FROM idempotency_key WHERE expires_at <= ?`, now.UnixMilli())
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected()
+}
+
+func scanIdempotencyRecord(row scanner) (models.IdempotencyRecord, error) {
+	var record models.IdempotencyRecord
+	var header string
+	var expireAt int64
+
+	err := row.Scan(
+		&record.Owner,
+		&record.Key,
+		&record.RequestHash,
+		&record.Status,
+		&record.ContentType,
+		&header,
+		&record.Body,
+		&record.CreateAt,
+		&expireAt,
+	)
+	if err != nil {
+		return models.IdempotencyRecord{}, err
+	}
+	record.ExpireAt = time.UnixMilli(expireAt)
+
+	return record, json.Unmarshal([]byte(header), &record.Header)
+}
@@ -0,0 +1,196 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/geekAshish/DriveDesk/apperrors"
+	"github.com/geekAshish/DriveDesk/models"
+	"github.com/geekAshish/DriveDesk/store"
+	"go.opentelemetry.io/otel"
+)
+
+const outboxColumns = `seq, sink, aggregate_type, aggregate_id, event_id, event_type, payload,
+	status, attempts, next_attempt_at, last_error, created_at, published_at`
+
+type OutboxStore struct {
+	db *sql.DB
+	tx *store.TxManager
+}
+
+func NewOutboxStore(db *sql.DB) *OutboxStore {
+	return &OutboxStore{db: db, tx: store.NewTxManager(db)}
+}
+
+func (s *OutboxStore) AppendOutbox(ctx context.Context, messages []models.OutboxMessage) error {
+	tracer := otel.Tracer("OutboxStore")
+	ctx, span := tracer.Start(ctx, "AppendOutbox-SQLiteStore")
+	defer span.End()
+
+	conn := store.Conn(ctx, s.db)
+
+	for _, message := range messages {
+		_, err := conn.ExecContext(ctx, `
+		INSERT INTO outbox (sink, aggregate_type, aggregate_id, event_id, event_type, payload, status, attempts, next_attempt_at, last_error, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+			message.Sink,
+			message.AggregateType,
+			message.AggregateID,
+			message.EventID.String(),
+			message.EventType,
+			string(message.Payload),
+			message.Status,
+			message.Attempts,
+			message.NextAttemptAt.UnixMilli(),
+			message.LastError,
+			message.CreateAt.UnixMilli(),
+		)
+		if err != nil {
+			return store.DBError(err)
+		}
+	}
+
+	return nil
+}
+
+func (s *OutboxStore) ClaimOutbox(ctx context.Context, sink string, now, leaseUntil time.Time, limit int) ([]models.OutboxMessage, error) {
+	tracer := otel.Tracer("OutboxStore")
+	ctx, span := tracer.Start(ctx, "ClaimOutbox-SQLiteStore")
+	defer span.End()
+
+	var claimed []models.OutboxMessage
+
+	// a single writer at a time, so selecting and then updating inside one transaction is enough
+	err := s.tx.WithTx(ctx, func(ctx context.Context) error {
+		conn := store.Conn(ctx, s.db)
+
+		rows, err := conn.QueryContext(ctx, `
+		SELECT `+outboxColumns+` FROM outbox o
+		WHERE o.sink = ? AND o.status = ? AND o.next_attempt_at <= ?
+		AND NOT EXISTS (
+			SELECT 1 FROM outbox e
+			WHERE e.sink = o.sink AND e.aggregate_type = o.aggregate_type AND e.aggregate_id = o.aggregate_id
+			AND e.status = ? AND e.seq < o.seq
+		)
+		ORDER BY o.seq
+		LIMIT ?`, sink, models.OutboxPending, now.UnixMilli(), models.OutboxPending, limit)
+		if err != nil {
+			return err
+		}
+
+		claimed, err = scanOutbox(rows)
+		if err != nil {
+			return err
+		}
+
+		for i := range claimed {
+			claimed[i].NextAttemptAt = time.UnixMilli(leaseUntil.UnixMilli())
+
+			_, err := conn.ExecContext(ctx, `UPDATE outbox SET next_attempt_at = ? WHERE seq = ?`,
+				leaseUntil.UnixMilli(), claimed[i].Seq)
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return claimed, nil
+}
+
+func (s *OutboxStore) UpdateOutbox(ctx context.Context, message models.OutboxMessage) error {
+	tracer := otel.Tracer("OutboxStore")
+	ctx, span := tracer.Start(ctx, "UpdateOutbox-SQLiteStore")
+	defer span.End()
+
+	var publishedAt *int64
+	if message.PublishedAt != nil {
+		millis := message.PublishedAt.UnixMilli()
+		publishedAt = &millis
+	}
+
+	result, err := store.Conn(ctx, s.db).ExecContext(ctx, `
+	UPDATE outbox
+	SET status = ?, attempts = ?, next_attempt_at = ?, last_error = ?, published_at = ?
+	WHERE seq = ?`,
+		message.Status,
+		message.Attempts,
+		message.NextAttemptAt.UnixMilli(),
+		message.LastError,
+		publishedAt,
+		message.Seq,
+	)
+	if err != nil {
+		return err
+	}
+
+	if updated, err := result.RowsAffected(); err != nil || updated == 0 {
+		if err != nil {
+			return err
+		}
+		return apperrors.NotFound("outbox message not found")
+	}
+
+	return nil
+}
+
+func (s *OutboxStore) DeletePublishedOutbox(ctx context.Context, t time.Time) (int64, error) {
+	tracer := otel.Tracer("OutboxStore")
+	ctx, span := tracer.Start(ctx, "DeletePublishedOutbox-SQLiteStore")
+	defer span.End()
+
+	result, err := store.Conn(ctx, s.db).ExecContext(ctx, `DELETE FROM outbox WHERE published_at < ?`, t.UnixMilli())
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected()
+}
+
+func scanOutbox(rows *sql.Rows) ([]models.OutboxMessage, error) {
+	defer rows.Close()
+
+	messages := []models.OutboxMessage{}
+	for rows.Next() {
+		var message models.OutboxMessage
+		var payload string
+		var nextAttemptAt, createdAt int64
+		var publishedAt sql.NullInt64
+
+		err := rows.Scan(
+			&message.Seq,
+			&message.Sink,
+			&message.AggregateType,
+			&message.AggregateID,
+			&message.EventID,
+			&message.EventType,
+			&payload,
+			&message.Status,
+			&message.Attempts,
+			&nextAttemptAt,
+			&message.LastError,
+			&createdAt,
+			&publishedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		message.Payload = []byte(payload)
+		message.NextAttemptAt = time.UnixMilli(nextAttemptAt).UTC()
+		message.CreateAt = time.UnixMilli(createdAt).UTC()
+		if publishedAt.Valid {
+			published := time.UnixMilli(publishedAt.Int64).UTC()
+			message.PublishedAt = &published
+		}
+		messages = append(messages, message)
+	}
+
+	return messages, rows.Err()
+}
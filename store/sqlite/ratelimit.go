@@ -0,0 +1,74 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/geekAshish/DriveDesk/models"
+	"github.com/geekAshish/DriveDesk/store"
+	"go.opentelemetry.io/otel"
+)
+
+type RateLimitStore struct {
+	db *sql.DB
+	tx *store.TxManager
+}
+
+func NewRateLimitStore(db *sql.DB) *RateLimitStore {
+	return &RateLimitStore{db: db, tx: store.NewTxManager(db)}
+}
+
+func (s *RateLimitStore) TakeRateLimitToken(ctx context.Context, key string, limit models.RateLimit, now time.Time) (models.RateLimitBucket, bool, error) {
+	tracer := otel.Tracer("RateLimitStore")
+	ctx, span := tracer.Start(ctx, "TakeRateLimitToken-SQLiteStore")
+	defer span.End()
+
+	var bucket models.RateLimitBucket
+	taken := false
+
+	err := s.tx.WithTx(ctx, func(ctx context.Context) error {
+		conn := store.Conn(ctx, s.db)
+
+		// a new client starts with a full bucket; the insert also takes the
+		// write lock, so concurrent requests of the client wait for each other
+		_, err := conn.ExecContext(ctx, `
+		INSERT INTO rate_limit_bucket (key, tokens, updated_at) VALUES (?, ?, ?)
+		ON CONFLICT (key) DO NOTHING`, key, float64(limit.Burst), now.UnixMilli())
+		if err != nil {
+			return store.DBError(err)
+		}
+
+		var updateAt int64
+		if err := conn.QueryRowContext(ctx, `SELECT tokens, updated_at FROM rate_limit_bucket WHERE key = ?`, key).
+			Scan(&bucket.Tokens, &updateAt); err != nil {
+			return err
+		}
+		bucket.UpdateAt = time.UnixMilli(updateAt)
+
+		bucket, taken = bucket.Take(limit, now)
+
+		_, err = conn.ExecContext(ctx, `UPDATE rate_limit_bucket SET tokens = ?, updated_at = ? WHERE key = ?`,
+			bucket.Tokens, bucket.UpdateAt.UnixMilli(), key)
+		return err
+	})
+
+	if err != nil {
+		return models.RateLimitBucket{}, false, err
+	}
+
+	return bucket, taken, nil
+}
+
+func (s *RateLimitStore) DeleteRateLimitBucketsBefore(ctx context.Context, t time.Time) (int64, error) {
+	tracer := otel.Tracer("RateLimitStore")
+	ctx, span := tracer.Start(ctx, "DeleteRateLimitBucketsBefore-SQLiteStore")
+	defer span.End()
+
+	result, err := store.Conn(ctx, s.db).ExecContext(ctx, `DELETE FROM rate_limit_bucket WHERE updated_at < ?`, t.UnixMilli())
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected()
+}
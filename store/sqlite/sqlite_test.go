@@ -0,0 +1,46 @@
+package sqlite_test
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/geekAshish/DriveDesk/driver"
+	"github.com/geekAshish/DriveDesk/store"
+	"github.com/geekAshish/DriveDesk/store/migrations"
+	"github.com/geekAshish/DriveDesk/store/sqlite"
+	"github.com/geekAshish/DriveDesk/store/storetest"
+)
+
+func TestConformance(t *testing.T) {
+	storetest.Run(t, func(t *testing.T) storetest.Stores {
+		db, err := driver.OpenSQLite(filepath.Join(t.TempDir(), "drivedesk.db"))
+		if err != nil {
+			t.Fatalf("opening sqlite: %v", err)
+		}
+		t.Cleanup(func() { db.Close() })
+
+		migrator, err := migrations.NewSQLite(db)
+		if err != nil {
+			t.Fatalf("loading migrations: %v", err)
+		}
+		if _, err := migrator.Up(context.Background()); err != nil {
+			t.Fatalf("applying migrations: %v", err)
+		}
+
+		return storetest.Stores{
+			Car:       sqlite.NewCarStore(db),
+			Engine:    sqlite.NewEngineStore(db),
+			Tx:        store.NewTxManager(db),
+			Reference: sqlite.NewReferenceStore(db),
+			Rule:      sqlite.NewValidationRuleStore(db),
+
+			Idempotency: sqlite.NewIdempotencyStore(db),
+			Webhook:     sqlite.NewWebhookStore(db),
+			Event:       sqlite.NewEventStore(db),
+			Outbox:      sqlite.NewOutboxStore(db),
+			Sync:        sqlite.NewSyncStore(db),
+			RateLimit:   sqlite.NewRateLimitStore(db),
+		}
+	})
+}
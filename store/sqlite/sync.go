@@ -0,0 +1,149 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/geekAshish/DriveDesk/apperrors"
+	"github.com/geekAshish/DriveDesk/models"
+	"github.com/geekAshish/DriveDesk/store"
+	"go.opentelemetry.io/otel"
+)
+
+const syncColumns = `entity, id, parent_id, version, deleted, updated_at`
+
+type SyncStore struct {
+	db *sql.DB
+	tx *store.TxManager
+}
+
+func NewSyncStore(db *sql.DB) *SyncStore {
+	return &SyncStore{db: db, tx: store.NewTxManager(db)}
+}
+
+func (s *SyncStore) RecordSyncChange(ctx context.Context, row models.SyncRow) (int64, error) {
+	tracer := otel.Tracer("SyncStore")
+	ctx, span := tracer.Start(ctx, "RecordSyncChange-SQLiteStore")
+	defer span.End()
+
+	var version int64
+	err := s.tx.WithTx(ctx, func(ctx context.Context) error {
+		var err error
+		version, err = s.record(ctx, row)
+		return err
+	})
+
+	return version, err
+}
+
+// record must run in a transaction, so the counter and the row change
+// together.
+func (s *SyncStore) record(ctx context.Context, row models.SyncRow) (int64, error) {
+	conn := store.Conn(ctx, s.db)
+
+	var version int64
+	err := conn.QueryRowContext(ctx, `UPDATE sync_clock SET version = version + 1 WHERE id = 1 RETURNING version`).Scan(&version)
+	if err != nil {
+		return 0, err
+	}
+
+	_, err = conn.ExecContext(ctx, `
+	INSERT INTO sync_row (entity, id, parent_id, version, deleted, updated_at)
+	VALUES (?, ?, ?, ?, ?, ?)
+	ON CONFLICT (entity, id) DO UPDATE
+	SET parent_id = excluded.parent_id, version = excluded.version, deleted = excluded.deleted, updated_at = excluded.updated_at`,
+		row.Entity, row.ID, row.ParentID, version, row.Deleted, time.Now().UnixMilli())
+	if err != nil {
+		return 0, store.DBError(err)
+	}
+
+	return version, nil
+}
+
+func (s *SyncStore) DeleteSyncChildren(ctx context.Context, entity, parentID string) error {
+	tracer := otel.Tracer("SyncStore")
+	ctx, span := tracer.Start(ctx, "DeleteSyncChildren-SQLiteStore")
+	defer span.End()
+
+	return s.tx.WithTx(ctx, func(ctx context.Context) error {
+		rows, err := store.Conn(ctx, s.db).QueryContext(ctx,
+			`SELECT `+syncColumns+` FROM sync_row WHERE entity = ? AND parent_id = ? AND deleted = 0 ORDER BY version`, entity, parentID)
+		if err != nil {
+			return err
+		}
+
+		children, err := scanSyncRows(rows)
+		if err != nil {
+			return err
+		}
+
+		for _, child := range children {
+			child.Deleted = true
+			if _, err := s.record(ctx, child); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+func (s *SyncStore) GetSyncRow(ctx context.Context, entity, id string) (models.SyncRow, error) {
+	tracer := otel.Tracer("SyncStore")
+	ctx, span := tracer.Start(ctx, "GetSyncRow-SQLiteStore")
+	defer span.End()
+
+	// SQLite has a single writer, so the transaction needs no row lock
+	row := store.Conn(ctx, s.db).QueryRowContext(ctx, `SELECT `+syncColumns+` FROM sync_row WHERE entity = ? AND id = ?`, entity, id)
+
+	syncRow, err := scanSyncRow(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return models.SyncRow{}, apperrors.NotFound("%s not found", entity)
+	}
+
+	return syncRow, err
+}
+
+func (s *SyncStore) ListSyncRows(ctx context.Context, since int64, limit int) ([]models.SyncRow, error) {
+	tracer := otel.Tracer("SyncStore")
+	ctx, span := tracer.Start(ctx, "ListSyncRows-SQLiteStore")
+	defer span.End()
+
+	rows, err := store.Conn(ctx, s.db).QueryContext(ctx,
+		`SELECT `+syncColumns+` FROM sync_row WHERE version > ? ORDER BY version LIMIT ?`, since, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	return scanSyncRows(rows)
+}
+
+func scanSyncRow(row scanner) (models.SyncRow, error) {
+	var syncRow models.SyncRow
+	var updatedAt int64
+
+	err := row.Scan(&syncRow.Entity, &syncRow.ID, &syncRow.ParentID, &syncRow.Version, &syncRow.Deleted, &updatedAt)
+	if err != nil {
+		return models.SyncRow{}, err
+	}
+
+	syncRow.UpdateAt = time.UnixMilli(updatedAt).UTC()
+	return syncRow, nil
+}
+
+func scanSyncRows(rows *sql.Rows) ([]models.SyncRow, error) {
+	defer rows.Close()
+
+	syncRows := []models.SyncRow{}
+	for rows.Next() {
+		syncRow, err := scanSyncRow(rows)
+		if err != nil {
+			return nil, err
+		}
+		syncRows = append(syncRows, syncRow)
+	}
+
+	return syncRows, rows.Err()
+}
@@ -0,0 +1,134 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/geekAshish/DriveDesk/apperrors"
+	"github.com/geekAshish/DriveDesk/models"
+	"github.com/geekAshish/DriveDesk/store"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel"
+)
+
+const validationRuleColumns = `id, target, field, operator, value, code, message, created_at`
+
+type ValidationRuleStore struct {
+	db *sql.DB
+	tx *store.TxManager
+}
+
+func NewValidationRuleStore(db *sql.DB) *ValidationRuleStore {
+	return &ValidationRuleStore{db: db, tx: store.NewTxManager(db)}
+}
+
+func (s *ValidationRuleStore) ListValidationRules(ctx context.Context) ([]models.ValidationRule, error) {
+	tracer := otel.Tracer("ValidationRuleStore")
+	ctx, span := tracer.Start(ctx, "ListValidationRules-SQLiteStore")
+	defer span.End()
+
+	rows, err := store.Conn(ctx, s.db).QueryContext(ctx, `SELECT `+validationRuleColumns+` FROM validation_rule ORDER BY created_at, id`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	rules := []models.ValidationRule{}
+	for rows.Next() {
+		rule, err := scanValidationRule(rows)
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, rule)
+	}
+
+	return rules, rows.Err()
+}
+
+func (s *ValidationRuleStore) CreateValidationRule(ctx context.Context, req *models.ValidationRuleRequest) (models.ValidationRule, error) {
+	tracer := otel.Tracer("ValidationRuleStore")
+	ctx, span := tracer.Start(ctx, "CreateValidationRule-SQLiteStore")
+	defer span.End()
+
+	rule := models.ValidationRule{
+		ID:       uuid.New(),
+		Target:   req.Target,
+		Field:    req.Field,
+		Operator: req.Operator,
+		Value:    req.Value,
+		Code:     req.Code,
+		Message:  req.Message,
+		CreateAt: time.Now().UTC(),
+	}
+
+	_, err := store.Conn(ctx, s.db).ExecContext(ctx, `
+	INSERT INTO validation_rule (`+validationRuleColumns+`)
+	VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+		rule.ID.String(),
+		rule.Target,
+		rule.Field,
+		rule.Operator,
+		store.NullString(string(rule.Value)),
+		rule.Code,
+		rule.Message,
+		rule.CreateAt,
+	)
+	if err != nil {
+		return models.ValidationRule{}, store.DBError(err)
+	}
+
+	return rule, nil
+}
+
+func (s *ValidationRuleStore) DeleteValidationRule(ctx context.Context, id string) (models.ValidationRule, error) {
+	tracer := otel.Tracer("ValidationRuleStore")
+	ctx, span := tracer.Start(ctx, "DeleteValidationRule-SQLiteStore")
+	defer span.End()
+
+	var deleted models.ValidationRule
+
+	err := s.tx.WithTx(ctx, func(ctx context.Context) error {
+		conn := store.Conn(ctx, s.db)
+
+		var err error
+		deleted, err = scanValidationRule(conn.QueryRowContext(ctx, `SELECT `+validationRuleColumns+` FROM validation_rule WHERE id = ?`, id))
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return apperrors.NotFound("validation rule not found")
+			}
+			return err
+		}
+
+		_, err = conn.ExecContext(ctx, `DELETE FROM validation_rule WHERE id = ?`, id)
+		return store.DBError(err)
+	})
+
+	if err != nil {
+		return models.ValidationRule{}, err
+	}
+
+	return deleted, nil
+}
+
+func scanValidationRule(row scanner) (models.ValidationRule, error) {
+	var rule models.ValidationRule
+	var value sql.NullString
+
+	err := row.Scan(
+		&rule.ID,
+		&rule.Target,
+		&rule.Field,
+		&rule.Operator,
+		&value,
+		&rule.Code,
+		&rule.Message,
+		&rule.CreateAt,
+	)
+	if value.Valid {
+		rule.Value = []byte(value.String)
+	}
+
+	return rule, err
+}
@@ -0,0 +1,381 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/geekAshish/DriveDesk/apperrors"
+	"github.com/geekAshish/DriveDesk/models"
+	"github.com/geekAshish/DriveDesk/store"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel"
+)
+
+const (
+	webhookColumns  = `id, url, events, secret, active, created_at, updated_at`
+	deliveryColumns = `id, webhook_id, event_id, event_type, payload, status, attempts, next_attempt_at, last_status_code, last_error, created_at, updated_at`
+)
+
+type WebhookStore struct {
+	db *sql.DB
+	tx *store.TxManager
+}
+
+func NewWebhookStore(db *sql.DB) *WebhookStore {
+	return &WebhookStore{db: db, tx: store.NewTxManager(db)}
+}
+
+func (s *WebhookStore) ListWebhooks(ctx context.Context) ([]models.Webhook, error) {
+	tracer := otel.Tracer("WebhookStore")
+	ctx, span := tracer.Start(ctx, "ListWebhooks-SQLiteStore")
+	defer span.End()
+
+	rows, err := store.Conn(ctx, s.db).QueryContext(ctx, `SELECT `+webhookColumns+` FROM webhook ORDER BY created_at, id`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	webhooks := []models.Webhook{}
+	for rows.Next() {
+		webhook, err := scanWebhook(rows)
+		if err != nil {
+			return nil, err
+		}
+		webhooks = append(webhooks, webhook)
+	}
+
+	return webhooks, rows.Err()
+}
+
+func (s *WebhookStore) GetWebhook(ctx context.Context, id string) (models.Webhook, error) {
+	tracer := otel.Tracer("WebhookStore")
+	ctx, span := tracer.Start(ctx, "GetWebhook-SQLiteStore")
+	defer span.End()
+
+	return getWebhook(ctx, store.Conn(ctx, s.db), id)
+}
+
+func (s *WebhookStore) CreateWebhook(ctx context.Context, req *models.WebhookRequest) (models.Webhook, error) {
+	tracer := otel.Tracer("WebhookStore")
+	ctx, span := tracer.Start(ctx, "CreateWebhook-SQLiteStore")
+	defer span.End()
+
+	now := time.Now().UTC()
+	webhook := models.Webhook{
+		ID:       uuid.New(),
+		URL:      req.URL,
+		Events:   req.Events,
+		Secret:   req.Secret,
+		Active:   req.Active == nil || *req.Active,
+		CreateAt: now,
+		UpdateAt: now,
+	}
+
+	events, err := json.Marshal(webhook.Events)
+	if err != nil {
+		return models.Webhook{}, err
+	}
+
+	_, err = store.Conn(ctx, s.db).ExecContext(ctx, `
+	INSERT INTO webhook (`+webhookColumns+`)
+	VALUES (?, ?, ?, ?, ?, ?, ?)`,
+		webhook.ID.String(), webhook.URL, string(events), webhook.Secret, webhook.Active, webhook.CreateAt, webhook.UpdateAt)
+	if err != nil {
+		return models.Webhook{}, store.DBError(err)
+	}
+
+	return webhook, nil
+}
+
+func (s *WebhookStore) UpdateWebhook(ctx context.Context, id string, req *models.WebhookRequest) (models.Webhook, error) {
+	tracer := otel.Tracer("WebhookStore")
+	ctx, span := tracer.Start(ctx, "UpdateWebhook-SQLiteStore")
+	defer span.End()
+
+	var updated models.Webhook
+
+	err := s.tx.WithTx(ctx, func(ctx context.Context) error {
+		conn := store.Conn(ctx, s.db)
+
+		var err error
+		updated, err = getWebhook(ctx, conn, id)
+		if err != nil {
+			return err
+		}
+
+		updated.URL = req.URL
+		updated.Events = req.Events
+		if req.Secret != "" {
+			updated.Secret = req.Secret
+		}
+		if req.Active != nil {
+			updated.Active = *req.Active
+		}
+		updated.UpdateAt = time.Now().UTC()
+
+		events, err := json.Marshal(updated.Events)
+		if err != nil {
+			return err
+		}
+
+		_, err = conn.ExecContext(ctx, `
+		UPDATE webhook SET url = ?, events = ?, secret = ?, active = ?, updated_at = ?
+		WHERE id = ?`,
+			updated.URL, string(events), updated.Secret, updated.Active, updated.UpdateAt, updated.ID.String())
+		return store.DBError(err)
+	})
+
+	if err != nil {
+		return models.Webhook{}, err
+	}
+
+	return updated, nil
+}
+
+func (s *WebhookStore) DeleteWebhook(ctx context.Context, id string) (models.Webhook, error) {
+	tracer := otel.Tracer("WebhookStore")
+	ctx, span := tracer.Start(ctx, "DeleteWebhook-SQLiteStore")
+	defer span.End()
+
+	var deleted models.Webhook
+
+	err := s.tx.WithTx(ctx, func(ctx context.Context) error {
+		conn := store.Conn(ctx, s.db)
+
+		var err error
+		deleted, err = getWebhook(ctx, conn, id)
+		if err != nil {
+			return err
+		}
+
+		// deliveries go with the webhook through ON DELETE CASCADE
+		_, err = conn.ExecContext(ctx, `DELETE FROM webhook WHERE id = ?`, deleted.ID.String())
+		return store.DBError(err)
+	})
+
+	if err != nil {
+		return models.Webhook{}, err
+	}
+
+	return deleted, nil
+}
+
+func (s *WebhookStore) CreateWebhookDeliveries(ctx context.Context, deliveries []models.WebhookDelivery) error {
+	tracer := otel.Tracer("WebhookStore")
+	ctx, span := tracer.Start(ctx, "CreateWebhookDeliveries-SQLiteStore")
+	defer span.End()
+
+	return s.tx.WithTx(ctx, func(ctx context.Context) error {
+		conn := store.Conn(ctx, s.db)
+
+		for _, delivery := range deliveries {
+			_, err := conn.ExecContext(ctx, `
+			INSERT INTO webhook_delivery (`+deliveryColumns+`)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+				delivery.ID.String(),
+				delivery.WebhookID.String(),
+				delivery.EventID.String(),
+				delivery.EventType,
+				string(delivery.Payload),
+				delivery.Status,
+				delivery.Attempts,
+				delivery.NextAttemptAt.UnixMilli(),
+				delivery.LastStatusCode,
+				delivery.LastError,
+				delivery.CreateAt.UTC(),
+				delivery.UpdateAt.UTC(),
+			)
+			if err != nil {
+				return store.DBError(err)
+			}
+		}
+
+		return nil
+	})
+}
+
+func (s *WebhookStore) ListWebhookDeliveries(ctx context.Context, filter models.WebhookDeliveryFilter) ([]models.WebhookDelivery, error) {
+	tracer := otel.Tracer("WebhookStore")
+	ctx, span := tracer.Start(ctx, "ListWebhookDeliveries-SQLiteStore")
+	defer span.End()
+
+	// a negative limit is no limit in SQLite
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = -1
+	}
+
+	rows, err := store.Conn(ctx, s.db).QueryContext(ctx, `
+	SELECT `+deliveryColumns+` FROM webhook_delivery
+	WHERE (? = '' OR webhook_id = ?) AND (? = '' OR status = ?)
+	ORDER BY created_at DESC, id DESC
+	LIMIT ?`, filter.WebhookID, filter.WebhookID, filter.Status, filter.Status, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	return scanDeliveries(rows)
+}
+
+func (s *WebhookStore) GetWebhookDelivery(ctx context.Context, id string) (models.WebhookDelivery, error) {
+	tracer := otel.Tracer("WebhookStore")
+	ctx, span := tracer.Start(ctx, "GetWebhookDelivery-SQLiteStore")
+	defer span.End()
+
+	delivery, err := scanDelivery(store.Conn(ctx, s.db).QueryRowContext(ctx,
+		`SELECT `+deliveryColumns+` FROM webhook_delivery WHERE id = ?`, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return models.WebhookDelivery{}, apperrors.NotFound("webhook delivery not found")
+	}
+
+	return delivery, err
+}
+
+func (s *WebhookStore) ClaimWebhookDeliveries(ctx context.Context, now, leaseUntil time.Time, limit int) ([]models.WebhookDelivery, error) {
+	tracer := otel.Tracer("WebhookStore")
+	ctx, span := tracer.Start(ctx, "ClaimWebhookDeliveries-SQLiteStore")
+	defer span.End()
+
+	var claimed []models.WebhookDelivery
+
+	// a single writer at a time, so selecting and then updating inside one transaction is enough
+	err := s.tx.WithTx(ctx, func(ctx context.Context) error {
+		conn := store.Conn(ctx, s.db)
+
+		rows, err := conn.QueryContext(ctx, `
+		SELECT `+deliveryColumns+` FROM webhook_delivery
+		WHERE status = ? AND next_attempt_at <= ?
+		ORDER BY next_attempt_at, created_at
+		LIMIT ?`, models.DeliveryPending, now.UnixMilli(), limit)
+		if err != nil {
+			return err
+		}
+
+		claimed, err = scanDeliveries(rows)
+		if err != nil {
+			return err
+		}
+
+		for i := range claimed {
+			claimed[i].NextAttemptAt = time.UnixMilli(leaseUntil.UnixMilli())
+
+			_, err := conn.ExecContext(ctx, `UPDATE webhook_delivery SET next_attempt_at = ? WHERE id = ?`,
+				leaseUntil.UnixMilli(), claimed[i].ID.String())
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return claimed, nil
+}
+
+func (s *WebhookStore) UpdateWebhookDelivery(ctx context.Context, delivery models.WebhookDelivery) error {
+	tracer := otel.Tracer("WebhookStore")
+	ctx, span := tracer.Start(ctx, "UpdateWebhookDelivery-SQLiteStore")
+	defer span.End()
+
+	result, err := store.Conn(ctx, s.db).ExecContext(ctx, `
+	UPDATE webhook_delivery
+	SET status = ?, attempts = ?, next_attempt_at = ?, last_status_code = ?, last_error = ?, updated_at = ?
+	WHERE id = ?`,
+		delivery.Status,
+		delivery.Attempts,
+		delivery.NextAttemptAt.UnixMilli(),
+		delivery.LastStatusCode,
+		delivery.LastError,
+		time.Now().UTC(),
+		delivery.ID.String(),
+	)
+	if err != nil {
+		return err
+	}
+
+	if updated, err := result.RowsAffected(); err != nil || updated == 0 {
+		if err != nil {
+			return err
+		}
+		return apperrors.NotFound("webhook delivery not found")
+	}
+
+	return nil
+}
+
+func getWebhook(ctx context.Context, conn store.DBTX, id string) (models.Webhook, error) {
+	webhook, err := scanWebhook(conn.QueryRowContext(ctx, `SELECT `+webhookColumns+` FROM webhook WHERE id = ?`, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return models.Webhook{}, apperrors.NotFound("webhook not found")
+	}
+
+	return webhook, err
+}
+
+func scanWebhook(row scanner) (models.Webhook, error) {
+	var webhook models.Webhook
+	var events string
+
+	err := row.Scan(
+		&webhook.ID,
+		&webhook.URL,
+		&events,
+		&webhook.Secret,
+		&webhook.Active,
+		&webhook.CreateAt,
+		&webhook.UpdateAt,
+	)
+	if err != nil {
+		return models.Webhook{}, err
+	}
+
+	return webhook, json.Unmarshal([]byte(events), &webhook.Events)
+}
+
+func scanDeliveries(rows *sql.Rows) ([]models.WebhookDelivery, error) {
+	defer rows.Close()
+
+	deliveries := []models.WebhookDelivery{}
+	for rows.Next() {
+		delivery, err := scanDelivery(rows)
+		if err != nil {
+			return nil, err
+		}
+		deliveries = append(deliveries, delivery)
+	}
+
+	return deliveries, rows.Err()
+}
+
+func scanDelivery(row scanner) (models.WebhookDelivery, error) {
+	var delivery models.WebhookDelivery
+	var payload string
+	var nextAttemptAt int64
+
+	err := row.Scan(
+		&delivery.ID,
+		&delivery.WebhookID,
+		&delivery.EventID,
+		&delivery.EventType,
+		&payload,
+		&delivery.Status,
+		&delivery.Attempts,
+		&nextAttemptAt,
+		&delivery.LastStatusCode,
+		&delivery.LastError,
+		&delivery.CreateAt,
+		&delivery.UpdateAt,
+	)
+	delivery.Payload = []byte(payload)
+	delivery.NextAttemptAt = time.UnixMilli(nextAttemptAt)
+
+	return delivery, err
+}
@@ -0,0 +1,71 @@
+package storetest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/geekAshish/DriveDesk/models"
+	"github.com/google/uuid"
+)
+
+func testEventLog(t *testing.T, s Stores) {
+	if s.Event == nil {
+		t.Skip("backend has no event store")
+	}
+
+	ctx := context.Background()
+
+	if seq, err := s.Event.LatestEventSeq(ctx); err != nil || seq != 0 {
+		t.Fatalf("LatestEventSeq of an empty store = %d, %v, want 0", seq, err)
+	}
+
+	now := time.Now().UTC().Truncate(time.Millisecond)
+
+	var appended []models.ChangeEvent
+	for i, age := range []time.Duration{2 * time.Hour, time.Hour, 0} {
+		event, err := s.Event.AppendEvent(ctx, models.ChangeEvent{
+			ID:       uuid.New(),
+			Type:     models.EventCarCreated,
+			Subject:  uuid.NewString(),
+			Brand:    "Honda",
+			Data:     []byte(`{"brand":"Honda"}`),
+			CreateAt: now.Add(-age),
+		})
+		if err != nil {
+			t.Fatalf("AppendEvent: %v", err)
+		}
+		if i > 0 && event.Seq <= appended[i-1].Seq {
+			t.Fatalf("AppendEvent seq = %d after %d, want it to increase", event.Seq, appended[i-1].Seq)
+		}
+		appended = append(appended, event)
+	}
+
+	if seq, err := s.Event.LatestEventSeq(ctx); err != nil || seq != appended[2].Seq {
+		t.Errorf("LatestEventSeq = %d, %v, want %d", seq, err, appended[2].Seq)
+	}
+
+	events, err := s.Event.ListEventsSince(ctx, appended[0].Seq, 10)
+	if err != nil {
+		t.Fatalf("ListEventsSince: %v", err)
+	}
+	if len(events) != 2 || events[0].Seq != appended[1].Seq || events[1].Seq != appended[2].Seq {
+		t.Fatalf("ListEventsSince = %+v, want the last two events in order", events)
+	}
+	if got := events[0]; got.ID != appended[1].ID || got.Brand != "Honda" || string(got.Data) != `{"brand":"Honda"}` || !got.CreateAt.Equal(appended[1].CreateAt) {
+		t.Errorf("listed event = %+v, want %+v", got, appended[1])
+	}
+
+	if limited, err := s.Event.ListEventsSince(ctx, 0, 1); err != nil || len(limited) != 1 || limited[0].Seq != appended[0].Seq {
+		t.Errorf("ListEventsSince with a limit = %+v, %v, want the first event", limited, err)
+	}
+
+	deleted, err := s.Event.DeleteEventsBefore(ctx, now.Add(-30*time.Minute))
+	if err != nil || deleted != 2 {
+		t.Fatalf("DeleteEventsBefore = %d, %v, want 2", deleted, err)
+	}
+
+	if events, err := s.Event.ListEventsSince(ctx, 0, 10); err != nil || len(events) != 1 || events[0].Seq != appended[2].Seq {
+		t.Errorf("ListEventsSince after the purge = %+v, %v, want the newest event", events, err)
+	}
+}
@@ -0,0 +1,119 @@
+package storetest
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/geekAshish/DriveDesk/models"
+)
+
+func testIdempotencyRoundTrip(t *testing.T, s Stores) {
+	if s.Idempotency == nil {
+		t.Skip("backend has no idempotency store")
+	}
+
+	ctx := context.Background()
+	now := time.Now()
+
+	record := models.IdempotencyRecord{
+		Owner:       "alice",
+		Key:         "retry-1",
+		RequestHash: "hash-1",
+		CreateAt:    now,
+		ExpireAt:    now.Add(time.Minute),
+	}
+
+	if _, reserved, err := s.Idempotency.ReserveIdempotencyKey(ctx, record); err != nil || !reserved {
+		t.Fatalf("ReserveIdempotencyKey = %v, %v, want a reservation", reserved, err)
+	}
+
+	existing, reserved, err := s.Idempotency.ReserveIdempotencyKey(ctx, models.IdempotencyRecord{
+		Owner: "alice", Key: "retry-1", RequestHash: "hash-2", CreateAt: now, ExpireAt: now.Add(time.Minute),
+	})
+	if err != nil || reserved {
+		t.Fatalf("second ReserveIdempotencyKey = %v, %v, want the key to be taken", reserved, err)
+	}
+	if existing.RequestHash != "hash-1" || existing.Status != 0 {
+		t.Errorf("held record = %+v, want the first request still in progress", existing)
+	}
+
+	// keys are scoped to their owner
+	if _, reserved, err := s.Idempotency.ReserveIdempotencyKey(ctx, models.IdempotencyRecord{
+		Owner: "bob", Key: "retry-1", RequestHash: "hash-3", CreateAt: now, ExpireAt: now.Add(time.Minute),
+	}); err != nil || !reserved {
+		t.Fatalf("ReserveIdempotencyKey for another owner = %v, %v, want a reservation", reserved, err)
+	}
+
+	record.Status = 201
+	record.ContentType = "application/json"
+	record.Header = http.Header{"Location": {"/cars/1"}, "Vary": {"Accept"}}
+	record.Body = []byte(`{"id":"1"}`)
+	record.ExpireAt = now.Add(time.Hour)
+	if err := s.Idempotency.SaveIdempotencyResponse(ctx, record); err != nil {
+		t.Fatalf("SaveIdempotencyResponse: %v", err)
+	}
+
+	existing, reserved, err = s.Idempotency.ReserveIdempotencyKey(ctx, models.IdempotencyRecord{
+		Owner: "alice", Key: "retry-1", RequestHash: "hash-1", CreateAt: now, ExpireAt: now.Add(time.Minute),
+	})
+	if err != nil || reserved {
+		t.Fatalf("ReserveIdempotencyKey after saving = %v, %v, want the key to be taken", reserved, err)
+	}
+	if existing.Status != 201 || existing.ContentType != "application/json" || !reflect.DeepEqual(existing.Header, record.Header) || !bytes.Equal(existing.Body, record.Body) {
+		t.Errorf("saved record = %+v, want the stored response", existing)
+	}
+
+	if err := s.Idempotency.DeleteIdempotencyKey(ctx, "alice", "retry-1"); err != nil {
+		t.Fatalf("DeleteIdempotencyKey: %v", err)
+	}
+	if _, reserved, err := s.Idempotency.ReserveIdempotencyKey(ctx, record); err != nil || !reserved {
+		t.Fatalf("ReserveIdempotencyKey after delete = %v, %v, want a reservation", reserved, err)
+	}
+}
+
+func testIdempotencyExpiry(t *testing.T, s Stores) {
+	if s.Idempotency == nil {
+		t.Skip("backend has no idempotency store")
+	}
+
+	ctx := context.Background()
+	now := time.Now()
+
+	for _, key := range []string{"old-1", "old-2"} {
+		if _, _, err := s.Idempotency.ReserveIdempotencyKey(ctx, models.IdempotencyRecord{
+			Owner: "alice", Key: key, RequestHash: "hash-1", CreateAt: now.Add(-time.Hour), ExpireAt: now.Add(-time.Minute),
+		}); err != nil {
+			t.Fatalf("ReserveIdempotencyKey: %v", err)
+		}
+	}
+	if _, _, err := s.Idempotency.ReserveIdempotencyKey(ctx, models.IdempotencyRecord{
+		Owner: "alice", Key: "live", RequestHash: "hash-1", CreateAt: now, ExpireAt: now.Add(time.Hour),
+	}); err != nil {
+		t.Fatalf("ReserveIdempotencyKey: %v", err)
+	}
+
+	// an expired key is free again
+	if _, reserved, err := s.Idempotency.ReserveIdempotencyKey(ctx, models.IdempotencyRecord{
+		Owner: "alice", Key: "old-1", RequestHash: "hash-2", CreateAt: now, ExpireAt: now.Add(-time.Second),
+	}); err != nil || !reserved {
+		t.Fatalf("ReserveIdempotencyKey of an expired key = %v, %v, want a reservation", reserved, err)
+	}
+
+	deleted, err := s.Idempotency.DeleteExpiredIdempotencyKeys(ctx, now)
+	if err != nil {
+		t.Fatalf("DeleteExpiredIdempotencyKeys: %v", err)
+	}
+	if deleted != 2 {
+		t.Errorf("DeleteExpiredIdempotencyKeys deleted %d records, want 2", deleted)
+	}
+
+	if _, reserved, err := s.Idempotency.ReserveIdempotencyKey(ctx, models.IdempotencyRecord{
+		Owner: "alice", Key: "live", RequestHash: "hash-1", CreateAt: now, ExpireAt: now.Add(time.Hour),
+	}); err != nil || reserved {
+		t.Fatalf("ReserveIdempotencyKey of a live key = %v, %v, want the key to be taken", reserved, err)
+	}
+}
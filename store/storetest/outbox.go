@@ -0,0 +1,164 @@
+package storetest
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/geekAshish/DriveDesk/models"
+	"github.com/google/uuid"
+)
+
+func outboxMessage(sink, aggregateID string, now time.Time) models.OutboxMessage {
+	return models.OutboxMessage{
+		Sink:          sink,
+		AggregateType: models.EntityCar,
+		AggregateID:   aggregateID,
+		EventID:       uuid.New(),
+		EventType:     models.EventCarUpdated,
+		Payload:       []byte(`{"id":"` + aggregateID + `"}`),
+		Status:        models.OutboxPending,
+		NextAttemptAt: now,
+		CreateAt:      now,
+	}
+}
+
+func claimOutbox(t *testing.T, s Stores, sink string, now time.Time) []models.OutboxMessage {
+	t.Helper()
+
+	claimed, err := s.Outbox.ClaimOutbox(context.Background(), sink, now, now.Add(time.Minute), 10)
+	if err != nil {
+		t.Fatalf("ClaimOutbox: %v", err)
+	}
+	return claimed
+}
+
+func aggregateIDs(messages []models.OutboxMessage) []string {
+	ids := []string{}
+	for _, message := range messages {
+		ids = append(ids, message.AggregateID)
+	}
+	return ids
+}
+
+func testOutboxOrdering(t *testing.T, s Stores) {
+	if s.Outbox == nil {
+		t.Skip("backend has no outbox store")
+	}
+
+	ctx := context.Background()
+	now := time.Now().UTC().Truncate(time.Millisecond)
+
+	err := s.Outbox.AppendOutbox(ctx, []models.OutboxMessage{
+		outboxMessage("webhooks", "a", now),
+		outboxMessage("webhooks", "b", now),
+		outboxMessage("webhooks", "a", now),
+		outboxMessage("file", "a", now),
+	})
+	if err != nil {
+		t.Fatalf("AppendOutbox: %v", err)
+	}
+
+	// the second message of a waits for the first
+	claimed := claimOutbox(t, s, "webhooks", now)
+	if ids := aggregateIDs(claimed); len(ids) != 2 || ids[0] != "a" || ids[1] != "b" {
+		t.Fatalf("claimed %v, want the first messages of a and b", ids)
+	}
+	if got := claimed[0]; got.Seq >= claimed[1].Seq || got.EventType != models.EventCarUpdated || string(got.Payload) != `{"id":"a"}` || got.Attempts != 0 || !got.CreateAt.Equal(now) {
+		t.Errorf("claimed message = %+v", got)
+	}
+
+	if again := claimOutbox(t, s, "webhooks", now); len(again) != 0 {
+		t.Fatalf("claimed %v again while they are leased", aggregateIDs(again))
+	}
+
+	if file := claimOutbox(t, s, "file", now); len(file) != 1 || file[0].AggregateID != "a" {
+		t.Errorf("claimed %v for another sink, want its own message of a", aggregateIDs(file))
+	}
+
+	published := claimed[0]
+	published.Status = models.OutboxPublished
+	published.Attempts = 1
+	published.PublishedAt = &now
+	if err := s.Outbox.UpdateOutbox(ctx, published); err != nil {
+		t.Fatalf("UpdateOutbox: %v", err)
+	}
+
+	next := claimOutbox(t, s, "webhooks", now)
+	if len(next) != 1 || next[0].AggregateID != "a" || next[0].Seq <= published.Seq {
+		t.Fatalf("claimed %+v after publishing, want the second message of a", next)
+	}
+
+	// a dead message no longer holds its entity back
+	if err := s.Outbox.AppendOutbox(ctx, []models.OutboxMessage{outboxMessage("webhooks", "b", now)}); err != nil {
+		t.Fatalf("AppendOutbox: %v", err)
+	}
+	if held := claimOutbox(t, s, "webhooks", now); len(held) != 0 {
+		t.Fatalf("claimed %v while the first message of b is pending", aggregateIDs(held))
+	}
+
+	dead := claimed[1]
+	dead.Status = models.OutboxDead
+	dead.Attempts = 3
+	dead.LastError = "broken"
+	if err := s.Outbox.UpdateOutbox(ctx, dead); err != nil {
+		t.Fatalf("UpdateOutbox: %v", err)
+	}
+	freed := claimOutbox(t, s, "webhooks", now)
+	if len(freed) != 1 || freed[0].AggregateID != "b" {
+		t.Fatalf("claimed %v after the first message of b died, want the second", aggregateIDs(freed))
+	}
+	freed[0].Status = models.OutboxPublished
+	freed[0].PublishedAt = &now
+	if err := s.Outbox.UpdateOutbox(ctx, freed[0]); err != nil {
+		t.Fatalf("UpdateOutbox: %v", err)
+	}
+
+	// messages retried later are not due yet
+	retry := next[0]
+	retry.Attempts = 1
+	retry.NextAttemptAt = now.Add(time.Hour)
+	if err := s.Outbox.UpdateOutbox(ctx, retry); err != nil {
+		t.Fatalf("UpdateOutbox: %v", err)
+	}
+	if early := claimOutbox(t, s, "webhooks", now.Add(30*time.Minute)); len(early) != 0 {
+		t.Fatalf("claimed %v before the retry is due", aggregateIDs(early))
+	}
+	if due := claimOutbox(t, s, "webhooks", now.Add(2*time.Hour)); len(due) != 1 || due[0].Seq != retry.Seq || due[0].Attempts != 1 {
+		t.Fatalf("claimed %+v once the retry is due, want it", due)
+	}
+
+	if err := s.Outbox.UpdateOutbox(ctx, models.OutboxMessage{Seq: 1 << 40, Status: models.OutboxPublished}); err == nil {
+		t.Error("UpdateOutbox of a missing message succeeded")
+	}
+
+	deleted, err := s.Outbox.DeletePublishedOutbox(ctx, now.Add(time.Second))
+	if err != nil || deleted != 2 {
+		t.Errorf("DeletePublishedOutbox = %d, %v, want the two published messages", deleted, err)
+	}
+}
+
+func testOutboxTransaction(t *testing.T, s Stores) {
+	if s.Outbox == nil || s.Tx == nil {
+		t.Skip("backend has no outbox store or no transaction manager")
+	}
+
+	ctx := context.Background()
+	now := time.Now().UTC().Truncate(time.Millisecond)
+	failed := errors.New("rolled back")
+
+	err := s.Tx.WithTx(ctx, func(ctx context.Context) error {
+		if err := s.Outbox.AppendOutbox(ctx, []models.OutboxMessage{outboxMessage("webhooks", "a", now)}); err != nil {
+			return err
+		}
+		return failed
+	})
+	if !errors.Is(err, failed) {
+		t.Fatalf("WithTx = %v, want the error from fn", err)
+	}
+
+	if claimed := claimOutbox(t, s, "webhooks", now); len(claimed) != 0 {
+		t.Errorf("claimed %v written in a rolled back transaction", aggregateIDs(claimed))
+	}
+}
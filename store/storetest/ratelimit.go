@@ -0,0 +1,52 @@
+package storetest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/geekAshish/DriveDesk/models"
+)
+
+func testRateLimitBuckets(t *testing.T, s Stores) {
+	if s.RateLimit == nil {
+		t.Skip("backend has no rate limit store")
+	}
+
+	ctx := context.Background()
+	limit := models.RateLimit{Rate: 1, Burst: 2}
+	// whole milliseconds survive every backend, and a zone other than UTC
+	// catches backends that store local times without one
+	now := time.Now().Truncate(time.Millisecond).In(time.FixedZone("UTC+5", 5*60*60))
+
+	for i, want := range []bool{true, true, false} {
+		if _, taken, err := s.RateLimit.TakeRateLimitToken(ctx, "user:alice", limit, now); err != nil || taken != want {
+			t.Fatalf("TakeRateLimitToken #%d = %v, %v, want %v", i+1, taken, err, want)
+		}
+	}
+
+	// buckets are kept per key
+	bucket, taken, err := s.RateLimit.TakeRateLimitToken(ctx, "user:bob", limit, now)
+	if err != nil || !taken || bucket.Remaining() != 1 {
+		t.Fatalf("TakeRateLimitToken for another key = %+v, %v, %v, want one token left", bucket, taken, err)
+	}
+
+	// a second later the bucket holds one token again
+	bucket, taken, err = s.RateLimit.TakeRateLimitToken(ctx, "user:alice", limit, now.Add(time.Second))
+	if err != nil || !taken || bucket.Remaining() != 0 {
+		t.Fatalf("TakeRateLimitToken after a second = %+v, %v, %v, want the refilled token taken", bucket, taken, err)
+	}
+	if !bucket.UpdateAt.Equal(now.Add(time.Second)) {
+		t.Errorf("bucket updated at %v, want %v", bucket.UpdateAt, now.Add(time.Second))
+	}
+
+	deleted, err := s.RateLimit.DeleteRateLimitBucketsBefore(ctx, now.Add(time.Millisecond))
+	if err != nil || deleted != 1 {
+		t.Fatalf("DeleteRateLimitBucketsBefore = %d, %v, want only bob's bucket deleted", deleted, err)
+	}
+
+	// bob starts over with a full bucket
+	if bucket, _, err := s.RateLimit.TakeRateLimitToken(ctx, "user:bob", limit, now.Add(time.Second)); err != nil || bucket.Remaining() != 1 {
+		t.Errorf("TakeRateLimitToken after delete = %+v, %v, want a full bucket", bucket, err)
+	}
+}
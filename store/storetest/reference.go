@@ -0,0 +1,145 @@
+package storetest
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/geekAshish/DriveDesk/apperrors"
+	"github.com/geekAshish/DriveDesk/models"
+)
+
+func testReferenceDefaults(t *testing.T, s Stores) {
+	if s.Reference == nil {
+		t.Skip("backend has no reference store")
+	}
+
+	values, err := s.Reference.ListReferenceValues(context.Background(), models.ReferenceKindFuelType)
+	if err != nil {
+		t.Fatalf("ListReferenceValues: %v", err)
+	}
+
+	catalog := models.NewEnumCatalog(values)
+	for _, fuelType := range []string{"petrol", "diesel", "electric", "hybrid"} {
+		if code, ok := catalog.Canonical(models.ReferenceKindFuelType, fuelType); !ok || code != fuelType {
+			t.Errorf("fuel type %s resolves to %q, %v", fuelType, code, ok)
+		}
+	}
+	if code, ok := catalog.Canonical(models.ReferenceKindFuelType, "Gasoline"); !ok || code != "petrol" {
+		t.Errorf("Gasoline resolves to %q, %v; want petrol", code, ok)
+	}
+}
+
+func testReferenceRoundTrip(t *testing.T, s Stores) {
+	if s.Reference == nil {
+		t.Skip("backend has no reference store")
+	}
+
+	ctx := context.Background()
+
+	created, err := s.Reference.CreateReferenceValue(ctx, models.ReferenceKindBodyType, &models.ReferenceValueRequest{
+		Code:    "sedan",
+		Label:   "Sedan",
+		Aliases: []string{"saloon"},
+	})
+	if err != nil {
+		t.Fatalf("CreateReferenceValue: %v", err)
+	}
+	assertReferenceValue(t, created, "Sedan", []string{"saloon"}, true)
+
+	got, err := s.Reference.GetReferenceValue(ctx, models.ReferenceKindBodyType, "sedan")
+	if err != nil {
+		t.Fatalf("GetReferenceValue: %v", err)
+	}
+	assertReferenceValue(t, got, "Sedan", []string{"saloon"}, true)
+
+	inactive := false
+	updated, err := s.Reference.UpdateReferenceValue(ctx, models.ReferenceKindBodyType, "sedan", &models.ReferenceValueRequest{
+		Code:    "sedan",
+		Label:   "Sedan / Saloon",
+		Aliases: []string{"notchback"},
+		Active:  &inactive,
+	})
+	if err != nil {
+		t.Fatalf("UpdateReferenceValue: %v", err)
+	}
+	assertReferenceValue(t, updated, "Sedan / Saloon", []string{"notchback"}, false)
+
+	values, err := s.Reference.ListReferenceValues(ctx, models.ReferenceKindBodyType)
+	if err != nil {
+		t.Fatalf("ListReferenceValues: %v", err)
+	}
+	if len(values) != 1 || values[0].Code != "sedan" {
+		t.Fatalf("ListReferenceValues(body_type) = %+v, want only sedan", values)
+	}
+	assertReferenceValue(t, values[0], "Sedan / Saloon", []string{"notchback"}, false)
+
+	deleted, err := s.Reference.DeleteReferenceValue(ctx, models.ReferenceKindBodyType, "sedan")
+	if err != nil {
+		t.Fatalf("DeleteReferenceValue: %v", err)
+	}
+	if deleted.Code != "sedan" {
+		t.Errorf("DeleteReferenceValue code = %s, want sedan", deleted.Code)
+	}
+
+	if _, err := s.Reference.GetReferenceValue(ctx, models.ReferenceKindBodyType, "sedan"); !errors.Is(err, apperrors.ErrNotFound) {
+		t.Errorf("GetReferenceValue after delete: got %v, want ErrNotFound", err)
+	}
+	if _, err := s.Reference.UpdateReferenceValue(ctx, models.ReferenceKindBodyType, "sedan", &models.ReferenceValueRequest{Code: "sedan", Label: "Sedan"}); !errors.Is(err, apperrors.ErrNotFound) {
+		t.Errorf("UpdateReferenceValue after delete: got %v, want ErrNotFound", err)
+	}
+	if _, err := s.Reference.DeleteReferenceValue(ctx, models.ReferenceKindBodyType, "sedan"); !errors.Is(err, apperrors.ErrNotFound) {
+		t.Errorf("DeleteReferenceValue after delete: got %v, want ErrNotFound", err)
+	}
+}
+
+func testReferenceConflicts(t *testing.T, s Stores) {
+	if s.Reference == nil {
+		t.Skip("backend has no reference store")
+	}
+
+	ctx := context.Background()
+	kind := models.ReferenceKindBodyType
+
+	if _, err := s.Reference.CreateReferenceValue(ctx, kind, &models.ReferenceValueRequest{Code: "suv", Label: "SUV", Aliases: []string{"crossover"}}); err != nil {
+		t.Fatalf("CreateReferenceValue: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		req  models.ReferenceValueRequest
+	}{
+		{"DuplicateCode", models.ReferenceValueRequest{Code: "suv", Label: "SUV"}},
+		{"CodeIsAlias", models.ReferenceValueRequest{Code: "crossover", Label: "Crossover"}},
+		{"AliasIsCode", models.ReferenceValueRequest{Code: "jeep", Label: "Jeep", Aliases: []string{"suv"}}},
+		{"AliasTaken", models.ReferenceValueRequest{Code: "jeep", Label: "Jeep", Aliases: []string{"crossover"}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := s.Reference.CreateReferenceValue(ctx, kind, &tt.req); !errors.Is(err, apperrors.ErrConflict) {
+				t.Errorf("CreateReferenceValue: got %v, want ErrConflict", err)
+			}
+		})
+	}
+
+	// the failed creates must not have left anything behind
+	if _, err := s.Reference.GetReferenceValue(ctx, kind, "jeep"); !errors.Is(err, apperrors.ErrNotFound) {
+		t.Errorf("GetReferenceValue(jeep): got %v, want ErrNotFound", err)
+	}
+
+	// the same code may exist under another kind
+	if _, err := s.Reference.CreateReferenceValue(ctx, models.ReferenceKindTransmission, &models.ReferenceValueRequest{Code: "suv", Label: "SUV"}); err != nil {
+		t.Errorf("CreateReferenceValue in another kind: %v", err)
+	}
+}
+
+func assertReferenceValue(t *testing.T, got models.ReferenceValue, label string, aliases []string, active bool) {
+	t.Helper()
+
+	if got.Label != label || got.Active != active || !slices.Equal(got.Aliases, aliases) {
+		t.Errorf("reference value = {%s %v %v}, want {%s %v %v}",
+			got.Label, got.Aliases, got.Active, label, aliases, active)
+	}
+}
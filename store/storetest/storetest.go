@@ -0,0 +1,556 @@
+// Package storetest is a conformance suite for store.CarStoreInterface,
+// store.EngineStoreInterface, store.ReferenceStoreInterface,
+// store.ValidationRuleStoreInterface, store.IdempotencyStoreInterface,
+// store.WebhookStoreInterface, store.EventStoreInterface,
+// store.OutboxStoreInterface, store.SyncStoreInterface and
+// store.RateLimitStoreInterface. Every backend runs
+// it from its own tests so they all behave the same way behind the services.
+package storetest
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/geekAshish/DriveDesk/apperrors"
+	"github.com/geekAshish/DriveDesk/models"
+	"github.com/geekAshish/DriveDesk/store"
+	"github.com/google/uuid"
+)
+
+// Stores is one backend under test. Tx may be nil for backends without
+// transactions, in which case the unit-of-work tests are skipped. Reference,
+// Rule, Idempotency, Webhook, Event, Outbox, Sync and RateLimit may be nil for
+// backends without reference data, validation rules, idempotency keys,
+// webhooks, a change stream, an outbox, delta sync or rate limits.
+type Stores struct {
+	Car       store.CarStoreInterface
+	Engine    store.EngineStoreInterface
+	Tx        store.TxManagerInterface
+	Reference store.ReferenceStoreInterface
+	Rule      store.ValidationRuleStoreInterface
+
+	Idempotency store.IdempotencyStoreInterface
+	Webhook     store.WebhookStoreInterface
+	Event       store.EventStoreInterface
+	Outbox      store.OutboxStoreInterface
+	Sync        store.SyncStoreInterface
+	RateLimit   store.RateLimitStoreInterface
+}
+
+// Factory returns stores backed by a fresh, empty database. It is called once
+// per subtest and should register any cleanup with t.Cleanup.
+type Factory func(t *testing.T) Stores
+
+// Run runs the whole suite against the backend built by newStores.
+func Run(t *testing.T, newStores Factory) {
+	tests := []struct {
+		name string
+		fn   func(t *testing.T, s Stores)
+	}{
+		{"EngineRoundTrip", testEngineRoundTrip},
+		{"EngineNotFound", testEngineNotFound},
+		{"GetEnginesByIds", testGetEnginesByIds},
+		{"CarRoundTrip", testCarRoundTrip},
+		{"CarNotFound", testCarNotFound},
+		{"CarRequiresEngine", testCarRequiresEngine},
+		{"DeleteEngineCascadesToCars", testDeleteEngineCascades},
+		{"GetCarByBrand", testGetCarByBrand},
+		{"GetCarsByEngine", testGetCarsByEngine},
+		{"CarVIN", testCarVIN},
+		{"TxRollback", testTxRollback},
+		{"ReferenceDefaults", testReferenceDefaults},
+		{"ReferenceRoundTrip", testReferenceRoundTrip},
+		{"ReferenceConflicts", testReferenceConflicts},
+		{"ValidationRuleRoundTrip", testValidationRuleRoundTrip},
+		{"IdempotencyRoundTrip", testIdempotencyRoundTrip},
+		{"IdempotencyExpiry", testIdempotencyExpiry},
+		{"WebhookRoundTrip", testWebhookRoundTrip},
+		{"WebhookDeliveries", testWebhookDeliveries},
+		{"EventLog", testEventLog},
+		{"OutboxOrdering", testOutboxOrdering},
+		{"OutboxTransaction", testOutboxTransaction},
+		{"SyncRows", testSyncRows},
+		{"SyncTransaction", testSyncTransaction},
+		{"RateLimitBuckets", testRateLimitBuckets},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.fn(t, newStores(t))
+		})
+	}
+}
+
+func testEngineRoundTrip(t *testing.T, s Stores) {
+	ctx := context.Background()
+
+	created, err := s.Engine.CreateEngine(ctx, &models.EngineRequest{Dispacement: 2000, NoOfCylinders: 4, CarRange: 600})
+	if err != nil {
+		t.Fatalf("CreateEngine: %v", err)
+	}
+	if created.EngineID == uuid.Nil {
+		t.Fatal("CreateEngine returned a nil id")
+	}
+	assertEngine(t, created, 2000, 4, 600)
+
+	got, err := s.Engine.GetEngineById(ctx, created.EngineID.String())
+	if err != nil {
+		t.Fatalf("GetEngineById: %v", err)
+	}
+	if got.EngineID != created.EngineID {
+		t.Fatalf("GetEngineById id = %s, want %s", got.EngineID, created.EngineID)
+	}
+	assertEngine(t, got, 2000, 4, 600)
+
+	updated, err := s.Engine.UpdateEngine(ctx, created.EngineID.String(), &models.EngineRequest{Dispacement: 1600, NoOfCylinders: 3, CarRange: 450})
+	if err != nil {
+		t.Fatalf("UpdateEngine: %v", err)
+	}
+	if updated.EngineID != created.EngineID {
+		t.Fatalf("UpdateEngine id = %s, want %s", updated.EngineID, created.EngineID)
+	}
+	assertEngine(t, updated, 1600, 3, 450)
+
+	got, err = s.Engine.GetEngineById(ctx, created.EngineID.String())
+	if err != nil {
+		t.Fatalf("GetEngineById after update: %v", err)
+	}
+	assertEngine(t, got, 1600, 3, 450)
+
+	deleted, err := s.Engine.DeleteEngine(ctx, created.EngineID.String())
+	if err != nil {
+		t.Fatalf("DeleteEngine: %v", err)
+	}
+	if deleted.EngineID != created.EngineID {
+		t.Fatalf("DeleteEngine id = %s, want %s", deleted.EngineID, created.EngineID)
+	}
+
+	if _, err := s.Engine.GetEngineById(ctx, created.EngineID.String()); !errors.Is(err, apperrors.ErrNotFound) {
+		t.Fatalf("GetEngineById after delete error = %v, want ErrNotFound", err)
+	}
+}
+
+func testEngineNotFound(t *testing.T, s Stores) {
+	ctx := context.Background()
+	req := &models.EngineRequest{Dispacement: 2000, NoOfCylinders: 4, CarRange: 600}
+
+	for _, id := range []string{uuid.NewString(), "not-a-uuid"} {
+		if _, err := s.Engine.GetEngineById(ctx, id); !errors.Is(err, apperrors.ErrNotFound) {
+			t.Errorf("GetEngineById(%q) error = %v, want ErrNotFound", id, err)
+		}
+		if _, err := s.Engine.UpdateEngine(ctx, id, req); !errors.Is(err, apperrors.ErrNotFound) {
+			t.Errorf("UpdateEngine(%q) error = %v, want ErrNotFound", id, err)
+		}
+		if _, err := s.Engine.DeleteEngine(ctx, id); !errors.Is(err, apperrors.ErrNotFound) {
+			t.Errorf("DeleteEngine(%q) error = %v, want ErrNotFound", id, err)
+		}
+	}
+}
+
+func testGetEnginesByIds(t *testing.T, s Stores) {
+	ctx := context.Background()
+
+	first := createEngine(t, s)
+	second := createEngine(t, s)
+	createEngine(t, s)
+
+	got, err := s.Engine.GetEnginesByIds(ctx, []string{
+		second.EngineID.String(), first.EngineID.String(), uuid.NewString(), "not-a-uuid", first.EngineID.String(),
+	})
+	if err != nil {
+		t.Fatalf("GetEnginesByIds: %v", err)
+	}
+
+	found := map[uuid.UUID]models.Engine{}
+	for _, engine := range got {
+		found[engine.EngineID] = engine
+	}
+	if len(got) != 2 || len(found) != 2 {
+		t.Fatalf("GetEnginesByIds returned %d engines, want first and second once each", len(got))
+	}
+	for _, want := range []models.Engine{first, second} {
+		engine, ok := found[want.EngineID]
+		if !ok {
+			t.Fatalf("GetEnginesByIds is missing engine %s", want.EngineID)
+		}
+		assertEngine(t, engine, want.Dispacement, want.NoOfCylinders, want.CarRange)
+	}
+
+	if got, err := s.Engine.GetEnginesByIds(ctx, nil); err != nil || len(got) != 0 {
+		t.Fatalf("GetEnginesByIds(nil) = %v, %v; want no engines", got, err)
+	}
+}
+
+func testCarRoundTrip(t *testing.T, s Stores) {
+	ctx := context.Background()
+	engine := createEngine(t, s)
+	otherEngine := createEngine(t, s)
+
+	req := carRequest("Honda Civic", "Honda", engine.EngineID)
+
+	created, err := s.Car.CreateCar(ctx, &req)
+	if err != nil {
+		t.Fatalf("CreateCar: %v", err)
+	}
+	if created.ID == uuid.Nil {
+		t.Fatal("CreateCar returned a nil id")
+	}
+	assertCar(t, created, req)
+	if created.CreateAt.IsZero() || created.UpdateAt.IsZero() {
+		t.Errorf("CreateCar timestamps not set: created_at %v, updated_at %v", created.CreateAt, created.UpdateAt)
+	}
+
+	got, err := s.Car.GetCarById(ctx, created.ID.String())
+	if err != nil {
+		t.Fatalf("GetCarById: %v", err)
+	}
+	if got.ID != created.ID {
+		t.Fatalf("GetCarById id = %s, want %s", got.ID, created.ID)
+	}
+	assertCar(t, got, req)
+	assertEngine(t, got.Engine, 2000, 4, 600)
+
+	update := carRequest("Honda Accord", "Honda", otherEngine.EngineID)
+	update.Year = "2021"
+	update.Price = 31000
+
+	updated, err := s.Car.UpdateCar(ctx, created.ID.String(), &update)
+	if err != nil {
+		t.Fatalf("UpdateCar: %v", err)
+	}
+	if updated.ID != created.ID {
+		t.Fatalf("UpdateCar id = %s, want %s", updated.ID, created.ID)
+	}
+	assertCar(t, updated, update)
+	if updated.UpdateAt.Before(created.UpdateAt.Add(-time.Second)) {
+		t.Errorf("UpdateCar updated_at %v is before the original %v", updated.UpdateAt, created.UpdateAt)
+	}
+
+	got, err = s.Car.GetCarById(ctx, created.ID.String())
+	if err != nil {
+		t.Fatalf("GetCarById after update: %v", err)
+	}
+	assertCar(t, got, update)
+
+	deleted, err := s.Car.DeleteCar(ctx, created.ID.String())
+	if err != nil {
+		t.Fatalf("DeleteCar: %v", err)
+	}
+	if deleted.ID != created.ID {
+		t.Fatalf("DeleteCar id = %s, want %s", deleted.ID, created.ID)
+	}
+
+	if _, err := s.Car.GetCarById(ctx, created.ID.String()); !errors.Is(err, apperrors.ErrNotFound) {
+		t.Fatalf("GetCarById after delete error = %v, want ErrNotFound", err)
+	}
+
+	// deleting a car leaves its engine alone
+	if _, err := s.Engine.GetEngineById(ctx, otherEngine.EngineID.String()); err != nil {
+		t.Fatalf("GetEngineById after deleting its car: %v", err)
+	}
+}
+
+func testCarNotFound(t *testing.T, s Stores) {
+	ctx := context.Background()
+	engine := createEngine(t, s)
+	req := carRequest("Honda Civic", "Honda", engine.EngineID)
+
+	for _, id := range []string{uuid.NewString(), "not-a-uuid"} {
+		if _, err := s.Car.GetCarById(ctx, id); !errors.Is(err, apperrors.ErrNotFound) {
+			t.Errorf("GetCarById(%q) error = %v, want ErrNotFound", id, err)
+		}
+		if _, err := s.Car.UpdateCar(ctx, id, &req); !errors.Is(err, apperrors.ErrNotFound) {
+			t.Errorf("UpdateCar(%q) error = %v, want ErrNotFound", id, err)
+		}
+		if _, err := s.Car.DeleteCar(ctx, id); !errors.Is(err, apperrors.ErrNotFound) {
+			t.Errorf("DeleteCar(%q) error = %v, want ErrNotFound", id, err)
+		}
+	}
+}
+
+func testCarRequiresEngine(t *testing.T, s Stores) {
+	ctx := context.Background()
+
+	req := carRequest("Honda Civic", "Honda", uuid.New())
+	if _, err := s.Car.CreateCar(ctx, &req); !errors.Is(err, apperrors.ErrValidation) {
+		t.Fatalf("CreateCar with an unknown engine error = %v, want ErrValidation", err)
+	}
+
+	cars, err := s.Car.GetCarByBrand(ctx, "Honda", false)
+	if err != nil {
+		t.Fatalf("GetCarByBrand: %v", err)
+	}
+	if len(cars) != 0 {
+		t.Fatalf("CreateCar with an unknown engine stored %d cars", len(cars))
+	}
+
+	engine := createEngine(t, s)
+	created := createCar(t, s, "Honda Civic", "Honda", engine.EngineID)
+
+	update := carRequest("Honda Civic", "Honda", uuid.New())
+	if _, err := s.Car.UpdateCar(ctx, created.ID.String(), &update); !errors.Is(err, apperrors.ErrValidation) {
+		t.Fatalf("UpdateCar with an unknown engine error = %v, want ErrValidation", err)
+	}
+
+	got, err := s.Car.GetCarById(ctx, created.ID.String())
+	if err != nil {
+		t.Fatalf("GetCarById: %v", err)
+	}
+	if got.Engine.EngineID != engine.EngineID {
+		t.Fatalf("failed UpdateCar changed engine to %s", got.Engine.EngineID)
+	}
+}
+
+func testDeleteEngineCascades(t *testing.T, s Stores) {
+	ctx := context.Background()
+	engine := createEngine(t, s)
+	keptEngine := createEngine(t, s)
+
+	removed := createCar(t, s, "Honda Civic", "Honda", engine.EngineID)
+	kept := createCar(t, s, "Honda Jazz", "Honda", keptEngine.EngineID)
+
+	if _, err := s.Engine.DeleteEngine(ctx, engine.EngineID.String()); err != nil {
+		t.Fatalf("DeleteEngine: %v", err)
+	}
+
+	if _, err := s.Car.GetCarById(ctx, removed.ID.String()); err == nil {
+		t.Fatal("car using a deleted engine should be deleted with it")
+	}
+
+	if _, err := s.Car.GetCarById(ctx, kept.ID.String()); err != nil {
+		t.Fatalf("car using another engine was removed: %v", err)
+	}
+}
+
+func testGetCarByBrand(t *testing.T, s Stores) {
+	ctx := context.Background()
+	engine := createEngine(t, s)
+
+	civic := createCar(t, s, "Honda Civic", "Honda", engine.EngineID)
+	jazz := createCar(t, s, "Honda Jazz", "Honda", engine.EngineID)
+	createCar(t, s, "Toyota Corolla", "Toyota", engine.EngineID)
+
+	cars, err := s.Car.GetCarByBrand(ctx, "Honda", false)
+	if err != nil {
+		t.Fatalf("GetCarByBrand: %v", err)
+	}
+	if len(cars) != 2 {
+		t.Fatalf("GetCarByBrand(Honda) returned %d cars, want 2", len(cars))
+	}
+
+	ids := map[uuid.UUID]bool{}
+	for _, car := range cars {
+		ids[car.ID] = true
+
+		if car.Brand != "Honda" {
+			t.Errorf("GetCarByBrand(Honda) returned a %s", car.Brand)
+		}
+		if car.Engine.EngineID != engine.EngineID {
+			t.Errorf("engine id = %s, want %s", car.Engine.EngineID, engine.EngineID)
+		}
+		if car.Engine.Dispacement != 0 {
+			t.Errorf("engine details returned without isEngine: %+v", car.Engine)
+		}
+	}
+	if !ids[civic.ID] || !ids[jazz.ID] {
+		t.Fatalf("GetCarByBrand(Honda) = %v, want %s and %s", ids, civic.ID, jazz.ID)
+	}
+
+	cars, err = s.Car.GetCarByBrand(ctx, "Honda", true)
+	if err != nil {
+		t.Fatalf("GetCarByBrand with engine: %v", err)
+	}
+	if len(cars) != 2 {
+		t.Fatalf("GetCarByBrand(Honda, true) returned %d cars, want 2", len(cars))
+	}
+	for _, car := range cars {
+		assertEngine(t, car.Engine, 2000, 4, 600)
+	}
+
+	cars, err = s.Car.GetCarByBrand(ctx, "Lada", false)
+	if err != nil {
+		t.Fatalf("GetCarByBrand(Lada): %v", err)
+	}
+	if cars == nil || len(cars) != 0 {
+		t.Fatalf("GetCarByBrand(Lada) = %v, want an empty, non-nil list", cars)
+	}
+}
+
+func testGetCarsByEngine(t *testing.T, s Stores) {
+	ctx := context.Background()
+	engine := createEngine(t, s)
+	otherEngine := createEngine(t, s)
+
+	civic := createCar(t, s, "Honda Civic", "Honda", engine.EngineID)
+	corolla := createCar(t, s, "Toyota Corolla", "Toyota", engine.EngineID)
+	createCar(t, s, "Honda Jazz", "Honda", otherEngine.EngineID)
+
+	cars, err := s.Car.GetCarsByEngine(ctx, engine.EngineID.String())
+	if err != nil {
+		t.Fatalf("GetCarsByEngine: %v", err)
+	}
+	if len(cars) != 2 || cars[0].ID != civic.ID || cars[1].ID != corolla.ID {
+		t.Fatalf("GetCarsByEngine = %v, want %s and %s", cars, civic.ID, corolla.ID)
+	}
+	for _, car := range cars {
+		assertEngine(t, car.Engine, 2000, 4, 600)
+	}
+
+	for _, id := range []string{uuid.NewString(), "not-a-uuid"} {
+		cars, err := s.Car.GetCarsByEngine(ctx, id)
+		if err != nil || cars == nil || len(cars) != 0 {
+			t.Errorf("GetCarsByEngine(%s) = %v, %v, want an empty, non-nil list", id, cars, err)
+		}
+	}
+}
+
+func testCarVIN(t *testing.T, s Stores) {
+	ctx := context.Background()
+	engine := createEngine(t, s)
+
+	req := carRequest("Honda Civic", "Honda", engine.EngineID)
+	req.VIN = "JHMCM56557C404453"
+
+	withVIN, err := s.Car.CreateCar(ctx, &req)
+	if err != nil {
+		t.Fatalf("CreateCar: %v", err)
+	}
+	if withVIN.VIN != req.VIN {
+		t.Errorf("CreateCar vin = %q, want %q", withVIN.VIN, req.VIN)
+	}
+
+	got, err := s.Car.GetCarById(ctx, withVIN.ID.String())
+	if err != nil {
+		t.Fatalf("GetCarById: %v", err)
+	}
+	if got.VIN != req.VIN {
+		t.Errorf("GetCarById vin = %q, want %q", got.VIN, req.VIN)
+	}
+
+	if _, err := s.Car.CreateCar(ctx, &req); !errors.Is(err, apperrors.ErrConflict) {
+		t.Errorf("CreateCar with a taken VIN: got %v, want ErrConflict", err)
+	}
+
+	// cars without a VIN never clash with each other
+	noVIN := carRequest("Honda Jazz", "Honda", engine.EngineID)
+	first := createCar(t, s, noVIN.Name, noVIN.Brand, engine.EngineID)
+	createCar(t, s, noVIN.Name, noVIN.Brand, engine.EngineID)
+
+	if first.VIN != "" {
+		t.Errorf("car created without a VIN has vin %q", first.VIN)
+	}
+
+	noVIN.VIN = req.VIN
+	if _, err := s.Car.UpdateCar(ctx, first.ID.String(), &noVIN); !errors.Is(err, apperrors.ErrConflict) {
+		t.Errorf("UpdateCar to a taken VIN: got %v, want ErrConflict", err)
+	}
+
+	// keeping its own VIN is not a clash
+	req.Price = 27000
+	if _, err := s.Car.UpdateCar(ctx, withVIN.ID.String(), &req); err != nil {
+		t.Errorf("UpdateCar keeping its VIN: %v", err)
+	}
+}
+
+func testTxRollback(t *testing.T, s Stores) {
+	if s.Tx == nil {
+		t.Skip("backend has no transaction manager")
+	}
+
+	ctx := context.Background()
+	engine := createEngine(t, s)
+
+	var carID string
+
+	err := s.Tx.WithTx(ctx, func(ctx context.Context) error {
+		req := carRequest("Honda Civic", "Honda", engine.EngineID)
+
+		car, err := s.Car.CreateCar(ctx, &req)
+		if err != nil {
+			return err
+		}
+		carID = car.ID.String()
+
+		if _, err := s.Engine.UpdateEngine(ctx, engine.EngineID.String(), &models.EngineRequest{Dispacement: 1, NoOfCylinders: 1, CarRange: 1}); err != nil {
+			return err
+		}
+
+		// a failing call to another store aborts the whole unit of work
+		_, err = s.Engine.GetEngineById(ctx, uuid.NewString())
+		return err
+	})
+	if err == nil {
+		t.Fatal("WithTx: expected the error from the failing call")
+	}
+
+	if _, err := s.Car.GetCarById(ctx, carID); err == nil {
+		t.Error("car created in a rolled back unit of work is still there")
+	}
+
+	got, err := s.Engine.GetEngineById(ctx, engine.EngineID.String())
+	if err != nil {
+		t.Fatalf("GetEngineById: %v", err)
+	}
+	assertEngine(t, got, 2000, 4, 600)
+}
+
+func createEngine(t *testing.T, s Stores) models.Engine {
+	t.Helper()
+
+	engine, err := s.Engine.CreateEngine(context.Background(), &models.EngineRequest{Dispacement: 2000, NoOfCylinders: 4, CarRange: 600})
+	if err != nil {
+		t.Fatalf("CreateEngine: %v", err)
+	}
+
+	return engine
+}
+
+func createCar(t *testing.T, s Stores, name, brand string, engineID uuid.UUID) models.Car {
+	t.Helper()
+
+	req := carRequest(name, brand, engineID)
+
+	car, err := s.Car.CreateCar(context.Background(), &req)
+	if err != nil {
+		t.Fatalf("CreateCar: %v", err)
+	}
+
+	return car
+}
+
+func carRequest(name, brand string, engineID uuid.UUID) models.CarRequest {
+	return models.CarRequest{
+		Name:     name,
+		Year:     "2023",
+		Brand:    brand,
+		FuelType: "petrol",
+		Price:    25000,
+		Engine:   models.Engine{EngineID: engineID},
+	}
+}
+
+func assertCar(t *testing.T, got models.Car, want models.CarRequest) {
+	t.Helper()
+
+	if got.Name != want.Name || got.Year != want.Year || got.Brand != want.Brand || got.FuelType != want.FuelType || got.Price != want.Price {
+		t.Errorf("car = {%s %s %s %s %v}, want {%s %s %s %s %v}",
+			got.Name, got.Year, got.Brand, got.FuelType, got.Price,
+			want.Name, want.Year, want.Brand, want.FuelType, want.Price)
+	}
+
+	if got.Engine.EngineID != want.Engine.EngineID {
+		t.Errorf("car engine id = %s, want %s", got.Engine.EngineID, want.Engine.EngineID)
+	}
+}
+
+func assertEngine(t *testing.T, got models.Engine, displacement, cylinders, carRange float64) {
+	t.Helper()
+
+	if got.Dispacement != displacement || got.NoOfCylinders != cylinders || got.CarRange != carRange {
+		t.Errorf("engine = {%v %v %v}, want {%v %v %v}",
+			got.Dispacement, got.NoOfCylinders, got.CarRange,
+			displacement, cylinders, carRange)
+	}
+}
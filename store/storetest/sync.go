@@ -0,0 +1,117 @@
+package storetest
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/geekAshish/DriveDesk/apperrors"
+	"github.com/geekAshish/DriveDesk/models"
+)
+
+func recordSync(t *testing.T, s Stores, ctx context.Context, row models.SyncRow) int64 {
+	t.Helper()
+
+	version, err := s.Sync.RecordSyncChange(ctx, row)
+	if err != nil {
+		t.Fatalf("RecordSyncChange: %v", err)
+	}
+	return version
+}
+
+func listSync(t *testing.T, s Stores, since int64) []models.SyncRow {
+	t.Helper()
+
+	rows, err := s.Sync.ListSyncRows(context.Background(), since, 10)
+	if err != nil {
+		t.Fatalf("ListSyncRows: %v", err)
+	}
+	return rows
+}
+
+func testSyncRows(t *testing.T, s Stores) {
+	if s.Sync == nil {
+		t.Skip("backend has no sync store")
+	}
+
+	ctx := context.Background()
+
+	engine := recordSync(t, s, ctx, models.SyncRow{Entity: models.EntityEngine, ID: "e1"})
+	first := recordSync(t, s, ctx, models.SyncRow{Entity: models.EntityCar, ID: "c1", ParentID: "e1"})
+	second := recordSync(t, s, ctx, models.SyncRow{Entity: models.EntityCar, ID: "c2", ParentID: "e1"})
+	other := recordSync(t, s, ctx, models.SyncRow{Entity: models.EntityCar, ID: "c3", ParentID: "e2"})
+	updated := recordSync(t, s, ctx, models.SyncRow{Entity: models.EntityCar, ID: "c1", ParentID: "e1"})
+
+	if !(engine < first && first < second && second < other && other < updated) {
+		t.Fatalf("versions %d, %d, %d, %d, %d do not increase", engine, first, second, other, updated)
+	}
+
+	row, err := s.Sync.GetSyncRow(ctx, models.EntityCar, "c1")
+	if err != nil || row.Version != updated || row.ParentID != "e1" || row.Deleted || row.UpdateAt.IsZero() {
+		t.Errorf("GetSyncRow = %+v, %v, want c1 at version %d", row, err, updated)
+	}
+
+	if _, err := s.Sync.GetSyncRow(ctx, models.EntityEngine, "c1"); !errors.Is(err, apperrors.ErrNotFound) {
+		t.Errorf("GetSyncRow of an engine with a car's id = %v, want not found", err)
+	}
+
+	if err := s.Sync.DeleteSyncChildren(ctx, models.EntityCar, "e1"); err != nil {
+		t.Fatalf("DeleteSyncChildren: %v", err)
+	}
+
+	rows := listSync(t, s, other)
+	if len(rows) != 2 || rows[0].ID != "c2" || rows[1].ID != "c1" || !rows[0].Deleted || !rows[1].Deleted {
+		t.Fatalf("rows after %d = %+v, want tombstones for c2 then c1", other, rows)
+	}
+	if rows[0].Version <= updated || rows[1].Version <= rows[0].Version {
+		t.Errorf("tombstone versions %d, %d, want new ones after %d", rows[0].Version, rows[1].Version, updated)
+	}
+
+	all := listSync(t, s, 0)
+	if len(all) != 4 || all[0].ID != "e1" || all[1].ID != "c3" {
+		t.Errorf("all rows = %+v, want e1, c3 and the two tombstones", all)
+	}
+
+	if limited, err := s.Sync.ListSyncRows(ctx, 0, 1); err != nil || len(limited) != 1 || limited[0].ID != "e1" {
+		t.Errorf("ListSyncRows with a limit = %+v, %v, want e1", limited, err)
+	}
+
+	if err := s.Sync.DeleteSyncChildren(ctx, models.EntityCar, "e1"); err != nil {
+		t.Fatalf("DeleteSyncChildren again: %v", err)
+	}
+	if again := listSync(t, s, rows[1].Version); len(again) != 0 {
+		t.Errorf("deleting the children again gave %+v, want no new versions", again)
+	}
+}
+
+func testSyncTransaction(t *testing.T, s Stores) {
+	if s.Sync == nil || s.Tx == nil {
+		t.Skip("backend has no sync store or no transaction manager")
+	}
+
+	ctx := context.Background()
+	before := recordSync(t, s, ctx, models.SyncRow{Entity: models.EntityEngine, ID: "e1"})
+	failed := errors.New("rolled back")
+
+	err := s.Tx.WithTx(ctx, func(ctx context.Context) error {
+		recordSync(t, s, ctx, models.SyncRow{Entity: models.EntityEngine, ID: "e1", Deleted: true})
+		recordSync(t, s, ctx, models.SyncRow{Entity: models.EntityEngine, ID: "e2"})
+		return failed
+	})
+	if !errors.Is(err, failed) {
+		t.Fatalf("WithTx = %v, want the error from fn", err)
+	}
+
+	if rows := listSync(t, s, before); len(rows) != 0 {
+		t.Errorf("rows after a rolled back transaction = %+v, want none", rows)
+	}
+	if row, err := s.Sync.GetSyncRow(ctx, models.EntityEngine, "e1"); err != nil || row.Deleted {
+		t.Errorf("GetSyncRow = %+v, %v, want e1 still live", row, err)
+	}
+
+	// the versions of the rolled back changes may be given again, but never
+	// one below a committed version
+	if next := recordSync(t, s, ctx, models.SyncRow{Entity: models.EntityEngine, ID: "e3"}); next <= before {
+		t.Errorf("version after the rollback = %d, want more than %d", next, before)
+	}
+}
@@ -0,0 +1,85 @@
+package storetest
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/geekAshish/DriveDesk/apperrors"
+	"github.com/geekAshish/DriveDesk/models"
+	"github.com/google/uuid"
+)
+
+func testValidationRuleRoundTrip(t *testing.T, s Stores) {
+	if s.Rule == nil {
+		t.Skip("backend has no validation rule store")
+	}
+
+	ctx := context.Background()
+
+	minYear, err := s.Rule.CreateValidationRule(ctx, &models.ValidationRuleRequest{
+		Target:   models.RuleTargetCar,
+		Field:    "year",
+		Operator: models.RuleOperatorMin,
+		Value:    json.RawMessage(`2005`),
+		Code:     "too_old",
+		Message:  "imports must be 2005 or newer",
+	})
+	if err != nil {
+		t.Fatalf("CreateValidationRule: %v", err)
+	}
+	if minYear.ID == uuid.Nil {
+		t.Fatal("CreateValidationRule returned a nil id")
+	}
+
+	requireVIN, err := s.Rule.CreateValidationRule(ctx, &models.ValidationRuleRequest{
+		Target:   models.RuleTargetCar,
+		Field:    "vin",
+		Operator: models.RuleOperatorRequired,
+		Code:     "required",
+		Message:  "vin is required",
+	})
+	if err != nil {
+		t.Fatalf("CreateValidationRule: %v", err)
+	}
+
+	rules, err := s.Rule.ListValidationRules(ctx)
+	if err != nil {
+		t.Fatalf("ListValidationRules: %v", err)
+	}
+	if len(rules) != 2 || rules[0].ID != minYear.ID || rules[1].ID != requireVIN.ID {
+		t.Fatalf("ListValidationRules = %+v, want the two rules in creation order", rules)
+	}
+
+	got := rules[0]
+	if got.Target != models.RuleTargetCar || got.Field != "year" || got.Operator != models.RuleOperatorMin ||
+		string(got.Value) != "2005" || got.Code != "too_old" || got.Message != "imports must be 2005 or newer" {
+		t.Errorf("listed rule = %+v, want the created min year rule", got)
+	}
+	if len(rules[1].Value) != 0 {
+		t.Errorf("required rule value = %s, want none", rules[1].Value)
+	}
+
+	deleted, err := s.Rule.DeleteValidationRule(ctx, minYear.ID.String())
+	if err != nil {
+		t.Fatalf("DeleteValidationRule: %v", err)
+	}
+	if deleted.ID != minYear.ID {
+		t.Errorf("DeleteValidationRule id = %s, want %s", deleted.ID, minYear.ID)
+	}
+
+	for _, id := range []string{minYear.ID.String(), uuid.NewString(), "not-a-uuid"} {
+		if _, err := s.Rule.DeleteValidationRule(ctx, id); !errors.Is(err, apperrors.ErrNotFound) {
+			t.Errorf("DeleteValidationRule(%s): got %v, want ErrNotFound", id, err)
+		}
+	}
+
+	rules, err = s.Rule.ListValidationRules(ctx)
+	if err != nil {
+		t.Fatalf("ListValidationRules: %v", err)
+	}
+	if len(rules) != 1 || rules[0].ID != requireVIN.ID {
+		t.Errorf("ListValidationRules after delete = %+v, want only the vin rule", rules)
+	}
+}
@@ -0,0 +1,179 @@
+package storetest
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/geekAshish/DriveDesk/apperrors"
+	"github.com/geekAshish/DriveDesk/models"
+	"github.com/google/uuid"
+)
+
+func testWebhookRoundTrip(t *testing.T, s Stores) {
+	if s.Webhook == nil {
+		t.Skip("backend has no webhook store")
+	}
+
+	ctx := context.Background()
+
+	created, err := s.Webhook.CreateWebhook(ctx, &models.WebhookRequest{
+		URL:    "http://localhost:9000/hook",
+		Events: []string{models.EventCarCreated, models.EventEngineDeleted},
+		Secret: "0123456789abcdef",
+	})
+	if err != nil {
+		t.Fatalf("CreateWebhook: %v", err)
+	}
+	if created.ID == uuid.Nil || !created.Active {
+		t.Fatalf("CreateWebhook = %+v, want an active webhook with an id", created)
+	}
+
+	got, err := s.Webhook.GetWebhook(ctx, created.ID.String())
+	if err != nil {
+		t.Fatalf("GetWebhook: %v", err)
+	}
+	if got.URL != created.URL || got.Secret != created.Secret || len(got.Events) != 2 || got.Events[1] != models.EventEngineDeleted {
+		t.Errorf("GetWebhook = %+v, want %+v", got, created)
+	}
+
+	inactive := false
+	updated, err := s.Webhook.UpdateWebhook(ctx, created.ID.String(), &models.WebhookRequest{
+		URL:    "http://localhost:9000/other",
+		Events: []string{models.EventCarUpdated},
+		Active: &inactive,
+	})
+	if err != nil {
+		t.Fatalf("UpdateWebhook: %v", err)
+	}
+	if updated.URL != "http://localhost:9000/other" || updated.Active || updated.Secret != created.Secret {
+		t.Errorf("UpdateWebhook = %+v, want the new url, inactive and the old secret kept", updated)
+	}
+
+	list, err := s.Webhook.ListWebhooks(ctx)
+	if err != nil {
+		t.Fatalf("ListWebhooks: %v", err)
+	}
+	if len(list) != 1 || list[0].ID != created.ID {
+		t.Errorf("ListWebhooks = %+v, want the one webhook", list)
+	}
+
+	if _, err := s.Webhook.DeleteWebhook(ctx, created.ID.String()); err != nil {
+		t.Fatalf("DeleteWebhook: %v", err)
+	}
+	if _, err := s.Webhook.GetWebhook(ctx, created.ID.String()); !errors.Is(err, apperrors.ErrNotFound) {
+		t.Errorf("GetWebhook after delete error = %v, want not found", err)
+	}
+	if _, err := s.Webhook.DeleteWebhook(ctx, created.ID.String()); !errors.Is(err, apperrors.ErrNotFound) {
+		t.Errorf("second DeleteWebhook error = %v, want not found", err)
+	}
+}
+
+func testWebhookDeliveries(t *testing.T, s Stores) {
+	if s.Webhook == nil {
+		t.Skip("backend has no webhook store")
+	}
+
+	ctx := context.Background()
+
+	webhook, err := s.Webhook.CreateWebhook(ctx, &models.WebhookRequest{
+		URL:    "http://localhost:9000/hook",
+		Events: []string{models.EventCarCreated},
+		Secret: "0123456789abcdef",
+	})
+	if err != nil {
+		t.Fatalf("CreateWebhook: %v", err)
+	}
+
+	now := time.Now().UTC().Truncate(time.Millisecond)
+	newDelivery := func(age time.Duration, due time.Time) models.WebhookDelivery {
+		return models.WebhookDelivery{
+			ID:            uuid.New(),
+			WebhookID:     webhook.ID,
+			EventID:       uuid.New(),
+			EventType:     models.EventCarCreated,
+			Payload:       []byte(`{"type":"car.created"}`),
+			Status:        models.DeliveryPending,
+			NextAttemptAt: due,
+			CreateAt:      now.Add(-age),
+			UpdateAt:      now.Add(-age),
+		}
+	}
+
+	older := newDelivery(2*time.Minute, now.Add(-time.Second))
+	newer := newDelivery(time.Minute, now.Add(-time.Second))
+	later := newDelivery(0, now.Add(time.Hour))
+
+	if err := s.Webhook.CreateWebhookDeliveries(ctx, []models.WebhookDelivery{newer, older, later}); err != nil {
+		t.Fatalf("CreateWebhookDeliveries: %v", err)
+	}
+
+	leaseUntil := now.Add(time.Minute)
+	claimed, err := s.Webhook.ClaimWebhookDeliveries(ctx, now, leaseUntil, 10)
+	if err != nil {
+		t.Fatalf("ClaimWebhookDeliveries: %v", err)
+	}
+	if len(claimed) != 2 || claimed[0].ID != older.ID || claimed[1].ID != newer.ID {
+		t.Fatalf("ClaimWebhookDeliveries = %+v, want the two due deliveries, oldest first", claimed)
+	}
+	if string(claimed[0].Payload) != string(older.Payload) || !claimed[0].NextAttemptAt.Equal(leaseUntil) {
+		t.Errorf("claimed delivery = %+v, want the payload and the lease", claimed[0])
+	}
+
+	// claimed deliveries are leased until leaseUntil, so nobody else picks them up
+	if again, err := s.Webhook.ClaimWebhookDeliveries(ctx, now, leaseUntil, 10); err != nil || len(again) != 0 {
+		t.Errorf("second ClaimWebhookDeliveries = %+v, %v, want nothing", again, err)
+	}
+
+	dead := claimed[0]
+	dead.Status = models.DeliveryDead
+	dead.Attempts = 3
+	dead.LastStatusCode = 500
+	dead.LastError = "receiver answered 500"
+	if err := s.Webhook.UpdateWebhookDelivery(ctx, dead); err != nil {
+		t.Fatalf("UpdateWebhookDelivery: %v", err)
+	}
+
+	got, err := s.Webhook.GetWebhookDelivery(ctx, dead.ID.String())
+	if err != nil {
+		t.Fatalf("GetWebhookDelivery: %v", err)
+	}
+	if got.Status != models.DeliveryDead || got.Attempts != 3 || got.LastStatusCode != 500 || got.LastError != dead.LastError {
+		t.Errorf("GetWebhookDelivery = %+v, want the updated delivery", got)
+	}
+
+	missing := dead
+	missing.ID = uuid.New()
+	if err := s.Webhook.UpdateWebhookDelivery(ctx, missing); !errors.Is(err, apperrors.ErrNotFound) {
+		t.Errorf("UpdateWebhookDelivery of a missing delivery error = %v, want not found", err)
+	}
+
+	all, err := s.Webhook.ListWebhookDeliveries(ctx, models.WebhookDeliveryFilter{WebhookID: webhook.ID.String()})
+	if err != nil {
+		t.Fatalf("ListWebhookDeliveries: %v", err)
+	}
+	if len(all) != 3 || all[0].ID != later.ID || all[2].ID != older.ID {
+		t.Errorf("ListWebhookDeliveries = %+v, want all three, newest first", all)
+	}
+
+	deadOnly, err := s.Webhook.ListWebhookDeliveries(ctx, models.WebhookDeliveryFilter{Status: models.DeliveryDead})
+	if err != nil {
+		t.Fatalf("ListWebhookDeliveries by status: %v", err)
+	}
+	if len(deadOnly) != 1 || deadOnly[0].ID != dead.ID {
+		t.Errorf("ListWebhookDeliveries by status = %+v, want the dead delivery", deadOnly)
+	}
+
+	limited, err := s.Webhook.ListWebhookDeliveries(ctx, models.WebhookDeliveryFilter{Limit: 1})
+	if err != nil || len(limited) != 1 {
+		t.Errorf("ListWebhookDeliveries with a limit = %+v, %v, want one delivery", limited, err)
+	}
+
+	if _, err := s.Webhook.DeleteWebhook(ctx, webhook.ID.String()); err != nil {
+		t.Fatalf("DeleteWebhook: %v", err)
+	}
+	if _, err := s.Webhook.GetWebhookDelivery(ctx, dead.ID.String()); !errors.Is(err, apperrors.ErrNotFound) {
+		t.Errorf("GetWebhookDelivery after deleting the webhook error = %v, want not found", err)
+	}
+}
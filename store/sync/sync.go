@@ -0,0 +1,135 @@
+package sync
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/geekAshish/DriveDesk/apperrors"
+	"github.com/geekAshish/DriveDesk/models"
+	"github.com/geekAshish/DriveDesk/store"
+	"go.opentelemetry.io/otel"
+)
+
+const syncColumns = `entity, id, parent_id, version, deleted, updated_at`
+
+type Store struct {
+	db *sql.DB
+}
+
+func New(db *sql.DB) *Store {
+	return &Store{db: db}
+}
+
+func (s *Store) RecordSyncChange(ctx context.Context, row models.SyncRow) (int64, error) {
+	tracer := otel.Tracer("SyncStore")
+	ctx, span := tracer.Start(ctx, "RecordSyncChange-Store")
+	defer span.End()
+
+	return record(ctx, store.Conn(ctx, s.db), row)
+}
+
+// record increments the counter and stores the row in one statement. The
+// counter stays locked until the transaction ends, so a later version is
+// never visible before an earlier one.
+func record(ctx context.Context, conn store.DBTX, row models.SyncRow) (int64, error) {
+	var version int64
+	err := conn.QueryRowContext(ctx, `
+	WITH clock AS (
+		UPDATE sync_clock SET version = version + 1 WHERE id = 1 RETURNING version
+	)
+	INSERT INTO sync_row (entity, id, parent_id, version, deleted, updated_at)
+	SELECT $1, $2, $3, version, $4, $5 FROM clock
+	ON CONFLICT (entity, id) DO UPDATE
+	SET parent_id = EXCLUDED.parent_id, version = EXCLUDED.version, deleted = EXCLUDED.deleted, updated_at = EXCLUDED.updated_at
+	RETURNING version`,
+		row.Entity, row.ID, row.ParentID, row.Deleted, time.Now().UTC(),
+	).Scan(&version)
+	if err != nil {
+		return 0, store.DBError(err)
+	}
+
+	return version, nil
+}
+
+func (s *Store) DeleteSyncChildren(ctx context.Context, entity, parentID string) error {
+	tracer := otel.Tracer("SyncStore")
+	ctx, span := tracer.Start(ctx, "DeleteSyncChildren-Store")
+	defer span.End()
+
+	conn := store.Conn(ctx, s.db)
+
+	rows, err := conn.QueryContext(ctx,
+		`SELECT `+syncColumns+` FROM sync_row WHERE entity = $1 AND parent_id = $2 AND NOT deleted ORDER BY version`, entity, parentID)
+	if err != nil {
+		return err
+	}
+
+	children, err := scanSyncRows(rows)
+	if err != nil {
+		return err
+	}
+
+	for _, child := range children {
+		child.Deleted = true
+		if _, err := record(ctx, conn, child); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *Store) GetSyncRow(ctx context.Context, entity, id string) (models.SyncRow, error) {
+	tracer := otel.Tracer("SyncStore")
+	ctx, span := tracer.Start(ctx, "GetSyncRow-Store")
+	defer span.End()
+
+	// FOR UPDATE keeps a concurrent sync from changing the row between the
+	// version check and the write
+	row := store.Conn(ctx, s.db).QueryRowContext(ctx,
+		`SELECT `+syncColumns+` FROM sync_row WHERE entity = $1 AND id = $2 FOR UPDATE`, entity, id)
+
+	var syncRow models.SyncRow
+	err := row.Scan(&syncRow.Entity, &syncRow.ID, &syncRow.ParentID, &syncRow.Version, &syncRow.Deleted, &syncRow.UpdateAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return models.SyncRow{}, apperrors.NotFound("%s not found", entity)
+	}
+	if err != nil {
+		return models.SyncRow{}, err
+	}
+
+	return syncRow, nil
+}
+
+func (s *Store) ListSyncRows(ctx context.Context, since int64, limit int) ([]models.SyncRow, error) {
+	tracer := otel.Tracer("SyncStore")
+	ctx, span := tracer.Start(ctx, "ListSyncRows-Store")
+	defer span.End()
+
+	rows, err := store.Conn(ctx, s.db).QueryContext(ctx,
+		`SELECT `+syncColumns+` FROM sync_row WHERE version > $1 ORDER BY version LIMIT $2`, since, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	return scanSyncRows(rows)
+}
+
+func scanSyncRows(rows *sql.Rows) ([]models.SyncRow, error) {
+	defer rows.Close()
+
+	syncRows := []models.SyncRow{}
+	for rows.Next() {
+		var syncRow models.SyncRow
+
+		err := rows.Scan(&syncRow.Entity, &syncRow.ID, &syncRow.ParentID, &syncRow.Version, &syncRow.Deleted, &syncRow.UpdateAt)
+		if err != nil {
+			return nil, err
+		}
+		syncRows = append(syncRows, syncRow)
+	}
+
+	return syncRows, rows.Err()
+}
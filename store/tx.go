@@ -0,0 +1,83 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+// DBTX is the part of *sql.DB and *sql.Tx the stores use, so a query runs the
+// same way whether or not it is part of a unit of work.
+type DBTX interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+// txKey is scoped to a database handle so a transaction opened on one
+// database is never picked up by a store backed by another.
+type txKey struct {
+	db *sql.DB
+}
+
+type TxManager struct {
+	db *sql.DB
+}
+
+func NewTxManager(db *sql.DB) *TxManager {
+	return &TxManager{db: db}
+}
+
+// WithTx runs fn in a single transaction, carried to the stores through the
+// context passed to fn. It commits when fn returns nil and rolls back
+// otherwise, running the AfterCommit callbacks after a commit. When ctx
+// already carries a transaction, fn joins it and the outermost caller
+// decides whether it is committed.
+func (m *TxManager) WithTx(ctx context.Context, fn func(ctx context.Context) error) (err error) {
+	if _, ok := ctx.Value(txKey{db: m.db}).(*sql.Tx); ok {
+		return fn(ctx)
+	}
+
+	tx, err := m.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		if p := recover(); p != nil {
+			_ = tx.Rollback()
+			panic(p)
+		}
+	}()
+
+	ctx, unit := BeginUnit(ctx)
+
+	if err := fn(context.WithValue(ctx, txKey{db: m.db}, tx)); err != nil {
+		if rbErr := tx.Rollback(); rbErr != nil {
+			return fmt.Errorf("%w (rollback failed: %v)", err, rbErr)
+		}
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	unit.Committed()
+	return nil
+}
+
+// Conn returns the transaction carried by ctx for db, or db itself when the
+// call is not part of a unit of work.
+func Conn(ctx context.Context, db *sql.DB) DBTX {
+	if tx, ok := ctx.Value(txKey{db: db}).(*sql.Tx); ok {
+		return tx
+	}
+	return db
+}
+
+// NullString stores the empty string as NULL, for optional columns with a
+// unique index where several rows may leave the value out.
+func NullString(s string) sql.NullString {
+	return sql.NullString{String: s, Valid: s != ""}
+}
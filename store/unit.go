@@ -0,0 +1,43 @@
+package store
+
+import "context"
+
+// unitKey carries the unit of work of whichever backend opened one, so code
+// above the stores can tell it is inside a transaction without knowing which.
+type unitKey struct{}
+
+// Unit is the backend neutral side of a transaction: the callbacks to run
+// once it commits. TxManagers open one with BeginUnit.
+type Unit struct {
+	afterCommit []func()
+}
+
+// BeginUnit returns ctx carrying a new Unit. The TxManager that calls it
+// must call Committed on the Unit after its transaction commits.
+func BeginUnit(ctx context.Context) (context.Context, *Unit) {
+	unit := &Unit{}
+	return context.WithValue(ctx, unitKey{}, unit), unit
+}
+
+// Committed runs the callbacks registered with AfterCommit, in order.
+func (u *Unit) Committed() {
+	for _, fn := range u.afterCommit {
+		fn()
+	}
+}
+
+// InTx reports whether ctx is part of a unit of work, of any backend.
+func InTx(ctx context.Context) bool {
+	_, ok := ctx.Value(unitKey{}).(*Unit)
+	return ok
+}
+
+// AfterCommit runs fn once the unit of work carried by ctx commits, and
+// never if it rolls back. Outside a unit of work fn runs right away.
+func AfterCommit(ctx context.Context, fn func()) {
+	if unit, ok := ctx.Value(unitKey{}).(*Unit); ok {
+		unit.afterCommit = append(unit.afterCommit, fn)
+		return
+	}
+	fn()
+}
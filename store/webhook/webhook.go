@@ -0,0 +1,382 @@
+package webhook
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/geekAshish/DriveDesk/apperrors"
+	"github.com/geekAshish/DriveDesk/models"
+	"github.com/geekAshish/DriveDesk/store"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel"
+)
+
+const (
+	webhookColumns  = `id, url, events, secret, active, created_at, updated_at`
+	deliveryColumns = `id, webhook_id, event_id, event_type, payload, status, attempts, next_attempt_at, last_status_code, last_error, created_at, updated_at`
+)
+
+type Store struct {
+	db *sql.DB
+	tx *store.TxManager
+}
+
+func New(db *sql.DB) *Store {
+	return &Store{db: db, tx: store.NewTxManager(db)}
+}
+
+func (s *Store) ListWebhooks(ctx context.Context) ([]models.Webhook, error) {
+	tracer := otel.Tracer("WebhookStore")
+	ctx, span := tracer.Start(ctx, "ListWebhooks-Store")
+	defer span.End()
+
+	rows, err := store.Conn(ctx, s.db).QueryContext(ctx, `SELECT `+webhookColumns+` FROM webhook ORDER BY created_at, id`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	webhooks := []models.Webhook{}
+	for rows.Next() {
+		webhook, err := scanWebhook(rows)
+		if err != nil {
+			return nil, err
+		}
+		webhooks = append(webhooks, webhook)
+	}
+
+	return webhooks, rows.Err()
+}
+
+func (s *Store) GetWebhook(ctx context.Context, id string) (models.Webhook, error) {
+	tracer := otel.Tracer("WebhookStore")
+	ctx, span := tracer.Start(ctx, "GetWebhook-Store")
+	defer span.End()
+
+	return getWebhook(ctx, store.Conn(ctx, s.db), id)
+}
+
+func (s *Store) CreateWebhook(ctx context.Context, req *models.WebhookRequest) (models.Webhook, error) {
+	tracer := otel.Tracer("WebhookStore")
+	ctx, span := tracer.Start(ctx, "CreateWebhook-Store")
+	defer span.End()
+
+	now := time.Now()
+	webhook := models.Webhook{
+		ID:       uuid.New(),
+		URL:      req.URL,
+		Events:   req.Events,
+		Secret:   req.Secret,
+		Active:   req.Active == nil || *req.Active,
+		CreateAt: now,
+		UpdateAt: now,
+	}
+
+	events, err := json.Marshal(webhook.Events)
+	if err != nil {
+		return models.Webhook{}, err
+	}
+
+	_, err = store.Conn(ctx, s.db).ExecContext(ctx, `
+	INSERT INTO webhook (`+webhookColumns+`)
+	VALUES ($1, $2, $3, $4, $5, $6, $7)`,
+		webhook.ID, webhook.URL, string(events), webhook.Secret, webhook.Active, webhook.CreateAt, webhook.UpdateAt)
+	if err != nil {
+		return models.Webhook{}, store.DBError(err)
+	}
+
+	return webhook, nil
+}
+
+func (s *Store) UpdateWebhook(ctx context.Context, id string, req *models.WebhookRequest) (models.Webhook, error) {
+	tracer := otel.Tracer("WebhookStore")
+	ctx, span := tracer.Start(ctx, "UpdateWebhook-Store")
+	defer span.End()
+
+	var updated models.Webhook
+
+	err := s.tx.WithTx(ctx, func(ctx context.Context) error {
+		conn := store.Conn(ctx, s.db)
+
+		var err error
+		updated, err = getWebhook(ctx, conn, id)
+		if err != nil {
+			return err
+		}
+
+		updated.URL = req.URL
+		updated.Events = req.Events
+		if req.Secret != "" {
+			updated.Secret = req.Secret
+		}
+		if req.Active != nil {
+			updated.Active = *req.Active
+		}
+		updated.UpdateAt = time.Now()
+
+		events, err := json.Marshal(updated.Events)
+		if err != nil {
+			return err
+		}
+
+		_, err = conn.ExecContext(ctx, `
+		UPDATE webhook SET url = $1, events = $2, secret = $3, active = $4, updated_at = $5
+		WHERE id = $6`,
+			updated.URL, string(events), updated.Secret, updated.Active, updated.UpdateAt, updated.ID)
+		return store.DBError(err)
+	})
+
+	if err != nil {
+		return models.Webhook{}, err
+	}
+
+	return updated, nil
+}
+
+func (s *Store) DeleteWebhook(ctx context.Context, id string) (models.Webhook, error) {
+	tracer := otel.Tracer("WebhookStore")
+	ctx, span := tracer.Start(ctx, "DeleteWebhook-Store")
+	defer span.End()
+
+	var deleted models.Webhook
+
+	err := s.tx.WithTx(ctx, func(ctx context.Context) error {
+		conn := store.Conn(ctx, s.db)
+
+		var err error
+		deleted, err = getWebhook(ctx, conn, id)
+		if err != nil {
+			return err
+		}
+
+		// deliveries go with the webhook through ON DELETE CASCADE
+		_, err = conn.ExecContext(ctx, `DELETE FROM webhook WHERE id = $1`, deleted.ID)
+		return store.DBError(err)
+	})
+
+	if err != nil {
+		return models.Webhook{}, err
+	}
+
+	return deleted, nil
+}
+
+func (s *Store) CreateWebhookDeliveries(ctx context.Context, deliveries []models.WebhookDelivery) error {
+	tracer := otel.Tracer("WebhookStore")
+	ctx, span := tracer.Start(ctx, "CreateWebhookDeliveries-Store")
+	defer span.End()
+
+	return s.tx.WithTx(ctx, func(ctx context.Context) error {
+		conn := store.Conn(ctx, s.db)
+
+		for _, delivery := range deliveries {
+			_, err := conn.ExecContext(ctx, `
+			INSERT INTO webhook_delivery (`+deliveryColumns+`)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)`,
+				delivery.ID,
+				delivery.WebhookID,
+				delivery.EventID,
+				delivery.EventType,
+				string(delivery.Payload),
+				delivery.Status,
+				delivery.Attempts,
+				delivery.NextAttemptAt,
+				delivery.LastStatusCode,
+				delivery.LastError,
+				delivery.CreateAt,
+				delivery.UpdateAt,
+			)
+			if err != nil {
+				return store.DBError(err)
+			}
+		}
+
+		return nil
+	})
+}
+
+func (s *Store) ListWebhookDeliveries(ctx context.Context, filter models.WebhookDeliveryFilter) ([]models.WebhookDelivery, error) {
+	tracer := otel.Tracer("WebhookStore")
+	ctx, span := tracer.Start(ctx, "ListWebhookDeliveries-Store")
+	defer span.End()
+
+	if filter.WebhookID != "" {
+		if !store.ValidID(filter.WebhookID) {
+			return []models.WebhookDelivery{}, nil
+		}
+	}
+
+	limit := sql.NullInt64{Int64: int64(filter.Limit), Valid: filter.Limit > 0}
+
+	rows, err := store.Conn(ctx, s.db).QueryContext(ctx, `
+	SELECT `+deliveryColumns+` FROM webhook_delivery
+	WHERE ($1 = '' OR webhook_id::text = $1) AND ($2 = '' OR status = $2)
+	ORDER BY created_at DESC, id DESC
+	LIMIT $3`, filter.WebhookID, filter.Status, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	return scanDeliveries(rows)
+}
+
+func (s *Store) GetWebhookDelivery(ctx context.Context, id string) (models.WebhookDelivery, error) {
+	tracer := otel.Tracer("WebhookStore")
+	ctx, span := tracer.Start(ctx, "GetWebhookDelivery-Store")
+	defer span.End()
+
+	if !store.ValidID(id) {
+		return models.WebhookDelivery{}, apperrors.NotFound("webhook delivery not found")
+	}
+
+	delivery, err := scanDelivery(store.Conn(ctx, s.db).QueryRowContext(ctx,
+		`SELECT `+deliveryColumns+` FROM webhook_delivery WHERE id = $1`, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return models.WebhookDelivery{}, apperrors.NotFound("webhook delivery not found")
+	}
+
+	return delivery, err
+}
+
+func (s *Store) ClaimWebhookDeliveries(ctx context.Context, now, leaseUntil time.Time, limit int) ([]models.WebhookDelivery, error) {
+	tracer := otel.Tracer("WebhookStore")
+	ctx, span := tracer.Start(ctx, "ClaimWebhookDeliveries-Store")
+	defer span.End()
+
+	// SKIP LOCKED lets instances polling at the same time claim different deliveries
+	rows, err := store.Conn(ctx, s.db).QueryContext(ctx, `
+	UPDATE webhook_delivery SET next_attempt_at = $2
+	WHERE id IN (
+		SELECT id FROM webhook_delivery
+		WHERE status = $3 AND next_attempt_at <= $1
+		ORDER BY next_attempt_at, created_at
+		LIMIT $4
+		FOR UPDATE SKIP LOCKED
+	)
+	RETURNING `+deliveryColumns, now, leaseUntil, models.DeliveryPending, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	deliveries, err := scanDeliveries(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	// RETURNING has no order; all claimed rows share the lease, so created_at decides
+	sort.SliceStable(deliveries, func(i, j int) bool {
+		return deliveries[i].CreateAt.Before(deliveries[j].CreateAt)
+	})
+
+	return deliveries, nil
+}
+
+func (s *Store) UpdateWebhookDelivery(ctx context.Context, delivery models.WebhookDelivery) error {
+	tracer := otel.Tracer("WebhookStore")
+	ctx, span := tracer.Start(ctx, "UpdateWebhookDelivery-Store")
+	defer span.End()
+
+	result, err := store.Conn(ctx, s.db).ExecContext(ctx, `
+	UPDATE webhook_delivery
+	SET status = $1, attempts = $2, next_attempt_at = $3, last_status_code = $4, last_error = $5, updated_at = $6
+	WHERE id = $7`,
+		delivery.Status,
+		delivery.Attempts,
+		delivery.NextAttemptAt,
+		delivery.LastStatusCode,
+		delivery.LastError,
+		time.Now(),
+		delivery.ID,
+	)
+	if err != nil {
+		return err
+	}
+
+	if updated, err := result.RowsAffected(); err != nil || updated == 0 {
+		if err != nil {
+			return err
+		}
+		return apperrors.NotFound("webhook delivery not found")
+	}
+
+	return nil
+}
+
+func getWebhook(ctx context.Context, conn store.DBTX, id string) (models.Webhook, error) {
+	if !store.ValidID(id) {
+		return models.Webhook{}, apperrors.NotFound("webhook not found")
+	}
+
+	webhook, err := scanWebhook(conn.QueryRowContext(ctx, `SELECT `+webhookColumns+` FROM webhook WHERE id = $1`, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return models.Webhook{}, apperrors.NotFound("webhook not found")
+	}
+
+	return webhook, err
+}
+
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+func scanWebhook(row scanner) (models.Webhook, error) {
+	var webhook models.Webhook
+	var events string
+
+	err := row.Scan(
+		&webhook.ID,
+		&webhook.URL,
+		&events,
+		&webhook.Secret,
+		&webhook.Active,
+		&webhook.CreateAt,
+		&webhook.UpdateAt,
+	)
+	if err != nil {
+		return models.Webhook{}, err
+	}
+
+	return webhook, json.Unmarshal([]byte(events), &webhook.Events)
+}
+
+func scanDeliveries(rows *sql.Rows) ([]models.WebhookDelivery, error) {
+	defer rows.Close()
+
+	deliveries := []models.WebhookDelivery{}
+	for rows.Next() {
+		delivery, err := scanDelivery(rows)
+		if err != nil {
+			return nil, err
+		}
+		deliveries = append(deliveries, delivery)
+	}
+
+	return deliveries, rows.Err()
+}
+
+func scanDelivery(row scanner) (models.WebhookDelivery, error) {
+	var delivery models.WebhookDelivery
+	var payload string
+
+	err := row.Scan(
+		&delivery.ID,
+		&delivery.WebhookID,
+		&delivery.EventID,
+		&delivery.EventType,
+		&payload,
+		&delivery.Status,
+		&delivery.Attempts,
+		&delivery.NextAttemptAt,
+		&delivery.LastStatusCode,
+		&delivery.LastError,
+		&delivery.CreateAt,
+		&delivery.UpdateAt,
+	)
+	delivery.Payload = []byte(payload)
+
+	return delivery, err
+}
@@ -0,0 +1,147 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+
+	"github.com/geekAshish/DriveDesk/driver"
+	"github.com/geekAshish/DriveDesk/store"
+	"github.com/geekAshish/DriveDesk/store/memory"
+	"github.com/geekAshish/DriveDesk/store/migrations"
+	"github.com/geekAshish/DriveDesk/store/sqlite"
+
+	carStore "github.com/geekAshish/DriveDesk/store/car"
+	engineStore "github.com/geekAshish/DriveDesk/store/engine"
+	eventStore "github.com/geekAshish/DriveDesk/store/event"
+	idempotencyStore "github.com/geekAshish/DriveDesk/store/idempotency"
+	outboxStore "github.com/geekAshish/DriveDesk/store/outbox"
+	rateLimitStore "github.com/geekAshish/DriveDesk/store/ratelimit"
+	referenceStore "github.com/geekAshish/DriveDesk/store/reference"
+	ruleStore "github.com/geekAshish/DriveDesk/store/rule"
+	syncStore "github.com/geekAshish/DriveDesk/store/sync"
+	webhookStore "github.com/geekAshish/DriveDesk/store/webhook"
+)
+
+// stores is the storage backend picked by DB_DRIVER.
+type stores struct {
+	car       store.CarStoreInterface
+	engine    store.EngineStoreInterface
+	reference store.ReferenceStoreInterface
+	rule      store.ValidationRuleStoreInterface
+	tx        store.TxManagerInterface
+	close     func()
+
+	idempotency store.IdempotencyStoreInterface
+	webhook     store.WebhookStoreInterface
+	event       store.EventStoreInterface
+	outbox      store.OutboxStoreInterface
+	sync        store.SyncStoreInterface
+	rateLimit   store.RateLimitStoreInterface
+
+	// changes reports the changes to cars and engines made by every
+	// instance; nil unless the backend is Postgres
+	changes *driver.Listener
+}
+
+// openStores connects to the backend named by dbDriver: "postgres" (the
+// default), "sqlite" for single-machine deployments, or "memory", which keeps
+// everything in process and needs no database at all.
+func openStores(dbDriver string) (stores, error) {
+	if dbDriver == "memory" {
+		log.Println("using in-memory stores, data is lost on restart")
+
+		db := memory.NewDB()
+
+		return stores{
+			car:       memory.NewCarStore(db),
+			engine:    memory.NewEngineStore(db),
+			reference: memory.NewReferenceStore(db),
+			rule:      memory.NewValidationRuleStore(db),
+			tx:        memory.NewTxManager(db),
+			close:     func() {},
+
+			idempotency: memory.NewIdempotencyStore(db),
+			webhook:     memory.NewWebhookStore(db),
+			event:       memory.NewEventStore(db),
+			outbox:      memory.NewOutboxStore(db),
+			sync:        memory.NewSyncStore(db),
+			rateLimit:   memory.NewRateLimitStore(db),
+		}, nil
+	}
+
+	db, migrator, err := openDB(dbDriver)
+	if err != nil {
+		return stores{}, err
+	}
+
+	// every replica runs this on start, the advisory lock makes sure only one applies each migration
+	if _, err := migrator.Up(context.Background()); err != nil {
+		driver.CloseDB()
+		return stores{}, fmt.Errorf("applying migrations: %w", err)
+	}
+
+	if dbDriver == "sqlite" {
+		return stores{
+			car:       sqlite.NewCarStore(db),
+			engine:    sqlite.NewEngineStore(db),
+			reference: sqlite.NewReferenceStore(db),
+			rule:      sqlite.NewValidationRuleStore(db),
+			tx:        store.NewTxManager(db),
+			close:     driver.CloseDB,
+
+			idempotency: sqlite.NewIdempotencyStore(db),
+			webhook:     sqlite.NewWebhookStore(db),
+			event:       sqlite.NewEventStore(db),
+			outbox:      sqlite.NewOutboxStore(db),
+			sync:        sqlite.NewSyncStore(db),
+			rateLimit:   sqlite.NewRateLimitStore(db),
+		}, nil
+	}
+
+	return stores{
+		car:       carStore.New(db),
+		engine:    engineStore.New(db),
+		reference: referenceStore.New(db),
+		rule:      ruleStore.New(db),
+		tx:        store.NewTxManager(db),
+		close:     driver.CloseDB,
+
+		idempotency: idempotencyStore.New(db),
+		webhook:     webhookStore.New(db),
+		event:       eventStore.New(db),
+		outbox:      outboxStore.New(db),
+		sync:        syncStore.New(db),
+		rateLimit:   rateLimitStore.New(db),
+
+		changes: driver.NewListener(driver.ConnString()),
+	}, nil
+}
+
+// openDB connects to the SQL database named by dbDriver and returns the
+// migrator for its dialect.
+func openDB(dbDriver string) (*sql.DB, *migrations.Migrator, error) {
+	var newMigrator func(db *sql.DB) (*migrations.Migrator, error)
+
+	switch dbDriver {
+	case "", "postgres":
+		driver.InitDB()
+		newMigrator = migrations.NewPostgres
+	case "sqlite":
+		driver.InitSQLite()
+		newMigrator = migrations.NewSQLite
+	default:
+		return nil, nil, fmt.Errorf("unknown DB_DRIVER %q, expected postgres, sqlite or memory", dbDriver)
+	}
+
+	db := driver.GetDB()
+
+	migrator, err := newMigrator(db)
+	if err != nil {
+		driver.CloseDB()
+		return nil, nil, err
+	}
+
+	return db, migrator, nil
+}
@@ -0,0 +1,131 @@
+// Package validation checks request fields against reusable rules and
+// collects every violation, so clients can fix all of them in one round trip.
+package validation
+
+import (
+	"slices"
+
+	"github.com/geekAshish/DriveDesk/apperrors"
+)
+
+// Rule is a single check on a value of type T. Code is the stable reason
+// reported to clients when Test returns false.
+type Rule[T any] struct {
+	Code    string
+	Message string
+	Test    func(value T) bool
+}
+
+// Check adds the violations of a set of rules that are not known up front,
+// such as rules configured per deployment, to a validator.
+type Check func(v *Validator)
+
+// Validator collects field errors. The zero value is not usable, use New.
+type Validator struct {
+	prefix string
+	errs   *[]apperrors.FieldError
+}
+
+func New() *Validator {
+	return &Validator{errs: &[]apperrors.FieldError{}}
+}
+
+// Nested returns a validator for the fields of a nested object. Its field
+// errors are reported as "<name>.<field>" on the parent.
+func (v *Validator) Nested(name string) *Validator {
+	return &Validator{prefix: v.path(name), errs: v.errs}
+}
+
+// Add records a violation that was checked by hand.
+func (v *Validator) Add(field, code, message string) {
+	*v.errs = append(*v.errs, apperrors.FieldError{Field: v.path(field), Code: code, Message: message})
+}
+
+// Has reports whether a violation was already recorded for field.
+func (v *Validator) Has(field string) bool {
+	path := v.path(field)
+	for _, err := range *v.errs {
+		if err.Field == path {
+			return true
+		}
+	}
+	return false
+}
+
+// Errors returns the violations collected so far.
+func (v *Validator) Errors() []apperrors.FieldError {
+	return slices.Clone(*v.errs)
+}
+
+// Err returns a validation error carrying every violation, or nil when there
+// are none.
+func (v *Validator) Err() error {
+	if len(*v.errs) == 0 {
+		return nil
+	}
+	return apperrors.InvalidFields(v.Errors())
+}
+
+func (v *Validator) path(field string) string {
+	if v.prefix == "" {
+		return field
+	}
+	return v.prefix + "." + field
+}
+
+// Field checks value against rules in order and records the first rule it
+// breaks. Later rules usually assume earlier ones passed, e.g. a range check
+// after a "must be a number" check, so they are skipped.
+func Field[T any](v *Validator, field string, value T, rules ...Rule[T]) {
+	for _, rule := range rules {
+		if !rule.Test(value) {
+			v.Add(field, rule.Code, rule.Message)
+			return
+		}
+	}
+}
+
+// Required fails on the zero value of T.
+func Required[T comparable](message string) Rule[T] {
+	return Rule[T]{
+		Code:    "required",
+		Message: message,
+		Test: func(value T) bool {
+			var zero T
+			return value != zero
+		},
+	}
+}
+
+// Positive fails on values that are zero or negative.
+func Positive[T ~int | ~int64 | ~float64](message string) Rule[T] {
+	return Rule[T]{
+		Code:    "must_be_positive",
+		Message: message,
+		Test: func(value T) bool {
+			return value > 0
+		},
+	}
+}
+
+// Between fails on values outside [min, max].
+func Between[T ~int | ~int64 | ~float64](min, max T, message string) Rule[T] {
+	return Rule[T]{
+		Code:    "out_of_range",
+		Message: message,
+		Test: func(value T) bool {
+			return value >= min && value <= max
+		},
+	}
+}
+
+// OneOf fails on values that are not in allowed.
+func OneOf[T comparable](allowed []T, message string) Rule[T] {
+	return Rule[T]{
+		Code:    "invalid_choice",
+		Message: message,
+		Test: func(value T) bool {
+			return slices.Contains(allowed, value)
+		},
+	}
+}
@@ -0,0 +1,56 @@
+package validation
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/geekAshish/DriveDesk/apperrors"
+)
+
+func TestValidatorCollectsEveryField(t *testing.T) {
+	v := New()
+
+	Field(v, "name", "", Required[string]("name is required"))
+	Field(v, "price", -1.0, Positive[float64]("price must be greater than zero"))
+	Field(v.Nested("engine"), "car_range", 0.0, Positive[float64]("car range must be greater than zero"))
+	Field(v, "brand", "Honda", Required[string]("brand is required"))
+
+	want := []apperrors.FieldError{
+		{Field: "name", Code: "required", Message: "name is required"},
+		{Field: "price", Code: "must_be_positive", Message: "price must be greater than zero"},
+		{Field: "engine.car_range", Code: "must_be_positive", Message: "car range must be greater than zero"},
+	}
+
+	err := v.Err()
+	if !errors.Is(err, apperrors.ErrValidation) {
+		t.Fatalf("Err() = %v, want a validation error", err)
+	}
+	if got := apperrors.Fields(err); !reflect.DeepEqual(got, want) {
+		t.Fatalf("Fields = %+v, want %+v", got, want)
+	}
+}
+
+func TestFieldStopsAtFirstBrokenRule(t *testing.T) {
+	v := New()
+
+	Field(v, "fuel_type", "",
+		Required[string]("fuel type is required"),
+		OneOf([]string{"petrol"}, "not a valid fuel type"),
+	)
+
+	errs := v.Errors()
+	if len(errs) != 1 || errs[0].Code != "required" {
+		t.Fatalf("errors = %+v, want only the required violation", errs)
+	}
+}
+
+func TestValidatorWithoutViolations(t *testing.T) {
+	v := New()
+
+	Field(v, "year", 2020, Between(1886, 2030, "year out of range"))
+
+	if err := v.Err(); err != nil {
+		t.Fatalf("Err() = %v, want nil", err)
+	}
+}